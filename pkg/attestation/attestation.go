@@ -6,11 +6,15 @@ SPDX-License-Identifier: Apache-2.0
 package attestation
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	intoto "github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/common"
+	"github.com/package-url/packageurl-go"
 
 	"github.com/openvex/go-vex/pkg/vex"
 )
@@ -55,3 +59,135 @@ func (att *Attestation) AddSubjects(subs []intoto.Subject) error {
 	att.Subject = append(att.Subject, subs...)
 	return nil
 }
+
+// PayloadType is the DSSE payload type of in-toto statements
+const PayloadType = "application/vnd.in-toto+json"
+
+// intotoAlgorithms maps the OpenVEX hash algorithm names to the names used
+// in in-toto digest sets.
+var intotoAlgorithms = map[vex.Algorithm]string{
+	vex.MD5:        "md5",
+	vex.SHA1:       "sha1",
+	vex.SHA256:     "sha256",
+	vex.SHA384:     "sha384",
+	vex.SHA512:     "sha512",
+	vex.SHA3224:    "sha3_224",
+	vex.SHA3256:    "sha3_256",
+	vex.SHA3384:    "sha3_384",
+	vex.SHA3512:    "sha3_512",
+	vex.BLAKE2S256: "blake2s",
+	vex.BLAKE2B512: "blake2b",
+}
+
+// NewFromDocument returns an attestation wrapping the VEX document as its
+// predicate. The attestation subjects are derived from the digests of the
+// products in the document (see SubjectsFromDocument).
+func NewFromDocument(doc *vex.VEX) (*Attestation, error) {
+	subjects := SubjectsFromDocument(doc)
+	if len(subjects) == 0 {
+		return nil, fmt.Errorf("unable to derive attestation subjects, no product digests found in document")
+	}
+	att := New()
+	att.Predicate = *doc
+	if err := att.AddSubjects(subjects); err != nil {
+		return nil, fmt.Errorf("adding subjects: %w", err)
+	}
+	return att, nil
+}
+
+// SubjectsFromDocument returns a list of in-toto subjects built from the
+// products in a VEX document. Digests are read from the product hashes and
+// from the version of OCI purls, which is the image digest. Products without
+// digests are skipped.
+func SubjectsFromDocument(doc *vex.VEX) []intoto.Subject {
+	subjects := []intoto.Subject{}
+	index := map[string]int{}
+	for i := range doc.Statements {
+		for j := range doc.Statements[i].Products {
+			p := &doc.Statements[i].Products[j]
+			name, digests := productDigests(&p.Component)
+			if len(digests) == 0 {
+				continue
+			}
+			if n, ok := index[name]; ok {
+				for algo, val := range digests {
+					subjects[n].Digest[algo] = val
+				}
+				continue
+			}
+			index[name] = len(subjects)
+			subjects = append(subjects, intoto.Subject{Name: name, Digest: digests})
+		}
+	}
+	return subjects
+}
+
+// productDigests returns a name and the digest set of a component
+func productDigests(c *vex.Component) (string, common.DigestSet) {
+	digests := common.DigestSet{}
+	for algo, val := range c.Hashes {
+		if name, ok := intotoAlgorithms[algo]; ok {
+			digests[name] = string(val)
+		}
+	}
+
+	name := c.ID
+	purl := c.ID
+	if !strings.HasPrefix(purl, "pkg:") {
+		purl = c.Identifiers[vex.PURL]
+	}
+	if p, err := packageurl.FromString(purl); err == nil && p.Type == packageurl.TypeOCI {
+		if algo, val, ok := strings.Cut(p.Version, ":"); ok {
+			digests[algo] = val
+		}
+	}
+	if name == "" {
+		name = purl
+	}
+	return name, digests
+}
+
+// envelope captures the fields of a DSSE envelope needed to read its payload
+type envelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+}
+
+// Parse reads an OpenVEX attestation. The data can be an in-toto statement or
+// a DSSE envelope wrapping it. An error is returned if the predicate type of
+// the statement is not the OpenVEX type.
+func Parse(data []byte) (*Attestation, error) {
+	env := envelope{}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("decoding attestation: %w", err)
+	}
+	if env.PayloadType != "" {
+		if env.PayloadType != PayloadType {
+			return nil, fmt.Errorf("unsupported envelope payload type %q", env.PayloadType)
+		}
+		payload, err := base64.StdEncoding.DecodeString(env.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("decoding envelope payload: %w", err)
+		}
+		data = payload
+	}
+
+	att := &Attestation{}
+	if err := json.Unmarshal(data, att); err != nil {
+		return nil, fmt.Errorf("decoding attestation: %w", err)
+	}
+	if att.PredicateType != vex.TypeURI {
+		return nil, fmt.Errorf("attestation predicate type is %q, expected %q", att.PredicateType, vex.TypeURI)
+	}
+	return att, nil
+}
+
+// ExtractVEX parses an attestation and returns the VEX document in its
+// predicate.
+func ExtractVEX(data []byte) (*vex.VEX, error) {
+	att, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return &att.Predicate, nil
+}
@@ -7,9 +7,11 @@ package attestation
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"testing"
 
+	intoto "github.com/in-toto/in-toto-golang/in_toto"
 	"github.com/stretchr/testify/require"
 
 	"github.com/openvex/go-vex/pkg/vex"
@@ -30,3 +32,79 @@ func TestSerialize(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, att2.Predicate.Author, "Chainguard")
 }
+
+func TestNewFromDocument(t *testing.T) {
+	doc := vex.New()
+	doc.Statements = []vex.Statement{
+		{
+			Vulnerability: vex.Vulnerability{Name: "CVE-2023-1234"},
+			Status:        vex.StatusFixed,
+			Products: []vex.Product{
+				{Component: vex.Component{
+					ID: "pkg:oci/curl@sha256%3A47fed8868b46b060efb8699dc40e981a0c785650223e03602d8c4493fc75b68c",
+				}},
+				{Component: vex.Component{
+					ID:     "https://example.com/bin/tool",
+					Hashes: map[vex.Algorithm]vex.Hash{vex.SHA512: "abcd", vex.SHA256: "1234"},
+				}},
+				{Component: vex.Component{ID: "pkg:apk/wolfi/bash@1.0.0"}},
+			},
+		},
+		{
+			Vulnerability: vex.Vulnerability{Name: "CVE-2023-5678"},
+			Status:        vex.StatusFixed,
+			Products: []vex.Product{
+				{Component: vex.Component{
+					ID:     "https://example.com/bin/tool",
+					Hashes: map[vex.Algorithm]vex.Hash{vex.SHA1: "5678"},
+				}},
+			},
+		},
+	}
+
+	att, err := NewFromDocument(&doc)
+	require.NoError(t, err)
+	require.Equal(t, vex.TypeURI, att.PredicateType)
+	require.Len(t, att.Subject, 2)
+	require.Equal(t, "47fed8868b46b060efb8699dc40e981a0c785650223e03602d8c4493fc75b68c", att.Subject[0].Digest["sha256"])
+	require.Equal(t, "https://example.com/bin/tool", att.Subject[1].Name)
+	require.Len(t, att.Subject[1].Digest, 3)
+
+	doc.Statements = doc.Statements[:1]
+	doc.Statements[0].Products = doc.Statements[0].Products[2:]
+	_, err = NewFromDocument(&doc)
+	require.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	att := New()
+	att.Predicate.Author = "Chainguard"
+	require.NoError(t, att.AddSubjects([]intoto.Subject{{Name: "image", Digest: map[string]string{"sha256": "1234"}}}))
+
+	var b bytes.Buffer
+	require.NoError(t, att.ToJSON(&b))
+
+	// Plain in-toto statement
+	doc, err := ExtractVEX(b.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "Chainguard", doc.Author)
+
+	// DSSE envelope
+	env, err := json.Marshal(map[string]any{
+		"payloadType": PayloadType,
+		"payload":     base64.StdEncoding.EncodeToString(b.Bytes()),
+		"signatures":  []any{},
+	})
+	require.NoError(t, err)
+	att2, err := Parse(env)
+	require.NoError(t, err)
+	require.Equal(t, "image", att2.Subject[0].Name)
+	require.Equal(t, "Chainguard", att2.Predicate.Author)
+
+	// Wrong predicate type
+	att.PredicateType = "https://slsa.dev/provenance/v1"
+	b.Reset()
+	require.NoError(t, att.ToJSON(&b))
+	_, err = Parse(b.Bytes())
+	require.Error(t, err)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cel
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// Program is a compiled expression that evaluates to a boolean.
+type Program struct {
+	expr    string
+	program cel.Program
+}
+
+// NewEnv returns the CEL environment declaring the statement and document
+// variables.
+func NewEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable(StatementVariable, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(DocumentVariable, cel.MapType(cel.StringType, cel.DynType)),
+	)
+}
+
+// Compile parses and checks an expression. The expression must evaluate to
+// a boolean.
+func Compile(expr string) (*Program, error) {
+	env, err := NewEnv()
+	if err != nil {
+		return nil, fmt.Errorf("creating CEL environment: %w", err)
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("compiling expression: %w", iss.Err())
+	}
+	if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression must evaluate to a boolean, not %s", ast.OutputType())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("building program: %w", err)
+	}
+	return &Program{expr: expr, program: prg}, nil
+}
+
+// Eval evaluates the expression against a statement of a document.
+func (p *Program) Eval(doc *vex.VEX, stmt *vex.Statement) (bool, error) {
+	d, err := documentVariable(doc)
+	if err != nil {
+		return false, err
+	}
+	return p.eval(d, stmt)
+}
+
+// eval evaluates the expression against a statement of the document
+// converted to d.
+func (p *Program) eval(d map[string]any, stmt *vex.Statement) (bool, error) {
+	vars, err := statementVariables(d, stmt)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := p.program.Eval(vars)
+	if err != nil {
+		return false, fmt.Errorf("evaluating %q: %w", p.expr, err)
+	}
+	b, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q returned %T, not a boolean", p.expr, out.Value())
+	}
+	return b, nil
+}
+
+// Filter returns the statements of the document for which the expression
+// evaluates to true.
+func (p *Program) Filter(doc *vex.VEX) ([]vex.Statement, error) {
+	d, err := documentVariable(doc)
+	if err != nil {
+		return nil, err
+	}
+	ret := []vex.Statement{}
+	for i := range doc.Statements {
+		ok, err := p.eval(d, &doc.Statements[i])
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i, err)
+		}
+		if ok {
+			ret = append(ret, doc.Statements[i])
+		}
+	}
+	return ret, nil
+}
+
+// Rule returns a policy rule rejecting the statements for which the
+// expression evaluates to false, so expressions can gate the statements
+// accepted by a vex.Policy:
+//
+//	policy.Rules = append(policy.Rules, program.Rule())
+func (p *Program) Rule() vex.PolicyRule {
+	return func(doc *vex.VEX, stmt *vex.Statement) error {
+		ok, err := p.Eval(doc, stmt)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("expression %q is false", p.expr)
+		}
+		return nil
+	}
+}
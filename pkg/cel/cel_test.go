@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestCompile(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{"boolean", "statement.status == 'affected'", false},
+		{"dynamic", "statement.status", false},
+		{"syntax error", "statement.status ==", true},
+		{"undeclared variable", "stmt.status == 'affected'", true},
+		{"not a boolean", "'affected'", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Compile(tc.expr)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := vex.New()
+	doc.Author = "Example Inc."
+	doc.Timestamp = &ts
+	doc.Statements = []vex.Statement{
+		{
+			Vulnerability:   vex.Vulnerability{Name: "CVE-2023-1111"},
+			Products:        []vex.Product{{Component: vex.Component{ID: "pkg:oci/app"}}},
+			Status:          vex.StatusAffected,
+			ActionStatement: "Upgrade to 1.2",
+		},
+		{
+			Vulnerability: vex.Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []vex.Product{{Component: vex.Component{ID: "pkg:oci/app"}}},
+			Status:        vex.StatusNotAffected,
+			Justification: vex.VulnerableCodeNotPresent,
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		expr    string
+		want    []bool
+		wantErr bool
+	}{
+		{
+			name: "status",
+			expr: "statement.status == 'affected'",
+			want: []bool{true, false},
+		},
+		{
+			name: "has",
+			expr: "has(statement.action_statement)",
+			want: []bool{true, false},
+		},
+		{
+			name: "document",
+			expr: "document.author == 'Example Inc.' && statement.vulnerability.name.startsWith('CVE-2023-2')",
+			want: []bool{false, true},
+		},
+		{
+			name: "products",
+			expr: "statement.products.exists(p, p['@id'] == 'pkg:oci/app')",
+			want: []bool{true, true},
+		},
+		{
+			name:    "missing field",
+			expr:    "statement.justification == 'vulnerable_code_not_present'",
+			wantErr: true,
+		},
+		{
+			name:    "not a boolean at runtime",
+			expr:    "statement.status",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := Compile(tc.expr)
+			require.NoError(t, err)
+			for i := range doc.Statements {
+				got, err := p.Eval(&doc, &doc.Statements[i])
+				if tc.wantErr {
+					if err != nil {
+						return
+					}
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.want[i], got, "statement %d", i)
+			}
+			require.False(t, tc.wantErr, "expected an error")
+		})
+	}
+
+	p, err := Compile("statement.status == 'affected'")
+	require.NoError(t, err)
+	stmts, err := p.Filter(&doc)
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	require.Equal(t, vex.VulnerabilityID("CVE-2023-1111"), stmts[0].Vulnerability.Name)
+
+	rule := p.Rule()
+	require.NoError(t, rule(&doc, &doc.Statements[0]))
+	require.ErrorContains(t, rule(&doc, &doc.Statements[1]), "is false")
+
+	p, err = Compile("statement.justification == 'vulnerable_code_not_present'")
+	require.NoError(t, err)
+	_, err = p.Filter(&doc)
+	require.Error(t, err)
+	require.Error(t, p.Rule()(&doc, &doc.Statements[0]))
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cel evaluates CEL expressions against OpenVEX statements so policy
+// engines can consume VEX data without bespoke Go filters:
+//
+//	statement.status == 'affected' && has(statement.action_statement)
+//
+// Expressions see two variables: statement, holding the statement being
+// evaluated, and document, holding the document metadata. Both are maps
+// using the field names of the OpenVEX JSON serialization, so fields that
+// are not set are absent and can be tested with has().
+//
+// Variables is exported so the same data can feed other evaluators.
+package cel
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// StatementVariable is the name of the variable holding the statement
+	StatementVariable = "statement"
+
+	// DocumentVariable is the name of the variable holding the document
+	// metadata
+	DocumentVariable = "document"
+)
+
+// Variables returns the variables an expression is evaluated with for a
+// statement of a document.
+func Variables(doc *vex.VEX, stmt *vex.Statement) (map[string]any, error) {
+	d, err := documentVariable(doc)
+	if err != nil {
+		return nil, err
+	}
+	return statementVariables(d, stmt)
+}
+
+// documentVariable returns the value of the document variable. It is
+// converted once and shared by the statements of the document.
+func documentVariable(doc *vex.VEX) (map[string]any, error) {
+	if doc == nil {
+		return map[string]any{}, nil
+	}
+	d, err := toMap(&doc.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("converting document metadata: %w", err)
+	}
+	return d, nil
+}
+
+// statementVariables returns the variables for a statement of the document
+// converted to d.
+func statementVariables(d map[string]any, stmt *vex.Statement) (map[string]any, error) {
+	s, err := toMap(stmt)
+	if err != nil {
+		return nil, fmt.Errorf("converting statement: %w", err)
+	}
+	return map[string]any{
+		StatementVariable: s,
+		DocumentVariable:  d,
+	}, nil
+}
+
+// toMap converts v to a generic map through its JSON serialization.
+func toMap(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestVariables(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := vex.New()
+	doc.Author = "Example Inc."
+	doc.Timestamp = &ts
+	stmt := vex.Statement{
+		Vulnerability: vex.Vulnerability{Name: "CVE-2023-1111"},
+		Products:      []vex.Product{{Component: vex.Component{ID: "pkg:oci/app"}}},
+		Status:        vex.StatusAffected,
+	}
+
+	vars, err := Variables(&doc, &stmt)
+	require.NoError(t, err)
+
+	s := vars[StatementVariable].(map[string]any)
+	require.Equal(t, "affected", s["status"])
+	require.Equal(t, "CVE-2023-1111", s["vulnerability"].(map[string]any)["name"])
+	_, ok := s["action_statement"]
+	require.False(t, ok, "unset fields are absent")
+
+	d := vars[DocumentVariable].(map[string]any)
+	require.Equal(t, "Example Inc.", d["author"])
+	_, ok = d["statements"]
+	require.False(t, ok)
+
+	vars, err = Variables(nil, &stmt)
+	require.NoError(t, err)
+	require.Empty(t, vars[DocumentVariable])
+}
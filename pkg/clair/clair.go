@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clair
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// VulnerabilityReport lists the packages found in a manifest and the
+// vulnerabilities affecting them. All the maps are keyed by ID.
+type VulnerabilityReport struct {
+	ManifestHash           string                   `json:"manifest_hash"`
+	Packages               map[string]Package       `json:"packages"`
+	Distributions          map[string]Distribution  `json:"distributions"`
+	Repositories           map[string]Repository    `json:"repository"`
+	Environments           map[string][]Environment `json:"environments"`
+	Vulnerabilities        map[string]Vulnerability `json:"vulnerabilities"`
+	PackageVulnerabilities map[string][]string      `json:"package_vulnerabilities"`
+}
+
+// Package is a package found in the manifest.
+type Package struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	Version           string   `json:"version"`
+	Kind              string   `json:"kind,omitempty"`
+	Source            *Package `json:"source,omitempty"`
+	Module            string   `json:"module,omitempty"`
+	Arch              string   `json:"arch,omitempty"`
+	CPE               string   `json:"cpe,omitempty"`
+	NormalizedVersion any      `json:"normalized_version,omitempty"`
+}
+
+// Distribution is the operating system a package was installed from.
+type Distribution struct {
+	ID         string `json:"id"`
+	DID        string `json:"did"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	VersionID  string `json:"version_id"`
+	PrettyName string `json:"pretty_name"`
+	CPE        string `json:"cpe,omitempty"`
+}
+
+// Repository is the package repository a package was installed from.
+type Repository struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Key  string `json:"key,omitempty"`
+	URI  string `json:"uri,omitempty"`
+	CPE  string `json:"cpe,omitempty"`
+}
+
+// Environment describes where a package was found in the manifest.
+type Environment struct {
+	PackageDB      string   `json:"package_db"`
+	IntroducedIn   string   `json:"introduced_in"`
+	DistributionID string   `json:"distribution_id"`
+	RepositoryIDs  []string `json:"repository_ids"`
+}
+
+// Vulnerability is a vulnerability affecting packages of the manifest.
+type Vulnerability struct {
+	ID                 string    `json:"id"`
+	Updater            string    `json:"updater"`
+	Name               string    `json:"name"`
+	Description        string    `json:"description,omitempty"`
+	Issued             time.Time `json:"issued"`
+	Links              string    `json:"links,omitempty"`
+	Severity           string    `json:"severity,omitempty"`
+	NormalizedSeverity string    `json:"normalized_severity,omitempty"`
+	FixedInVersion     string    `json:"fixed_in_version,omitempty"`
+}
+
+// Open reads and parses a vulnerability report from the given file path.
+func Open(path string) (*VulnerabilityReport, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("clair: failed to open report: %w", err)
+	}
+	defer fh.Close()
+
+	return Decode(fh)
+}
+
+// Decode reads a vulnerability report from r.
+func Decode(r io.Reader) (*VulnerabilityReport, error) {
+	report := &VulnerabilityReport{}
+	if err := json.NewDecoder(r).Decode(report); err != nil {
+		return nil, fmt.Errorf("clair: failed to decode report: %w", err)
+	}
+	return report, nil
+}
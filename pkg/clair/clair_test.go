@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clair
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	report, err := Open("testdata/clair-report.json")
+	require.NoError(t, err)
+	require.Equal(t, "sha256:fc84b5febd328eccaa913807716887b3eb5ed08bc22cc6933a9ebf82766725e3", report.ManifestHash)
+
+	require.Len(t, report.Packages, 4)
+	require.Equal(t, "openssl", report.Packages["1"].Name)
+	require.Equal(t, "x86_64", report.Packages["1"].Arch)
+	require.Equal(t, "alpine", report.Distributions["1"].DID)
+	require.Equal(t, "https://pypi.org/simple", report.Repositories["1"].URI)
+	require.Equal(t, []string{"1"}, report.Environments["3"][0].RepositoryIDs)
+
+	vuln := report.Vulnerabilities["10"]
+	require.Equal(t, "CVE-2023-1111", vuln.Name)
+	require.Equal(t, "3.0.9-r0", vuln.FixedInVersion)
+	require.Equal(t, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), vuln.Issued)
+	require.Equal(t, []string{"20"}, report.PackageVulnerabilities["3"])
+
+	_, err = Open("testdata/missing.json")
+	require.Error(t, err)
+	_, err = Decode(bytes.NewReader([]byte(`{"packages":[]}`)))
+	require.Error(t, err)
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	report, err := Open("testdata/clair-report.json")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	decoded, err := Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, report, decoded)
+}
@@ -0,0 +1,10 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package clair provides a minimal library to read the vulnerability
+// reports produced by Clair v4 for container image manifests.
+//
+// https://quay.github.io/clair/reference/api.html
+package clair
@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package conformance ships a corpus of valid and invalid OpenVEX documents
+// and a harness to run parser and serializer implementations against it.
+// Integrators can use it to verify their handling of OpenVEX documents
+// matches the reference library, from their tests with the conformancetest
+// package.
+package conformance
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+//go:embed corpus
+var corpus embed.FS
+
+const (
+	validDir   = "corpus/valid"
+	invalidDir = "corpus/invalid"
+)
+
+// Case is a golden document from the conformance corpus.
+type Case struct {
+	// Name is the name of the case, derived from its file name
+	Name string
+
+	// Valid indicates if the document conforms to the OpenVEX spec
+	Valid bool
+
+	// Data is the raw document
+	Data []byte
+}
+
+// Implementation is the parser and serializer under test.
+type Implementation struct {
+	// Parse must decode valid documents and return an error for invalid ones.
+	Parse func([]byte) (*vex.VEX, error)
+
+	// Serialize encodes a document. It is optional, when defined, valid
+	// documents are round-tripped through it.
+	Serialize func(*vex.VEX) ([]byte, error)
+}
+
+// Result captures the outcome of running a case.
+type Result struct {
+	Case Case
+	Err  error
+}
+
+// Passed returns true if the implementation handled the case correctly.
+func (r *Result) Passed() bool {
+	return r.Err == nil
+}
+
+// Reference returns the reference implementation: documents are checked
+// against the OpenVEX JSON schema and parsed with vex.Parse, documents are
+// serialized with (*VEX).ToJSON.
+func Reference() Implementation {
+	return Implementation{
+		Parse: func(data []byte) (*vex.VEX, error) {
+			if err := vex.Validate(data); err != nil {
+				return nil, err
+			}
+			return vex.Parse(data)
+		},
+		Serialize: func(doc *vex.VEX) ([]byte, error) {
+			var b bytes.Buffer
+			if err := doc.ToJSON(&b); err != nil {
+				return nil, err
+			}
+			return b.Bytes(), nil
+		},
+	}
+}
+
+// Corpus returns all the cases in the conformance corpus sorted by name.
+func Corpus() ([]Case, error) {
+	cases := []Case{}
+	for _, dir := range []string{validDir, invalidDir} {
+		entries, err := fs.ReadDir(corpus, dir)
+		if err != nil {
+			return nil, fmt.Errorf("reading corpus: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() || path.Ext(e.Name()) != ".json" {
+				continue
+			}
+			data, err := fs.ReadFile(corpus, path.Join(dir, e.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("reading corpus file: %w", err)
+			}
+			cases = append(cases, Case{
+				Name:  path.Join(path.Base(dir), e.Name()),
+				Valid: dir == validDir,
+				Data:  data,
+			})
+		}
+	}
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+	return cases, nil
+}
+
+// Run runs the implementation against every case in the corpus and returns
+// the results.
+func Run(impl Implementation) ([]Result, error) {
+	cases, err := Corpus()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, 0, len(cases))
+	for _, c := range cases {
+		results = append(results, Result{Case: c, Err: RunCase(impl, c)})
+	}
+	return results, nil
+}
+
+// RunCase runs a single case and returns an error describing the failure if
+// the implementation did not handle it correctly.
+func RunCase(impl Implementation, c Case) error { //nolint:gocritic // cases are small
+	doc, err := impl.Parse(c.Data)
+	if !c.Valid {
+		if err == nil {
+			return fmt.Errorf("%s: invalid document was accepted", c.Name)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: valid document was rejected: %w", c.Name, err)
+	}
+	if doc == nil {
+		return fmt.Errorf("%s: parser returned no document", c.Name)
+	}
+	if impl.Serialize == nil {
+		return nil
+	}
+
+	data, err := impl.Serialize(doc)
+	if err != nil {
+		return fmt.Errorf("%s: serializing document: %w", c.Name, err)
+	}
+	if err := vex.Validate(data); err != nil {
+		return fmt.Errorf("%s: serialized document is not valid: %w", c.Name, err)
+	}
+	doc2, err := impl.Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: parsing serialized document: %w", c.Name, err)
+	}
+
+	// Compare both documents in their encoded form
+	before, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("%s: marshaling document: %w", c.Name, err)
+	}
+	after, err := json.Marshal(doc2)
+	if err != nil {
+		return fmt.Errorf("%s: marshaling document: %w", c.Name, err)
+	}
+	if !bytes.Equal(before, after) {
+		return fmt.Errorf("%s: document changed after a serialization round trip", c.Name)
+	}
+	return nil
+}
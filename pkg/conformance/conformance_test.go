@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestReference(t *testing.T) {
+	results, err := Run(Reference())
+	require.NoError(t, err)
+	for i := range results {
+		require.NoError(t, results[i].Err)
+	}
+}
+
+func TestLenientParserFails(t *testing.T) {
+	// vex.Parse alone does not check the spec requirements so it
+	// must fail some of the invalid cases.
+	results, err := Run(Implementation{Parse: vex.Parse})
+	require.NoError(t, err)
+
+	failed := map[string]struct{}{}
+	for i := range results {
+		if results[i].Case.Valid {
+			require.True(t, results[i].Passed(), results[i].Case.Name)
+			continue
+		}
+		if !results[i].Passed() {
+			failed[results[i].Case.Name] = struct{}{}
+		}
+	}
+	require.Contains(t, failed, "invalid/invalid-status.json")
+	require.NotContains(t, failed, "invalid/truncated.json")
+}
+
+func TestCorpus(t *testing.T) {
+	cases, err := Corpus()
+	require.NoError(t, err)
+	valid := 0
+	for _, c := range cases {
+		if c.Valid {
+			valid++
+		}
+		require.NotEmpty(t, c.Data)
+	}
+	require.Equal(t, 3, valid)
+	require.Len(t, cases, 15)
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package conformancetest runs the conformance corpus from Go tests. It is
+// kept apart from the conformance package so that only test binaries link
+// the testing package.
+package conformancetest
+
+import (
+	"testing"
+
+	"github.com/openvex/go-vex/pkg/conformance"
+)
+
+// RunTests runs the corpus as subtests of t, one for each case.
+func RunTests(t *testing.T, impl conformance.Implementation) {
+	t.Helper()
+	cases, err := conformance.Corpus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			if err := conformance.RunCase(impl, c); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
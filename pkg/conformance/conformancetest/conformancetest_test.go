@@ -0,0 +1,16 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package conformancetest
+
+import (
+	"testing"
+
+	"github.com/openvex/go-vex/pkg/conformance"
+)
+
+func TestReference(t *testing.T) {
+	RunTests(t, conformance.Reference())
+}
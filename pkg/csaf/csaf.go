@@ -3,6 +3,7 @@ package csaf
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"time"
 )
@@ -59,6 +60,7 @@ type Reference struct {
 // https://docs.oasis-open.org/csaf/csaf/v2.0/os/csaf-v2.0-os.html#32112-document-property---tracking
 type Tracking struct {
 	ID                 string    `json:"id"`
+	Version            string    `json:"version"`
 	CurrentReleaseDate time.Time `json:"current_release_date"`
 	InitialReleaseDate time.Time `json:"initial_release_date"`
 }
@@ -113,6 +115,11 @@ type Vulnerability struct {
 	// https://docs.oasis-open.org/csaf/csaf/v2.0/os/csaf-v2.0-os.html#32310-vulnerabilities-property---references
 	References []Reference `json:"references"`
 
+	// Notes holds notes associated with this vulnerability item.
+	//
+	// https://docs.oasis-open.org/csaf/csaf/v2.0/os/csaf-v2.0-os.html#32311-vulnerabilities-property---notes
+	Notes []Note `json:"notes"`
+
 	ReleaseDate time.Time `json:"release_date"`
 }
 
@@ -151,6 +158,7 @@ type RemediationData struct {
 	GroupIDs     []string    `json:"group_ids"`
 	ProductIDs   []string    `json:"product_ids"`
 	Restart      RestartData `json:"restart_required"`
+	URL          string      `json:"url"`
 }
 
 // Remediation instructions for restart of affected software.
@@ -181,6 +189,22 @@ type ProductBranch struct {
 	Branches      []ProductBranch `json:"branches"`
 	Product       Product         `json:"product,omitempty"`
 	Relationships []Relationship  `json:"relationships"`
+
+	// FullProductNames and ProductGroups are only defined at the root of
+	// the product tree.
+	//
+	// https://docs.oasis-open.org/csaf/csaf/v2.0/os/csaf-v2.0-os.html#3222-product-tree-property---full-product-names
+	FullProductNames []Product      `json:"full_product_names"`
+	ProductGroups    []ProductGroup `json:"product_groups"`
+}
+
+// ProductGroup defines a logical group of products referenced by its ID.
+//
+// https://docs.oasis-open.org/csaf/csaf/v2.0/os/csaf-v2.0-os.html#3223-product-tree-property---product-groups
+type ProductGroup struct {
+	ID         string   `json:"group_id"`
+	ProductIDs []string `json:"product_ids"`
+	Summary    string   `json:"summary"`
 }
 
 // Relationship establishes a link between two existing full_product_name_t elements, allowing
@@ -212,8 +236,13 @@ func Open(path string) (*CSAF, error) {
 	}
 	defer fh.Close()
 
+	return Decode(fh)
+}
+
+// Decode reads a CSAF document from r.
+func Decode(r io.Reader) (*CSAF, error) {
 	csafDoc := &CSAF{}
-	err = json.NewDecoder(fh).Decode(csafDoc)
+	err := json.NewDecoder(r).Decode(csafDoc)
 	if err != nil {
 		return nil, fmt.Errorf("csaf: failed to decode document: %w", err)
 	}
@@ -347,3 +376,52 @@ func (csafDoc *CSAF) ListProducts() ProductList {
 	}
 	return prods
 }
+
+// ProductsByID returns all the products defined in the product tree indexed
+// by their product ID. It includes products in branches, the full product
+// names at the root of the tree and the products defined by relationships.
+func (csafDoc *CSAF) ProductsByID() map[string]Product {
+	index := map[string]Product{}
+	var walk func(*ProductBranch)
+	walk = func(branch *ProductBranch) {
+		if branch.Product.ID != "" {
+			index[branch.Product.ID] = branch.Product
+		}
+		for i := range branch.Branches {
+			walk(&branch.Branches[i])
+		}
+	}
+	walk(&csafDoc.ProductTree)
+
+	for _, p := range csafDoc.ProductTree.FullProductNames {
+		index[p.ID] = p
+	}
+	for _, r := range csafDoc.ProductTree.Relationships {
+		if r.FullProductName.ID != "" {
+			index[r.FullProductName.ID] = r.FullProductName
+		}
+	}
+	return index
+}
+
+// RelationshipsByID returns the relationships in the product tree indexed by
+// the product ID of the full product name they define.
+func (csafDoc *CSAF) RelationshipsByID() map[string]Relationship {
+	index := map[string]Relationship{}
+	for _, r := range csafDoc.ProductTree.Relationships {
+		if r.FullProductName.ID != "" {
+			index[r.FullProductName.ID] = r
+		}
+	}
+	return index
+}
+
+// GroupsByID returns the product IDs of each product group in the tree
+// indexed by the group ID.
+func (csafDoc *CSAF) GroupsByID() map[string][]string {
+	index := map[string][]string{}
+	for _, g := range csafDoc.ProductTree.ProductGroups {
+		index[g.ID] = g.ProductIDs
+	}
+	return index
+}
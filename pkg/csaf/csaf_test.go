@@ -75,3 +75,19 @@ func TestListProducts(t *testing.T) {
 	require.NotNil(t, allProds)
 	require.Len(t, allProds, 3)
 }
+
+func TestProductIndexes(t *testing.T) {
+	doc, err := Open("testdata/csaf.json")
+	require.NoError(t, err)
+
+	products := doc.ProductsByID()
+	require.Contains(t, products, "CSAFPID-0001")
+	require.Equal(t, "pkg:maven/@1.3.4", products["CSAFPID-0001"].IdentificationHelper["purl"])
+
+	rels := doc.RelationshipsByID()
+	require.Len(t, rels, 1)
+	for id, r := range rels {
+		require.Equal(t, id, r.FullProductName.ID)
+		require.Contains(t, products, id)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cyclonedx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// BOMFormat is the value of the bomFormat field of CycloneDX documents
+const BOMFormat = "CycloneDX"
+
+// Document is a CycloneDX bill of materials.
+//
+// https://cyclonedx.org/docs/1.5/json/
+type Document struct {
+	BOMFormat    string      `json:"bomFormat"`
+	SpecVersion  string      `json:"specVersion"`
+	SerialNumber string      `json:"serialNumber"`
+	Version      int         `json:"version"`
+	Metadata     Metadata    `json:"metadata"`
+	Components   []Component `json:"components"`
+
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// Metadata describes the document and the component it is about.
+//
+// https://cyclonedx.org/docs/1.5/json/#metadata
+type Metadata struct {
+	Timestamp *time.Time              `json:"timestamp"`
+	Authors   []OrganizationalContact `json:"authors"`
+	Supplier  *OrganizationalEntity   `json:"supplier"`
+	Component *Component              `json:"component"`
+}
+
+// OrganizationalContact is a person in an organization.
+//
+// https://cyclonedx.org/docs/1.5/json/#metadata_authors
+type OrganizationalContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// OrganizationalEntity is an organization.
+//
+// https://cyclonedx.org/docs/1.5/json/#metadata_supplier
+type OrganizationalEntity struct {
+	Name string   `json:"name"`
+	URL  []string `json:"url"`
+}
+
+// Component is a piece of software listed in the document. Components may
+// nest other components.
+//
+// https://cyclonedx.org/docs/1.5/json/#components
+type Component struct {
+	BOMRef     string      `json:"bom-ref"`
+	Type       string      `json:"type"`
+	Name       string      `json:"name"`
+	Version    string      `json:"version"`
+	PURL       string      `json:"purl"`
+	CPE        string      `json:"cpe"`
+	Hashes     []Hash      `json:"hashes"`
+	Components []Component `json:"components"`
+}
+
+// Hash is a digest of a component.
+//
+// https://cyclonedx.org/docs/1.5/json/#components_items_hashes
+type Hash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+// Vulnerability is a vulnerability listed in the document. Documents used as
+// VEX record the analysis of the vulnerability in the affected components.
+//
+// https://cyclonedx.org/docs/1.5/json/#vulnerabilities
+type Vulnerability struct {
+	BOMRef         string                   `json:"bom-ref"`
+	ID             string                   `json:"id"`
+	Source         *Source                  `json:"source"`
+	References     []VulnerabilityReference `json:"references"`
+	CWEs           []int                    `json:"cwes"`
+	Description    string                   `json:"description"`
+	Detail         string                   `json:"detail"`
+	Recommendation string                   `json:"recommendation"`
+	Advisories     []Advisory               `json:"advisories"`
+	Created        *time.Time               `json:"created"`
+	Published      *time.Time               `json:"published"`
+	Updated        *time.Time               `json:"updated"`
+	Analysis       *Analysis                `json:"analysis"`
+	Affects        []Affects                `json:"affects"`
+}
+
+// Source is the organization publishing vulnerability data.
+//
+// https://cyclonedx.org/docs/1.5/json/#vulnerabilities_items_source
+type Source struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// VulnerabilityReference points to the same vulnerability in another source,
+// such as a GHSA for a CVE.
+//
+// https://cyclonedx.org/docs/1.5/json/#vulnerabilities_items_references
+type VulnerabilityReference struct {
+	ID     string  `json:"id"`
+	Source *Source `json:"source"`
+}
+
+// Advisory is a link to an advisory about the vulnerability.
+//
+// https://cyclonedx.org/docs/1.5/json/#vulnerabilities_items_advisories
+type Advisory struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Analysis is the impact analysis of a vulnerability, the VEX data.
+//
+// https://cyclonedx.org/docs/1.5/json/#vulnerabilities_items_analysis
+type Analysis struct {
+	State         string     `json:"state"`
+	Justification string     `json:"justification"`
+	Response      []string   `json:"response"`
+	Detail        string     `json:"detail"`
+	FirstIssued   *time.Time `json:"firstIssued"`
+	LastUpdated   *time.Time `json:"lastUpdated"`
+}
+
+// Affects points to a component affected by the vulnerability through its
+// bom-ref or a BOM-Link.
+//
+// https://cyclonedx.org/docs/1.5/json/#vulnerabilities_items_affects
+type Affects struct {
+	Ref string `json:"ref"`
+}
+
+// Open reads and parses a CycloneDX JSON document from the given file path.
+func Open(path string) (*Document, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cyclonedx: failed to open document: %w", err)
+	}
+	defer fh.Close()
+
+	return Decode(fh)
+}
+
+// Decode reads a CycloneDX JSON document from r.
+func Decode(r io.Reader) (*Document, error) {
+	doc := &Document{}
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("cyclonedx: failed to decode document: %w", err)
+	}
+	if doc.BOMFormat != BOMFormat {
+		return nil, fmt.Errorf("cyclonedx: document format is %q, not %q", doc.BOMFormat, BOMFormat)
+	}
+	return doc, nil
+}
+
+// AllComponents returns the components of the document, including the
+// nested ones, in depth first order. The metadata component is not included.
+func (doc *Document) AllComponents() []*Component {
+	ret := []*Component{}
+	var walk func(cs []Component)
+	walk = func(cs []Component) {
+		for i := range cs {
+			ret = append(ret, &cs[i])
+			walk(cs[i].Components)
+		}
+	}
+	walk(doc.Components)
+	return ret
+}
+
+// ComponentByRef returns the component with a bom-ref, looking also in the
+// metadata component and the nested components. It returns nil if there is
+// no component with the reference.
+func (doc *Document) ComponentByRef(ref string) *Component {
+	if ref == "" {
+		return nil
+	}
+	if doc.Metadata.Component != nil && doc.Metadata.Component.BOMRef == ref {
+		return doc.Metadata.Component
+	}
+	for _, c := range doc.AllComponents() {
+		if c.BOMRef == ref {
+			return c
+		}
+	}
+	return nil
+}
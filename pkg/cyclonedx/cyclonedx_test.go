@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cyclonedx
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	doc, err := Open("testdata/sbom.cdx.json")
+	require.NoError(t, err)
+	require.Equal(t, "1.5", doc.SpecVersion)
+	require.Equal(t, "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79", doc.SerialNumber)
+	require.NotNil(t, doc.Metadata.Timestamp)
+
+	components := doc.AllComponents()
+	require.Len(t, components, 3)
+	require.Equal(t, "openssl", components[0].BOMRef)
+	require.Equal(t, "libcrypto", components[1].BOMRef)
+	require.Equal(t, "a1b2c3", components[0].Hashes[0].Content)
+
+	require.Equal(t, "pkg:oci/example-image@sha256%3Aabcdef", doc.ComponentByRef("app").PURL)
+	require.Equal(t, "pkg:apk/wolfi/libcrypto3@3.0.8", doc.ComponentByRef("libcrypto").PURL)
+	require.Nil(t, doc.ComponentByRef("missing"))
+	require.Nil(t, doc.ComponentByRef(""))
+
+	_, err = Open("testdata/missing.cdx.json")
+	require.Error(t, err)
+	_, err = Decode(bytes.NewReader([]byte(`{"bomFormat":"SPDX"}`)))
+	require.Error(t, err)
+}
+
+func TestOpenVulnerabilities(t *testing.T) {
+	doc, err := Open("testdata/vex.cdx.json")
+	require.NoError(t, err)
+	require.Len(t, doc.Metadata.Authors, 1)
+	require.Len(t, doc.Vulnerabilities, 3)
+
+	vuln := doc.Vulnerabilities[0]
+	require.Equal(t, "CVE-2023-0286", vuln.ID)
+	require.Equal(t, "NVD", vuln.Source.Name)
+	require.Equal(t, "GHSA-x4qr-2fvf-3mr5", vuln.References[0].ID)
+	require.Equal(t, []int{843}, vuln.CWEs)
+	require.Equal(t, "not_affected", vuln.Analysis.State)
+	require.Equal(t, "code_not_reachable", vuln.Analysis.Justification)
+	require.NotNil(t, vuln.Analysis.LastUpdated)
+	require.Equal(t, []Affects{{Ref: "openssl"}, {Ref: "app"}}, vuln.Affects)
+
+	require.Equal(t, []string{"update"}, doc.Vulnerabilities[1].Analysis.Response)
+	require.Nil(t, doc.Vulnerabilities[2].Analysis)
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	for _, path := range []string{"testdata/sbom.cdx.json", "testdata/vex.cdx.json"} {
+		doc, err := Open(path)
+		require.NoError(t, err, path)
+
+		data, err := json.Marshal(doc)
+		require.NoError(t, err, path)
+		decoded, err := Decode(bytes.NewReader(data))
+		require.NoError(t, err, path)
+		require.Equal(t, doc, decoded, path)
+	}
+}
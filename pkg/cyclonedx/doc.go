@@ -0,0 +1,10 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package cyclonedx provides a minimal library to read the component
+// inventory and the vulnerability analysis (VEX) of CycloneDX JSON documents.
+//
+// https://cyclonedx.org/docs/1.5/json/
+package cyclonedx
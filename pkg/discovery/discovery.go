@@ -0,0 +1,329 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package discovery locates the OpenVEX documents published for a piece of
+// software, identified by a purl or a repository URL.
+//
+// Publishers advertise their documents at the /.well-known/openvex path of
+// their web host. The path serves either a VEX document or an Index listing
+// the URLs of the documents. When the well known location does not exist,
+// the URL is looked up in the DNS TXT records of the _openvex subdomain
+// (openvex=<url>) and in the Link headers with the openvex relation returned
+// for the repository URL. OCI images identified by a purl are discovered
+// through the attestations attached to them in their registry.
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/openvex/go-vex/pkg/oci"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// WellKnownPath is where publishers serve their documents or index
+	WellKnownPath = "/.well-known/openvex"
+
+	// DNSPrefix is prepended to the host to look up the TXT records
+	DNSPrefix = "_openvex."
+
+	// LinkRelation is the relation of Link headers pointing to the documents
+	LinkRelation = "openvex"
+
+	// maxDocumentSize limits the size of the documents and indexes read
+	maxDocumentSize = 16 << 20
+)
+
+// ErrNotFound is returned when no documents are advertised for the software
+var ErrNotFound = errors.New("no OpenVEX documents advertised")
+
+// purlHosts maps purl types to the host of their repositories
+var purlHosts = map[string]string{
+	packageurl.TypeGithub:    "github.com",
+	packageurl.TypeGitlab:    "gitlab.com",
+	packageurl.TypeBitbucket: "bitbucket.org",
+}
+
+// Index lists the URLs of the documents of a publisher. Relative URLs are
+// resolved against the URL of the index.
+type Index struct {
+	Documents []string `json:"documents"`
+}
+
+// Discoverer finds and fetches the documents advertised for a purl or
+// repository URL.
+type Discoverer struct {
+	// HTTPClient is used to perform requests
+	HTTPClient *http.Client
+
+	// OCI is used to discover the documents attached to OCI images
+	OCI *oci.Client
+
+	// LookupTXT resolves DNS TXT records. It defaults to the system
+	// resolver.
+	LookupTXT func(ctx context.Context, name string) ([]string, error)
+
+	// PlainHTTP probes the hosts of purls over HTTP instead of HTTPS
+	PlainHTTP bool
+}
+
+// New returns a discoverer using the default HTTP client and resolver.
+func New() *Discoverer {
+	return &Discoverer{
+		HTTPClient: http.DefaultClient,
+		OCI:        oci.NewClient(),
+		LookupTXT:  net.DefaultResolver.LookupTXT,
+	}
+}
+
+// Discover returns the documents advertised for the software identified by
+// locator, a purl or a repository URL. It returns ErrNotFound if none of
+// the discovery mechanisms finds documents.
+func (d *Discoverer) Discover(ctx context.Context, locator string) ([]*vex.VEX, error) {
+	if strings.HasPrefix(locator, "pkg:") {
+		p, err := packageurl.FromString(locator)
+		if err != nil {
+			return nil, fmt.Errorf("parsing purl: %w", err)
+		}
+		if p.Type == packageurl.TypeOCI {
+			return d.discoverOCI(ctx, &p)
+		}
+		base, err := d.purlBase(&p)
+		if err != nil {
+			return nil, err
+		}
+		return d.discoverHost(ctx, base, nil)
+	}
+
+	u, err := url.Parse(locator)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("locator %q is not a purl or URL", locator)
+	}
+	return d.discoverHost(ctx, &url.URL{Scheme: u.Scheme, Host: u.Host}, u)
+}
+
+// purlBase returns the URL of the host publishing documents for a purl,
+// read from its repository_url qualifier or its namespace.
+func (d *Discoverer) purlBase(p *packageurl.PackageURL) (*url.URL, error) {
+	scheme := "https"
+	if d.PlainHTTP {
+		scheme = "http"
+	}
+	if repo := p.Qualifiers.Map()["repository_url"]; repo != "" {
+		if !strings.Contains(repo, "://") {
+			repo = scheme + "://" + repo
+		}
+		u, err := url.Parse(repo)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid repository_url %q", repo)
+		}
+		return &url.URL{Scheme: u.Scheme, Host: u.Host}, nil
+	}
+	if host, ok := purlHosts[p.Type]; ok {
+		return &url.URL{Scheme: scheme, Host: host}, nil
+	}
+	// Go modules are named after the host serving them
+	if p.Type == packageurl.TypeGolang && p.Namespace != "" {
+		host, _, _ := strings.Cut(p.Namespace, "/")
+		if strings.Contains(host, ".") {
+			return &url.URL{Scheme: scheme, Host: host}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unable to determine the publisher of %s", ErrNotFound, p.ToString())
+}
+
+// discoverOCI returns the documents attached to the image of an oci purl,
+// both in attestations and as plain artifacts. The purl must have a digest
+// version and a repository_url qualifier.
+func (d *Discoverer) discoverOCI(ctx context.Context, p *packageurl.PackageURL) ([]*vex.VEX, error) {
+	repo := p.Qualifiers.Map()["repository_url"]
+	if repo == "" || p.Version == "" {
+		return nil, fmt.Errorf("%w: oci purls need a digest and a repository_url", ErrNotFound)
+	}
+	ref, err := oci.ParseReference(repo + "@" + p.Version)
+	if err != nil {
+		return nil, fmt.Errorf("parsing image reference: %w", err)
+	}
+	docs, err := d.OCI.DiscoverDocuments(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := d.OCI.PullDocuments(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	docs = append(docs, plain...)
+	if len(docs) == 0 {
+		return nil, ErrNotFound
+	}
+	return docs, nil
+}
+
+// discoverHost tries the well known location of the host, then its DNS
+// records and finally the Link headers of the repository URL, if any.
+func (d *Discoverer) discoverHost(ctx context.Context, base, repo *url.URL) ([]*vex.VEX, error) {
+	wellKnown := base.JoinPath(WellKnownPath)
+	docs, err := d.fetch(ctx, wellKnown)
+	if err == nil || !errors.Is(err, ErrNotFound) {
+		return docs, err
+	}
+
+	if d.LookupTXT != nil {
+		records, err := d.LookupTXT(ctx, DNSPrefix+base.Hostname())
+		var dnsErr *net.DNSError
+		if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+			return nil, fmt.Errorf("looking up DNS records: %w", err)
+		}
+		for _, r := range records {
+			loc, ok := strings.CutPrefix(strings.TrimSpace(r), LinkRelation+"=")
+			if !ok {
+				continue
+			}
+			u, err := url.Parse(loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid URL in DNS record: %w", err)
+			}
+			return d.fetch(ctx, u)
+		}
+	}
+
+	if repo != nil {
+		if u, err := d.linkHeader(ctx, repo); err != nil {
+			return nil, err
+		} else if u != nil {
+			return d.fetch(ctx, u)
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// linkHeader returns the URL of the Link header with the openvex relation
+// returned for u, or nil if there is none.
+func (d *Discoverer) linkHeader(ctx context.Context, u *url.URL) (*url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	res, err := d.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", u, err)
+	}
+	res.Body.Close()
+
+	for _, header := range res.Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if k != "rel" || !hasRelation(strings.Trim(v, `"`), LinkRelation) {
+					continue
+				}
+				return u.Parse(target[1 : len(target)-1])
+			}
+		}
+	}
+	return nil, nil
+}
+
+// hasRelation returns true if rel, a space separated list, includes name.
+func hasRelation(rel, name string) bool {
+	for _, r := range strings.Fields(rel) {
+		if strings.EqualFold(r, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch returns the document at u or, if u serves an index, the documents
+// it lists.
+func (d *Discoverer) fetch(ctx context.Context, u *url.URL) ([]*vex.VEX, error) {
+	data, err := d.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	probe := struct {
+		Documents  []string          `json:"documents"`
+		Statements []json.RawMessage `json:"statements"`
+	}{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", u, err)
+	}
+	if probe.Statements != nil || probe.Documents == nil {
+		doc, err := vex.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", u, err)
+		}
+		return []*vex.VEX{doc}, nil
+	}
+
+	docs := []*vex.VEX{}
+	for _, loc := range probe.Documents {
+		du, err := u.Parse(loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid document URL %q in %s: %w", loc, u, err)
+		}
+		data, err := d.get(ctx, du)
+		if errors.Is(err, ErrNotFound) {
+			// Not ErrNotFound, the index exists but is out of date
+			return nil, fmt.Errorf("document %s listed in %s does not exist", du, u)
+		}
+		if err != nil {
+			return nil, err
+		}
+		doc, err := vex.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", du, err)
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		return nil, ErrNotFound
+	}
+	return docs, nil
+}
+
+// get fetches u. It returns ErrNotFound if the server responds with 404.
+func (d *Discoverer) get(ctx context.Context, u *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := d.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", u, err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("fetching %s: server returned %s", u, res.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", u, err)
+	}
+	if len(data) > maxDocumentSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", u, maxDocumentSize)
+	}
+	return data, nil
+}
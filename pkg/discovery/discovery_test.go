@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/%s",
+  "author": "Example Inc.",
+  "timestamp": "2023-06-01T00:00:00Z",
+  "version": 1,
+  "statements": [
+    {
+      "vulnerability": {"name": "CVE-2023-1111"},
+      "products": [{"@id": "pkg:npm/example@1.0.0"}],
+      "status": "fixed"
+    }
+  ]
+}`
+
+func newTestServer(t *testing.T, routes map[string]string, headers map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+		body, ok := routes[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body)) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func noTXT(context.Context, string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", IsNotFound: true}
+}
+
+func TestDiscoverWellKnown(t *testing.T) {
+	srv := newTestServer(t, map[string]string{
+		WellKnownPath:         `{"documents": ["/vex/1.json", "2.json"]}`,
+		"/vex/1.json":         fmt.Sprintf(testDocument, "1"),
+		"/.well-known/2.json": fmt.Sprintf(testDocument, "2"),
+	}, nil)
+
+	d := &Discoverer{HTTPClient: srv.Client(), LookupTXT: noTXT, PlainHTTP: true}
+	docs, err := d.Discover(context.Background(), srv.URL+"/example/repo")
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "https://example.com/vex/1", docs[0].ID)
+	require.Equal(t, "https://example.com/vex/2", docs[1].ID)
+
+	// purls name the host in their repository_url
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	docs, err = d.Discover(context.Background(), "pkg:npm/example@1.0.0?repository_url="+u.Host)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+}
+
+func TestDiscoverDocument(t *testing.T) {
+	srv := newTestServer(t, map[string]string{WellKnownPath: fmt.Sprintf(testDocument, "1")}, nil)
+	d := &Discoverer{HTTPClient: srv.Client(), LookupTXT: noTXT}
+	docs, err := d.Discover(context.Background(), srv.URL)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+}
+
+func TestDiscoverDNS(t *testing.T) {
+	srv := newTestServer(t, map[string]string{"/vex.json": fmt.Sprintf(testDocument, "1")}, nil)
+	lookups := []string{}
+	d := &Discoverer{
+		HTTPClient: srv.Client(),
+		LookupTXT: func(_ context.Context, name string) ([]string, error) {
+			lookups = append(lookups, name)
+			return []string{"v=spf1 -all", "openvex=" + srv.URL + "/vex.json"}, nil
+		},
+	}
+	docs, err := d.Discover(context.Background(), srv.URL)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, []string{"_openvex.127.0.0.1"}, lookups)
+}
+
+func TestDiscoverLinkHeader(t *testing.T) {
+	srv := newTestServer(t, map[string]string{"/vex.json": fmt.Sprintf(testDocument, "1")}, map[string]string{
+		"Link": `<https://example.com/other>; rel="next", </vex.json>; rel="alternate openvex"`,
+	})
+	d := &Discoverer{HTTPClient: srv.Client(), LookupTXT: noTXT}
+	docs, err := d.Discover(context.Background(), srv.URL+"/example/repo")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+}
+
+func TestDiscoverErrors(t *testing.T) {
+	srv := newTestServer(t, map[string]string{}, nil)
+	d := &Discoverer{HTTPClient: srv.Client(), LookupTXT: noTXT}
+
+	_, err := d.Discover(context.Background(), srv.URL)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = d.Discover(context.Background(), "pkg:npm/example@1.0.0")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = d.Discover(context.Background(), "pkg:oci/image@sha256%3Aabc")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = d.Discover(context.Background(), "not a locator")
+	require.Error(t, err)
+
+	// Stale index
+	srv = newTestServer(t, map[string]string{WellKnownPath: `{"documents": ["/missing.json"]}`}, nil)
+	d.HTTPClient = srv.Client()
+	_, err = d.Discover(context.Background(), srv.URL)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNotFound)
+}
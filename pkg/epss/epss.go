@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package epss annotates VEX documents with the Exploit Prediction Scoring
+// System scores published by FIRST, so statements can be prioritized by the
+// likelihood of their vulnerabilities being exploited:
+//
+//	if err := epss.NewClient().Annotate(ctx, doc); err != nil {
+//		return err
+//	}
+//	urgent := doc.StatementsAboveEPSS(0.5)
+package epss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// DefaultURL is the endpoint of the FIRST EPSS API
+	DefaultURL = "https://api.first.org/data/v1/epss"
+
+	// batchSize is the number of CVEs looked up in each request
+	batchSize = 100
+
+	// maxResponseSize limits the size of the responses read
+	maxResponseSize = 8 << 20
+)
+
+// Client fetches scores from the EPSS API.
+type Client struct {
+	// URL is the endpoint of the API
+	URL string
+
+	// HTTPClient is used to perform requests
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client of the FIRST EPSS API.
+func NewClient() *Client {
+	return &Client{URL: DefaultURL, HTTPClient: http.DefaultClient}
+}
+
+// Scores returns the scores of the CVEs, keyed by CVE. CVEs without a score
+// are not in the map.
+func (c *Client) Scores(ctx context.Context, cves []string) (map[string]vex.EPSSScore, error) {
+	scores := map[string]vex.EPSSScore{}
+	for start := 0; start < len(cves); start += batchSize {
+		batch := cves[start:min(start+batchSize, len(cves))]
+		if err := c.fetch(ctx, batch, scores); err != nil {
+			return nil, err
+		}
+	}
+	return scores, nil
+}
+
+// Annotate sets the EPSS score of the vulnerabilities in the document whose
+// name or aliases are CVEs. Scores already set are replaced.
+func (c *Client) Annotate(ctx context.Context, doc *vex.VEX) error {
+	cves := []string{}
+	seen := map[string]bool{}
+	for i := range doc.Statements {
+		if id := doc.Statements[i].Vulnerability.CVE(); id != "" && !seen[id] {
+			seen[id] = true
+			cves = append(cves, id)
+		}
+	}
+	scores, err := c.Scores(ctx, cves)
+	if err != nil {
+		return fmt.Errorf("annotating EPSS scores: %w", err)
+	}
+	for i := range doc.Statements {
+		v := &doc.Statements[i].Vulnerability
+		if score, ok := scores[v.CVE()]; ok {
+			v.EPSS = &score
+		}
+	}
+	return nil
+}
+
+// response is the EPSS API response. Numbers are sent as strings.
+type response struct {
+	Data []struct {
+		CVE        string `json:"cve"`
+		EPSS       string `json:"epss"`
+		Percentile string `json:"percentile"`
+		Date       string `json:"date"`
+	} `json:"data"`
+}
+
+func (c *Client) fetch(ctx context.Context, cves []string, scores map[string]vex.EPSSScore) error {
+	u := c.URL
+	if u == "" {
+		u = DefaultURL
+	}
+	q := url.Values{"cve": {strings.Join(cves, ",")}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u+"?"+q.Encode(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching scores: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024)) //nolint:errcheck
+		return fmt.Errorf("epss returned %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	resp := response{}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(&resp); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	for _, d := range resp.Data {
+		score, err := strconv.ParseFloat(d.EPSS, 64)
+		if err != nil {
+			return fmt.Errorf("parsing score of %s: %w", d.CVE, err)
+		}
+		s := vex.EPSSScore{Score: score, Date: d.Date}
+		if d.Percentile != "" {
+			if s.Percentile, err = strconv.ParseFloat(d.Percentile, 64); err != nil {
+				return fmt.Errorf("parsing percentile of %s: %w", d.CVE, err)
+			}
+		}
+		scores[strings.ToUpper(d.CVE)] = s
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package epss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestAnnotate(t *testing.T) {
+	known := map[string]string{
+		"CVE-2021-44228": `{"cve": "CVE-2021-44228", "epss": "0.975560000", "percentile": "0.999970000", "date": "2024-03-01"}`,
+		"CVE-2022-0001":  `{"cve": "CVE-2022-0001", "epss": "0.000430000", "percentile": "0.081000000", "date": "2024-03-01"}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := []string{}
+		for _, cve := range strings.Split(r.URL.Query().Get("cve"), ",") {
+			if d, ok := known[cve]; ok {
+				data = append(data, d)
+			}
+		}
+		fmt.Fprintf(w, `{"status": "OK", "data": [%s]}`, strings.Join(data, ","))
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.URL = srv.URL
+
+	doc := vex.New()
+	doc.Statements = []vex.Statement{
+		{Vulnerability: vex.Vulnerability{Name: "cve-2021-44228"}, Status: vex.StatusAffected},
+		{Vulnerability: vex.Vulnerability{Name: "GHSA-aaaa-bbbb-cccc", Aliases: []vex.VulnerabilityID{"CVE-2022-0001"}}, Status: vex.StatusAffected},
+		{Vulnerability: vex.Vulnerability{Name: "CVE-2000-0001"}, Status: vex.StatusAffected},
+		{Vulnerability: vex.Vulnerability{Name: "GO-2022-0001"}, Status: vex.StatusAffected},
+	}
+	require.NoError(t, c.Annotate(context.Background(), &doc))
+	require.Equal(t, &vex.EPSSScore{Score: 0.97556, Percentile: 0.99997, Date: "2024-03-01"}, doc.Statements[0].Vulnerability.EPSS)
+	require.Equal(t, 0.00043, doc.Statements[1].Vulnerability.EPSS.Score)
+	require.Nil(t, doc.Statements[2].Vulnerability.EPSS)
+	require.Nil(t, doc.Statements[3].Vulnerability.EPSS)
+
+	above := doc.StatementsAboveEPSS(0.5)
+	require.Len(t, above, 1)
+	require.Equal(t, vex.VulnerabilityID("cve-2021-44228"), above[0].Vulnerability.Name)
+}
+
+func TestScoresBatches(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.LessOrEqual(t, len(strings.Split(r.URL.Query().Get("cve"), ",")), batchSize)
+		w.Write([]byte(`{"data": []}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.URL = srv.URL
+	cves := []string{}
+	for i := 0; i < 250; i++ {
+		cves = append(cves, fmt.Sprintf("CVE-2023-%04d", i))
+	}
+	scores, err := c.Scores(context.Background(), cves)
+	require.NoError(t, err)
+	require.Empty(t, scores)
+	require.Equal(t, 3, requests)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// DefaultURL is the endpoint of the GitHub GraphQL API
+const DefaultURL = "https://api.github.com/graphql"
+
+// Client talks to the GitHub GraphQL API.
+type Client struct {
+	// URL is the GraphQL endpoint
+	URL string
+
+	// Token is the token used to authenticate. It needs permission to read
+	// and dismiss the security alerts of the repository.
+	Token string
+
+	// HTTPClient is used to perform requests
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for github.com authenticating with token.
+func NewClient(token string) *Client {
+	return &Client{URL: DefaultURL, Token: token, HTTPClient: http.DefaultClient}
+}
+
+const alertsQuery = `query($owner: String!, $name: String!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    vulnerabilityAlerts(first: 100, after: $cursor, states: [OPEN]) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        id
+        number
+        state
+        vulnerableRequirements
+        securityVulnerability {
+          package { name ecosystem }
+          advisory { ghsaId identifiers { type value } }
+        }
+      }
+    }
+  }
+}`
+
+type alertsResponse struct {
+	Repository *struct {
+		VulnerabilityAlerts struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []struct {
+				ID                     string `json:"id"`
+				Number                 int    `json:"number"`
+				State                  string `json:"state"`
+				VulnerableRequirements string `json:"vulnerableRequirements"`
+				SecurityVulnerability  struct {
+					Package struct {
+						Name      string `json:"name"`
+						Ecosystem string `json:"ecosystem"`
+					} `json:"package"`
+					Advisory struct {
+						GHSAID      string `json:"ghsaId"`
+						Identifiers []struct {
+							Type  string `json:"type"`
+							Value string `json:"value"`
+						} `json:"identifiers"`
+					} `json:"advisory"`
+				} `json:"securityVulnerability"`
+			} `json:"nodes"`
+		} `json:"vulnerabilityAlerts"`
+	} `json:"repository"`
+}
+
+// Alerts returns the open Dependabot alerts of a repository.
+func (c *Client) Alerts(ctx context.Context, owner, repo string) ([]Alert, error) {
+	alerts := []Alert{}
+	var cursor *string
+	for {
+		res := alertsResponse{}
+		req := &GraphQLRequest{
+			Query:     alertsQuery,
+			Variables: map[string]any{"owner": owner, "name": repo, "cursor": cursor},
+		}
+		if err := c.do(ctx, req, &res); err != nil {
+			return nil, fmt.Errorf("listing alerts: %w", err)
+		}
+		if res.Repository == nil {
+			return nil, fmt.Errorf("repository %s/%s not found", owner, repo)
+		}
+
+		page := &res.Repository.VulnerabilityAlerts
+		for i := range page.Nodes {
+			n := &page.Nodes[i]
+			alert := Alert{
+				ID:                     n.ID,
+				Number:                 n.Number,
+				State:                  n.State,
+				Ecosystem:              n.SecurityVulnerability.Package.Ecosystem,
+				Package:                n.SecurityVulnerability.Package.Name,
+				VulnerableRequirements: n.VulnerableRequirements,
+				VulnerabilityIDs:       []string{n.SecurityVulnerability.Advisory.GHSAID},
+			}
+			for _, id := range n.SecurityVulnerability.Advisory.Identifiers {
+				if id.Value != n.SecurityVulnerability.Advisory.GHSAID {
+					alert.VulnerabilityIDs = append(alert.VulnerabilityIDs, id.Value)
+				}
+			}
+			alerts = append(alerts, alert)
+		}
+
+		if !page.PageInfo.HasNextPage {
+			return alerts, nil
+		}
+		next := page.PageInfo.EndCursor
+		cursor = &next
+	}
+}
+
+// Dismiss dismisses an alert.
+func (c *Client) Dismiss(ctx context.Context, d *Dismissal) error {
+	if err := c.do(ctx, d.Mutation(), nil); err != nil {
+		return fmt.Errorf("dismissing alert #%d: %w", d.Alert.Number, err)
+	}
+	return nil
+}
+
+// Sync dismisses the open alerts of a repository that the document marks as
+// not_affected and returns the dismissals performed. Statements about the
+// repository must use RepositoryProduct as their product and the dependency
+// purls as subcomponents.
+func (c *Client) Sync(ctx context.Context, doc *vex.VEX, owner, repo string) ([]Dismissal, error) {
+	alerts, err := c.Alerts(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	dismissed := []Dismissal{}
+	for _, d := range Dismissals(doc, RepositoryProduct(owner, repo), alerts) {
+		if err := c.Dismiss(ctx, &d); err != nil {
+			return dismissed, err
+		}
+		dismissed = append(dismissed, d)
+	}
+	return dismissed, nil
+}
+
+// do performs a GraphQL request and decodes its data into v.
+func (c *Client) do(ctx context.Context, gql *GraphQLRequest, v any) error {
+	data, err := json.Marshal(gql)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+	url := c.URL
+	if url == "" {
+		url = DefaultURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("performing request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024)) //nolint:errcheck
+		return fmt.Errorf("github returned %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	response := struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("decoding github response: %w", err)
+	}
+	if len(response.Errors) > 0 {
+		errs := []error{}
+		for _, e := range response.Errors {
+			errs = append(errs, errors.New(e.Message))
+		}
+		return errors.Join(errs...)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Data, v); err != nil {
+		return fmt.Errorf("decoding github response data: %w", err)
+	}
+	return nil
+}
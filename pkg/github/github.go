@@ -0,0 +1,200 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package github syncs OpenVEX statements to the state of the security
+// alerts of a GitHub repository. Dependabot alerts about vulnerabilities
+// that a document marks as not_affected are dismissed through the GitHub
+// GraphQL API with a reason derived from the statement justification.
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// DismissReason is the reason recorded when dismissing an alert. The values
+// are those of the DismissReason enum in the GitHub GraphQL API.
+type DismissReason string
+
+const (
+	// ReasonFixStarted records that work to fix the alert has started
+	ReasonFixStarted DismissReason = "FIX_STARTED"
+
+	// ReasonInaccurate records that the alert does not apply
+	ReasonInaccurate DismissReason = "INACCURATE"
+
+	// ReasonNoBandwidth records that there is no capacity to address the alert
+	ReasonNoBandwidth DismissReason = "NO_BANDWIDTH"
+
+	// ReasonNotUsed records that the vulnerable code is not used
+	ReasonNotUsed DismissReason = "NOT_USED"
+
+	// ReasonTolerableRisk records that the risk of the alert is acceptable
+	ReasonTolerableRisk DismissReason = "TOLERABLE_RISK"
+)
+
+// justificationReasons maps the VEX justifications to the closest dismissal
+// reason.
+var justificationReasons = map[vex.Justification]DismissReason{
+	vex.ComponentNotPresent:                         ReasonInaccurate,
+	vex.VulnerableCodeNotPresent:                    ReasonInaccurate,
+	vex.VulnerableCodeNotInExecutePath:              ReasonNotUsed,
+	vex.VulnerableCodeCannotBeControlledByAdversary: ReasonTolerableRisk,
+	vex.InlineMitigationsAlreadyExist:               ReasonTolerableRisk,
+}
+
+// ReasonForJustification returns the dismissal reason for a justification.
+// Statements without a known justification are dismissed as inaccurate.
+func ReasonForJustification(j vex.Justification) DismissReason {
+	if r, ok := justificationReasons[j]; ok {
+		return r
+	}
+	return ReasonInaccurate
+}
+
+// ecosystemTypes maps the GitHub security advisory ecosystems to purl types
+var ecosystemTypes = map[string]string{
+	"ACTIONS":  "github",
+	"COMPOSER": "composer",
+	"ERLANG":   "hex",
+	"GO":       "golang",
+	"MAVEN":    "maven",
+	"NPM":      "npm",
+	"NUGET":    "nuget",
+	"PIP":      "pypi",
+	"PUB":      "pub",
+	"RUBYGEMS": "gem",
+	"RUST":     "cargo",
+	"SWIFT":    "swift",
+}
+
+// Alert is a Dependabot alert of a repository.
+type Alert struct {
+	// ID is the GraphQL node ID of the alert
+	ID string
+
+	// Number is the alert number shown in the repository
+	Number int
+
+	// VulnerabilityIDs lists the GHSA ID of the advisory and its aliases
+	VulnerabilityIDs []string
+
+	// Ecosystem and Package identify the vulnerable dependency
+	Ecosystem string
+	Package   string
+
+	// VulnerableRequirements is the version requirement of the dependency
+	// in the manifest, eg "= 4.17.15".
+	VulnerableRequirements string
+
+	// State is the alert state: OPEN, DISMISSED, FIXED or AUTO_DISMISSED
+	State string
+}
+
+// PackageURL returns the purl of the alert dependency. The version is only
+// included when the manifest pins an exact one.
+func (a *Alert) PackageURL() string {
+	t, ok := ecosystemTypes[strings.ToUpper(a.Ecosystem)]
+	if !ok {
+		return ""
+	}
+	name := a.Package
+	if t == "maven" {
+		name = strings.Replace(name, ":", "/", 1)
+	}
+	purl := "pkg:" + t + "/" + name
+	if v, ok := strings.CutPrefix(strings.TrimSpace(a.VulnerableRequirements), "= "); ok {
+		purl += "@" + strings.TrimSpace(v)
+	}
+	return purl
+}
+
+// Dismissal is an alert to be dismissed based on a VEX statement.
+type Dismissal struct {
+	Alert     Alert
+	Reason    DismissReason
+	Statement *vex.Statement
+}
+
+// Comment returns the text explaining the dismissal.
+func (d *Dismissal) Comment() string {
+	parts := []string{"VEX: " + string(d.Statement.Status)}
+	if d.Statement.Justification != "" {
+		parts = append(parts, string(d.Statement.Justification))
+	}
+	if d.Statement.ImpactStatement != "" {
+		parts = append(parts, d.Statement.ImpactStatement)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// GraphQLRequest is a request to the GitHub GraphQL API.
+type GraphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+const dismissMutation = `mutation($id: ID!, $reason: DismissReason!) {
+  dismissRepositoryVulnerabilityAlert(input: {repositoryVulnerabilityAlertId: $id, dismissReason: $reason}) {
+    repositoryVulnerabilityAlert { id state }
+  }
+}`
+
+// Mutation returns the GraphQL mutation dismissing the alert.
+func (d *Dismissal) Mutation() *GraphQLRequest {
+	return &GraphQLRequest{
+		Query: dismissMutation,
+		Variables: map[string]any{
+			"id":     d.Alert.ID,
+			"reason": string(d.Reason),
+		},
+	}
+}
+
+// RepositoryProduct returns the product identifier used for a repository
+// when syncing its alerts.
+func RepositoryProduct(owner, repo string) string {
+	return fmt.Sprintf("pkg:github/%s/%s", owner, repo)
+}
+
+// Dismissals returns the open alerts that the document marks as not_affected
+// in product. The latest statement matching any of the alert vulnerability
+// IDs, the product and the alert dependency decides: when it is not
+// not_affected the alert is left open.
+func Dismissals(doc *vex.VEX, product string, alerts []Alert) []Dismissal {
+	ret := []Dismissal{}
+	for i := range alerts {
+		if alerts[i].State != "" && alerts[i].State != "OPEN" {
+			continue
+		}
+		subcomponents := []string{}
+		if purl := alerts[i].PackageURL(); purl != "" {
+			subcomponents = append(subcomponents, purl)
+		}
+
+		var latest *vex.Statement
+		for _, id := range alerts[i].VulnerabilityIDs {
+			matches := doc.Matches(id, product, subcomponents)
+			if len(matches) == 0 {
+				continue
+			}
+			s := matches[len(matches)-1]
+			if latest == nil || s.EffectiveTime(doc).After(latest.EffectiveTime(doc)) {
+				latest = &s
+			}
+		}
+		if latest == nil || latest.Status != vex.StatusNotAffected {
+			continue
+		}
+		ret = append(ret, Dismissal{
+			Alert:     alerts[i],
+			Reason:    ReasonForJustification(latest.Justification),
+			Statement: latest,
+		})
+	}
+	return ret
+}
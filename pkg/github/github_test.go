@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestAlertPackageURL(t *testing.T) {
+	for _, tc := range []struct {
+		alert Alert
+		purl  string
+	}{
+		{Alert{Ecosystem: "NPM", Package: "lodash", VulnerableRequirements: "= 4.17.15"}, "pkg:npm/lodash@4.17.15"},
+		{Alert{Ecosystem: "NPM", Package: "lodash", VulnerableRequirements: "< 4.17.19"}, "pkg:npm/lodash"},
+		{Alert{Ecosystem: "MAVEN", Package: "org.apache.logging.log4j:log4j-core"}, "pkg:maven/org.apache.logging.log4j/log4j-core"},
+		{Alert{Ecosystem: "UNKNOWN", Package: "x"}, ""},
+	} {
+		require.Equal(t, tc.purl, tc.alert.PackageURL())
+	}
+}
+
+func TestDismissals(t *testing.T) {
+	alerts := []Alert{
+		{ID: "A1", Number: 1, VulnerabilityIDs: []string{"GHSA-p6mc-m468-83gw", "CVE-2020-8203"}, Ecosystem: "NPM", Package: "lodash", VulnerableRequirements: "= 4.17.15", State: "OPEN"},
+		{ID: "A2", Number: 2, VulnerabilityIDs: []string{"GHSA-xxxx-yyyy-zzzz", "CVE-2023-2222"}, Ecosystem: "NPM", Package: "express", State: "OPEN"},
+		{ID: "A3", Number: 3, VulnerabilityIDs: []string{"GHSA-p6mc-m468-83gw"}, Ecosystem: "NPM", Package: "other", State: "OPEN"},
+		{ID: "A4", Number: 4, VulnerabilityIDs: []string{"GHSA-p6mc-m468-83gw"}, Ecosystem: "NPM", Package: "lodash", State: "DISMISSED"},
+	}
+	doc, err := vex.Open("testdata/vex.json")
+	require.NoError(t, err)
+	ds := Dismissals(doc, RepositoryProduct("example", "app"), alerts)
+	require.Len(t, ds, 1)
+	require.Equal(t, "A1", ds[0].Alert.ID)
+	require.Equal(t, ReasonNotUsed, ds[0].Reason)
+	require.Equal(t, "VEX: not_affected: vulnerable_code_not_in_execute_path", ds[0].Comment())
+	require.Equal(t, map[string]any{"id": "A1", "reason": "NOT_USED"}, ds[0].Mutation().Variables)
+}
+
+func TestSync(t *testing.T) {
+	dismissed := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		req := GraphQLRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if strings.HasPrefix(req.Query, "mutation") {
+			dismissed = append(dismissed, req.Variables["id"].(string))
+			w.Write([]byte(`{"data":{}}`)) //nolint:errcheck
+			return
+		}
+		require.Equal(t, "app", req.Variables["name"])
+		if req.Variables["cursor"] == nil {
+			w.Write([]byte(`{"data":{"repository":{"vulnerabilityAlerts":{
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+				"nodes":[{"id":"A1","number":1,"state":"OPEN","vulnerableRequirements":"= 4.17.15",
+					"securityVulnerability":{"package":{"name":"lodash","ecosystem":"NPM"},
+					"advisory":{"ghsaId":"GHSA-p6mc-m468-83gw","identifiers":[{"type":"GHSA","value":"GHSA-p6mc-m468-83gw"},{"type":"CVE","value":"CVE-2020-8203"}]}}}]}}}}`)) //nolint:errcheck
+			return
+		}
+		w.Write([]byte(`{"data":{"repository":{"vulnerabilityAlerts":{
+			"pageInfo":{"hasNextPage":false},
+			"nodes":[{"id":"A2","number":2,"state":"OPEN",
+				"securityVulnerability":{"package":{"name":"express","ecosystem":"NPM"},
+				"advisory":{"ghsaId":"GHSA-xxxx-yyyy-zzzz","identifiers":[{"type":"CVE","value":"CVE-2023-2222"}]}}}]}}}}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := &Client{URL: srv.URL, Token: "token"}
+	alerts, err := c.Alerts(context.Background(), "example", "app")
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	require.Equal(t, []string{"GHSA-p6mc-m468-83gw", "CVE-2020-8203"}, alerts[0].VulnerabilityIDs)
+
+	doc, err := vex.Open("testdata/vex.json")
+	require.NoError(t, err)
+	ds, err := c.Sync(context.Background(), doc, "example", "app")
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.Equal(t, []string{"A1"}, dismissed)
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors":[{"message":"Resource not accessible by integration"}]}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := &Client{URL: srv.URL}
+	_, err := c.Alerts(context.Background(), "example", "app")
+	require.ErrorContains(t, err, "Resource not accessible by integration")
+}
@@ -0,0 +1,10 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package govulncheck reads the JSON output of govulncheck, the Go
+// vulnerability scanner, produced by running it with the -json flag.
+//
+// https://pkg.go.dev/golang.org/x/vuln/cmd/govulncheck
+package govulncheck
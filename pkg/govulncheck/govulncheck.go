@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package govulncheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ScanLevel is how deep govulncheck analyzed the code
+type ScanLevel string
+
+const (
+	// ScanLevelModule findings only tell a vulnerable module is required
+	ScanLevelModule ScanLevel = "module"
+
+	// ScanLevelPackage findings tell a vulnerable package is imported
+	ScanLevelPackage ScanLevel = "package"
+
+	// ScanLevelSymbol findings tell a vulnerable symbol is called
+	ScanLevelSymbol ScanLevel = "symbol"
+)
+
+// Report holds the messages of a govulncheck run.
+type Report struct {
+	Config   *Config
+	OSV      []Entry
+	Findings []Finding
+}
+
+// Message is one of the JSON objects govulncheck writes. Only one of the
+// fields is set in each message.
+type Message struct {
+	Config   *Config   `json:"config,omitempty"`
+	Progress *Progress `json:"progress,omitempty"`
+	OSV      *Entry    `json:"osv,omitempty"`
+	Finding  *Finding  `json:"finding,omitempty"`
+}
+
+// Config describes the scanner and how it was run.
+type Config struct {
+	ProtocolVersion string     `json:"protocol_version"`
+	ScannerName     string     `json:"scanner_name"`
+	ScannerVersion  string     `json:"scanner_version"`
+	DB              string     `json:"db"`
+	DBLastModified  *time.Time `json:"db_last_modified,omitempty"`
+	GoVersion       string     `json:"go_version"`
+	ScanLevel       ScanLevel  `json:"scan_level"`
+}
+
+// Progress is a message about the progress of the scan.
+type Progress struct {
+	Message string `json:"message"`
+}
+
+// Entry is the OSV record of a vulnerability. Only the fields needed to
+// identify the vulnerability are read.
+//
+// https://ossf.github.io/osv-schema/
+type Entry struct {
+	ID        string    `json:"id"`
+	Aliases   []string  `json:"aliases,omitempty"`
+	Summary   string    `json:"summary,omitempty"`
+	Modified  time.Time `json:"modified"`
+	Published time.Time `json:"published"`
+}
+
+// Finding reports a vulnerability affecting the scanned code. The trace
+// goes from the vulnerable symbol, package or module to the code scanned.
+type Finding struct {
+	OSV          string   `json:"osv"`
+	FixedVersion string   `json:"fixed_version,omitempty"`
+	Trace        []*Frame `json:"trace"`
+}
+
+// Frame is a step in the trace of a finding.
+type Frame struct {
+	Module   string    `json:"module"`
+	Version  string    `json:"version,omitempty"`
+	Package  string    `json:"package,omitempty"`
+	Function string    `json:"function,omitempty"`
+	Receiver string    `json:"receiver,omitempty"`
+	Position *Position `json:"position,omitempty"`
+}
+
+// Position is a location in a source file.
+type Position struct {
+	Filename string `json:"filename"`
+	Offset   int    `json:"offset"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// Open reads and parses govulncheck JSON output from the given file path.
+func Open(path string) (*Report, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("govulncheck: failed to open output: %w", err)
+	}
+	defer fh.Close()
+
+	return Decode(fh)
+}
+
+// Decode reads the stream of govulncheck JSON messages from r.
+func Decode(r io.Reader) (*Report, error) {
+	report := &Report{}
+	dec := json.NewDecoder(r)
+	for {
+		msg := Message{}
+		err := dec.Decode(&msg)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("govulncheck: failed to decode message: %w", err)
+		}
+		switch {
+		case msg.Config != nil:
+			report.Config = msg.Config
+		case msg.OSV != nil:
+			report.OSV = append(report.OSV, *msg.OSV)
+		case msg.Finding != nil:
+			report.Findings = append(report.Findings, *msg.Finding)
+		}
+	}
+	return report, nil
+}
+
+// Level returns how deep the finding goes: symbol if a vulnerable function
+// is called, package if a vulnerable package is imported or module if a
+// vulnerable module is only required.
+func (f *Finding) Level() ScanLevel {
+	if len(f.Trace) == 0 {
+		return ScanLevelModule
+	}
+	switch {
+	case f.Trace[0].Function != "":
+		return ScanLevelSymbol
+	case f.Trace[0].Package != "":
+		return ScanLevelPackage
+	default:
+		return ScanLevelModule
+	}
+}
+
+// Module returns the vulnerable module and its version.
+func (f *Finding) Module() (module, version string) {
+	if len(f.Trace) == 0 {
+		return "", ""
+	}
+	return f.Trace[0].Module, f.Trace[0].Version
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package govulncheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	report, err := Open("testdata/govulncheck.json")
+	require.NoError(t, err)
+	require.NotNil(t, report.Config)
+	require.Equal(t, ScanLevelSymbol, report.Config.ScanLevel)
+	require.Equal(t, "v1.0.1", report.Config.ScannerVersion)
+
+	require.Len(t, report.OSV, 3)
+	require.Equal(t, "GO-2023-1111", report.OSV[0].ID)
+	require.Equal(t, []string{"CVE-2023-1111", "GHSA-aaaa-bbbb-cccc"}, report.OSV[0].Aliases)
+
+	require.Len(t, report.Findings, 5)
+	for i, expected := range []ScanLevel{ScanLevelModule, ScanLevelPackage, ScanLevelSymbol, ScanLevelPackage, ScanLevelModule} {
+		require.Equal(t, expected, report.Findings[i].Level(), i)
+	}
+	module, version := report.Findings[3].Module()
+	require.Equal(t, "stdlib", module)
+	require.Equal(t, "v1.21.0", version)
+	require.Equal(t, 10, report.Findings[2].Trace[1].Position.Line)
+
+	_, err = Open("testdata/missing.json")
+	require.Error(t, err)
+	_, err = Decode(bytes.NewReader([]byte(`{"finding":{"trace":{}}}`)))
+	require.Error(t, err)
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	report, err := Open("testdata/govulncheck.json")
+	require.NoError(t, err)
+
+	// Write the report back as a stream of messages
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	require.NoError(t, enc.Encode(Message{Config: report.Config}))
+	for i := range report.OSV {
+		require.NoError(t, enc.Encode(Message{OSV: &report.OSV[i]}))
+	}
+	for i := range report.Findings {
+		require.NoError(t, enc.Encode(Message{Finding: &report.Findings[i]}))
+	}
+
+	decoded, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, report, decoded)
+}
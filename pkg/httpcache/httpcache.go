@@ -0,0 +1,372 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package httpcache caches remote documents on disk. Its Transport plugs
+// into the HTTPClient of the clients in this module, such as hub.Client and
+// discovery.Discoverer, so repeated scans revalidate vendor documents with
+// conditional requests instead of downloading them again, and fall back to
+// the cached copy when the network is not available.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/internal/fsutil"
+)
+
+const (
+	// StatusHeader is set in the responses served by the Transport to tell
+	// where they came from, one of the Status constants
+	StatusHeader = "X-OpenVEX-Cache"
+
+	// StatusMiss means the response was fetched and stored in the cache
+	StatusMiss = "miss"
+
+	// StatusRevalidated means the server confirmed the cached copy is
+	// current
+	StatusRevalidated = "revalidated"
+
+	// StatusStale means the server could not be reached and the cached
+	// copy was served
+	StatusStale = "stale"
+)
+
+// maxBodySize limits the size of the responses stored in the cache. Larger
+// responses are passed through without being stored.
+const maxBodySize = 16 << 20
+
+// Transport is an http.RoundTripper caching the successful responses to GET
+// requests in a directory. Cached responses are revalidated with the ETag
+// and Last-Modified validators sent by the server.
+//
+// Cache entries are keyed by URL and, for responses with a Vary header, by
+// the values of the listed request headers. Requests carrying credentials
+// in the Authorization or Cookie headers bypass the cache, and responses
+// marked no-store or private with Cache-Control or varying on all headers
+// are never stored. Requests with their own If-None-Match or
+// If-Modified-Since validators are revalidated against the caller's copy,
+// so a 304 response is passed through to them.
+type Transport struct {
+	// Dir is the cache directory
+	Dir string
+
+	// Base performs the requests. It defaults to http.DefaultTransport.
+	Base http.RoundTripper
+
+	// Offline serves cached responses without contacting the server.
+	// Requests not in the cache fail.
+	Offline bool
+}
+
+// NewTransport returns a transport caching responses in dir.
+func NewTransport(dir string) *Transport {
+	return &Transport{Dir: dir}
+}
+
+// Client returns an HTTP client using the transport.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// entry is the metadata stored next to each cached body.
+type entry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Fetched      time.Time   `json:"fetched"`
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" ||
+		req.Header.Get("Authorization") != "" || req.Header.Get("Cookie") != "" {
+		return t.base().RoundTrip(req)
+	}
+
+	u := req.URL.String()
+	vary, err := t.loadVary(u)
+	if err != nil {
+		return nil, err
+	}
+	key := t.key(u, req, vary)
+	cached, body, err := t.load(key)
+	if err != nil {
+		return nil, err
+	}
+	if t.Offline {
+		if cached == nil {
+			return nil, fmt.Errorf("%s is not in the cache", req.URL)
+		}
+		return cachedResponse(req, cached, body, StatusStale), nil
+	}
+
+	// The validators of the caller refer to their own copy, the cached one
+	// is only revalidated when there are none.
+	conditional := req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+	if cached != nil && !conditional {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	res, err := t.base().RoundTrip(req)
+	if err != nil {
+		if cached != nil && req.Context().Err() == nil {
+			return cachedResponse(req, cached, body, StatusStale), nil
+		}
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && cached != nil && !conditional:
+		res.Body.Close()
+		cached.refresh(res.Header)
+		if err := t.storeEntry(key, cached); err != nil {
+			return nil, err
+		}
+		return cachedResponse(req, cached, body, StatusRevalidated), nil
+	case res.StatusCode >= 500 && cached != nil:
+		res.Body.Close()
+		return cachedResponse(req, cached, body, StatusStale), nil
+	case res.StatusCode != http.StatusOK:
+		return res, nil
+	case !storable(res):
+		res.Header.Set(StatusHeader, StatusMiss)
+		return res, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxBodySize+1))
+	if err != nil {
+		res.Body.Close()
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if len(data) > maxBodySize {
+		// Too large to be cached, the rest of the body is read by the caller
+		res.Body = readCloser{io.MultiReader(bytes.NewReader(data), res.Body), res.Body}
+		res.Header.Set(StatusHeader, StatusMiss)
+		return res, nil
+	}
+	res.Body.Close()
+
+	if names := varyNames(res.Header); !slices.Equal(names, vary) {
+		if err := t.storeVary(u, names); err != nil {
+			return nil, err
+		}
+		key = t.key(u, req, names)
+	}
+	e := &entry{
+		URL:          u,
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+		Header:       res.Header,
+		Fetched:      time.Now().UTC(),
+	}
+	if err := t.store(key, e, data); err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(data))
+	res.Header.Set(StatusHeader, StatusMiss)
+	return res, nil
+}
+
+// refresh updates an entry with the headers of a 304 response revalidating
+// it, which carry its current validators and freshness.
+func (e *entry) refresh(header http.Header) {
+	if e.Header == nil {
+		e.Header = http.Header{}
+	}
+	for name, values := range header {
+		if name == "Content-Length" {
+			continue
+		}
+		e.Header[name] = values
+	}
+	if etag := header.Get("ETag"); etag != "" {
+		e.ETag = etag
+	}
+	if modified := header.Get("Last-Modified"); modified != "" {
+		e.LastModified = modified
+	}
+	e.Fetched = time.Now().UTC()
+}
+
+// readCloser reads from a reader and closes the body it wraps.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// key returns the name of the cache files of a request to a URL. Responses
+// varying on the request headers listed in vary are stored under a key
+// including the values of those headers.
+func (t *Transport) key(u string, req *http.Request, vary []string) string {
+	h := sha256.New()
+	h.Write([]byte(u))
+	for _, name := range vary {
+		fmt.Fprintf(h, "\n%s: %s", name, strings.Join(req.Header.Values(name), ", "))
+	}
+	return filepath.Join(t.Dir, hex.EncodeToString(h.Sum(nil)))
+}
+
+// loadVary returns the request headers the cached responses of a URL vary
+// on, as recorded by storeVary.
+func (t *Transport) loadVary(u string) ([]string, error) {
+	data, err := os.ReadFile(t.key(u, nil, nil) + ".vary")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache entry: %w", err)
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		// Treat corrupt lists as missing, they are rewritten on success
+		return nil, nil //nolint:nilerr
+	}
+	return names, nil
+}
+
+// storeVary records the request headers the responses of a URL vary on.
+func (t *Transport) storeVary(u string, names []string) error {
+	name := t.key(u, nil, nil) + ".vary"
+	if len(names) == 0 {
+		if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("writing cache entry: %w", err)
+		}
+		return nil
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return fmt.Errorf("marshaling cache entry: %w", err)
+	}
+	if err := fsutil.WriteFile(name, data, 0o600); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	return nil
+}
+
+// load reads a cache entry. It returns nil if the URL is not cached.
+func (t *Transport) load(key string) (*entry, []byte, error) {
+	meta, err := os.ReadFile(key + ".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading cache entry: %w", err)
+	}
+	e := &entry{}
+	if err := json.Unmarshal(meta, e); err != nil {
+		// Treat corrupt entries as missing, they are rewritten on success
+		return nil, nil, nil //nolint:nilerr
+	}
+	body, err := os.ReadFile(key + ".body")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading cached body: %w", err)
+	}
+	return e, body, nil
+}
+
+// store writes a cache entry. The body is written first so the metadata
+// never points to a missing or partial body.
+func (t *Transport) store(key string, e *entry, body []byte) error {
+	if err := fsutil.WriteFile(key+".body", body, 0o600); err != nil {
+		return fmt.Errorf("writing cached body: %w", err)
+	}
+	return t.storeEntry(key, e)
+}
+
+// storeEntry writes the metadata of a cache entry.
+func (t *Transport) storeEntry(key string, e *entry) error {
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling cache entry: %w", err)
+	}
+	if err := fsutil.WriteFile(key+".json", meta, 0o600); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	return nil
+}
+
+// storable returns false if the Cache-Control header of a response forbids
+// storing it in a shared cache or if it varies on all request headers.
+func storable(res *http.Response) bool {
+	if slices.Contains(varyNames(res.Header), "*") {
+		return false
+	}
+	for _, v := range res.Header.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if strings.EqualFold(name, "no-store") || strings.EqualFold(name, "private") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// varyNames returns the sorted, canonical names of the request headers
+// listed in the Vary header of a response.
+func varyNames(header http.Header) []string {
+	names := []string{}
+	for _, v := range header.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func cachedResponse(req *http.Request, e *entry, body []byte, status string) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(StatusHeader, status)
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpcache
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, c *http.Client, u string) (*http.Response, string) {
+	t.Helper()
+	res, err := c.Get(u)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res, string(data)
+}
+
+func TestTransport(t *testing.T) {
+	var requests, downloads atomic.Int32
+	body := `{"@id": "https://example.com/vex/1"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Last-Modified", "Thu, 01 Jun 2023 00:00:00 GMT")
+		switch req.URL.Path {
+		case "/etag":
+			if req.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+		case "/modified":
+			if req.Header.Get("If-Modified-Since") != "" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		downloads.Add(1)
+		w.Write([]byte(body)) //nolint:errcheck
+	}))
+
+	tr := NewTransport(t.TempDir())
+	c := tr.Client()
+
+	for _, path := range []string{"/etag", "/modified"} {
+		res, data := get(t, c, srv.URL+path)
+		require.Equal(t, StatusMiss, res.Header.Get(StatusHeader))
+		require.Equal(t, body, data)
+
+		res, data = get(t, c, srv.URL+path)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, StatusRevalidated, res.Header.Get(StatusHeader))
+		require.Equal(t, body, data)
+	}
+	require.EqualValues(t, 4, requests.Load())
+	require.EqualValues(t, 2, downloads.Load())
+
+	// Errors are not cached
+	res, _ := get(t, c, srv.URL+"/missing")
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	// Cached copies are served when the server is gone
+	srv.Close()
+	res, data := get(t, c, srv.URL+"/etag")
+	require.Equal(t, StatusStale, res.Header.Get(StatusHeader))
+	require.Equal(t, body, data)
+
+	_, err := c.Get(srv.URL + "/other")
+	require.Error(t, err)
+
+	tr.Offline = true
+	res, data = get(t, c, srv.URL+"/modified")
+	require.Equal(t, StatusStale, res.Header.Get(StatusHeader))
+	require.Equal(t, body, data)
+	_, err = c.Get(srv.URL + "/other")
+	require.Error(t, err)
+}
+
+func TestTransportPrivate(t *testing.T) {
+	var downloads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		downloads.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		switch req.URL.Path {
+		case "/no-store":
+			w.Header().Set("Cache-Control", "max-age=0, no-store")
+		case "/private":
+			w.Header().Set("Cache-Control", `private="Set-Cookie"`)
+		}
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(req.Header.Get("Authorization"))) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	tr := NewTransport(t.TempDir())
+	c := tr.Client()
+
+	// Responses the server marks as not storable are always downloaded
+	for _, path := range []string{"/no-store", "/private"} {
+		for i := 0; i < 2; i++ {
+			res, _ := get(t, c, srv.URL+path)
+			require.Equal(t, StatusMiss, res.Header.Get(StatusHeader), path)
+		}
+	}
+	require.EqualValues(t, 4, downloads.Load())
+
+	// Requests with credentials are neither cached nor served from the cache
+	for _, token := range []string{"Bearer one", "Bearer two"} {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/doc", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", token)
+		res, err := c.Do(req)
+		require.NoError(t, err)
+		data, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Empty(t, res.Header.Get(StatusHeader))
+		require.Equal(t, token, string(data))
+	}
+	res, data := get(t, c, srv.URL+"/doc")
+	require.Equal(t, StatusMiss, res.Header.Get(StatusHeader))
+	require.Empty(t, data)
+}
+
+func TestTransportRevalidation(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", version.Load()))
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("v1")) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	tr := NewTransport(t.TempDir())
+	c := tr.Client()
+	res, _ := get(t, c, srv.URL)
+	require.Equal(t, StatusMiss, res.Header.Get(StatusHeader))
+
+	// A 304 for the validators of the caller is passed through
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", `"v1"`)
+	res, err = c.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusNotModified, res.StatusCode)
+
+	// A 304 for the cached copy refreshes its headers
+	version.Store(2)
+	res, data := get(t, c, srv.URL)
+	require.Equal(t, StatusRevalidated, res.Header.Get(StatusHeader))
+	require.Equal(t, "v1", data)
+	tr.Offline = true
+	res, _ = get(t, c, srv.URL)
+	require.Equal(t, "max-age=2", res.Header.Get("Cache-Control"))
+}
+
+func TestTransportVary(t *testing.T) {
+	var downloads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("ETag", `"`+req.Header.Get("Accept")+`"`)
+		switch req.URL.Path {
+		case "/all":
+			w.Header().Set("Vary", "*")
+		default:
+			w.Header().Set("Vary", "accept")
+		}
+		if req.Header.Get("If-None-Match") == w.Header().Get("ETag") {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads.Add(1)
+		w.Write([]byte(req.Header.Get("Accept"))) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := NewTransport(t.TempDir()).Client()
+	fetch := func(path, accept string) (*http.Response, string) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", accept)
+		res, err := c.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		data, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, string(data)
+	}
+
+	// Each variant is cached separately
+	for i := 0; i < 2; i++ {
+		for _, accept := range []string{"application/json", "text/plain"} {
+			_, data := fetch("/doc", accept)
+			require.Equal(t, accept, data)
+		}
+	}
+	require.EqualValues(t, 2, downloads.Load())
+	res, _ := fetch("/doc", "text/plain")
+	require.Equal(t, StatusRevalidated, res.Header.Get(StatusHeader))
+
+	// Responses varying on everything are not cached
+	for i := 0; i < 2; i++ {
+		res, _ := fetch("/all", "text/plain")
+		require.Equal(t, StatusMiss, res.Header.Get(StatusHeader))
+	}
+	require.EqualValues(t, 4, downloads.Load())
+}
+
+func TestTransportLargeBody(t *testing.T) {
+	body := bytes.Repeat([]byte("x"), maxBodySize+1)
+	var downloads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		downloads.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(body) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	// Responses too large to be cached are passed through whole
+	c := NewTransport(t.TempDir()).Client()
+	for i := 0; i < 2; i++ {
+		res, data := get(t, c, srv.URL)
+		require.Equal(t, StatusMiss, res.Header.Get(StatusHeader))
+		require.Equal(t, len(body), len(data))
+	}
+	require.EqualValues(t, 2, downloads.Load())
+}
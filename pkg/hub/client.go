@@ -0,0 +1,226 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package hub fetches VEX documents by purl from a remote repository, such
+// as a VEX Hub style index, so scanners can pull the statements about the
+// packages they find at scan time.
+//
+// The repository serves the documents about a package at
+//
+//	GET <url>/v1/documents?purl=<purl>&page_token=<token>
+//
+// which responds with a page of documents and the token of the next page,
+// if any:
+//
+//	{"documents": [...], "next_page_token": "..."}
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a failed request is retried
+	DefaultMaxRetries = 3
+
+	// DefaultBackoff is the wait before the first retry. It doubles on
+	// every attempt.
+	DefaultBackoff = 500 * time.Millisecond
+
+	// maxBackoff caps the wait between retries
+	maxBackoff = 30 * time.Second
+
+	// maxPageSize limits the size of the pages read
+	maxPageSize = 32 << 20
+)
+
+// Client fetches documents from a repository.
+type Client struct {
+	// URL is the base URL of the repository
+	URL string
+
+	// Token is sent as a bearer token when set
+	Token string
+
+	// HTTPClient is used to perform requests
+	HTTPClient *http.Client
+
+	// MaxRetries is the number of times requests failing with a network
+	// error, 429 or 5xx status are retried. Zero disables retries.
+	MaxRetries int
+
+	// Backoff is the wait before the first retry, it doubles on every
+	// attempt. A Retry-After header returned by the server takes
+	// precedence.
+	Backoff time.Duration
+}
+
+// NewClient returns a client for the repository at url.
+func NewClient(url string) *Client {
+	return &Client{
+		URL:        strings.TrimSuffix(url, "/"),
+		HTTPClient: http.DefaultClient,
+		MaxRetries: DefaultMaxRetries,
+		Backoff:    DefaultBackoff,
+	}
+}
+
+// page is a response of the documents endpoint
+type page struct {
+	Documents     []json.RawMessage `json:"documents"`
+	NextPageToken string            `json:"next_page_token"`
+}
+
+// Documents returns all the documents the repository has about purl,
+// following the pages of the response.
+func (c *Client) Documents(ctx context.Context, purl string) ([]*vex.VEX, error) {
+	docs := []*vex.VEX{}
+	err := c.EachDocument(ctx, purl, func(doc *vex.VEX) error {
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// EachDocument calls fn with each of the documents the repository has about
+// purl as the pages are fetched. It stops and returns the error if fn
+// returns one.
+func (c *Client) EachDocument(ctx context.Context, purl string, fn func(*vex.VEX) error) error {
+	token := ""
+	for {
+		q := url.Values{"purl": {purl}}
+		if token != "" {
+			q.Set("page_token", token)
+		}
+		p := page{}
+		if err := c.get(ctx, c.URL+"/v1/documents?"+q.Encode(), &p); err != nil {
+			return fmt.Errorf("fetching documents of %s: %w", purl, err)
+		}
+		for i, data := range p.Documents {
+			doc, err := vex.Parse(data)
+			if err != nil {
+				return fmt.Errorf("parsing document %d of %s: %w", i, purl, err)
+			}
+			if err := fn(doc); err != nil {
+				return err
+			}
+		}
+		if p.NextPageToken == "" {
+			return nil
+		}
+		if p.NextPageToken == token {
+			return fmt.Errorf("fetching documents of %s: repository returned the same page token", purl)
+		}
+		token = p.NextPageToken
+	}
+}
+
+// retryableError is returned by do for failures worth retrying
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// get fetches u into v, retrying transient failures.
+func (c *Client) get(ctx context.Context, u string, v any) error {
+	backoff := c.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		err := c.do(ctx, u, v)
+		var retryable *retryableError
+		if err == nil || !errors.As(err, &retryable) || attempt >= c.MaxRetries {
+			return err
+		}
+
+		wait := retryable.retryAfter
+		if wait == 0 {
+			wait = min(backoff<<attempt, maxBackoff)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &retryableError{err: err}
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500:
+		return &retryableError{
+			err:        fmt.Errorf("repository returned %s", res.Status),
+			retryAfter: retryAfter(res.Header.Get("Retry-After")),
+		}
+	case res.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024)) //nolint:errcheck
+		return fmt.Errorf("repository returned %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxPageSize+1))
+	if err != nil {
+		return &retryableError{err: fmt.Errorf("reading response: %w", err)}
+	}
+	if len(data) > maxPageSize {
+		return fmt.Errorf("response is larger than %d bytes", maxPageSize)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// retryAfter parses the value of a Retry-After header, either a number of
+// seconds or an HTTP date. It returns zero if the value is not valid.
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(value); err == nil && s >= 0 {
+		return min(time.Duration(s)*time.Second, maxBackoff)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return min(max(time.Until(t), 0), maxBackoff)
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestDocuments(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := requests.Add(1)
+		require.Equal(t, "/v1/documents", req.URL.Path)
+		require.Equal(t, "pkg:npm/example@1.0.0", req.URL.Query().Get("purl"))
+		require.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+
+		switch req.URL.Query().Get("page_token") {
+		case "":
+			// The first attempt fails and is retried
+			if n == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			writeFile(t, w, "testdata/page-1.json")
+		case "2":
+			writeFile(t, w, "testdata/page-2.json")
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL + "/")
+	c.Token = "secret"
+	c.Backoff = time.Millisecond
+	docs, err := c.Documents(context.Background(), "pkg:npm/example@1.0.0")
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	require.Equal(t, "https://example.com/vex/3", docs[2].ID)
+	require.Equal(t, int32(3), requests.Load())
+
+	// Stopping early
+	stop := errors.New("stop")
+	n := 0
+	err = c.EachDocument(context.Background(), "pkg:npm/example@1.0.0", func(*vex.VEX) error {
+		n++
+		return stop
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, 1, n)
+}
+
+// writeFile writes the contents of a testdata file as the response body
+func writeFile(t *testing.T, w http.ResponseWriter, name string) {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+	w.Write(data) //nolint:errcheck
+}
+
+func TestDocumentsErrors(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		switch req.URL.Query().Get("purl") {
+		case "unavailable":
+			w.WriteHeader(http.StatusBadGateway)
+		case "invalid":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid purl")) //nolint:errcheck
+		case "loop":
+			w.Write([]byte(`{"documents": [], "next_page_token": "same"}`)) //nolint:errcheck
+		case "bad document":
+			w.Write([]byte(`{"documents": [{"statements": 1}]}`)) //nolint:errcheck
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.Backoff = time.Millisecond
+	c.MaxRetries = 2
+
+	_, err := c.Documents(context.Background(), "unavailable")
+	require.ErrorContains(t, err, "502")
+	require.Equal(t, int32(3), requests.Load())
+
+	requests.Store(0)
+	_, err = c.Documents(context.Background(), "invalid")
+	require.ErrorContains(t, err, "invalid purl")
+	require.Equal(t, int32(1), requests.Load())
+
+	_, err = c.Documents(context.Background(), "loop")
+	require.Error(t, err)
+
+	_, err = c.Documents(context.Background(), "bad document")
+	require.Error(t, err)
+
+	// Cancelled while waiting to retry
+	c.Backoff = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.Documents(ctx, "unavailable")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryAfter(t *testing.T) {
+	require.Equal(t, 2*time.Second, retryAfter("2"))
+	require.Equal(t, maxBackoff, retryAfter("3600"))
+	require.Zero(t, retryAfter(""))
+	require.Zero(t, retryAfter("soon"))
+	require.Zero(t, retryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)))
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package kev cross-checks VEX documents against the CISA Known Exploited
+// Vulnerabilities catalog, reporting the products still affected, or under
+// investigation, by vulnerabilities that are being actively exploited.
+//
+// https://www.cisa.gov/known-exploited-vulnerabilities-catalog
+package kev
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// DefaultURL is the location of the catalog feed
+	DefaultURL = "https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json"
+
+	// maxCatalogSize limits the size of the catalog read
+	maxCatalogSize = 64 << 20
+)
+
+// Catalog is the Known Exploited Vulnerabilities catalog.
+type Catalog struct {
+	Title           string          `json:"title"`
+	Version         string          `json:"catalogVersion"`
+	Released        string          `json:"dateReleased"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+
+	once  sync.Once
+	byCVE map[string]*Vulnerability
+}
+
+// Vulnerability is an entry of the catalog.
+type Vulnerability struct {
+	CVE                        string `json:"cveID"`
+	VendorProject              string `json:"vendorProject"`
+	Product                    string `json:"product"`
+	Name                       string `json:"vulnerabilityName"`
+	DateAdded                  string `json:"dateAdded"`
+	ShortDescription           string `json:"shortDescription"`
+	RequiredAction             string `json:"requiredAction"`
+	DueDate                    string `json:"dueDate"`
+	KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+	Notes                      string `json:"notes"`
+}
+
+// Client fetches the catalog.
+type Client struct {
+	// URL is the location of the catalog feed
+	URL string
+
+	// HTTPClient is used to perform requests
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client fetching the catalog published by CISA.
+func NewClient() *Client {
+	return &Client{URL: DefaultURL, HTTPClient: http.DefaultClient}
+}
+
+// Catalog fetches the current catalog.
+func (c *Client) Catalog(ctx context.Context) (*Catalog, error) {
+	u := c.URL
+	if u == "" {
+		u = DefaultURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching catalog: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching catalog: server returned %s", res.Status)
+	}
+	return ParseCatalog(io.LimitReader(res.Body, maxCatalogSize))
+}
+
+// ParseCatalog reads a catalog in the JSON format published by CISA.
+func ParseCatalog(r io.Reader) (*Catalog, error) {
+	cat := &Catalog{}
+	if err := json.NewDecoder(r).Decode(cat); err != nil {
+		return nil, fmt.Errorf("decoding catalog: %w", err)
+	}
+	return cat, nil
+}
+
+// Lookup returns the catalog entry of a CVE or nil if it is not known to be
+// exploited. The vulnerabilities of the catalog must not be modified once
+// it has been used.
+func (cat *Catalog) Lookup(cve string) *Vulnerability {
+	cat.once.Do(func() {
+		cat.byCVE = make(map[string]*Vulnerability, len(cat.Vulnerabilities))
+		for i := range cat.Vulnerabilities {
+			cat.byCVE[strings.ToUpper(cat.Vulnerabilities[i].CVE)] = &cat.Vulnerabilities[i]
+		}
+	})
+	return cat.byCVE[strings.ToUpper(cve)]
+}
+
+// Finding is a product affected, or possibly affected, by a known exploited
+// vulnerability.
+type Finding struct {
+	// Product is the product ID of the statement
+	Product string `json:"product"`
+
+	// Status is the effective status of the vulnerability in the product,
+	// affected or under_investigation
+	Status vex.Status `json:"status"`
+
+	// Statement is the effective statement
+	Statement vex.Statement `json:"statement"`
+
+	// KEV is the catalog entry of the vulnerability
+	KEV Vulnerability `json:"kev"`
+}
+
+// Check returns the products that the documents mark as affected or under
+// investigation by vulnerabilities in the catalog, sorted by CVE and
+// product. The effective statement of each vulnerability and product is
+// resolved across all the documents, so products later marked as fixed or
+// not affected are not reported. Vulnerabilities are matched by their name
+// and aliases.
+func (cat *Catalog) Check(docs ...*vex.VEX) []Finding {
+	idx := vex.NewIndex(docs...)
+	findings := []Finding{}
+	seen := map[string]bool{}
+	for _, doc := range docs {
+		for i := range doc.Statements {
+			s := &doc.Statements[i]
+			entry, id := cat.lookupVulnerability(&s.Vulnerability)
+			if entry == nil {
+				continue
+			}
+			for j := range s.Products {
+				product := s.Products[j].ID
+				key := entry.CVE + " " + product
+				if product == "" || seen[key] {
+					continue
+				}
+				seen[key] = true
+				effective := idx.EffectiveStatement(product, id)
+				if effective == nil ||
+					(effective.Status != vex.StatusAffected && effective.Status != vex.StatusUnderInvestigation) {
+					continue
+				}
+				findings = append(findings, Finding{
+					Product:   product,
+					Status:    effective.Status,
+					Statement: *effective,
+					KEV:       *entry,
+				})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].KEV.CVE != findings[j].KEV.CVE {
+			return findings[i].KEV.CVE < findings[j].KEV.CVE
+		}
+		return findings[i].Product < findings[j].Product
+	})
+	return findings
+}
+
+// lookupVulnerability returns the catalog entry of a vulnerability and the
+// identifier it was found by.
+func (cat *Catalog) lookupVulnerability(v *vex.Vulnerability) (*Vulnerability, string) {
+	ids := append([]vex.VulnerabilityID{v.Name}, v.Aliases...)
+	for _, id := range ids {
+		if entry := cat.Lookup(string(id)); entry != nil {
+			return entry, string(id)
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kev
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const testCatalog = `{
+  "title": "CISA Catalog of Known Exploited Vulnerabilities",
+  "catalogVersion": "2024.03.01",
+  "dateReleased": "2024-03-01T15:00:00.0000Z",
+  "count": 2,
+  "vulnerabilities": [
+    {
+      "cveID": "CVE-2021-44228",
+      "vendorProject": "Apache",
+      "product": "Log4j2",
+      "vulnerabilityName": "Apache Log4j2 Remote Code Execution Vulnerability",
+      "dateAdded": "2021-12-10",
+      "shortDescription": "Apache Log4j2 contains a vulnerability where JNDI features do not protect against attacker-controlled JNDI-related endpoints.",
+      "requiredAction": "Apply updates per vendor instructions.",
+      "dueDate": "2021-12-24",
+      "knownRansomwareCampaignUse": "Known",
+      "notes": ""
+    },
+    {
+      "cveID": "CVE-2023-4966",
+      "vendorProject": "Citrix",
+      "product": "NetScaler",
+      "vulnerabilityName": "Citrix Bleed",
+      "dateAdded": "2023-10-18",
+      "shortDescription": "Sensitive information disclosure.",
+      "requiredAction": "Apply mitigations per vendor instructions.",
+      "dueDate": "2023-11-08",
+      "knownRansomwareCampaignUse": "Known",
+      "notes": ""
+    }
+  ]
+}`
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testCatalog)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.URL = srv.URL
+	cat, err := c.Catalog(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "2024.03.01", cat.Version)
+	require.NotNil(t, cat.Lookup("cve-2021-44228"))
+	require.Nil(t, cat.Lookup("CVE-2000-0001"))
+
+	t1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	doc1 := vex.New()
+	doc1.Timestamp = &t1
+	doc1.Statements = []vex.Statement{
+		{
+			Vulnerability: vex.Vulnerability{Name: "GHSA-jfh8-c2jp-5v3q", Aliases: []vex.VulnerabilityID{"CVE-2021-44228"}},
+			Products: []vex.Product{
+				{Component: vex.Component{ID: "pkg:oci/app"}},
+				{Component: vex.Component{ID: "pkg:oci/web"}},
+			},
+			Status:          vex.StatusAffected,
+			ActionStatement: "Update log4j",
+		},
+		{
+			Vulnerability: vex.Vulnerability{Name: "CVE-2023-4966"},
+			Products:      []vex.Product{{Component: vex.Component{ID: "pkg:oci/gateway"}}},
+			Status:        vex.StatusUnderInvestigation,
+		},
+		{
+			Vulnerability: vex.Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []vex.Product{{Component: vex.Component{ID: "pkg:oci/app"}}},
+			Status:        vex.StatusAffected,
+		},
+	}
+	doc2 := vex.New()
+	doc2.Timestamp = &t2
+	doc2.Statements = []vex.Statement{{
+		Vulnerability: vex.Vulnerability{Name: "CVE-2021-44228"},
+		Products:      []vex.Product{{Component: vex.Component{ID: "pkg:oci/web"}}},
+		Status:        vex.StatusFixed,
+	}}
+
+	findings := cat.Check(&doc1, &doc2)
+	require.Len(t, findings, 2)
+	require.Equal(t, "pkg:oci/app", findings[0].Product)
+	require.Equal(t, vex.StatusAffected, findings[0].Status)
+	require.Equal(t, "2021-12-24", findings[0].KEV.DueDate)
+	require.Equal(t, "Update log4j", findings[0].Statement.ActionStatement)
+	require.Equal(t, "pkg:oci/gateway", findings[1].Product)
+	require.Equal(t, vex.StatusUnderInvestigation, findings[1].Status)
+
+	require.Empty(t, cat.Check(&doc2))
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package nvd enriches the vulnerabilities of VEX documents with the data
+// published in the National Vulnerability Database, so documents carry the
+// description and references of the CVEs they talk about.
+//
+// The NVD API is rate limited, the Client spaces its requests accordingly
+// and keeps the records it fetched. Use an httpcache.Transport as its
+// HTTPClient to keep them across runs.
+package nvd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// DefaultURL is the endpoint of the NVD CVE API
+	DefaultURL = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+
+	// DefaultInterval is the wait between requests without an API key, the
+	// NVD allows 5 requests in 30 seconds.
+	DefaultInterval = 6 * time.Second
+
+	// DefaultKeyInterval is the wait between requests with an API key, the
+	// NVD allows 50 requests in 30 seconds.
+	DefaultKeyInterval = 600 * time.Millisecond
+
+	// maxResponseSize limits the size of the responses read
+	maxResponseSize = 8 << 20
+)
+
+// ErrNotFound is returned when the NVD has no record of a CVE.
+var ErrNotFound = errors.New("CVE not found in the NVD")
+
+// Record is the data about a CVE used to enrich documents.
+type Record struct {
+	ID          string          `json:"id"`
+	Description string          `json:"description"`
+	CVSS        []CVSS          `json:"cvss"`
+	References  []vex.Reference `json:"references"`
+}
+
+// CVSS is a CVSS score of a CVE. Records include CVSS 2.0 scores, which
+// are not added to documents.
+type CVSS = vex.CVSS
+
+// Client fetches CVE records from the NVD API.
+type Client struct {
+	// URL is the endpoint of the CVE API
+	URL string
+
+	// APIKey is sent with the requests when set, raising the rate limit
+	APIKey string
+
+	// HTTPClient is used to perform requests
+	HTTPClient *http.Client
+
+	// Interval is the minimum wait between requests. It defaults to
+	// DefaultInterval or DefaultKeyInterval if there is an API key.
+	Interval time.Duration
+
+	mu      sync.Mutex
+	last    time.Time
+	records map[string]*Record
+}
+
+// NewClient returns a client of the NVD API authenticating with apiKey,
+// which can be empty.
+func NewClient(apiKey string) *Client {
+	return &Client{URL: DefaultURL, APIKey: apiKey, HTTPClient: http.DefaultClient}
+}
+
+// CVE returns the record of a CVE. Records are fetched once per client.
+func (c *Client) CVE(ctx context.Context, id string) (*Record, error) {
+	id = strings.ToUpper(id)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r, ok := c.records[id]; ok {
+		if r == nil {
+			return nil, fmt.Errorf("%s: %w", id, ErrNotFound)
+		}
+		return r, nil
+	}
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	r, err := c.fetch(ctx, id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if c.records == nil {
+		c.records = map[string]*Record{}
+	}
+	c.records[id] = r
+	return r, err
+}
+
+// Enrich fills the description, references and CVSS scores of the
+// vulnerabilities in the document whose name or aliases are CVEs.
+// Descriptions already set are kept, references and scores are added to
+// the existing ones. CVEs unknown to the NVD are skipped.
+func (c *Client) Enrich(ctx context.Context, doc *vex.VEX) error {
+	for i := range doc.Statements {
+		v := &doc.Statements[i].Vulnerability
+		id := v.CVE()
+		if id == "" {
+			continue
+		}
+		r, err := c.CVE(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("enriching %s: %w", id, err)
+		}
+		if v.Description == "" {
+			v.Description = r.Description
+		}
+		for _, ref := range r.References {
+			if !slices.Contains(v.References, ref) {
+				v.References = append(v.References, ref)
+			}
+		}
+		for _, score := range r.CVSS {
+			if score.Version != "2.0" && !hasVector(v.CVSS, score.Vector) {
+				v.CVSS = append(v.CVSS, score)
+			}
+		}
+	}
+	return nil
+}
+
+// referenceType returns the type of a reference from its NVD tags.
+func referenceType(tags []string) vex.ReferenceType {
+	switch {
+	case slices.Contains(tags, "Patch"):
+		return vex.ReferenceFix
+	case slices.Contains(tags, "Vendor Advisory"), slices.Contains(tags, "Third Party Advisory"),
+		slices.Contains(tags, "Mitigation"):
+		return vex.ReferenceAdvisory
+	case slices.Contains(tags, "Issue Tracking"), slices.Contains(tags, "Mailing List"),
+		slices.Contains(tags, "Exploit"):
+		return vex.ReferenceReport
+	default:
+		return vex.ReferenceArticle
+	}
+}
+
+func hasVector(scores []CVSS, vector string) bool {
+	return slices.ContainsFunc(scores, func(s CVSS) bool { return s.Vector == vector })
+}
+
+// wait blocks until the next request is allowed. It must be called with
+// the lock held.
+func (c *Client) wait(ctx context.Context) error {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+		if c.APIKey != "" {
+			interval = DefaultKeyInterval
+		}
+	}
+	if d := time.Until(c.last.Add(interval)); !c.last.IsZero() && d > 0 {
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	c.last = time.Now()
+	return nil
+}
+
+// response is the part of the CVE API response read
+type response struct {
+	Vulnerabilities []struct {
+		CVE struct {
+			ID           string `json:"id"`
+			Descriptions []struct {
+				Lang  string `json:"lang"`
+				Value string `json:"value"`
+			} `json:"descriptions"`
+			Metrics    map[string][]metric `json:"metrics"`
+			References []struct {
+				URL  string   `json:"url"`
+				Tags []string `json:"tags"`
+			} `json:"references"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+type metric struct {
+	Source   string `json:"source"`
+	CVSSData struct {
+		Version      string  `json:"version"`
+		VectorString string  `json:"vectorString"`
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+	// CVSS v2 metrics have the severity outside of the data
+	BaseSeverity string `json:"baseSeverity"`
+}
+
+// metricKeys are the CVSS metrics read, from the newest version
+var metricKeys = []string{"cvssMetricV40", "cvssMetricV31", "cvssMetricV30", "cvssMetricV2"}
+
+func (c *Client) fetch(ctx context.Context, id string) (*Record, error) {
+	u := c.URL
+	if u == "" {
+		u = DefaultURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u+"?"+url.Values{"cveId": {id}}.Encode(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("apiKey", c.APIKey)
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", id, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024)) //nolint:errcheck
+		return nil, fmt.Errorf("nvd returned %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	resp := response{}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if len(resp.Vulnerabilities) == 0 {
+		return nil, fmt.Errorf("%s: %w", id, ErrNotFound)
+	}
+
+	cve := &resp.Vulnerabilities[0].CVE
+	r := &Record{ID: cve.ID, CVSS: []CVSS{}, References: []vex.Reference{}}
+	for _, d := range cve.Descriptions {
+		if d.Lang == "en" {
+			r.Description = d.Value
+			break
+		}
+	}
+	for _, key := range metricKeys {
+		for _, m := range cve.Metrics[key] {
+			severity := m.CVSSData.BaseSeverity
+			if severity == "" {
+				severity = m.BaseSeverity
+			}
+			r.CVSS = append(r.CVSS, CVSS{
+				Version:      m.CVSSData.Version,
+				Vector:       m.CVSSData.VectorString,
+				BaseScore:    m.CVSSData.BaseScore,
+				BaseSeverity: severity,
+				Source:       m.Source,
+			})
+		}
+	}
+	for _, ref := range cve.References {
+		r.References = append(r.References, vex.Reference{URL: ref.URL, Type: referenceType(ref.Tags)})
+	}
+	return r, nil
+}
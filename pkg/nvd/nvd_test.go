@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nvd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const testResponse = `{
+  "totalResults": 1,
+  "vulnerabilities": [{
+    "cve": {
+      "id": "CVE-2021-44228",
+      "descriptions": [
+        {"lang": "es", "value": "Apache Log4j2 ..."},
+        {"lang": "en", "value": "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP."}
+      ],
+      "metrics": {
+        "cvssMetricV31": [{
+          "source": "nvd@nist.gov",
+          "cvssData": {"version": "3.1", "vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", "baseScore": 10.0, "baseSeverity": "CRITICAL"}
+        }],
+        "cvssMetricV2": [{
+          "source": "nvd@nist.gov",
+          "cvssData": {"version": "2.0", "vectorString": "AV:N/AC:M/Au:N/C:C/I:C/A:C", "baseScore": 9.3},
+          "baseSeverity": "HIGH"
+        }]
+      },
+      "references": [
+        {"url": "https://logging.apache.org/log4j/2.x/security.html", "tags": ["Release Notes", "Vendor Advisory"]},
+        {"url": "https://github.com/apache/logging-log4j2/pull/608", "tags": ["Patch"]}
+      ]
+    }
+  }]
+}`
+
+func TestEnrich(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.Equal(t, "secret", r.Header.Get("apiKey"))
+		switch r.URL.Query().Get("cveId") {
+		case "CVE-2021-44228":
+			w.Write([]byte(testResponse)) //nolint:errcheck
+		default:
+			w.Write([]byte(`{"totalResults": 0, "vulnerabilities": []}`)) //nolint:errcheck
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient("secret")
+	c.URL = srv.URL
+	c.Interval = time.Millisecond
+
+	r, err := c.CVE(context.Background(), "cve-2021-44228")
+	require.NoError(t, err)
+	require.Equal(t, "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP.", r.Description)
+	require.Equal(t, []CVSS{
+		{Version: "3.1", Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", BaseScore: 10, BaseSeverity: "CRITICAL", Source: "nvd@nist.gov"},
+		{Version: "2.0", Vector: "AV:N/AC:M/Au:N/C:C/I:C/A:C", BaseScore: 9.3, BaseSeverity: "HIGH", Source: "nvd@nist.gov"},
+	}, r.CVSS)
+
+	_, err = c.CVE(context.Background(), "CVE-2000-0001")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	doc := vex.New()
+	doc.Statements = []vex.Statement{
+		{
+			Vulnerability: vex.Vulnerability{Name: "GHSA-jfh8-c2jp-5v3q", Aliases: []vex.VulnerabilityID{"CVE-2021-44228"}},
+			Status:        vex.StatusAffected,
+		},
+		{
+			Vulnerability: vex.Vulnerability{
+				Name:        "CVE-2021-44228",
+				Description: "Log4Shell",
+				References: []vex.Reference{
+					{URL: "https://logging.apache.org/log4j/2.x/security.html", Type: vex.ReferenceAdvisory},
+				},
+			},
+			Status: vex.StatusFixed,
+		},
+		{Vulnerability: vex.Vulnerability{Name: "CVE-2000-0001"}, Status: vex.StatusFixed},
+		{Vulnerability: vex.Vulnerability{Name: "GHSA-aaaa-bbbb-cccc"}, Status: vex.StatusFixed},
+	}
+	require.NoError(t, c.Enrich(context.Background(), &doc))
+	require.Equal(t, r.Description, doc.Statements[0].Vulnerability.Description)
+	require.Equal(t, []vex.Reference{
+		{URL: "https://logging.apache.org/log4j/2.x/security.html", Type: vex.ReferenceAdvisory},
+		{URL: "https://github.com/apache/logging-log4j2/pull/608", Type: vex.ReferenceFix},
+	}, doc.Statements[0].Vulnerability.References)
+	require.Equal(t, r.CVSS[:1], doc.Statements[0].Vulnerability.CVSS)
+	require.Equal(t, "Log4Shell", doc.Statements[1].Vulnerability.Description)
+	require.Len(t, doc.Statements[1].Vulnerability.References, 2)
+	require.Empty(t, doc.Statements[2].Vulnerability.Description)
+
+	// Records are fetched once
+	require.Equal(t, 2, requests)
+}
+
+func TestRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testResponse)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := NewClient("")
+	c.URL = srv.URL
+	c.Interval = time.Hour
+	_, err := c.CVE(context.Background(), "CVE-2021-44228")
+	require.NoError(t, err)
+
+	// Cached records are not limited, new requests wait
+	_, err = c.CVE(context.Background(), "CVE-2021-44228")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.CVE(ctx, "CVE-2021-45046")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	intoto "github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/common"
+
+	"github.com/openvex/go-vex/pkg/attestation"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// ArtifactType is the artifact type of VEX attestations attached to images
+	ArtifactType = attestation.PayloadType
+
+	// AnnotationPredicateType records the predicate type of the attestation
+	AnnotationPredicateType = "in-toto.io/predicate-type"
+)
+
+// AttachAttestation pushes a VEX attestation to the registry as a referrer
+// of the image referenced by ref.
+func (c *Client) AttachAttestation(ctx context.Context, ref Reference, att *attestation.Attestation) (*Descriptor, error) {
+	var b bytes.Buffer
+	if err := att.ToJSON(&b); err != nil {
+		return nil, err
+	}
+	return c.Attach(ctx, ref, &Artifact{
+		ArtifactType: ArtifactType,
+		MediaType:    attestation.PayloadType,
+		Data:         b.Bytes(),
+		Annotations: map[string]string{
+			AnnotationPredicateType: vex.TypeURI,
+		},
+	})
+}
+
+// AttachDocument wraps a VEX document in an attestation whose subject is
+// the image referenced by ref and attaches it to the image.
+func (c *Client) AttachDocument(ctx context.Context, ref Reference, doc *vex.VEX) (*Descriptor, error) {
+	subject, err := c.Resolve(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("resolving subject: %w", err)
+	}
+	algo, val, ok := strings.Cut(subject.Digest, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid subject digest %q", subject.Digest)
+	}
+
+	att := attestation.New()
+	att.Predicate = *doc
+	if err := att.AddSubjects([]intoto.Subject{{
+		Name:   ref.Registry + "/" + ref.Repository,
+		Digest: common.DigestSet{algo: val},
+	}}); err != nil {
+		return nil, fmt.Errorf("adding subject: %w", err)
+	}
+
+	// Attach to the resolved digest so the tag cannot move under us
+	ref.Digest = subject.Digest
+	return c.AttachAttestation(ctx, ref, att)
+}
+
+// DiscoverAttestations returns the VEX attestations attached to the image
+// referenced by ref. Referrers that cannot be parsed as OpenVEX attestations
+// are skipped.
+func (c *Client) DiscoverAttestations(ctx context.Context, ref Reference) ([]*attestation.Attestation, error) {
+	descs, err := c.Referrers(ctx, ref, ArtifactType)
+	if err != nil {
+		return nil, err
+	}
+	atts := []*attestation.Attestation{}
+	for i := range descs {
+		if pt, ok := descs[i].Annotations[AnnotationPredicateType]; ok && pt != vex.TypeURI {
+			continue
+		}
+		data, err := c.FetchArtifact(ctx, ref, &descs[i])
+		if err != nil {
+			return nil, fmt.Errorf("fetching attestation %s: %w", descs[i].Digest, err)
+		}
+		att, err := attestation.Parse(data)
+		if err != nil {
+			continue
+		}
+		atts = append(atts, att)
+	}
+	return atts, nil
+}
+
+// DiscoverDocuments returns the VEX documents in the attestations attached
+// to the image referenced by ref.
+func (c *Client) DiscoverDocuments(ctx context.Context, ref Reference) ([]*vex.VEX, error) {
+	atts, err := c.DiscoverAttestations(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]*vex.VEX, 0, len(atts))
+	for _, att := range atts {
+		docs = append(docs, &att.Predicate)
+	}
+	return docs, nil
+}
@@ -0,0 +1,611 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Media types used when building and reading artifacts
+const (
+	MediaTypeImageManifest = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeImageIndex    = "application/vnd.oci.image.index.v1+json"
+	MediaTypeEmptyJSON     = "application/vnd.oci.empty.v1+json"
+
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// emptyJSON is the content of the empty config blob used by artifacts
+var emptyJSON = []byte("{}")
+
+// Limits on the size of the content read from registries: manifests and
+// indexes are small, the blobs of artifacts may hold large documents.
+const (
+	maxManifestSize = 4 << 20
+	maxBlobSize     = 64 << 20
+)
+
+// Descriptor describes a piece of content stored in a registry.
+type Descriptor struct {
+	MediaType    string            `json:"mediaType"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is an OCI image manifest. Only the fields needed to push and read
+// artifacts are captured.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Subject       *Descriptor       `json:"subject,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Index is an OCI image index, as returned by the referrers API.
+type Index struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+// Artifact is a blob to attach to an image.
+type Artifact struct {
+	// ArtifactType describes the type of artifact stored
+	ArtifactType string
+
+	// MediaType is the media type of the data
+	MediaType string
+
+	// Data is the artifact content
+	Data []byte
+
+	// Annotations are recorded in the artifact manifest
+	Annotations map[string]string
+}
+
+// Client talks to OCI registries using the distribution API.
+type Client struct {
+	// HTTPClient is used to perform requests
+	HTTPClient *http.Client
+
+	// Username and Password are used to authenticate to the registry when
+	// it requests credentials. They are optional.
+	Username string
+	Password string
+
+	// PlainHTTP makes the client talk to registries over HTTP
+	PlainHTTP bool
+
+	// TrustedRealms lists the hosts of token servers other than the
+	// registry itself the credentials may be sent to, such as auth.docker.io.
+	// Credentials are never sent to token servers over plain HTTP unless
+	// they are served by the registry and PlainHTTP is set.
+	TrustedRealms []string
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// NewClient returns a new client using the default HTTP client.
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: http.DefaultClient,
+		tokens:     map[string]string{},
+	}
+}
+
+// Attach pushes an artifact to the repository of the image referenced by ref
+// with the image as its subject, so that it is listed by the referrers API.
+// It returns the descriptor of the artifact manifest.
+func (c *Client) Attach(ctx context.Context, ref Reference, artifact *Artifact) (*Descriptor, error) {
+	subject, err := c.Resolve(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("resolving subject: %w", err)
+	}
+
+	config, err := c.PushBlob(ctx, ref, MediaTypeEmptyJSON, emptyJSON)
+	if err != nil {
+		return nil, fmt.Errorf("pushing config blob: %w", err)
+	}
+	layer, err := c.PushBlob(ctx, ref, artifact.MediaType, artifact.Data)
+	if err != nil {
+		return nil, fmt.Errorf("pushing artifact blob: %w", err)
+	}
+
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		ArtifactType:  artifact.ArtifactType,
+		Config:        *config,
+		Layers:        []Descriptor{*layer},
+		Subject:       subject,
+		Annotations:   artifact.Annotations,
+	}
+	data, err := json.Marshal(&manifest)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling manifest: %w", err)
+	}
+	desc := &Descriptor{
+		MediaType:    MediaTypeImageManifest,
+		Digest:       digestOf(data),
+		Size:         int64(len(data)),
+		ArtifactType: artifact.ArtifactType,
+		Annotations:  artifact.Annotations,
+	}
+
+	res, err := c.do(ctx, ref, http.MethodPut, c.url(ref, "manifests/"+desc.Digest), MediaTypeImageManifest, data)
+	if err != nil {
+		return nil, fmt.Errorf("pushing manifest: %w", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("pushing manifest: registry returned %s", res.Status)
+	}
+
+	// Registries supporting the referrers API acknowledge the subject. If
+	// not, we fall back to maintaining the referrers tag.
+	if res.Header.Get("OCI-Subject") == "" {
+		if err := c.addToFallbackIndex(ctx, ref, subject.Digest, desc); err != nil {
+			return nil, fmt.Errorf("updating referrers tag: %w", err)
+		}
+	}
+	return desc, nil
+}
+
+// Referrers returns the descriptors of the artifacts attached to the image
+// referenced by ref. If artifactType is not empty, only artifacts of that
+// type are returned.
+func (c *Client) Referrers(ctx context.Context, ref Reference, artifactType string) ([]Descriptor, error) {
+	subject, err := c.Resolve(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("resolving subject: %w", err)
+	}
+
+	u := c.url(ref, "referrers/"+subject.Digest)
+	if artifactType != "" {
+		u += "?artifactType=" + url.QueryEscape(artifactType)
+	}
+	index, err := c.fetchIndex(ctx, ref, u)
+	if errors.Is(err, errNotFound) {
+		// Registry does not support the referrers API, try the tag schema
+		index, err = c.fetchIndex(ctx, ref, c.url(ref, "manifests/"+fallbackTag(subject.Digest)))
+		if errors.Is(err, errNotFound) {
+			return []Descriptor{}, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing referrers: %w", err)
+	}
+
+	ret := []Descriptor{}
+	for _, d := range index.Manifests {
+		if artifactType == "" || d.ArtifactType == artifactType {
+			ret = append(ret, d)
+		}
+	}
+	return ret, nil
+}
+
+// FetchArtifact pulls the manifest of an artifact and returns the contents
+// of its first layer.
+func (c *Client) FetchArtifact(ctx context.Context, ref Reference, desc *Descriptor) ([]byte, error) {
+	data, err := c.fetch(ctx, ref, c.url(ref, "manifests/"+desc.Digest), desc.Digest, MediaTypeImageManifest, maxManifestSize)
+	if err != nil {
+		return nil, fmt.Errorf("fetching artifact manifest: %w", err)
+	}
+	manifest := Manifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("decoding artifact manifest: %w", err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("artifact %s has no layers", desc.Digest)
+	}
+	blob, err := c.fetch(ctx, ref, c.url(ref, "blobs/"+manifest.Layers[0].Digest), manifest.Layers[0].Digest, "", maxBlobSize)
+	if err != nil {
+		return nil, fmt.Errorf("fetching artifact blob: %w", err)
+	}
+	return blob, nil
+}
+
+// Resolve returns the descriptor of the manifest referenced by ref. The
+// manifest is fetched when the registry does not send its size in the
+// response to the HEAD request.
+func (c *Client) Resolve(ctx context.Context, ref Reference) (*Descriptor, error) {
+	accept := strings.Join([]string{
+		MediaTypeImageManifest, MediaTypeImageIndex, mediaTypeDockerManifest, mediaTypeDockerManifestList,
+	}, ", ")
+	res, err := c.do(ctx, ref, http.MethodHead, c.url(ref, "manifests/"+ref.manifestRef()), accept, nil)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching manifest of %s: registry returned %s", ref, res.Status)
+	}
+
+	desc := &Descriptor{
+		MediaType: res.Header.Get("Content-Type"),
+		Digest:    res.Header.Get("Docker-Content-Digest"),
+		Size:      res.ContentLength,
+	}
+	if ref.Digest != "" {
+		desc.Digest = ref.Digest
+	}
+	if desc.Size < 0 {
+		// The registry did not send the length of the manifest, it is
+		// fetched to learn its size.
+		data, err := c.fetch(ctx, ref, c.url(ref, "manifests/"+ref.manifestRef()), desc.Digest, accept, maxManifestSize)
+		if err != nil {
+			return nil, fmt.Errorf("fetching manifest of %s: %w", ref, err)
+		}
+		desc.Size = int64(len(data))
+		if desc.Digest == "" {
+			desc.Digest = digestOf(data)
+		}
+	}
+	if desc.Digest == "" {
+		return nil, fmt.Errorf("registry did not return the digest of %s", ref)
+	}
+	return desc, nil
+}
+
+// PushBlob uploads a blob to the repository of ref, unless it already exists,
+// and returns its descriptor.
+func (c *Client) PushBlob(ctx context.Context, ref Reference, mediaType string, data []byte) (*Descriptor, error) {
+	desc := &Descriptor{MediaType: mediaType, Digest: digestOf(data), Size: int64(len(data))}
+
+	res, err := c.do(ctx, ref, http.MethodHead, c.url(ref, "blobs/"+desc.Digest), "", nil)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	if res.StatusCode == http.StatusOK {
+		return desc, nil
+	}
+
+	res, err = c.do(ctx, ref, http.MethodPost, c.url(ref, "blobs/uploads/"), "", nil)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("starting blob upload: registry returned %s", res.Status)
+	}
+
+	location, err := res.Request.URL.Parse(res.Header.Get("Location"))
+	if err != nil {
+		return nil, fmt.Errorf("parsing upload location: %w", err)
+	}
+	q := location.Query()
+	q.Set("digest", desc.Digest)
+	location.RawQuery = q.Encode()
+
+	res, err = c.do(ctx, ref, http.MethodPut, location.String(), "application/octet-stream", data)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("uploading blob: registry returned %s", res.Status)
+	}
+	return desc, nil
+}
+
+// addToFallbackIndex adds an artifact to the referrers tag of a subject for
+// registries not supporting the referrers API.
+func (c *Client) addToFallbackIndex(ctx context.Context, ref Reference, subjectDigest string, desc *Descriptor) error {
+	u := c.url(ref, "manifests/"+fallbackTag(subjectDigest))
+	index, err := c.fetchIndex(ctx, ref, u)
+	if errors.Is(err, errNotFound) {
+		index = &Index{SchemaVersion: 2, MediaType: MediaTypeImageIndex, Manifests: []Descriptor{}}
+	} else if err != nil {
+		return err
+	}
+	index.Manifests = append(index.Manifests, *desc)
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("marshaling index: %w", err)
+	}
+	res, err := c.do(ctx, ref, http.MethodPut, u, MediaTypeImageIndex, data)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return fmt.Errorf("pushing index: registry returned %s", res.Status)
+	}
+	return nil
+}
+
+var errNotFound = errors.New("not found")
+
+// fetchIndex reads an image index from the registry
+func (c *Client) fetchIndex(ctx context.Context, ref Reference, u string) (*Index, error) {
+	data, err := c.fetch(ctx, ref, u, "", MediaTypeImageIndex, maxManifestSize)
+	if err != nil {
+		return nil, err
+	}
+	index := &Index{}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("decoding index: %w", err)
+	}
+	return index, nil
+}
+
+// fetch reads up to limit bytes of content from the registry. If digest is
+// not empty, the content is verified against it.
+func (c *Client) fetch(ctx context.Context, ref Reference, u, digest, accept string, limit int64) ([]byte, error) {
+	res, err := c.do(ctx, ref, http.MethodGet, u, accept, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned %s", res.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("content is larger than %d bytes", limit)
+	}
+	if digest != "" && digestOf(data) != digest {
+		return nil, fmt.Errorf("content does not match digest %s", digest)
+	}
+	return data, nil
+}
+
+// do performs a request against the registry of ref, authenticating if
+// needed. For GET and HEAD requests accept is sent as the Accept header, for
+// the rest as the Content-Type of the body.
+func (c *Client) do(ctx context.Context, ref Reference, method, u, mediaType string, body []byte) (*http.Response, error) {
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if mediaType != "" {
+			if method == http.MethodGet || method == http.MethodHead {
+				req.Header.Set("Accept", mediaType)
+			} else {
+				req.Header.Set("Content-Type", mediaType)
+			}
+		}
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	key := tokenKey(method, req.URL)
+	if token, ok := c.token(key); ok {
+		req.Header.Set("Authorization", token)
+	}
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("performing request: %w", err)
+	}
+	if res.StatusCode != http.StatusUnauthorized {
+		return res, nil
+	}
+	res.Body.Close()
+
+	// Authenticate and retry the request
+	auth, err := c.authenticate(ctx, ref, req.URL, res.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		return nil, fmt.Errorf("authenticating to registry: %w", err)
+	}
+	c.setToken(key, auth)
+
+	req, err = newRequest()
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", auth)
+	res, err = c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("performing request: %w", err)
+	}
+	return res, nil
+}
+
+// tokenKey returns the key of the token cache for a request: the registry
+// host and the scope of the request, that is its repository and whether it
+// pulls or pushes. Requests outside a repository are keyed by host only.
+func tokenKey(method string, u *url.URL) string {
+	action := "pull"
+	if method != http.MethodGet && method != http.MethodHead {
+		action = "pull,push"
+	}
+	path := strings.TrimPrefix(u.Path, "/v2/")
+	for _, endpoint := range []string{"/manifests/", "/blobs/", "/referrers/", "/tags/"} {
+		if i := strings.LastIndex(path, endpoint); i > 0 {
+			return fmt.Sprintf("%s repository:%s:%s", u.Host, path[:i], action)
+		}
+	}
+	return u.Host
+}
+
+// token returns the cached Authorization header for a token key
+func (c *Client) token(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	token, ok := c.tokens[key]
+	return token, ok
+}
+
+// setToken caches the Authorization header for a token key
+func (c *Client) setToken(key, token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tokens == nil {
+		c.tokens = map[string]string{}
+	}
+	c.tokens[key] = token
+}
+
+// authenticate answers a WWW-Authenticate challenge sent by the server at u
+// for a request to the registry of ref and returns the value of the
+// Authorization header to send. The server may not be the registry itself,
+// such as the storage an upload is redirected to, so the credentials are only
+// sent to the servers trusted for the registry.
+func (c *Client) authenticate(ctx context.Context, ref Reference, u *url.URL, challenge string) (string, error) {
+	registry := &url.URL{Host: ref.Registry}
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if c.Username == "" {
+			return "", errors.New("registry requires credentials")
+		}
+		if !c.trustedRealm(registry, u) {
+			return "", fmt.Errorf("refusing to send credentials to %s", u.Redacted())
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", http.NoBody)
+		if err != nil {
+			return "", err
+		}
+		req.SetBasicAuth(c.Username, c.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+		return c.fetchToken(ctx, registry, parseChallengeParams(params))
+	default:
+		return "", fmt.Errorf("unsupported authentication scheme %q", scheme)
+	}
+}
+
+// fetchToken requests a bearer token from the token server of the registry
+// at u. The credentials are only sent to trusted token servers.
+func (c *Client) fetchToken(ctx context.Context, u *url.URL, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || (realm.Scheme != "https" && realm.Scheme != "http") {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+	if c.Username != "" && !c.trustedRealm(u, realm) {
+		return "", fmt.Errorf("refusing to send credentials to token realm %s", realm.Redacted())
+	}
+	q := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			q.Set(k, params[k])
+		}
+	}
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting token: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("requesting token: server returned %s", res.Status)
+	}
+
+	tr := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&tr); err != nil {
+		return "", fmt.Errorf("decoding token response: %w", err)
+	}
+	if tr.Token == "" {
+		tr.Token = tr.AccessToken
+	}
+	if tr.Token == "" {
+		return "", errors.New("token server did not return a token")
+	}
+	return "Bearer " + tr.Token, nil
+}
+
+// parseChallengeParams parses the key="value" pairs of an auth challenge
+func parseChallengeParams(s string) map[string]string {
+	params := map[string]string{}
+	for s != "" {
+		var kv string
+		// Values are quoted and may contain commas
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end == -1 {
+				break
+			}
+			kv, s = rest[1:end+1], rest[end+2:]
+		} else {
+			kv, s, _ = strings.Cut(rest, ",")
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = kv
+		s = strings.TrimLeft(s, ", ")
+	}
+	return params
+}
+
+// trustedRealm returns true if the credentials of the registry at u may be
+// sent to the token server at realm.
+func (c *Client) trustedRealm(u, realm *url.URL) bool {
+	sameHost := realm.Host == u.Host
+	if realm.Scheme != "https" {
+		return sameHost && c.PlainHTTP
+	}
+	return sameHost || slices.Contains(c.TrustedRealms, realm.Host)
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+// url returns the URL of an API endpoint in the repository of ref
+func (c *Client) url(ref Reference, path string) string {
+	scheme := "https"
+	if c.PlainHTTP {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/%s", scheme, ref.Registry, ref.Repository, path)
+}
+
+// fallbackTag returns the tag used to store the referrers of a digest in
+// registries without the referrers API.
+func fallbackTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
+
+func digestOf(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// MediaTypeDocument is the media type and artifact type of plain VEX
+	// documents attached to images
+	MediaTypeDocument = "application/openvex+json"
+
+	// AnnotationDocumentID records the @id of the document
+	AnnotationDocumentID = "dev.openvex.document.id"
+)
+
+// PushDocument attaches a VEX document to the image referenced by ref as a
+// plain artifact. Unlike AttachDocument the document is stored as is, not
+// wrapped in an attestation, so it can be pulled by any client that knows
+// the OpenVEX media type.
+func (c *Client) PushDocument(ctx context.Context, ref Reference, doc *vex.VEX) (*Descriptor, error) {
+	var b bytes.Buffer
+	if err := doc.ToJSON(&b); err != nil {
+		return nil, fmt.Errorf("marshaling document: %w", err)
+	}
+	artifact := &Artifact{
+		ArtifactType: MediaTypeDocument,
+		MediaType:    MediaTypeDocument,
+		Data:         b.Bytes(),
+	}
+	if doc.ID != "" {
+		artifact.Annotations = map[string]string{AnnotationDocumentID: doc.ID}
+	}
+	return c.Attach(ctx, ref, artifact)
+}
+
+// ListDocuments returns the descriptors of the plain VEX documents attached
+// to the image referenced by ref.
+func (c *Client) ListDocuments(ctx context.Context, ref Reference) ([]Descriptor, error) {
+	return c.Referrers(ctx, ref, MediaTypeDocument)
+}
+
+// PullDocument fetches and parses the plain VEX document described by desc.
+func (c *Client) PullDocument(ctx context.Context, ref Reference, desc *Descriptor) (*vex.VEX, error) {
+	data, err := c.FetchArtifact(ctx, ref, desc)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := vex.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing document %s: %w", desc.Digest, err)
+	}
+	return doc, nil
+}
+
+// PullDocuments returns all the plain VEX documents attached to the image
+// referenced by ref.
+func (c *Client) PullDocuments(ctx context.Context, ref Reference) ([]*vex.VEX, error) {
+	descs, err := c.ListDocuments(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]*vex.VEX, 0, len(descs))
+	for i := range descs {
+		doc, err := c.PullDocument(ctx, ref, &descs[i])
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
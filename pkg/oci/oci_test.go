@@ -0,0 +1,453 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestParseReference(t *testing.T) {
+	for s, tc := range map[string]struct {
+		expected  Reference
+		shouldErr bool
+	}{
+		"alpine":                  {expected: Reference{Registry: DefaultRegistry, Repository: "library/alpine", Tag: "latest"}},
+		"docker.io/org/image:1.0": {expected: Reference{Registry: DefaultRegistry, Repository: "org/image", Tag: "1.0"}},
+		"localhost:5000/image":    {expected: Reference{Registry: "localhost:5000", Repository: "image", Tag: "latest"}},
+		"ghcr.io/org/image@sha256:abcd": {
+			expected: Reference{Registry: "ghcr.io", Repository: "org/image", Digest: "sha256:abcd"},
+		},
+		"":                         {shouldErr: true},
+		"ghcr.io/org/image@abcd":   {shouldErr: true},
+		"ghcr.io/Org/Image:latest": {shouldErr: true},
+	} {
+		ref, err := ParseReference(s)
+		if tc.shouldErr {
+			require.Error(t, err, s)
+			continue
+		}
+		require.NoError(t, err, s)
+		require.Equal(t, tc.expected, ref, s)
+	}
+}
+
+// fakeRegistry is a minimal in-memory OCI registry
+type fakeRegistry struct {
+	mu        sync.Mutex
+	referrers bool
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	types     map[string]string
+	tags      map[string]string
+}
+
+func newFakeRegistry(referrers bool) *fakeRegistry {
+	return &fakeRegistry{
+		referrers: referrers,
+		blobs:     map[string][]byte{},
+		manifests: map[string][]byte{},
+		types:     map[string]string{},
+		tags:      map[string]string{},
+	}
+}
+
+func (r *fakeRegistry) putManifest(ref, mediaType string, data []byte) string {
+	d := digestOf(data)
+	r.manifests[d] = data
+	r.types[d] = mediaType
+	if !strings.HasPrefix(ref, "sha256:") {
+		r.tags[ref] = d
+	}
+	return d
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/test/image/")
+	kind, ref, _ := strings.Cut(path, "/")
+	switch {
+	case kind == "manifests" && req.Method == http.MethodPut:
+		data, _ := io.ReadAll(req.Body)
+		d := r.putManifest(ref, req.Header.Get("Content-Type"), data)
+		w.Header().Set("Docker-Content-Digest", d)
+		if r.referrers && strings.Contains(string(data), `"subject"`) {
+			w.Header().Set("OCI-Subject", "set")
+		}
+		w.WriteHeader(http.StatusCreated)
+	case kind == "manifests":
+		d := ref
+		if tagged, ok := r.tags[ref]; ok {
+			d = tagged
+		}
+		data, ok := r.manifests[d]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", r.types[d])
+		w.Header().Set("Docker-Content-Digest", d)
+		w.Write(data) //nolint:errcheck
+	case kind == "blobs" && ref == "uploads/" && req.Method == http.MethodPost:
+		w.Header().Set("Location", "/v2/test/image/blobs/uploads/session?state=1")
+		w.WriteHeader(http.StatusAccepted)
+	case kind == "blobs" && strings.HasPrefix(ref, "uploads/") && req.Method == http.MethodPut:
+		data, _ := io.ReadAll(req.Body)
+		if req.URL.Query().Get("state") != "1" || req.URL.Query().Get("digest") != digestOf(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.blobs[digestOf(data)] = data
+		w.WriteHeader(http.StatusCreated)
+	case kind == "blobs":
+		data, ok := r.blobs[ref]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data) //nolint:errcheck
+	case kind == "referrers" && r.referrers:
+		index := Index{SchemaVersion: 2, MediaType: MediaTypeImageIndex, Manifests: []Descriptor{}}
+		for d, data := range r.manifests {
+			m := Manifest{}
+			if err := json.Unmarshal(data, &m); err != nil || m.Subject == nil || m.Subject.Digest != ref {
+				continue
+			}
+			index.Manifests = append(index.Manifests, Descriptor{
+				MediaType: m.MediaType, Digest: d, Size: int64(len(data)),
+				ArtifactType: m.ArtifactType, Annotations: m.Annotations,
+			})
+		}
+		w.Header().Set("Content-Type", MediaTypeImageIndex)
+		json.NewEncoder(w).Encode(&index) //nolint:errcheck
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestAttachAndDiscover(t *testing.T) {
+	for name, referrers := range map[string]bool{"referrers API": true, "tag schema": false} {
+		t.Run(name, func(t *testing.T) {
+			registry := newFakeRegistry(referrers)
+			image := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json"}`)
+			imageDigest := registry.putManifest("v1", MediaTypeImageManifest, image)
+
+			srv := httptest.NewServer(registry)
+			defer srv.Close()
+			u, err := url.Parse(srv.URL)
+			require.NoError(t, err)
+
+			ref, err := ParseReference(u.Host + "/test/image:v1")
+			require.NoError(t, err)
+
+			c := NewClient()
+			c.PlainHTTP = true
+			ctx := context.Background()
+			doc, err := vex.Open("testdata/vex.json")
+			require.NoError(t, err)
+
+			// No attestations attached yet
+			atts, err := c.DiscoverAttestations(ctx, ref)
+			require.NoError(t, err)
+			require.Len(t, atts, 0)
+
+			desc, err := c.AttachDocument(ctx, ref, doc)
+			require.NoError(t, err)
+			require.Equal(t, ArtifactType, desc.ArtifactType)
+
+			if !referrers {
+				require.Contains(t, registry.tags, fallbackTag(imageDigest))
+			}
+
+			atts, err = c.DiscoverAttestations(ctx, ref)
+			require.NoError(t, err)
+			require.Len(t, atts, 1)
+			require.Equal(t, "https://openvex.dev/docs/test/oci", atts[0].Predicate.ID)
+			require.Len(t, atts[0].Subject, 1)
+			require.Equal(t, strings.TrimPrefix(imageDigest, "sha256:"), atts[0].Subject[0].Digest["sha256"])
+
+			docs, err := c.DiscoverDocuments(ctx, ref)
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			require.Equal(t, vex.StatusFixed, docs[0].Statements[0].Status)
+		})
+	}
+}
+
+func TestPushAndPullDocuments(t *testing.T) {
+	for name, referrers := range map[string]bool{"referrers API": true, "tag schema": false} {
+		t.Run(name, func(t *testing.T) {
+			registry := newFakeRegistry(referrers)
+			image := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json"}`)
+			registry.putManifest("v1", MediaTypeImageManifest, image)
+
+			srv := httptest.NewServer(registry)
+			defer srv.Close()
+			u, err := url.Parse(srv.URL)
+			require.NoError(t, err)
+
+			ref, err := ParseReference(u.Host + "/test/image:v1")
+			require.NoError(t, err)
+
+			c := NewClient()
+			c.PlainHTTP = true
+			ctx := context.Background()
+			doc, err := vex.Open("testdata/vex.json")
+			require.NoError(t, err)
+
+			docs, err := c.PullDocuments(ctx, ref)
+			require.NoError(t, err)
+			require.Len(t, docs, 0)
+
+			desc, err := c.PushDocument(ctx, ref, doc)
+			require.NoError(t, err)
+			require.Equal(t, MediaTypeDocument, desc.ArtifactType)
+			require.Equal(t, "https://openvex.dev/docs/test/oci", desc.Annotations[AnnotationDocumentID])
+
+			// Attestations are listed separately from plain documents
+			_, err = c.AttachDocument(ctx, ref, doc)
+			require.NoError(t, err)
+
+			descs, err := c.ListDocuments(ctx, ref)
+			require.NoError(t, err)
+			require.Len(t, descs, 1)
+			require.Equal(t, desc.Digest, descs[0].Digest)
+
+			docs, err = c.PullDocuments(ctx, ref)
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			require.Equal(t, "https://openvex.dev/docs/test/oci", docs[0].ID)
+			require.Equal(t, vex.StatusFixed, docs[0].Statements[0].Status)
+
+			atts, err := c.DiscoverAttestations(ctx, ref)
+			require.NoError(t, err)
+			require.Len(t, atts, 1)
+		})
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	registry := newFakeRegistry(true)
+	registry.putManifest("v1", MediaTypeImageManifest, []byte(`{}`))
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			user, pass, ok := req.BasicAuth()
+			if !ok || user != "user" || pass != "pass" || req.URL.Query().Get("scope") != "repository:test/image:pull" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"token":"secret"}`)) //nolint:errcheck
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+srv.URL+`/token",service="test",scope="repository:test/image:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		registry.ServeHTTP(w, req)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	ref, err := ParseReference(u.Host + "/test/image:v1")
+	require.NoError(t, err)
+
+	c := NewClient()
+	c.PlainHTTP = true
+	_, err = c.Resolve(context.Background(), ref)
+	require.Error(t, err)
+
+	c.Username, c.Password = "user", "pass"
+	desc, err := c.Resolve(context.Background(), ref)
+	require.NoError(t, err)
+	require.Equal(t, digestOf([]byte(`{}`)), desc.Digest)
+}
+
+func TestTokenKey(t *testing.T) {
+	for m, tc := range map[string]struct {
+		method, url, expected string
+	}{
+		"pull manifest": {http.MethodGet, "https://ghcr.io/v2/org/image/manifests/v1", "ghcr.io repository:org/image:pull"},
+		"head blob":     {http.MethodHead, "https://ghcr.io/v2/org/image/blobs/sha256:abcd", "ghcr.io repository:org/image:pull"},
+		"push manifest": {http.MethodPut, "https://ghcr.io/v2/org/image/manifests/v1", "ghcr.io repository:org/image:pull,push"},
+		"referrers":     {http.MethodGet, "https://ghcr.io/v2/org/image/referrers/sha256:abcd", "ghcr.io repository:org/image:pull"},
+		"other image":   {http.MethodGet, "https://ghcr.io/v2/org/other/manifests/v1", "ghcr.io repository:org/other:pull"},
+		"upload":        {http.MethodPut, "https://storage.example.com/upload?id=1", "storage.example.com"},
+	} {
+		u, err := url.Parse(tc.url)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, tokenKey(tc.method, u), m)
+	}
+}
+
+func TestTrustedRealm(t *testing.T) {
+	registry := &url.URL{Scheme: "https", Host: "registry.example.com"}
+	for m, tc := range map[string]struct {
+		realm     string
+		plainHTTP bool
+		trusted   []string
+		expected  bool
+	}{
+		"same host":          {realm: "https://registry.example.com/token", expected: true},
+		"cross host":         {realm: "https://auth.example.com/token", expected: false},
+		"trusted cross host": {realm: "https://auth.example.com/token", trusted: []string{"auth.example.com"}, expected: true},
+		"plain http":         {realm: "http://registry.example.com/token", expected: false},
+		"plain http opt in":  {realm: "http://registry.example.com/token", plainHTTP: true, expected: true},
+		"plain http trusted": {realm: "http://auth.example.com/token", plainHTTP: true, trusted: []string{"auth.example.com"}, expected: false},
+	} {
+		realm, err := url.Parse(tc.realm)
+		require.NoError(t, err)
+		c := &Client{PlainHTTP: tc.plainHTTP, TrustedRealms: tc.trusted}
+		require.Equal(t, tc.expected, c.trustedRealm(registry, realm), m)
+	}
+}
+
+func TestAuthenticationUntrustedRealm(t *testing.T) {
+	tokenRequested := false
+	authSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tokenRequested = true
+		w.Write([]byte(`{"token":"secret"}`)) //nolint:errcheck
+	}))
+	defer authSrv.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="`+authSrv.URL+`/token",service="test",scope="repository:test/image:pull"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	ref, err := ParseReference(u.Host + "/test/image:v1")
+	require.NoError(t, err)
+
+	c := NewClient()
+	c.PlainHTTP = true
+	c.Username, c.Password = "user", "pass"
+	_, err = c.Resolve(context.Background(), ref)
+	require.ErrorContains(t, err, "refusing to send credentials")
+	require.False(t, tokenRequested)
+}
+
+func TestFetchLargeArtifact(t *testing.T) {
+	registry := newFakeRegistry(true)
+	registry.putManifest("v1", MediaTypeImageManifest, []byte(`{}`))
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	ref, err := ParseReference(u.Host + "/test/image:v1")
+	require.NoError(t, err)
+
+	// Blobs are not limited to the size of manifests
+	c := NewClient()
+	c.PlainHTTP = true
+	data := []byte(strings.Repeat("a", maxManifestSize+1))
+	desc, err := c.Attach(context.Background(), ref, &Artifact{
+		ArtifactType: ArtifactType, MediaType: "application/json", Data: data,
+	})
+	require.NoError(t, err)
+	blob, err := c.FetchArtifact(context.Background(), ref, desc)
+	require.NoError(t, err)
+	require.Equal(t, data, blob)
+}
+
+func TestBasicAuthenticationForeignHost(t *testing.T) {
+	credentialsSent := false
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, _, ok := req.BasicAuth(); ok {
+			credentialsSent = true
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="storage"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer storage.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if user, pass, ok := req.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.Method == http.MethodPost {
+			w.Header().Set("Location", storage.URL+"/upload?state=1")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	ref, err := ParseReference(u.Host + "/test/image:v1")
+	require.NoError(t, err)
+
+	// The registry gets the credentials, the upload location does not
+	c := NewClient()
+	c.PlainHTTP = true
+	c.Username, c.Password = "user", "pass"
+	_, err = c.PushBlob(context.Background(), ref, "application/json", []byte(`{}`))
+	require.ErrorContains(t, err, "refusing to send credentials")
+	require.False(t, credentialsSent)
+}
+
+// noLengthTransport removes the length of the responses to HEAD requests, as
+// some registries do not send it.
+type noLengthTransport struct{}
+
+func (noLengthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil && req.Method == http.MethodHead {
+		res.Header.Del("Content-Length")
+		res.ContentLength = -1
+	}
+	return res, err
+}
+
+func TestResolveUnknownSize(t *testing.T) {
+	registry := newFakeRegistry(true)
+	manifest := []byte(`{"schemaVersion": 2}`)
+	d := registry.putManifest("v1", MediaTypeImageManifest, manifest)
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	c := NewClient()
+	c.PlainHTTP = true
+	c.HTTPClient = &http.Client{Transport: noLengthTransport{}}
+	for _, s := range []string{"/test/image:v1", "/test/image@" + d} {
+		ref, err := ParseReference(u.Host + s)
+		require.NoError(t, err)
+		desc, err := c.Resolve(context.Background(), ref)
+		require.NoError(t, err)
+		require.Equal(t, d, desc.Digest)
+		require.Equal(t, int64(len(manifest)), desc.Size)
+		require.Equal(t, MediaTypeImageManifest, desc.MediaType)
+	}
+
+	// The fetched manifest must match the digest
+	registry.manifests[d] = []byte(`{}`)
+	ref, err := ParseReference(u.Host + "/test/image:v1")
+	require.NoError(t, err)
+	_, err = c.Resolve(context.Background(), ref)
+	require.Error(t, err)
+}
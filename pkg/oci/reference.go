@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package oci attaches VEX attestations to container images as OCI
+// referrer artifacts and discovers the attestations attached to an image.
+package oci
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultRegistry is the registry used for references without one
+	DefaultRegistry = "index.docker.io"
+
+	dockerHub = "docker.io"
+)
+
+var digestRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-zA-Z0-9=_-]+$`)
+
+// Reference points to an image in a registry, either by tag or by digest.
+type Reference struct {
+	// Registry is the hostname (and optional port) of the registry
+	Registry string
+
+	// Repository is the path of the image in the registry
+	Repository string
+
+	// Tag is the image tag, empty when the reference has a digest
+	Tag string
+
+	// Digest is the digest of the image manifest
+	Digest string
+}
+
+// ParseReference parses an image reference string such as
+// ghcr.io/org/image@sha256:... or alpine:3.18. References without a
+// registry default to Docker Hub and references without a tag or digest
+// default to the latest tag.
+func ParseReference(s string) (Reference, error) {
+	ref := Reference{}
+	if s == "" {
+		return ref, fmt.Errorf("empty image reference")
+	}
+
+	name := s
+	if n, d, ok := strings.Cut(s, "@"); ok {
+		if !digestRegexp.MatchString(d) {
+			return ref, fmt.Errorf("invalid digest %q in reference", d)
+		}
+		name, ref.Digest = n, d
+	}
+
+	// A colon after the last slash separates the tag
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	first, rest, ok := strings.Cut(name, "/")
+	if ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, ref.Repository = first, rest
+	} else {
+		ref.Registry, ref.Repository = DefaultRegistry, name
+	}
+	if ref.Registry == dockerHub {
+		ref.Registry = DefaultRegistry
+	}
+	if ref.Registry == DefaultRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+
+	if ref.Repository == "" || ref.Repository != strings.ToLower(ref.Repository) {
+		return ref, fmt.Errorf("invalid repository name in reference %q", s)
+	}
+	return ref, nil
+}
+
+// String returns the reference in its canonical string form
+func (ref Reference) String() string {
+	s := ref.Registry + "/" + ref.Repository
+	if ref.Digest != "" {
+		return s + "@" + ref.Digest
+	}
+	return s + ":" + ref.Tag
+}
+
+// manifestRef returns the tag or digest used to fetch the manifest
+func (ref Reference) manifestRef() string {
+	if ref.Digest != "" {
+		return ref.Digest
+	}
+	return ref.Tag
+}
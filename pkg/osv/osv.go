@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package osv resolves vulnerability aliases with the OSV.dev API, so CVE,
+// GHSA and ecosystem identifiers such as Go vulnerability IDs can be used
+// interchangeably when matching VEX statements:
+//
+//	resolved, err := doc.ResolveAliases(ctx, osv.NewClient())
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// DefaultURL is the base URL of the OSV.dev API
+	DefaultURL = "https://api.osv.dev"
+
+	// maxResponseSize limits the size of the responses read
+	maxResponseSize = 8 << 20
+)
+
+var _ vex.AliasResolver = (*Client)(nil)
+
+// Client looks up vulnerabilities in the OSV.dev API. It implements
+// vex.AliasResolver and keeps the aliases it resolved.
+type Client struct {
+	// URL is the base URL of the API
+	URL string
+
+	// HTTPClient is used to perform requests
+	HTTPClient *http.Client
+
+	mu      sync.Mutex
+	aliases map[string][]string
+}
+
+// NewClient returns a client of the OSV.dev API.
+func NewClient() *Client {
+	return &Client{URL: DefaultURL, HTTPClient: http.DefaultClient}
+}
+
+// Aliases returns the aliases OSV.dev lists for the vulnerability. Unknown
+// vulnerabilities have none.
+func (c *Client) Aliases(ctx context.Context, id string) ([]string, error) {
+	c.mu.Lock()
+	if aliases, ok := c.aliases[id]; ok {
+		c.mu.Unlock()
+		return aliases, nil
+	}
+	c.mu.Unlock()
+
+	aliases, err := c.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.aliases == nil {
+		c.aliases = map[string][]string{}
+	}
+	c.aliases[id] = aliases
+	return aliases, nil
+}
+
+// vulnerability is the part of an OSV record read
+type vulnerability struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+}
+
+func (c *Client) fetch(ctx context.Context, id string) ([]string, error) {
+	base := c.URL
+	if base == "" {
+		base = DefaultURL
+	}
+	u := strings.TrimSuffix(base, "/") + "/v1/vulns/" + url.PathEscape(id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", id, err)
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return []string{}, nil
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024)) //nolint:errcheck
+		return nil, fmt.Errorf("osv returned %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	v := vulnerability{}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(&v); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", id, err)
+	}
+	aliases := []string{}
+	// Looking up an alias returns the record it is an alias of
+	if v.ID != "" && v.ID != id {
+		aliases = append(aliases, v.ID)
+	}
+	for _, a := range v.Aliases {
+		if a != id {
+			aliases = append(aliases, a)
+		}
+	}
+	return aliases, nil
+}
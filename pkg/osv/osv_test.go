@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestAliases(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/v1/vulns/GO-2021-0001":
+			w.Write([]byte(`{"id": "GO-2021-0001", "aliases": ["CVE-2021-44228", "GHSA-jfh8-c2jp-5v3q"]}`)) //nolint:errcheck
+		case "/v1/vulns/CVE-2021-44228":
+			w.Write([]byte(`{"id": "GHSA-jfh8-c2jp-5v3q", "aliases": ["CVE-2021-44228"]}`)) //nolint:errcheck
+		case "/v1/vulns/CVE-2000-0001":
+			http.Error(w, `{"code": 5, "message": "Bug not found."}`, http.StatusNotFound)
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.URL = srv.URL
+	ctx := context.Background()
+
+	aliases, err := c.Aliases(ctx, "GO-2021-0001")
+	require.NoError(t, err)
+	require.Equal(t, []string{"CVE-2021-44228", "GHSA-jfh8-c2jp-5v3q"}, aliases)
+
+	aliases, err = c.Aliases(ctx, "CVE-2021-44228")
+	require.NoError(t, err)
+	require.Equal(t, []string{"GHSA-jfh8-c2jp-5v3q"}, aliases)
+
+	aliases, err = c.Aliases(ctx, "CVE-2000-0001")
+	require.NoError(t, err)
+	require.Empty(t, aliases)
+
+	_, err = c.Aliases(ctx, "CVE-2000-0002")
+	require.Error(t, err)
+
+	// Resolved aliases are kept
+	_, err = c.Aliases(ctx, "GO-2021-0001")
+	require.NoError(t, err)
+	require.Equal(t, 4, requests)
+
+	doc := vex.New()
+	doc.Statements = []vex.Statement{{
+		Vulnerability: vex.Vulnerability{Name: "GO-2021-0001"},
+		Products:      []vex.Product{{Component: vex.Component{ID: "pkg:oci/app"}}},
+		Status:        vex.StatusFixed,
+	}}
+	resolved, err := doc.ResolveAliases(ctx, c)
+	require.NoError(t, err)
+	require.NotNil(t, resolved.EffectiveStatement("pkg:oci/app", "CVE-2021-44228"))
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package rekor records OpenVEX document signatures in a Rekor transparency
+// log and verifies that recorded signatures are included in the log.
+package rekor
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// DefaultURL is the URL of the public Rekor instance
+const DefaultURL = "https://rekor.sigstore.dev"
+
+const entriesPath = "/api/v1/log/entries"
+
+// Client talks to a Rekor server.
+type Client struct {
+	// URL is the base URL of the Rekor server
+	URL string
+
+	// HTTPClient is used to perform requests
+	HTTPClient *http.Client
+
+	// LogPublicKey is the public key of the log, used to check the signed
+	// entry timestamps of its entries. It is required to verify entries.
+	LogPublicKey crypto.PublicKey
+}
+
+// NewClient returns a client for the public Rekor instance. Set the public
+// key of the log before verifying entries.
+func NewClient() *Client {
+	return &Client{URL: DefaultURL, HTTPClient: http.DefaultClient}
+}
+
+// ParsePublicKey parses a PEM encoded public key, such as the one of a log.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// rekordEntry is a rekord v0.0.1 proposed entry
+type rekordEntry struct {
+	APIVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Spec       rekordSpec `json:"spec"`
+}
+
+type rekordSpec struct {
+	Signature struct {
+		Format    string `json:"format"`
+		Content   string `json:"content"`
+		PublicKey struct {
+			Content string `json:"content"`
+		} `json:"publicKey"`
+	} `json:"signature"`
+	Data struct {
+		Content string `json:"content,omitempty"`
+		Hash    *struct {
+			Algorithm string `json:"algorithm"`
+			Value     string `json:"value"`
+		} `json:"hash,omitempty"`
+	} `json:"data"`
+}
+
+// logEntry is an entry as returned by the Rekor API
+type logEntry struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+	Verification   *struct {
+		InclusionProof *struct {
+			LogIndex int64    `json:"logIndex"`
+			TreeSize int64    `json:"treeSize"`
+			RootHash string   `json:"rootHash"`
+			Hashes   []string `json:"hashes"`
+		} `json:"inclusionProof"`
+		SignedEntryTimestamp string `json:"signedEntryTimestamp"`
+	} `json:"verification"`
+}
+
+// Upload records a document signature in the log and returns the new entry.
+// publicKey is the PEM encoded public key of the signer.
+func (c *Client) Upload(ctx context.Context, sig *vex.DocumentSignature, publicKey []byte) (*vex.TransparencyLogEntry, error) {
+	entry := rekordEntry{APIVersion: "0.0.1", Kind: "rekord"}
+	entry.Spec.Signature.Format = "x509"
+	entry.Spec.Signature.Content = base64.StdEncoding.EncodeToString(sig.Signature)
+	entry.Spec.Signature.PublicKey.Content = base64.StdEncoding.EncodeToString(publicKey)
+	entry.Spec.Data.Content = base64.StdEncoding.EncodeToString(sig.SignedData())
+
+	data, err := json.Marshal(&entry)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling log entry: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(entriesPath), bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.doEntryRequest(req, http.StatusCreated)
+}
+
+// GetEntry fetches the entry at logIndex from the log.
+func (c *Client) GetEntry(ctx context.Context, logIndex int64) (*vex.TransparencyLogEntry, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, fmt.Sprintf("%s?logIndex=%d", c.url(entriesPath), logIndex), http.NoBody,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	return c.doEntryRequest(req, http.StatusOK)
+}
+
+// Record uploads a document signature to the log and appends the resulting
+// entry to the transparency log entries in the document metadata.
+func (c *Client) Record(ctx context.Context, doc *vex.VEX, sig *vex.DocumentSignature, publicKey []byte) (*vex.TransparencyLogEntry, error) {
+	entry, err := c.Upload(ctx, sig, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	doc.TransparencyLog = append(doc.TransparencyLog, *entry)
+	return entry, nil
+}
+
+// Verify checks that the document signature, made with the PEM encoded
+// publicKey, covers the document and is recorded in the log. The entries
+// stored in the document metadata are looked up in the log, their content
+// compared to the signature and their inclusion verified with the log key.
+func (c *Client) Verify(ctx context.Context, doc *vex.VEX, sig *vex.DocumentSignature, publicKey []byte) error {
+	if c.LogPublicKey == nil {
+		return errors.New("the log public key is required to verify entries")
+	}
+	hash, err := doc.SigningHash()
+	if err != nil {
+		return fmt.Errorf("hashing document: %w", err)
+	}
+	if sig.DocumentHash != hash {
+		return errors.New("signature does not cover the document")
+	}
+	if len(doc.TransparencyLog) == 0 {
+		return errors.New("document has no transparency log entries")
+	}
+	errs := []error{}
+	for i := range doc.TransparencyLog {
+		logged, err := c.GetEntry(ctx, doc.TransparencyLog[i].LogIndex)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", doc.TransparencyLog[i].LogIndex, err))
+			continue
+		}
+		if doc.TransparencyLog[i].Body != "" && doc.TransparencyLog[i].Body != logged.Body {
+			errs = append(errs, fmt.Errorf("entry %d: recorded body does not match the log", logged.LogIndex))
+			continue
+		}
+		if err := VerifyEntry(logged, sig, publicKey, c.LogPublicKey); err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", logged.LogIndex, err))
+			continue
+		}
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// VerifyEntry checks offline that a log entry records the signature made
+// with the PEM encoded publicKey and that it is included in the log of
+// logKey.
+func VerifyEntry(entry *vex.TransparencyLogEntry, sig *vex.DocumentSignature, publicKey []byte, logKey crypto.PublicKey) error {
+	body, err := base64.StdEncoding.DecodeString(entry.Body)
+	if err != nil {
+		return fmt.Errorf("decoding entry body: %w", err)
+	}
+	rekord := rekordEntry{}
+	if err := json.Unmarshal(body, &rekord); err != nil {
+		return fmt.Errorf("unmarshaling entry body: %w", err)
+	}
+	if rekord.Kind != "rekord" {
+		return fmt.Errorf("unsupported entry kind %q", rekord.Kind)
+	}
+
+	// The log replaces the data content with its hash
+	digest := sha256.Sum256(sig.SignedData())
+	switch {
+	case rekord.Spec.Data.Hash != nil:
+		if rekord.Spec.Data.Hash.Algorithm != "sha256" || rekord.Spec.Data.Hash.Value != hex.EncodeToString(digest[:]) {
+			return errors.New("entry does not record the document hash")
+		}
+	case rekord.Spec.Data.Content != base64.StdEncoding.EncodeToString(sig.SignedData()):
+		return errors.New("entry does not record the document hash")
+	}
+	if rekord.Spec.Signature.Content != base64.StdEncoding.EncodeToString(sig.Signature) {
+		return errors.New("entry does not record the document signature")
+	}
+	if err := sameKey(rekord.Spec.Signature.PublicKey.Content, publicKey); err != nil {
+		return err
+	}
+	return entry.VerifyInclusion(logKey)
+}
+
+// sameKey checks that the base64 encoded PEM key recorded in an entry is the
+// PEM encoded key of the signer.
+func sameKey(recorded string, publicKey []byte) error {
+	data, err := base64.StdEncoding.DecodeString(recorded)
+	if err != nil {
+		return fmt.Errorf("decoding entry public key: %w", err)
+	}
+	entryKey, _ := pem.Decode(data)
+	signerKey, _ := pem.Decode(publicKey)
+	if entryKey == nil || signerKey == nil || !bytes.Equal(entryKey.Bytes, signerKey.Bytes) {
+		return errors.New("entry was not signed by the signer's key")
+	}
+	return nil
+}
+
+// doEntryRequest performs a request returning log entries and returns the
+// first one.
+func (c *Client) doEntryRequest(req *http.Request, expected int) (*vex.TransparencyLogEntry, error) {
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("performing request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != expected {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024)) //nolint:errcheck
+		return nil, fmt.Errorf("rekor returned %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	entries := map[string]logEntry{}
+	if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decoding rekor response: %w", err)
+	}
+	for uuid, e := range entries {
+		entry := &vex.TransparencyLogEntry{
+			LogIndex:       e.LogIndex,
+			LogID:          e.LogID,
+			UUID:           uuid,
+			IntegratedTime: e.IntegratedTime,
+			Body:           e.Body,
+		}
+		if e.Verification != nil {
+			entry.SignedEntryTimestamp = e.Verification.SignedEntryTimestamp
+		}
+		if e.Verification != nil && e.Verification.InclusionProof != nil {
+			p := e.Verification.InclusionProof
+			entry.InclusionProof = &vex.InclusionProof{
+				LogIndex: p.LogIndex, TreeSize: p.TreeSize, RootHash: p.RootHash, Hashes: p.Hashes,
+			}
+		}
+		return entry, nil
+	}
+	return nil, errors.New("rekor returned no entries")
+}
+
+func (c *Client) url(path string) string {
+	base := c.URL
+	if base == "" {
+		base = DefaultURL
+	}
+	return strings.TrimSuffix(base, "/") + path
+}
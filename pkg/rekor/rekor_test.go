@@ -0,0 +1,280 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rekor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// fakeRekor is an in-memory log returning RFC 6962 inclusion proofs and
+// signed entry timestamps
+type fakeRekor struct {
+	mu     sync.Mutex
+	key    *ecdsa.PrivateKey
+	bodies [][]byte
+}
+
+func newFakeRekor(t *testing.T) *fakeRekor {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &fakeRekor{key: key}
+}
+
+func (f *fakeRekor) logID() string {
+	der, err := x509.MarshalPKIXPublicKey(&f.key.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	id := sha256.Sum256(der)
+	return hex.EncodeToString(id[:])
+}
+
+// signedEntryTimestamp signs the canonical JSON of the entry fields
+func (f *fakeRekor) signedEntryTimestamp(body string, i int) string {
+	payload := fmt.Sprintf(`{"body":%q,"integratedTime":1700000000,"logID":%q,"logIndex":%d}`, body, f.logID(), i)
+	digest := sha256.Sum256([]byte(payload))
+	sig, err := ecdsa.SignASN1(rand.Reader, f.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func leafHash(data []byte) []byte {
+	h := sha256.Sum256(append([]byte{0}, data...))
+	return h[:]
+}
+
+func nodeHash(l, r []byte) []byte {
+	h := sha256.Sum256(append(append([]byte{1}, l...), r...))
+	return h[:]
+}
+
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+func treeHash(leaves [][]byte) []byte {
+	if len(leaves) == 1 {
+		return leafHash(leaves[0])
+	}
+	k := splitPoint(len(leaves))
+	return nodeHash(treeHash(leaves[:k]), treeHash(leaves[k:]))
+}
+
+func auditPath(m int, leaves [][]byte) [][]byte {
+	if len(leaves) == 1 {
+		return nil
+	}
+	k := splitPoint(len(leaves))
+	if m < k {
+		return append(auditPath(m, leaves[:k]), treeHash(leaves[k:]))
+	}
+	return append(auditPath(m-k, leaves[k:]), treeHash(leaves[:k]))
+}
+
+func (f *fakeRekor) entry(i int) map[string]any {
+	hashes := []string{}
+	for _, h := range auditPath(i, f.bodies) {
+		hashes = append(hashes, hex.EncodeToString(h))
+	}
+	body := base64.StdEncoding.EncodeToString(f.bodies[i])
+	return map[string]any{
+		fmt.Sprintf("uuid-%d", i): map[string]any{
+			"body":           body,
+			"integratedTime": 1700000000,
+			"logID":          f.logID(),
+			"logIndex":       i,
+			"verification": map[string]any{
+				"signedEntryTimestamp": f.signedEntryTimestamp(body, i),
+				"inclusionProof": map[string]any{
+					"logIndex": i,
+					"treeSize": len(f.bodies),
+					"rootHash": hex.EncodeToString(treeHash(f.bodies)),
+					"hashes":   hashes,
+				},
+			},
+		},
+	}
+}
+
+func (f *fakeRekor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPost:
+		proposed := rekordEntry{}
+		if err := json.NewDecoder(r.Body).Decode(&proposed); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Like Rekor, store the hash of the data instead of its content
+		data, err := base64.StdEncoding.DecodeString(proposed.Spec.Data.Content)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		digest := sha256.Sum256(data)
+		proposed.Spec.Data.Content = ""
+		proposed.Spec.Data.Hash = &struct {
+			Algorithm string `json:"algorithm"`
+			Value     string `json:"value"`
+		}{"sha256", hex.EncodeToString(digest[:])}
+		body, err := json.Marshal(&proposed)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		f.bodies = append(f.bodies, body)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(f.entry(len(f.bodies) - 1)) //nolint:errcheck
+	case http.MethodGet:
+		i, err := strconv.Atoi(r.URL.Query().Get("logIndex"))
+		if err != nil || i < 0 || i >= len(f.bodies) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(f.entry(i)) //nolint:errcheck
+	}
+}
+
+func TestRecordAndVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	log := newFakeRekor(t)
+	srv := httptest.NewServer(log)
+	defer srv.Close()
+	c := &Client{URL: srv.URL, HTTPClient: srv.Client(), LogPublicKey: &log.key.PublicKey}
+	ctx := context.Background()
+
+	doc := vex.New()
+	doc.ID = "https://openvex.dev/docs/test/rekor"
+	doc.Statements = []vex.Statement{{
+		Vulnerability: vex.Vulnerability{Name: "CVE-2023-1234"},
+		Products:      []vex.Product{{Component: vex.Component{ID: "pkg:oci/image"}}},
+		Status:        vex.StatusFixed,
+	}}
+	sig, err := doc.Sign(vex.NewED25519Signer("key", priv))
+	require.NoError(t, err)
+
+	// Verification fails before the signature is recorded
+	require.Error(t, c.Verify(ctx, &doc, sig, pemKey))
+
+	// Fill the log with other entries to get non trivial proofs
+	for i := 0; i < 4; i++ {
+		other := vex.New()
+		other.Author = fmt.Sprintf("other %d", i)
+		otherSig, err := other.Sign(vex.NewED25519Signer("key", priv))
+		require.NoError(t, err)
+		_, err = c.Upload(ctx, otherSig, pemKey)
+		require.NoError(t, err)
+	}
+
+	entry, err := c.Record(ctx, &doc, sig, pemKey)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), entry.LogIndex)
+	require.Len(t, doc.TransparencyLog, 1)
+	require.NoError(t, entry.VerifyInclusion(&log.key.PublicKey))
+
+	// More entries grow the tree, the proof fetched from the log still verifies
+	for i := 0; i < 3; i++ {
+		_, err = c.Upload(ctx, sig, pemKey)
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.Verify(ctx, &doc, sig, pemKey))
+	require.NoError(t, vex.Validate(mustJSON(t, &doc)))
+
+	// A signature over a different document is not covered by the entry
+	doc2 := vex.New()
+	doc2.Author = "Someone else"
+	sig2, err := doc2.Sign(vex.NewED25519Signer("key", priv))
+	require.NoError(t, err)
+	require.Error(t, c.Verify(ctx, &doc, sig2, pemKey))
+
+	// The logged signature does not cover an edited document
+	edited := doc.Clone()
+	edited.Statements[0].ActionStatement = "Update"
+	require.ErrorContains(t, c.Verify(ctx, edited, sig, pemKey), "does not cover")
+
+	// The entry must be signed by the signer's key
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(otherPub)
+	require.NoError(t, err)
+	otherKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.ErrorContains(t, c.Verify(ctx, &doc, sig, otherKey), "signer's key")
+
+	// Entries must be verified with the key of the log
+	otherLog := newFakeRekor(t)
+	require.Error(t, VerifyEntry(entry, sig, pemKey, &otherLog.key.PublicKey))
+	require.Error(t, (&Client{URL: srv.URL}).Verify(ctx, &doc, sig, pemKey))
+
+	// Tampered proofs and signed entry timestamps fail
+	tampered := entry.Clone()
+	tampered.InclusionProof.Hashes[0] = hex.EncodeToString(make([]byte, 32))
+	require.Error(t, VerifyEntry(&tampered, sig, pemKey, &log.key.PublicKey))
+
+	tampered = entry.Clone()
+	tampered.IntegratedTime++
+	require.Error(t, VerifyEntry(&tampered, sig, pemKey, &log.key.PublicKey))
+
+	tampered = entry.Clone()
+	tampered.SignedEntryTimestamp = ""
+	require.Error(t, VerifyEntry(&tampered, sig, pemKey, &log.key.PublicKey))
+
+	// Entries without an inclusion proof are not verified
+	tampered = entry.Clone()
+	tampered.InclusionProof = nil
+	require.ErrorContains(t, VerifyEntry(&tampered, sig, pemKey, &log.key.PublicKey), "inclusion proof")
+}
+
+func TestParsePublicKey(t *testing.T) {
+	log := newFakeRekor(t)
+	der, err := x509.MarshalPKIXPublicKey(&log.key.PublicKey)
+	require.NoError(t, err)
+
+	key, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	require.True(t, log.key.PublicKey.Equal(key))
+
+	_, err = ParsePublicKey([]byte("not a key"))
+	require.Error(t, err)
+}
+
+func mustJSON(t *testing.T, doc *vex.VEX) []byte {
+	t.Helper()
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/openvex/go-vex/internal/fsutil"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// GitRepo is a repository kept in a git working tree. Every document added
+// is committed with the index, so the git history of a document file
+// follows the versions of the document, and releases of the repository are
+// recorded as tags.
+//
+// GitRepo uses go-git, so git does not need to be installed, and hooks or
+// settings such as commit signing in the git configuration do not apply.
+type GitRepo struct {
+	*Repo
+
+	// Name and Email identify the committer. If empty, the user set in the
+	// git configuration is used.
+	Name  string
+	Email string
+
+	git *git.Repository
+}
+
+// OpenGit opens the repository in dir, initializing a git repository there
+// if there is none.
+func OpenGit(dir string) (*GitRepo, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating repository: %w", err)
+	}
+	g, err := git.PlainOpen(dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		g, err = git.PlainInit(dir, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening git repository: %w", err)
+	}
+	repo, err := Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepo{Repo: repo, git: g}, nil
+}
+
+// Add writes the document, updates the index and commits both. Versions
+// must increase: adding a document whose version is not greater than the
+// one in the repository is an error, which keeps the document versions in
+// step with the git history. If the commit fails, the files and the git
+// staging area are restored.
+func (r *GitRepo) Add(doc *vex.VEX) (p string, err error) {
+	if doc.ID == "" {
+		if _, err := doc.GenerateCanonicalID(); err != nil {
+			return "", fmt.Errorf("generating document ID: %w", err)
+		}
+	}
+	current, err := r.current(doc.ID)
+	if err != nil {
+		return "", err
+	}
+	if current != nil && doc.Version <= current.Version {
+		return "", fmt.Errorf(
+			"document %s version %d is not newer than version %d in the repository",
+			doc.ID, doc.Version, current.Version,
+		)
+	}
+
+	wt, err := r.git.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("opening git worktree: %w", err)
+	}
+	restore, err := r.snapshot(doc)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, restore())
+		}
+	}()
+
+	p, err = r.Repo.Add(doc)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []string{p, IndexFile} {
+		if _, err := wt.Add(name); err != nil {
+			return "", fmt.Errorf("staging %s: %w", name, err)
+		}
+	}
+	msg := fmt.Sprintf("Add %s version %d", doc.ID, doc.Version)
+	if _, err := wt.Commit(msg, &git.CommitOptions{Author: r.signature()}); err != nil {
+		return "", fmt.Errorf("committing %s: %w", p, err)
+	}
+	return p, nil
+}
+
+// Tag records a release of the repository at the current commit.
+func (r *GitRepo) Tag(name, message string) error {
+	if err := plumbing.NewTagReferenceName(name).Validate(); err != nil {
+		return fmt.Errorf("invalid tag name %q: %w", name, err)
+	}
+	if message == "" {
+		message = name
+	}
+	head, err := r.git.Head()
+	if err != nil {
+		return fmt.Errorf("resolving HEAD: %w", err)
+	}
+	if _, err := r.git.CreateTag(name, head.Hash(), &git.CreateTagOptions{
+		Tagger: r.signature(), Message: message,
+	}); err != nil {
+		return fmt.Errorf("creating tag %s: %w", name, err)
+	}
+	return nil
+}
+
+// History returns the committed versions of a document, from the newest to
+// the oldest.
+func (r *GitRepo) History(id string) ([]*vex.VEX, error) {
+	if _, err := r.git.Head(); errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return []*vex.VEX{}, nil
+	}
+	p := DocumentPath(id)
+	iter, err := r.git.Log(&git.LogOptions{FileName: &p})
+	if err != nil {
+		return nil, fmt.Errorf("reading git log: %w", err)
+	}
+	defer iter.Close()
+
+	docs := []*vex.VEX{}
+	err = iter.ForEach(func(c *object.Commit) error {
+		doc, err := documentAt(c, p)
+		if err != nil {
+			return fmt.Errorf("commit %s: %w", c.Hash, err)
+		}
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Rollback undoes changes to a document by adding a new version of it with
+// the statements of an earlier version. The new version is committed and
+// returned, see vex.VEX.Rollback.
+func (r *GitRepo) Rollback(id string, version int) (*vex.VEX, error) {
+	history, err := r.History(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("document %s is not in the repository", id)
+	}
+	for _, doc := range history[1:] {
+		if doc.Version != version {
+			continue
+		}
+		next, err := history[0].Rollback(doc)
+		if err != nil {
+			return nil, fmt.Errorf("rolling back %s: %w", id, err)
+		}
+		if _, err := r.Add(next); err != nil {
+			return nil, err
+		}
+		return next, nil
+	}
+	return nil, fmt.Errorf("document %s has no earlier version %d", id, version)
+}
+
+// DocumentAt returns a document as it was at a revision, such as a commit
+// or a release tag.
+func (r *GitRepo) DocumentAt(id, rev string) (*vex.VEX, error) {
+	hash, err := r.git.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("resolving revision %q: %w", rev, err)
+	}
+	c, err := r.git.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("reading commit %s: %w", hash, err)
+	}
+	return documentAt(c, DocumentPath(id))
+}
+
+// documentAt parses the document at path p in a commit.
+func documentAt(c *object.Commit, p string) (*vex.VEX, error) {
+	f, err := c.File(p)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", p, err)
+	}
+	data, err := f.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", p, err)
+	}
+	return vex.Parse([]byte(data))
+}
+
+// current returns the document with an @id in the working tree or nil.
+func (r *GitRepo) current(id string) (*vex.VEX, error) {
+	doc, err := vex.Load(filepath.Join(r.Dir, filepath.FromSlash(DocumentPath(id))))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// signature returns the committer configured in the repository or nil to
+// use the one in the git configuration.
+func (r *GitRepo) signature() *object.Signature {
+	if r.Name == "" && r.Email == "" {
+		return nil
+	}
+	return &object.Signature{Name: r.Name, Email: r.Email, When: time.Now()}
+}
+
+// snapshot records the files and the git staging area changed when adding
+// a document and returns a function restoring them.
+func (r *GitRepo) snapshot(doc *vex.VEX) (func() error, error) {
+	stage, err := r.git.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("reading git index: %w", err)
+	}
+	files := []string{
+		filepath.Join(r.Dir, IndexFile),
+		filepath.Join(r.Dir, filepath.FromSlash(DocumentPath(doc.ID))),
+	}
+	contents := map[string][]byte{}
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		default:
+			contents[name] = data
+		}
+	}
+
+	return func() error {
+		errs := []error{}
+		for _, name := range files {
+			if data, ok := contents[name]; ok {
+				errs = append(errs, fsutil.WriteFile(name, data, 0o644))
+			} else if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+		errs = append(errs, r.git.Storer.SetIndex(stage))
+		repo, err := Open(r.Dir)
+		if err == nil {
+			r.Repo = repo
+		}
+		errs = append(errs, err)
+		if err := errors.Join(errs...); err != nil {
+			return fmt.Errorf("restoring repository: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestGitRepo(t *testing.T) {
+	// The committer is not read from the user's git configuration
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	r, err := OpenGit(dir)
+	require.NoError(t, err)
+	r.Name, r.Email = "Test", "test@example.com"
+
+	doc, err := vex.Open("testdata/app-1.vex.json")
+	require.NoError(t, err)
+	doc.Statements[0].Status = vex.StatusUnderInvestigation
+	_, err = r.Add(doc)
+	require.NoError(t, err)
+	require.NoError(t, r.Tag("v1", ""))
+
+	// Invalid tag names and revisions are rejected
+	require.Error(t, r.Tag("--delete", ""))
+	require.Error(t, r.Tag("v1..2", ""))
+	_, err = r.DocumentAt("https://example.com/vex/1", "--output=/tmp/vex")
+	require.Error(t, err)
+
+	// Versions must increase
+	doc, err = vex.Open("testdata/app-1.vex.json")
+	require.NoError(t, err)
+	_, err = r.Add(doc)
+	require.Error(t, err)
+	doc.Version = 2
+	_, err = r.Add(doc)
+	require.NoError(t, err)
+
+	history, err := r.History("https://example.com/vex/1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, 2, history[0].Version)
+	require.Equal(t, 1, history[1].Version)
+
+	old, err := r.DocumentAt("https://example.com/vex/1", "v1")
+	require.NoError(t, err)
+	require.Equal(t, vex.StatusUnderInvestigation, old.Statements[0].Status)
+
+	// The working tree is clean and reopening keeps the index
+	requireClean(t, r)
+
+	r, err = OpenGit(dir)
+	require.NoError(t, err)
+	docs, err := r.Lookup("pkg:oci/app", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, vex.StatusFixed, docs[0].Statements[0].Status)
+
+	// Rolling back adds a version with the old statements
+	r.Name, r.Email = "Test", "test@example.com"
+	restored, err := r.Rollback("https://example.com/vex/1", 1)
+	require.NoError(t, err)
+	require.Equal(t, 3, restored.Version)
+	require.Equal(t, vex.StatusUnderInvestigation, restored.Statements[0].Status)
+	history, err = r.History("https://example.com/vex/1")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, 3, history[0].Version)
+
+	_, err = r.Rollback("https://example.com/vex/1", 3)
+	require.Error(t, err)
+	_, err = r.Rollback("https://example.com/vex/2", 1)
+	require.Error(t, err)
+}
+
+func TestGitRepoAddFailure(t *testing.T) {
+	// Without a committer the commit fails
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	r, err := OpenGit(dir)
+	require.NoError(t, err)
+	r.Name, r.Email = "Test", "test@example.com"
+	doc, err := vex.Open("testdata/app-1.vex.json")
+	require.NoError(t, err)
+	p, err := r.Add(doc)
+	require.NoError(t, err)
+	index, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(p)))
+	require.NoError(t, err)
+
+	r.Name, r.Email = "", ""
+	doc.Version = 2
+	doc.Statements[0].Status = vex.StatusAffected
+	doc.Statements[0].ActionStatement = "Update"
+	_, err = r.Add(doc)
+	require.Error(t, err)
+
+	// The files, the staging area and the index are restored
+	requireClean(t, r)
+	got, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	require.NoError(t, err)
+	require.Equal(t, index, got)
+	got, err = os.ReadFile(filepath.Join(dir, filepath.FromSlash(p)))
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+	require.Equal(t, 1, r.Index().Documents[0].Version)
+
+	// A new document is removed
+	other := vex.New(vex.WithID("https://example.com/vex/other"))
+	other.Statements = doc.Statements
+	_, err = r.Add(&other)
+	require.Error(t, err)
+	requireClean(t, r)
+	require.NoFileExists(t, filepath.Join(dir, filepath.FromSlash(DocumentPath(other.ID))))
+	require.Len(t, r.Index().Documents, 1)
+}
+
+func requireClean(t *testing.T, r *GitRepo) {
+	t.Helper()
+	wt, err := r.git.Worktree()
+	require.NoError(t, err)
+	status, err := wt.Status()
+	require.NoError(t, err)
+	require.True(t, status.IsClean(), status.String())
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package repository manages VEX documents stored in a directory with a
+// layout meant to be committed to git and served as static files:
+//
+//	index.json
+//	documents/<sha256 of the document @id>.json
+//
+// The index lists the documents and maps product and vulnerability keys to
+// the paths of the documents with statements about them, so clients can
+// find the documents they need by fetching the index alone. Products are
+// keyed as in vex.ProductKey and vulnerabilities by their name, IRI and
+// aliases.
+package repository
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/internal/fsutil"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// IndexFile is the name of the index at the root of a repository
+	IndexFile = "index.json"
+
+	// DocumentsDir is the directory holding the documents
+	DocumentsDir = "documents"
+)
+
+// Index is the content of the index file. Paths are relative to the root of
+// the repository and use forward slashes.
+type Index struct {
+	// Documents lists the documents in the repository sorted by path
+	Documents []Entry `json:"documents"`
+
+	// Products maps product keys to the paths of documents about them
+	Products map[string][]string `json:"products"`
+
+	// Vulnerabilities maps vulnerability IDs to the paths of documents
+	// about them
+	Vulnerabilities map[string][]string `json:"vulnerabilities"`
+}
+
+// Entry describes a document in the index.
+type Entry struct {
+	Path      string     `json:"path"`
+	ID        string     `json:"@id"`
+	Version   int        `json:"version"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// Repo is a repository of documents in a directory. It is not safe for
+// concurrent use.
+type Repo struct {
+	// Dir is the root directory of the repository
+	Dir string
+
+	index *Index
+}
+
+// Open opens the repository in dir, reading its index. The directory is
+// created with an empty index if it does not exist.
+func Open(dir string) (*Repo, error) {
+	r := &Repo{Dir: dir}
+	data, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if err := os.MkdirAll(filepath.Join(dir, DocumentsDir), 0o755); err != nil {
+			return nil, fmt.Errorf("creating repository: %w", err)
+		}
+		r.index = newIndex()
+		if err := r.writeIndex(); err != nil {
+			return nil, err
+		}
+		return r, nil
+	case err != nil:
+		return nil, fmt.Errorf("reading index: %w", err)
+	}
+
+	r.index = newIndex()
+	if err := json.Unmarshal(data, r.index); err != nil {
+		return nil, fmt.Errorf("decoding index: %w", err)
+	}
+	// Indexes listing null instead of empty lists decode to nil ones
+	if r.index.Documents == nil {
+		r.index.Documents = []Entry{}
+	}
+	if r.index.Products == nil {
+		r.index.Products = map[string][]string{}
+	}
+	if r.index.Vulnerabilities == nil {
+		r.index.Vulnerabilities = map[string][]string{}
+	}
+	return r, nil
+}
+
+// Index returns the index of the repository.
+func (r *Repo) Index() *Index {
+	return r.index
+}
+
+// DocumentPath returns the path of the file of a document relative to the
+// root of the repository.
+func DocumentPath(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return path.Join(DocumentsDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Add writes a document to the repository, replacing the document with the
+// same @id, and updates the index. Documents without an @id are stored with
+// their canonical one, the document passed is not modified.
+func (r *Repo) Add(doc *vex.VEX) (string, error) {
+	if doc.ID == "" {
+		d := *doc
+		if _, err := d.GenerateCanonicalID(); err != nil {
+			return "", fmt.Errorf("generating document ID: %w", err)
+		}
+		doc = &d
+	}
+
+	var b bytes.Buffer
+	if err := doc.ToJSON(&b); err != nil {
+		return "", fmt.Errorf("marshaling document: %w", err)
+	}
+	p := DocumentPath(doc.ID)
+	if err := fsutil.WriteFile(filepath.Join(r.Dir, filepath.FromSlash(p)), b.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("writing document: %w", err)
+	}
+
+	r.index.remove(p)
+	r.index.add(p, doc)
+	if err := r.writeIndex(); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// Lookup returns the documents with statements about the vulnerability in
+// the product. Either can be empty to match any. Candidates are found in
+// the index and checked with the matching rules of the vex package.
+func (r *Repo) Lookup(product, vulnID string) ([]*vex.VEX, error) {
+	candidates := []string{}
+	for _, e := range r.index.Documents {
+		candidates = append(candidates, e.Path)
+	}
+	if product != "" {
+		paths := append(slices.Clone(r.index.Products[product]), r.index.Products[vex.ProductKey(product)]...)
+		candidates = slices.DeleteFunc(candidates, func(p string) bool { return !slices.Contains(paths, p) })
+	}
+	if vulnID != "" {
+		paths := r.index.Vulnerabilities[vulnID]
+		candidates = slices.DeleteFunc(candidates, func(p string) bool { return !slices.Contains(paths, p) })
+	}
+
+	docs := []*vex.VEX{}
+	for _, p := range candidates {
+		doc, err := vex.Load(filepath.Join(r.Dir, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", p, err)
+		}
+		q := doc.Query()
+		if product != "" {
+			q = q.Product(product)
+		}
+		if vulnID != "" {
+			q = q.Vulnerability(vulnID)
+		}
+		if q.Count() > 0 {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// Reindex rebuilds the index from the documents in the repository. Use it
+// after adding, editing or removing document files by hand.
+func (r *Repo) Reindex() error {
+	dir := filepath.Join(r.Dir, DocumentsDir)
+	index := newIndex()
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+		doc, err := vex.Load(p)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", p, err)
+		}
+		rel, err := filepath.Rel(r.Dir, p)
+		if err != nil {
+			return err
+		}
+		index.add(filepath.ToSlash(rel), doc)
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("indexing documents: %w", err)
+	}
+	r.index = index
+	return r.writeIndex()
+}
+
+func (r *Repo) writeIndex() error {
+	data, err := json.MarshalIndent(r.index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling index: %w", err)
+	}
+	if err := fsutil.WriteFile(filepath.Join(r.Dir, IndexFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing index: %w", err)
+	}
+	return nil
+}
+
+func newIndex() *Index {
+	return &Index{
+		Documents:       []Entry{},
+		Products:        map[string][]string{},
+		Vulnerabilities: map[string][]string{},
+	}
+}
+
+// add indexes the document at path p, keeping all lists sorted so the
+// index file is stable across runs.
+func (idx *Index) add(p string, doc *vex.VEX) {
+	idx.Documents = append(idx.Documents, Entry{
+		Path: p, ID: doc.ID, Version: doc.Version, Timestamp: doc.Timestamp,
+	})
+	sort.Slice(idx.Documents, func(i, j int) bool { return idx.Documents[i].Path < idx.Documents[j].Path })
+
+	for i := range doc.Statements {
+		vulns, products := vex.StatementKeys(&doc.Statements[i])
+		for _, v := range vulns {
+			idx.Vulnerabilities[v] = insertPath(idx.Vulnerabilities[v], p)
+		}
+		for _, k := range products {
+			idx.Products[k] = insertPath(idx.Products[k], p)
+		}
+	}
+}
+
+// remove drops the document at path p from the index.
+func (idx *Index) remove(p string) {
+	idx.Documents = slices.DeleteFunc(idx.Documents, func(e Entry) bool { return e.Path == p })
+	for _, m := range []map[string][]string{idx.Products, idx.Vulnerabilities} {
+		for k, paths := range m {
+			if paths = slices.DeleteFunc(paths, func(s string) bool { return s == p }); len(paths) == 0 {
+				delete(m, k)
+			} else {
+				m[k] = paths
+			}
+		}
+	}
+}
+
+func insertPath(paths []string, p string) []string {
+	i, found := slices.BinarySearch(paths, p)
+	if found {
+		return paths
+	}
+	return slices.Insert(paths, i, p)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func documentIDs(docs []*vex.VEX) []string {
+	ret := []string{}
+	for _, doc := range docs {
+		ret = append(ret, doc.ID)
+	}
+	return ret
+}
+
+func TestRepo(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "repo")
+	r, err := Open(dir)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(dir, IndexFile))
+
+	doc, err := vex.Open("testdata/app-1.vex.json")
+	require.NoError(t, err)
+	p1, err := r.Add(doc)
+	require.NoError(t, err)
+	require.Equal(t, DocumentPath("https://example.com/vex/1"), p1)
+	require.FileExists(t, filepath.Join(dir, filepath.FromSlash(p1)))
+
+	doc, err = vex.Open("testdata/lib-2.vex.json")
+	require.NoError(t, err)
+	p2, err := r.Add(doc)
+	require.NoError(t, err)
+
+	// The index on disk has the keys of both documents
+	r, err = Open(dir)
+	require.NoError(t, err)
+	idx := r.Index()
+	require.Len(t, idx.Documents, 2)
+	require.ElementsMatch(t, []string{p1, p2}, idx.Vulnerabilities["CVE-2023-1111"])
+	require.Equal(t, []string{p1}, idx.Products["pkg:oci//app"])
+
+	for _, tc := range []struct {
+		product, vuln string
+		expected      []string
+	}{
+		{"pkg:oci/app@sha256%3Aabc", "CVE-2023-1111", []string{"https://example.com/vex/1"}},
+		{"pkg:oci/app@sha256%3Aabc", "", []string{"https://example.com/vex/1"}},
+		{"pkg:npm/lib@1.0.0", "", []string{"https://example.com/vex/2"}},
+		{"pkg:npm/lib@2.0.0", "", []string{}},
+		{"pkg:npm/lib@1.0.0", "CVE-2023-2222", []string{}},
+		{"", "CVE-2023-1111", []string{"https://example.com/vex/1", "https://example.com/vex/2"}},
+	} {
+		docs, err := r.Lookup(tc.product, tc.vuln)
+		require.NoError(t, err)
+		require.ElementsMatch(t, tc.expected, documentIDs(docs), tc.product+" "+tc.vuln)
+	}
+
+	// Replacing a document updates its keys
+	doc, err = vex.Open("testdata/app-1-update.vex.json")
+	require.NoError(t, err)
+	_, err = r.Add(doc)
+	require.NoError(t, err)
+	require.NotContains(t, r.Index().Vulnerabilities, "CVE-2023-2222")
+	require.Equal(t, []string{p2}, r.Index().Vulnerabilities["CVE-2023-1111"])
+	require.Len(t, r.Index().Documents, 2)
+}
+
+func TestReindex(t *testing.T) {
+	dir := t.TempDir()
+	r, err := Open(dir)
+	require.NoError(t, err)
+	doc, err := vex.Open("testdata/app-1.vex.json")
+	require.NoError(t, err)
+	p, err := r.Add(doc)
+	require.NoError(t, err)
+
+	// Files edited by hand are picked up by Reindex
+	for name, dest := range map[string]string{
+		"testdata/app-1-update.vex.json": filepath.Join(dir, filepath.FromSlash(p)),
+		"testdata/other.vex.json":        filepath.Join(dir, DocumentsDir, "other.json"),
+	} {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(dest, data, 0o644))
+	}
+
+	require.NoError(t, r.Reindex())
+	require.Len(t, r.Index().Documents, 2)
+	require.NotContains(t, r.Index().Vulnerabilities, "CVE-2023-1111")
+	require.Equal(t, []string{p}, r.Index().Vulnerabilities["CVE-2023-3333"])
+
+	docs, err := r.Lookup("pkg:oci/other", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/vex/other"}, documentIDs(docs))
+
+	// The index file is stable
+	before, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	require.NoError(t, err)
+	require.NoError(t, r.Reindex())
+	after, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	require.NoError(t, err)
+	require.Equal(t, string(before), string(after))
+}
+
+func TestOpenNullIndex(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, IndexFile),
+		[]byte(`{"documents": null, "products": null, "vulnerabilities": null}`), 0o644,
+	))
+	r, err := Open(dir)
+	require.NoError(t, err)
+
+	// Documents can be added to indexes with null lists
+	doc, err := vex.Open("testdata/app-1.vex.json")
+	require.NoError(t, err)
+	p, err := r.Add(doc)
+	require.NoError(t, err)
+	require.Equal(t, []string{p}, r.Index().Vulnerabilities["CVE-2023-1111"])
+}
+
+func TestAddWithoutID(t *testing.T) {
+	r, err := Open(t.TempDir())
+	require.NoError(t, err)
+	doc, err := vex.Open("testdata/app-1.vex.json")
+	require.NoError(t, err)
+	doc.ID = ""
+
+	// The document is stored under its canonical ID without modifying it
+	p, err := r.Add(doc)
+	require.NoError(t, err)
+	require.Empty(t, doc.ID)
+	id, err := doc.GenerateCanonicalID()
+	require.NoError(t, err)
+	require.Equal(t, DocumentPath(id), p)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sarif
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	report, err := Open("testdata/results.sarif")
+	require.NoError(t, err)
+	require.Equal(t, "2.1.0", report.Version)
+	require.Len(t, report.Runs, 1)
+
+	run := report.Runs[0]
+	require.Equal(t, "scanner", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	require.Len(t, run.Results, 2)
+	require.Equal(t, "CVE-2023-39325", *run.Results[0].RuleID)
+	require.Equal(t, "pkg:golang/golang.org/x/net@v0.7.0", *run.Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	_, err = Open("testdata/missing.sarif")
+	require.Error(t, err)
+}
+
+func TestToJSONRoundTrip(t *testing.T) {
+	report, err := Open("testdata/results.sarif")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.ToJSON(&buf))
+	decoded := New()
+	require.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	require.Equal(t, report, decoded)
+}
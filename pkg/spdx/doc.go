@@ -0,0 +1,10 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package spdx provides a minimal library to read the parts of SPDX 2.3 JSON
+// documents needed to import VEX data embedded in them.
+//
+// https://spdx.github.io/spdx-spec/v2.3/
+package spdx
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package spdx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Document is an SPDX 2.3 document.
+//
+// https://spdx.github.io/spdx-spec/v2.3/document-creation-information/
+type Document struct {
+	ID                string         `json:"SPDXID"`
+	Version           string         `json:"spdxVersion"`
+	Name              string         `json:"name"`
+	DocumentNamespace string         `json:"documentNamespace"`
+	DocumentDescribes []string       `json:"documentDescribes"`
+	CreationInfo      CreationInfo   `json:"creationInfo"`
+	Packages          []Package      `json:"packages"`
+	Annotations       []Annotation   `json:"annotations"`
+	Relationships     []Relationship `json:"relationships"`
+}
+
+// CreationInfo records who created the document and when.
+//
+// https://spdx.github.io/spdx-spec/v2.3/document-creation-information/#68-creator-field
+type CreationInfo struct {
+	Created  time.Time `json:"created"`
+	Creators []string  `json:"creators"`
+}
+
+// Package describes a package in the document.
+//
+// https://spdx.github.io/spdx-spec/v2.3/package-information/
+type Package struct {
+	ID           string        `json:"SPDXID"`
+	Name         string        `json:"name"`
+	VersionInfo  string        `json:"versionInfo"`
+	Checksums    []Checksum    `json:"checksums"`
+	ExternalRefs []ExternalRef `json:"externalRefs"`
+	Annotations  []Annotation  `json:"annotations"`
+}
+
+// Checksum is a digest of a package.
+//
+// https://spdx.github.io/spdx-spec/v2.3/package-information/#710-package-checksum-field
+type Checksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"checksumValue"`
+}
+
+// ExternalRef points to an external identifier of a package, such as its purl.
+//
+// https://spdx.github.io/spdx-spec/v2.3/package-information/#721-external-reference-field
+type ExternalRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+// Annotation is a comment attached to an element of the document.
+//
+// https://spdx.github.io/spdx-spec/v2.3/annotations/
+type Annotation struct {
+	Date      time.Time `json:"annotationDate"`
+	Type      string    `json:"annotationType"`
+	Annotator string    `json:"annotator"`
+	Comment   string    `json:"comment"`
+}
+
+// Relationship links two elements of the document.
+//
+// https://spdx.github.io/spdx-spec/v2.3/relationships-between-SPDX-elements/
+type Relationship struct {
+	Element        string `json:"spdxElementId"`
+	Type           string `json:"relationshipType"`
+	RelatedElement string `json:"relatedSpdxElement"`
+	Comment        string `json:"comment"`
+}
+
+// Open reads and parses an SPDX JSON document from the given file path.
+func Open(path string) (*Document, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("spdx: failed to open document: %w", err)
+	}
+	defer fh.Close()
+
+	return Decode(fh)
+}
+
+// Decode reads an SPDX JSON document from r.
+func Decode(r io.Reader) (*Document, error) {
+	doc := &Document{}
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("spdx: failed to decode document: %w", err)
+	}
+	return doc, nil
+}
+
+// PackageURL returns the purl of the package from its external references,
+// or an empty string if it has none.
+func (p *Package) PackageURL() string {
+	for _, ref := range p.ExternalRefs {
+		if ref.Type == "purl" {
+			return ref.Locator
+		}
+	}
+	return ""
+}
+
+// Describes returns the SPDXIDs of the elements the document describes,
+// either listed in documentDescribes or as DESCRIBES relationships of the
+// document.
+func (doc *Document) Describes() []string {
+	ret := append([]string{}, doc.DocumentDescribes...)
+	for _, rel := range doc.Relationships {
+		if rel.Type == "DESCRIBES" && rel.Element == doc.ID {
+			ret = append(ret, rel.RelatedElement)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package spdx
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	doc, err := Open("testdata/spdx-annotations.spdx.json")
+	require.NoError(t, err)
+	require.Equal(t, "SPDXRef-DOCUMENT", doc.ID)
+	require.Equal(t, "SPDX-2.3", doc.Version)
+	require.Equal(t, time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC), doc.CreationInfo.Created)
+	require.Equal(t, []string{"SPDXRef-Package-image"}, doc.Describes())
+
+	require.Len(t, doc.Packages, 3)
+	require.Equal(t, "pkg:oci/example-image@sha256%3Aabcdef", doc.Packages[0].PackageURL())
+	require.Equal(t, "", doc.Packages[2].PackageURL())
+	require.Len(t, doc.Packages[0].Annotations, 2)
+	require.Equal(t, "REVIEW", doc.Packages[0].Annotations[0].Type)
+	require.Len(t, doc.Annotations, 1)
+	require.Equal(t, "CONTAINS", doc.Relationships[0].Type)
+
+	_, err = Open("testdata/missing.spdx.json")
+	require.Error(t, err)
+	_, err = Decode(bytes.NewReader([]byte(`{"packages":{}}`)))
+	require.Error(t, err)
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	doc, err := Open("testdata/spdx-annotations.spdx.json")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	decoded, err := Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, doc, decoded)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package store
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openvex/go-vex/internal/fsutil"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// ErrIntegrity is returned when a stored document does not match its hash
+var ErrIntegrity = errors.New("document does not match its hash")
+
+// CAS is a content addressable store of documents in a directory. Documents
+// are stored as their JCS serialization (see vex.VEX.CanonicalBytes) and
+// retrieved by the SHA-256 hash of those bytes, so storing a document twice
+// keeps one copy and a document read back is checked against the hash it was
+// requested by. Any edit to a document, including to its metadata, gives it
+// a new hash.
+//
+// Files are laid out as <dir>/sha256/<first two hex digits>/<hash>.json.
+type CAS struct {
+	Dir string
+}
+
+// NewCAS returns a content addressable store in dir.
+func NewCAS(dir string) *CAS {
+	return &CAS{Dir: dir}
+}
+
+// Put stores a document and returns its hash. Documents already in the
+// store are not written again.
+func (c *CAS) Put(doc *vex.VEX) (string, error) {
+	data, err := doc.CanonicalBytes()
+	if err != nil {
+		return "", fmt.Errorf("serializing document: %w", err)
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256(data))
+	name := c.path(hash)
+	if _, err := os.Stat(name); err == nil {
+		return hash, nil
+	}
+
+	if err := fsutil.WriteFile(name, data, 0o444); err != nil {
+		return "", fmt.Errorf("writing document: %w", err)
+	}
+	return hash, nil
+}
+
+// Get returns the document with a hash. It returns ErrNotFound if there is
+// none and ErrIntegrity if the stored file was altered.
+func (c *CAS) Get(hash string) (*vex.VEX, error) {
+	if !isCASHash(hash) {
+		return nil, fmt.Errorf("invalid hash %q", hash)
+	}
+	data, err := os.ReadFile(c.path(hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading document: %w", err)
+	}
+	if got := fmt.Sprintf("%x", sha256.Sum256(data)); got != hash {
+		return nil, fmt.Errorf("%w: %s hashes to %s", ErrIntegrity, hash, got)
+	}
+	doc, err := vex.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing document: %w", err)
+	}
+	return doc, nil
+}
+
+// Has returns true if the store has a document with the hash.
+func (c *CAS) Has(hash string) bool {
+	if !isCASHash(hash) {
+		return false
+	}
+	_, err := os.Stat(c.path(hash))
+	return err == nil
+}
+
+// List returns the hashes of the documents in the store, sorted.
+func (c *CAS) List() ([]string, error) {
+	hashes := []string{}
+	err := filepath.WalkDir(filepath.Join(c.Dir, "sha256"), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		hash, ok := strings.CutSuffix(d.Name(), ".json")
+		if !d.IsDir() && ok && isCASHash(hash) {
+			hashes = append(hashes, hash)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("listing documents: %w", err)
+	}
+	sort.Strings(hashes)
+	return hashes, nil
+}
+
+// Verify reads all the documents in the store and returns the hashes of
+// those failing the integrity check.
+func (c *CAS) Verify() ([]string, error) {
+	hashes, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	bad := []string{}
+	for _, hash := range hashes {
+		if _, err := c.Get(hash); errors.Is(err, ErrIntegrity) {
+			bad = append(bad, hash)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return bad, nil
+}
+
+func (c *CAS) path(hash string) string {
+	return filepath.Join(c.Dir, "sha256", hash[:2], hash+".json")
+}
+
+// isCASHash checks a hash is a hex SHA-256 so it is safe to build paths.
+func isCASHash(hash string) bool {
+	if len(hash) != 64 {
+		return false
+	}
+	for _, r := range hash {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
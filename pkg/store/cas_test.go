@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package store
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestCAS(t *testing.T) {
+	c := NewCAS(t.TempDir())
+	doc, err := vex.Open("testdata/app-1.vex.json")
+	require.NoError(t, err)
+
+	hash, err := c.Put(doc)
+	require.NoError(t, err)
+	require.True(t, c.Has(hash))
+	// Hashing does not reorder the caller's statements
+	require.Equal(t, vex.VulnerabilityID("CVE-2023-2222"), doc.Statements[0].Vulnerability.Name)
+
+	expected, err := doc.ContentHash()
+	require.NoError(t, err)
+	require.Equal(t, expected, hash)
+
+	// Documents are stored as their canonical serialization
+	data, err := os.ReadFile(c.path(hash))
+	require.NoError(t, err)
+	canonical, err := doc.CanonicalBytes()
+	require.NoError(t, err)
+	require.Equal(t, string(canonical), string(data))
+
+	// Edits to any field change the hash
+	edited := doc.Clone()
+	edited.Statements[0].ActionStatement = "Upgrade to 1.3"
+	editedHash, err := c.Put(edited)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, editedHash)
+
+	// The same content is stored once
+	doc, err = vex.Open("testdata/app-1.vex.json")
+	require.NoError(t, err)
+	again, err := c.Put(doc)
+	require.NoError(t, err)
+	require.Equal(t, hash, again)
+
+	doc, err = vex.Open("testdata/app-2-fixed.vex.json")
+	require.NoError(t, err)
+	other, err := c.Put(doc)
+	require.NoError(t, err)
+
+	hashes, err := c.List()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{hash, editedHash, other}, hashes)
+
+	got, err := c.Get(hash)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/vex/1", got.ID)
+	again, err = c.Put(got)
+	require.NoError(t, err)
+	require.Equal(t, hash, again)
+
+	_, err = c.Get(strings.Repeat("0", 64))
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = c.Get("../../etc/passwd")
+	require.Error(t, err)
+	require.False(t, c.Has("../x"))
+
+	// Tampering is detected on read
+	name := c.path(other)
+	require.NoError(t, os.Chmod(name, 0o644))
+	data, err = os.ReadFile(name)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(name, []byte(strings.Replace(string(data), "fixed", "not_affected", 1)), 0o644))
+	_, err = c.Get(other)
+	require.ErrorIs(t, err, ErrIntegrity)
+
+	bad, err := c.Verify()
+	require.NoError(t, err)
+	require.Equal(t, []string{other}, bad)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// MemoryStore is a Store keeping documents in memory. It is safe for
+// concurrent use.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	// docs holds the revisions of each document ordered by version, the
+	// documents in the order they were first stored
+	docs [][]*vex.VEX
+}
+
+// NewMemoryStore returns a store holding docs. Documents without an @id are
+// kept but can't be replaced or deleted.
+func NewMemoryStore(docs ...*vex.VEX) *MemoryStore {
+	s := &MemoryStore{}
+	for _, doc := range docs {
+		s.add(doc)
+	}
+	return s
+}
+
+func (s *MemoryStore) add(doc *vex.VEX) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, revisions := range s.docs {
+		if doc.ID == "" || revisions[0].ID != doc.ID {
+			continue
+		}
+		j, found := slices.BinarySearchFunc(revisions, doc.Version, func(rev *vex.VEX, version int) int {
+			return rev.Version - version
+		})
+		if found {
+			revisions[j] = doc
+		} else {
+			s.docs[i] = slices.Insert(revisions, j, doc)
+		}
+		return
+	}
+	s.docs = append(s.docs, []*vex.VEX{doc})
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(_ context.Context, doc *vex.VEX) error {
+	if doc.ID == "" {
+		return errors.New("document has no @id")
+	}
+	s.add(doc)
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.docs, func(revisions []*vex.VEX) bool { return revisions[0].ID == id })
+	if i < 0 {
+		return ErrNotFound
+	}
+	s.docs = slices.Delete(s.docs, i, i+1)
+	return nil
+}
+
+// Document implements Store.
+func (s *MemoryStore) Document(_ context.Context, id string) (*vex.VEX, error) {
+	revisions, err := s.revisions(id)
+	if err != nil {
+		return nil, err
+	}
+	return revisions[len(revisions)-1], nil
+}
+
+// Revisions implements Store.
+func (s *MemoryStore) Revisions(_ context.Context, id string) ([]*vex.VEX, error) {
+	return s.revisions(id)
+}
+
+func (s *MemoryStore) revisions(id string) ([]*vex.VEX, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, revisions := range s.docs {
+		if revisions[0].ID == id {
+			return slices.Clone(revisions), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// DocumentsByProduct implements Store.
+func (s *MemoryStore) DocumentsByProduct(_ context.Context, product string) ([]*vex.VEX, error) {
+	return s.filter(func(doc *vex.VEX) bool {
+		return doc.Query().Product(product).Count() > 0
+	}), nil
+}
+
+// DocumentsByVulnerability implements Store.
+func (s *MemoryStore) DocumentsByVulnerability(_ context.Context, id string) ([]*vex.VEX, error) {
+	return s.filter(func(doc *vex.VEX) bool {
+		return doc.Query().Vulnerability(id).Count() > 0
+	}), nil
+}
+
+// EffectiveStatement implements Store.
+func (s *MemoryStore) EffectiveStatement(_ context.Context, product, vulnID string) (*vex.Statement, error) {
+	stmt := vex.NewIndex(s.latest()...).EffectiveStatement(product, vulnID)
+	if stmt == nil {
+		return nil, nil
+	}
+	ret := stmt.Clone()
+	return &ret, nil
+}
+
+// History implements Store.
+func (s *MemoryStore) History(_ context.Context, product, vulnID string) ([]vex.Statement, error) {
+	s.mu.RLock()
+	all := []*vex.VEX{}
+	for _, revisions := range s.docs {
+		all = append(all, revisions...)
+	}
+	s.mu.RUnlock()
+	return vex.NewIndex(all...).Matches(vulnID, product, nil), nil
+}
+
+// latest returns the latest revision of each document
+func (s *MemoryStore) latest() []*vex.VEX {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ret := make([]*vex.VEX, 0, len(s.docs))
+	for _, revisions := range s.docs {
+		ret = append(ret, revisions[len(revisions)-1])
+	}
+	return ret
+}
+
+func (s *MemoryStore) filter(keep func(*vex.VEX) bool) []*vex.VEX {
+	ret := []*vex.VEX{}
+	for _, doc := range s.latest() {
+		if keep(doc) {
+			ret = append(ret, doc)
+		}
+	}
+	return ret
+}
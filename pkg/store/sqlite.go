@@ -0,0 +1,368 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// sqliteSchema creates the tables of the SQLite store. Each revision of a
+// document is kept whole as JSON in documents, keyed by @id and version.
+// Statements are copied to their own table with the time they take effect
+// and statement_keys indexes them by the vulnerability and product keys
+// returned by vex.StatementKeys.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS documents (
+	seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+	id        TEXT NOT NULL,
+	version   INTEGER NOT NULL,
+	timestamp TEXT NOT NULL,
+	data      TEXT NOT NULL,
+	UNIQUE (id, version)
+);
+CREATE TABLE IF NOT EXISTS statements (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	document_seq INTEGER NOT NULL,
+	position     INTEGER NOT NULL,
+	timestamp    TEXT NOT NULL,
+	data         TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS statements_document ON statements (document_seq);
+CREATE TABLE IF NOT EXISTS statement_keys (
+	statement_id  INTEGER NOT NULL,
+	vulnerability TEXT NOT NULL,
+	product       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS statement_keys_lookup ON statement_keys (vulnerability, product);
+CREATE INDEX IF NOT EXISTS statement_keys_product ON statement_keys (product);
+CREATE INDEX IF NOT EXISTS statement_keys_statement ON statement_keys (statement_id);
+`
+
+// sqliteTimeFormat is a fixed width UTC format so times sort as strings
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// SQLiteStore is a Store persisting documents in a SQLite database.
+//
+// The store uses database/sql and does not import a driver: programs
+// register the one they prefer, such as modernc.org/sqlite or
+// github.com/mattn/go-sqlite3, and pass the opened database to
+// NewSQLiteStore. The queries only use SQL understood by SQLite. The module
+// requires modernc.org/sqlite for the tests of this package only, it is not
+// built into programs unless they import it.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore returns a store using db, creating its tables if needed.
+func NewSQLiteStore(ctx context.Context, db *sql.DB) (*SQLiteStore, error) {
+	for _, stmt := range strings.Split(sqliteSchema, ";") {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("creating schema: %w", err)
+		}
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Put implements Store. Replacing a revision replaces all its statements in
+// a single transaction.
+func (s *SQLiteStore) Put(ctx context.Context, doc *vex.VEX) error {
+	if doc.ID == "" {
+		return errors.New("document has no @id")
+	}
+	var b bytes.Buffer
+	if err := doc.ToJSON(&b); err != nil {
+		return fmt.Errorf("marshaling document: %w", err)
+	}
+	var docTime time.Time
+	if doc.Timestamp != nil {
+		docTime = *doc.Timestamp
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO documents (id, version, timestamp, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id, version) DO UPDATE SET timestamp = excluded.timestamp, data = excluded.data`,
+		doc.ID, doc.Version, formatSQLiteTime(docTime), b.String(),
+	); err != nil {
+		return fmt.Errorf("storing document: %w", err)
+	}
+	var seq int64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT seq FROM documents WHERE id = ? AND version = ?`, doc.ID, doc.Version,
+	).Scan(&seq); err != nil {
+		return fmt.Errorf("storing document: %w", err)
+	}
+	if err := deleteSQLiteStatements(ctx, tx, `document_seq = ?`, seq); err != nil {
+		return err
+	}
+
+	for i := range doc.Statements {
+		stmt := &doc.Statements[i]
+		data, err := json.Marshal(stmt)
+		if err != nil {
+			return fmt.Errorf("marshaling statement %d: %w", i, err)
+		}
+		t := docTime
+		if stmt.Timestamp != nil && !stmt.Timestamp.IsZero() {
+			t = *stmt.Timestamp
+		}
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO statements (document_seq, position, timestamp, data) VALUES (?, ?, ?, ?)`,
+			seq, i, formatSQLiteTime(t), string(data),
+		)
+		if err != nil {
+			return fmt.Errorf("storing statement %d: %w", i, err)
+		}
+		stmtID, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("storing statement %d: %w", i, err)
+		}
+
+		vulns, products := vex.StatementKeys(stmt)
+		seen := map[[2]string]bool{}
+		for _, v := range vulns {
+			for _, p := range products {
+				if seen[[2]string{v, p}] {
+					continue
+				}
+				seen[[2]string{v, p}] = true
+				if _, err := tx.ExecContext(ctx,
+					`INSERT INTO statement_keys (statement_id, vulnerability, product) VALUES (?, ?, ?)`,
+					stmtID, v, p,
+				); err != nil {
+					return fmt.Errorf("indexing statement %d: %w", i, err)
+				}
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing document: %w", err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *SQLiteStore) Delete(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM documents WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting document: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("deleting document: %w", err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	if err := deleteSQLiteStatements(ctx, tx, `document_seq NOT IN (SELECT seq FROM documents)`); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing deletion: %w", err)
+	}
+	return nil
+}
+
+// Document implements Store.
+func (s *SQLiteStore) Document(ctx context.Context, id string) (*vex.VEX, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT data FROM documents WHERE id = ? ORDER BY version DESC LIMIT 1`, id,
+	).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading document: %w", err)
+	}
+	return parseSQLiteDocument(data)
+}
+
+// Revisions implements Store.
+func (s *SQLiteStore) Revisions(ctx context.Context, id string) ([]*vex.VEX, error) {
+	docs, err := s.documents(ctx, `SELECT data FROM documents WHERE id = ? ORDER BY version`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, ErrNotFound
+	}
+	return docs, nil
+}
+
+// sqliteLatest selects the latest revision of each document and the position
+// of the document in the order they were first stored.
+const sqliteLatest = `SELECT id, MAX(version) AS version, MIN(seq) AS first FROM documents GROUP BY id`
+
+// DocumentsByProduct implements Store.
+func (s *SQLiteStore) DocumentsByProduct(ctx context.Context, product string) ([]*vex.VEX, error) {
+	docs, err := s.documents(ctx,
+		`SELECT d.data FROM documents d JOIN (`+sqliteLatest+`) l ON l.id = d.id AND l.version = d.version
+		WHERE d.seq IN (
+			SELECT s.document_seq FROM statements s JOIN statement_keys k ON k.statement_id = s.id
+			WHERE k.product IN (?, ?)
+		) ORDER BY l.first`,
+		product, vex.ProductKey(product),
+	)
+	if err != nil {
+		return nil, err
+	}
+	// Keys narrow down the candidates, matching is done as in the library
+	ret := []*vex.VEX{}
+	for _, doc := range docs {
+		if doc.Query().Product(product).Count() > 0 {
+			ret = append(ret, doc)
+		}
+	}
+	return ret, nil
+}
+
+// DocumentsByVulnerability implements Store.
+func (s *SQLiteStore) DocumentsByVulnerability(ctx context.Context, id string) ([]*vex.VEX, error) {
+	return s.documents(ctx,
+		`SELECT d.data FROM documents d JOIN (`+sqliteLatest+`) l ON l.id = d.id AND l.version = d.version
+		WHERE d.seq IN (
+			SELECT s.document_seq FROM statements s JOIN statement_keys k ON k.statement_id = s.id
+			WHERE k.vulnerability = ?
+		) ORDER BY l.first`,
+		id,
+	)
+}
+
+// EffectiveStatement implements Store.
+func (s *SQLiteStore) EffectiveStatement(ctx context.Context, product, vulnID string) (*vex.Statement, error) {
+	history, err := s.statements(ctx, true, product, vulnID)
+	if err != nil || len(history) == 0 {
+		return nil, err
+	}
+	return &history[len(history)-1], nil
+}
+
+// History implements Store. Statements taking effect at the same time are
+// ordered as the documents were first stored and then by version.
+func (s *SQLiteStore) History(ctx context.Context, product, vulnID string) ([]vex.Statement, error) {
+	return s.statements(ctx, false, product, vulnID)
+}
+
+// statements returns the statements about the vulnerability in the product
+// ordered from oldest to newest, only from the latest revision of each
+// document if latest is true.
+func (s *SQLiteStore) statements(ctx context.Context, latest bool, product, vulnID string) ([]vex.Statement, error) {
+	revisions := `SELECT r.id, r.version, (SELECT MIN(seq) FROM documents f WHERE f.id = r.id) AS first FROM documents r`
+	if latest {
+		revisions = sqliteLatest
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT DISTINCT s.id, s.timestamp, s.data, l.first, d.version, s.position
+		FROM statements s
+		JOIN statement_keys k ON k.statement_id = s.id
+		JOIN documents d ON d.seq = s.document_seq
+		JOIN (`+revisions+`) l ON l.id = d.id AND l.version = d.version
+		WHERE k.vulnerability = ? AND k.product IN (?, ?)
+		ORDER BY s.timestamp, l.first, d.version, s.position`,
+		vulnID, product, vex.ProductKey(product),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying statements: %w", err)
+	}
+	defer rows.Close()
+
+	ret := []vex.Statement{}
+	for rows.Next() {
+		var (
+			id, first, version, position int64
+			timestamp, data              string
+		)
+		if err := rows.Scan(&id, &timestamp, &data, &first, &version, &position); err != nil {
+			return nil, fmt.Errorf("reading statement: %w", err)
+		}
+		stmt := &vex.Statement{}
+		if err := json.Unmarshal([]byte(data), stmt); err != nil {
+			return nil, fmt.Errorf("parsing stored statement: %w", err)
+		}
+		if stmt.Matches(vulnID, product, nil) {
+			ret = append(ret, *stmt)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading statements: %w", err)
+	}
+	return ret, nil
+}
+
+func (s *SQLiteStore) documents(ctx context.Context, query string, args ...any) ([]*vex.VEX, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying documents: %w", err)
+	}
+	defer rows.Close()
+
+	ret := []*vex.VEX{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("reading document: %w", err)
+		}
+		doc, err := parseSQLiteDocument(data)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading documents: %w", err)
+	}
+	return ret, nil
+}
+
+// deleteSQLiteStatements deletes the statements matching the where clause
+// and their keys.
+func deleteSQLiteStatements(ctx context.Context, tx *sql.Tx, where string, args ...any) error {
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM statement_keys WHERE statement_id IN (SELECT id FROM statements WHERE `+where+`)`,
+		args...,
+	); err != nil {
+		return fmt.Errorf("deleting statement keys: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM statements WHERE `+where, args...); err != nil {
+		return fmt.Errorf("deleting statements: %w", err)
+	}
+	return nil
+}
+
+func parseSQLiteDocument(data string) (*vex.VEX, error) {
+	doc, err := vex.Parse([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing stored document: %w", err)
+	}
+	return doc, nil
+}
+
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeFormat)
+}
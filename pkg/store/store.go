@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package store persists VEX documents and answers queries about the
+// statements in them.
+//
+// Store implementations keep whole documents, keyed by their @id and
+// version, and index their statements by vulnerability, product and
+// timestamp. Storing a new version of a document keeps the earlier ones as
+// its revisions. Lookups follow
+// the matching rules of the vex package so all stores agree with Index on
+// which statements apply to a product.
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// ErrNotFound is returned when a document does not exist
+var ErrNotFound = errors.New("document not found")
+
+// Store keeps VEX documents. Documents returned must not be modified by the
+// caller.
+type Store interface {
+	// Put stores a revision of a document. It replaces the stored revision
+	// with the same @id and version and keeps the other versions. Documents
+	// must have an @id.
+	Put(ctx context.Context, doc *vex.VEX) error
+
+	// Delete removes all the revisions of the document with an @id or
+	// returns ErrNotFound
+	Delete(ctx context.Context, id string) error
+
+	// Document returns the latest version of the document with an @id or
+	// ErrNotFound
+	Document(ctx context.Context, id string) (*vex.VEX, error)
+
+	// Revisions returns all the stored versions of the document with an
+	// @id, from oldest to newest, or ErrNotFound
+	Revisions(ctx context.Context, id string) ([]*vex.VEX, error)
+
+	// DocumentsByProduct returns the latest version of the documents with
+	// statements about a product, matched as in Component.Matches
+	DocumentsByProduct(ctx context.Context, product string) ([]*vex.VEX, error)
+
+	// DocumentsByVulnerability returns the latest version of the documents
+	// with statements about a vulnerability, matched by its name, IRI or
+	// aliases
+	DocumentsByVulnerability(ctx context.Context, id string) ([]*vex.VEX, error)
+
+	// EffectiveStatement returns the latest statement about the
+	// vulnerability in the product across the latest version of all
+	// documents or nil if there is none
+	EffectiveStatement(ctx context.Context, product, vulnID string) (*vex.Statement, error)
+
+	// History returns all the statements about the vulnerability in the
+	// product in every revision of the documents, ordered from oldest to
+	// newest
+	History(ctx context.Context, product, vulnID string) ([]vex.Statement, error)
+}
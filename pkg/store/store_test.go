@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// testStoreImplementation checks the behavior every Store must have.
+func testStoreImplementation(t *testing.T, s Store) {
+	ctx := context.Background()
+	require.Error(t, s.Put(ctx, &vex.VEX{}))
+	for _, name := range []string{"testdata/app-1.vex.json", "testdata/app-2.vex.json"} {
+		doc, err := vex.Open(name)
+		require.NoError(t, err)
+		require.NoError(t, s.Put(ctx, doc))
+	}
+
+	doc, err := s.Document(ctx, "https://example.com/vex/1")
+	require.NoError(t, err)
+	require.Len(t, doc.Statements, 2)
+	_, err = s.Document(ctx, "https://example.com/vex/9")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	docs, err := s.DocumentsByProduct(ctx, "pkg:oci/app@sha256%3Aabc")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/vex/1", "https://example.com/vex/2"}, documentIDs(docs))
+	docs, err = s.DocumentsByProduct(ctx, "pkg:oci/app@sha256%3Adef")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/vex/1"}, documentIDs(docs))
+	docs, err = s.DocumentsByVulnerability(ctx, "CVE-2023-2222")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/vex/1"}, documentIDs(docs))
+
+	// Later statements override earlier ones
+	stmt, err := s.EffectiveStatement(ctx, "pkg:oci/app@sha256%3Aabc", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.NotNil(t, stmt)
+	require.Equal(t, vex.StatusNotAffected, stmt.Status)
+
+	history, err := s.History(ctx, "pkg:oci/app@sha256%3Aabc", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, vex.StatusUnderInvestigation, history[0].Status)
+	require.Equal(t, vex.StatusNotAffected, history[1].Status)
+
+	stmt, err = s.EffectiveStatement(ctx, "pkg:oci/other", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.Nil(t, stmt)
+
+	// A new version is the current document and keeps the earlier revision
+	doc, err = vex.Open("testdata/app-2-fixed.vex.json")
+	require.NoError(t, err)
+	require.NoError(t, s.Put(ctx, doc))
+	stmt, err = s.EffectiveStatement(ctx, "pkg:oci/app@sha256%3Aabc", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.Equal(t, vex.StatusFixed, stmt.Status)
+	history, err = s.History(ctx, "pkg:oci/app@sha256%3Aabc", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, vex.StatusUnderInvestigation, history[0].Status)
+	require.Equal(t, vex.StatusNotAffected, history[1].Status)
+	require.Equal(t, vex.StatusFixed, history[2].Status)
+
+	doc, err = s.Document(ctx, "https://example.com/vex/2")
+	require.NoError(t, err)
+	require.Equal(t, 2, doc.Version)
+	revisions, err := s.Revisions(ctx, "https://example.com/vex/2")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, 1, revisions[0].Version)
+	require.Equal(t, vex.StatusNotAffected, revisions[0].Statements[0].Status)
+	require.Equal(t, 2, revisions[1].Version)
+	_, err = s.Revisions(ctx, "https://example.com/vex/9")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// Documents are only found by their latest version
+	docs, err = s.DocumentsByProduct(ctx, "pkg:oci/app@sha256%3Aabc")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/vex/1", "https://example.com/vex/2"}, documentIDs(docs))
+	require.Equal(t, 2, docs[1].Version)
+
+	// Storing a version again replaces that revision
+	doc, err = vex.Open("testdata/app-2.vex.json")
+	require.NoError(t, err)
+	doc.Statements[0].Status = vex.StatusAffected
+	doc.Statements[0].Justification = ""
+	doc.Statements[0].ActionStatement = "Upgrade to 1.2"
+	require.NoError(t, s.Put(ctx, doc))
+	revisions, err = s.Revisions(ctx, "https://example.com/vex/2")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, vex.StatusAffected, revisions[0].Statements[0].Status)
+	history, err = s.History(ctx, "pkg:oci/app@sha256%3Aabc", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, vex.StatusAffected, history[1].Status)
+	stmt, err = s.EffectiveStatement(ctx, "pkg:oci/app@sha256%3Aabc", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.Equal(t, vex.StatusFixed, stmt.Status)
+
+	require.NoError(t, s.Delete(ctx, "https://example.com/vex/2"))
+	require.ErrorIs(t, s.Delete(ctx, "https://example.com/vex/2"), ErrNotFound)
+	stmt, err = s.EffectiveStatement(ctx, "pkg:oci/app@sha256%3Aabc", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.Equal(t, vex.StatusUnderInvestigation, stmt.Status)
+	history, err = s.History(ctx, "pkg:oci/app@sha256%3Aabc", "CVE-2023-1111")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	_, err = s.Revisions(ctx, "https://example.com/vex/2")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func documentIDs(docs []*vex.VEX) []string {
+	ret := []string{}
+	for _, doc := range docs {
+		ret = append(ret, doc.ID)
+	}
+	return ret
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStoreImplementation(t, NewMemoryStore())
+}
+
+func TestSQLiteStore(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	// Each connection to :memory: is a different database
+	db.SetMaxOpenConns(1)
+
+	s, err := NewSQLiteStore(context.Background(), db)
+	require.NoError(t, err)
+	testStoreImplementation(t, s)
+}
+
+func TestFormatSQLiteTime(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	t2 := t1.Add(time.Millisecond)
+	require.Equal(t, "2023-05-31T22:00:00.000000000Z", formatSQLiteTime(t1))
+	require.Less(t, formatSQLiteTime(t1), formatSQLiteTime(t2))
+	require.Less(t, formatSQLiteTime(time.Time{}), formatSQLiteTime(t1))
+}
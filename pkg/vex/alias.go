@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// AliasResolver looks up the other identifiers of a vulnerability, for
+// example the CVE of a GHSA advisory. The osv package implements it with
+// the OSV.dev API.
+type AliasResolver interface {
+	// Aliases returns the other identifiers of the vulnerability, or none
+	// if it is unknown.
+	Aliases(ctx context.Context, id string) ([]string, error)
+}
+
+// AliasMap is an AliasResolver backed by a map from vulnerability
+// identifiers to their aliases, for offline use and tests.
+type AliasMap map[string][]string
+
+// Aliases implements AliasResolver.
+func (m AliasMap) Aliases(_ context.Context, id string) ([]string, error) {
+	return m[id], nil
+}
+
+// ResolveAliases returns a copy of the document with the aliases found by
+// the resolver added to its vulnerabilities, so Matches and the functions
+// built on it treat the identifiers as equivalent even when the author did
+// not list them. Each vulnerability is resolved by its name.
+//
+// The document is not modified: the copy differs from what the author
+// published, so its hashes and signatures don't match those of the
+// original. Use it for matching and keep the original to verify, store or
+// redistribute.
+func (vexDoc *VEX) ResolveAliases(ctx context.Context, r AliasResolver) (*VEX, error) {
+	ret := vexDoc.Clone()
+	resolved := map[VulnerabilityID][]string{}
+	for i := range ret.Statements {
+		v := &ret.Statements[i].Vulnerability
+		if v.Name == "" {
+			continue
+		}
+		aliases, ok := resolved[v.Name]
+		if !ok {
+			var err error
+			aliases, err = r.Aliases(ctx, string(v.Name))
+			if err != nil {
+				return nil, fmt.Errorf("resolving aliases of %s: %w", v.Name, err)
+			}
+			resolved[v.Name] = aliases
+		}
+		for _, a := range aliases {
+			id := VulnerabilityID(a)
+			if a != "" && id != v.Name && !slices.Contains(v.Aliases, id) {
+				v.Aliases = append(v.Aliases, id)
+			}
+		}
+	}
+	return ret, nil
+}
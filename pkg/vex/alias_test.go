@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingResolver struct{}
+
+func (failingResolver) Aliases(context.Context, string) ([]string, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestResolveAliases(t *testing.T) {
+	doc := New()
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2021-44228", Aliases: []VulnerabilityID{"GHSA-jfh8-c2jp-5v3q"}},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "GO-2022-0001"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusFixed,
+		},
+	}
+	require.Nil(t, doc.EffectiveStatement("pkg:oci/app", "CVE-2022-0001"))
+
+	r := AliasMap{
+		"CVE-2021-44228": {"GHSA-jfh8-c2jp-5v3q", "CVE-2021-44228", "GO-2021-0001"},
+		"GO-2022-0001":   {"CVE-2022-0001"},
+	}
+	hash, err := doc.ContentHash()
+	require.NoError(t, err)
+	resolved, err := doc.ResolveAliases(context.Background(), r)
+	require.NoError(t, err)
+	require.Equal(t, []VulnerabilityID{"GHSA-jfh8-c2jp-5v3q", "GO-2021-0001"}, resolved.Statements[0].Vulnerability.Aliases)
+
+	s := resolved.EffectiveStatement("pkg:oci/app", "CVE-2022-0001")
+	require.NotNil(t, s)
+	require.Equal(t, StatusFixed, s.Status)
+	require.Len(t, resolved.Matches("GO-2021-0001", "pkg:oci/app", nil), 1)
+
+	// The original document is left as published
+	require.Equal(t, []VulnerabilityID{"GHSA-jfh8-c2jp-5v3q"}, doc.Statements[0].Vulnerability.Aliases)
+	require.Nil(t, doc.EffectiveStatement("pkg:oci/app", "CVE-2022-0001"))
+	after, err := doc.ContentHash()
+	require.NoError(t, err)
+	require.Equal(t, hash, after)
+
+	_, err = doc.ResolveAliases(context.Background(), failingResolver{})
+	require.Error(t, err)
+}
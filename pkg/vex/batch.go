@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// QueryPair is a vulnerability, product and subcomponents tuple to match in
+// a batch.
+type QueryPair struct {
+	Vulnerability string
+	Product       string
+	Subcomponents []string
+}
+
+// MatchResult holds the statements matching a QueryPair.
+type MatchResult struct {
+	Pair QueryPair
+
+	// Statements are the matching statements ordered from oldest to newest
+	Statements []Statement
+
+	// Effective is the latest of the matching statements or nil if there
+	// are none.
+	Effective *Statement
+}
+
+// MatchesBatch matches many tuples in one call. It returns a result for each
+// pair in the same order. Product keys and identifier comparisons are
+// computed once per distinct value in the batch instead of once per pair,
+// which makes it much faster than calling Matches in a loop when scanning
+// many components.
+func (idx *Index) MatchesBatch(pairs []QueryPair) []MatchResult {
+	b := newBatchMatcher()
+	results := make([]MatchResult, len(pairs))
+	for i := range pairs {
+		results[i] = b.match(idx, &pairs[i])
+	}
+	return results
+}
+
+// ConcurrentOptions configure concurrent matching.
+type ConcurrentOptions struct {
+	// Workers is the number of goroutines matching pairs. It defaults to
+	// GOMAXPROCS.
+	Workers int
+
+	// ChunkSize is the number of pairs a worker takes at a time. It
+	// defaults to 64.
+	ChunkSize int
+}
+
+// MatchesConcurrentFunc matches the pairs across a pool of goroutines and
+// calls fn with each result as it is ready, so results are not in the order
+// of the pairs. fn is always called from the calling goroutine. Matching
+// stops when fn returns false, in which case the error is nil, or when the
+// context is done, in which case the context error is returned.
+func (idx *Index) MatchesConcurrentFunc(ctx context.Context, pairs []QueryPair, opts *ConcurrentOptions, fn func(MatchResult) bool) error {
+	if opts == nil {
+		opts = &ConcurrentOptions{}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 64
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunks := make(chan []QueryPair)
+	results := make(chan []MatchResult, workers)
+
+	go func() {
+		defer close(chunks)
+		for start := 0; start < len(pairs); start += chunkSize {
+			end := min(start+chunkSize, len(pairs))
+			select {
+			case chunks <- pairs[start:end]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker memoizes on its own to avoid sharing maps
+			b := newBatchMatcher()
+			for chunk := range chunks {
+				res := make([]MatchResult, len(chunk))
+				for j := range chunk {
+					res[j] = b.match(idx, &chunk[j])
+				}
+				select {
+				case results <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for res := range results {
+		for i := range res {
+			if ctx.Err() != nil {
+				break
+			}
+			if !fn(res[i]) {
+				cancel()
+				return nil
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+type componentMatchKey struct {
+	component  *Component
+	identifier string
+}
+
+// batchMatcher memoizes the identifier comparisons of a batch.
+type batchMatcher struct {
+	productKeys map[string]string
+	components  map[componentMatchKey]bool
+}
+
+func newBatchMatcher() *batchMatcher {
+	return &batchMatcher{
+		productKeys: map[string]string{},
+		components:  map[componentMatchKey]bool{},
+	}
+}
+
+// match returns the result for a pair from the index.
+func (b *batchMatcher) match(idx *Index, pair *QueryPair) MatchResult {
+	key, ok := b.productKeys[pair.Product]
+	if !ok {
+		key = normalizeProductKey(pair.Product)
+		b.productKeys[pair.Product] = key
+	}
+
+	entries := idx.lookup(pair.Vulnerability, pair.Product, key, func(s *Statement) bool {
+		return b.statementMatches(s, pair.Product, pair.Subcomponents)
+	})
+
+	res := MatchResult{Pair: *pair, Statements: make([]Statement, 0, len(entries))}
+	for _, e := range entries {
+		res.Statements = append(res.Statements, *e.statement)
+	}
+	if len(entries) > 0 {
+		res.Effective = entries[len(entries)-1].statement
+	}
+	return res
+}
+
+func (b *batchMatcher) componentMatches(c *Component, identifier string) bool {
+	k := componentMatchKey{c, identifier}
+	if m, ok := b.components[k]; ok {
+		return m
+	}
+	m := c.Matches(identifier)
+	b.components[k] = m
+	return m
+}
+
+// statementMatches does the product part of Statement.Matches using the
+// memoized comparisons. The vulnerability is matched by the index lookup.
+func (b *batchMatcher) statementMatches(s *Statement, product string, subcomponents []string) bool {
+	for i := range s.Products {
+		p := &s.Products[i]
+		if !b.componentMatches(&p.Component, product) {
+			continue
+		}
+		if len(p.Subcomponents) == 0 || len(subcomponents) == 0 {
+			return true
+		}
+		for _, sc := range subcomponents {
+			if sc == "" {
+				return true
+			}
+			for j := range p.Subcomponents {
+				if b.componentMatches(&p.Subcomponents[j].Component, sc) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
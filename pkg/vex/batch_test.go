@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesBatch(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	doc1 := New()
+	doc1.Timestamp = &t1
+	doc1.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111", Aliases: []VulnerabilityID{"GHSA-aaaa-bbbb-cccc"}},
+			Products: []Product{{
+				Component:     Component{ID: "pkg:oci/app"},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/example.com/lib@v1.0.0"}}},
+			}},
+			Status: StatusUnderInvestigation,
+		},
+	}
+	doc2 := New()
+	doc2.Timestamp = &t2
+	doc2.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app@sha256%3Aabc"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		},
+	}
+	idx := NewIndex(&doc1, &doc2)
+
+	pairs := []QueryPair{
+		{Vulnerability: "CVE-2023-1111", Product: "pkg:oci/app@sha256%3Aabc", Subcomponents: []string{"pkg:golang/example.com/lib@v1.0.0"}},
+		{Vulnerability: "GHSA-aaaa-bbbb-cccc", Product: "pkg:oci/app@sha256%3Adef"},
+		{Vulnerability: "CVE-2023-1111", Product: "pkg:oci/app@sha256%3Adef", Subcomponents: []string{"pkg:golang/example.com/other@v1.0.0"}},
+		{Vulnerability: "CVE-2023-1111", Product: "pkg:oci/other"},
+		{Vulnerability: "CVE-2000-0000", Product: "pkg:oci/app"},
+	}
+	results := idx.MatchesBatch(pairs)
+	require.Len(t, results, len(pairs))
+
+	for i, r := range results {
+		require.Equal(t, pairs[i], r.Pair)
+		require.Equal(t, idx.Matches(r.Pair.Vulnerability, r.Pair.Product, r.Pair.Subcomponents), r.Statements, "pair %d", i)
+		if len(r.Statements) == 0 {
+			require.Nil(t, r.Effective)
+			continue
+		}
+		require.Equal(t, idx.EffectiveStatement(r.Pair.Product, r.Pair.Vulnerability), r.Effective)
+	}
+
+	require.Len(t, results[0].Statements, 2)
+	require.Equal(t, StatusNotAffected, results[0].Effective.Status)
+	require.Equal(t, StatusUnderInvestigation, results[1].Effective.Status)
+	require.Empty(t, results[2].Statements)
+	require.Empty(t, results[3].Statements)
+	require.Empty(t, results[4].Statements)
+}
+
+func BenchmarkIndexMatchesBatch(b *testing.B) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.Timestamp = &ts
+	for i := 0; i < 5000; i++ {
+		doc.Statements = append(doc.Statements, Statement{
+			Vulnerability: Vulnerability{Name: VulnerabilityID(fmt.Sprintf("CVE-2023-%d", i))},
+			Products:      []Product{{Component: Component{ID: fmt.Sprintf("pkg:apk/wolfi/pkg%d@1.0", i%100)}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		})
+	}
+	idx := NewIndex(&doc)
+	pairs := make([]QueryPair, 5000)
+	for i := range pairs {
+		pairs[i] = QueryPair{
+			Vulnerability: fmt.Sprintf("CVE-2023-%d", i),
+			Product:       fmt.Sprintf("pkg:apk/wolfi/pkg%d@1.0", i%100),
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.MatchesBatch(pairs)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+)
+
+// LinkPrevious sets the previous field of the document to the hash of the
+// full contents of its prior revision, see SigningHash. Transparency log
+// entries are left out of the hash as they are added to a revision after it
+// is published.
+func (vexDoc *VEX) LinkPrevious(prev *VEX) error {
+	hash, err := prev.SigningHash()
+	if err != nil {
+		return fmt.Errorf("hashing previous revision: %w", err)
+	}
+	vexDoc.Previous = hash
+	return nil
+}
+
+// VerifyChain checks that revisions, ordered from the oldest to the newest,
+// form a chain: all of them have the same @id and each one links to the
+// hash of the one before it, as set by LinkPrevious, and has a greater
+// version. The first revision may link to an older one not in the list.
+//
+// As the @id must not change, documents with canonical IDs generated from
+// their contents, which NextVersion regenerates, cannot be chained.
+func VerifyChain(revisions []*VEX) error {
+	for i := 1; i < len(revisions); i++ {
+		prev, doc := revisions[i-1], revisions[i]
+		if doc.ID != prev.ID {
+			return fmt.Errorf("revision %d has @id %q, not %q", i, doc.ID, prev.ID)
+		}
+		hash, err := prev.SigningHash()
+		if err != nil {
+			return fmt.Errorf("hashing revision %d: %w", i-1, err)
+		}
+		if doc.Previous == "" {
+			return fmt.Errorf("revision %d does not link to the previous one", i)
+		}
+		if doc.Previous != hash {
+			return fmt.Errorf("revision %d links to %s but the previous revision hashes to %s", i, doc.Previous, hash)
+		}
+		if doc.Version <= prev.Version {
+			return fmt.Errorf("%w: revision %d has version %d, not greater than %d", ErrVersionConflict, i, doc.Version, prev.Version)
+		}
+	}
+	return nil
+}
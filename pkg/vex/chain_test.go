@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyChain(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	v1 := New(WithTimestamp(func() time.Time { return t1 }), WithID("https://example.com/vex/1"))
+	require.NoError(t, v1.AddStatement(Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusUnderInvestigation,
+	}))
+	hash1, err := v1.SigningHash()
+	require.NoError(t, err)
+
+	v2, err := v1.NextVersion()
+	require.NoError(t, err)
+	require.Empty(t, v2.Previous)
+	require.NoError(t, v2.LinkPrevious(&v1))
+	require.Equal(t, hash1, v2.Previous)
+
+	// Linked documents keep linking new versions
+	v3, err := v2.NextVersion(func(d *VEX) error {
+		d.Statements[0].Status = StatusFixed
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, v3.Previous)
+	require.NotEqual(t, v2.Previous, v3.Previous)
+
+	require.NoError(t, VerifyChain([]*VEX{&v1, v2, v3}))
+	require.NoError(t, VerifyChain([]*VEX{v2, v3}))
+	require.NoError(t, VerifyChain(nil))
+
+	// The link is part of the hash
+	hash2, err := v2.CanonicalHash()
+	require.NoError(t, err)
+	v2.Previous = ""
+	unlinked, err := v2.CanonicalHash()
+	require.NoError(t, err)
+	require.NotEqual(t, hash2, unlinked)
+	require.Error(t, VerifyChain([]*VEX{&v1, v2}))
+	v2.Previous = hash1
+
+	// Tampering with any field of a revision breaks the chain
+	v2.Statements[0].Status = StatusAffected
+	require.Error(t, VerifyChain([]*VEX{&v1, v2, v3}))
+	v2.Statements[0].Status = StatusUnderInvestigation
+	v2.Statements[0].StatusNotes = "Looking into it"
+	require.Error(t, VerifyChain([]*VEX{&v1, v2, v3}))
+	v2.Statements[0].StatusNotes = ""
+	require.NoError(t, VerifyChain([]*VEX{&v1, v2, v3}))
+
+	// Transparency log entries are recorded after linking
+	v2.TransparencyLog = []TransparencyLogEntry{{LogIndex: 1}}
+	require.NoError(t, VerifyChain([]*VEX{&v1, v2, v3}))
+
+	// All revisions are of the same document
+	other := v3.Clone()
+	other.ID = "https://example.com/vex/2"
+	require.ErrorContains(t, VerifyChain([]*VEX{&v1, v2, other}), "@id")
+
+	require.Error(t, VerifyChain([]*VEX{&v1, v3}))
+	require.Error(t, VerifyChain([]*VEX{v2, &v1}))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Actions recorded in the changelog by the functions of this package
+const (
+	ChangeAddStatement = "add_statement"
+	ChangeMerge        = "merge"
+	ChangeApplyPatch   = "apply_patch"
+	ChangeRollback     = "rollback"
+)
+
+// ChangelogEntry records a change made to a document: when it happened,
+// who made it and with what tool. Documents only keep a changelog when
+// enabled with WithChangelog or when they already have one, in which case
+// AddStatement, MergeDocuments, ApplyPatch and Rollback append entries to
+// it.
+type ChangelogEntry struct {
+	// Timestamp is when the change was made
+	Timestamp time.Time `json:"timestamp"`
+
+	// Author and Tooling are those of the document at the time
+	Author  string `json:"author,omitempty"`
+	Tooling string `json:"tooling,omitempty"`
+
+	// Action is the kind of change, such as ChangeAddStatement
+	Action string `json:"action"`
+
+	// Summary describes the change
+	Summary string `json:"summary,omitempty"`
+}
+
+// WithChangelog enables the changelog of a new document.
+func WithChangelog() Option {
+	return func(doc *VEX) {
+		if doc.Changelog == nil {
+			doc.Changelog = []ChangelogEntry{}
+		}
+	}
+}
+
+// RecordChange appends an entry to the changelog of the document, if it
+// keeps one. Tools making changes not covered by the functions of this
+// package use it to keep the changelog complete.
+func (vexDoc *VEX) RecordChange(action, summary string) {
+	if vexDoc.Changelog == nil {
+		return
+	}
+	// Clip so documents sharing the changelog don't see the entry
+	vexDoc.Changelog = append(slices.Clip(vexDoc.Changelog), ChangelogEntry{
+		Timestamp: time.Now().UTC(),
+		Author:    vexDoc.Author,
+		Tooling:   vexDoc.Tooling,
+		Action:    action,
+		Summary:   summary,
+	})
+}
+
+// statementSummary describes a statement in a changelog entry.
+func statementSummary(s *Statement) string {
+	products := []string{}
+	for i := range s.Products {
+		products = append(products, s.Products[i].ID)
+	}
+	return fmt.Sprintf("%s on %s: %s", s.Vulnerability.Name, strings.Join(products, ", "), s.Status)
+}
+
+// mergeChangelogs returns the entries of the documents sorted by time or
+// nil if none of them keeps a changelog.
+func mergeChangelogs(docs []*VEX) []ChangelogEntry {
+	var ret []ChangelogEntry
+	for _, doc := range docs {
+		if doc.Changelog == nil {
+			continue
+		}
+		if ret == nil {
+			ret = []ChangelogEntry{}
+		}
+		ret = append(ret, doc.Changelog...)
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Timestamp.Before(ret[j].Timestamp) })
+	return ret
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelog(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	stmt := Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusFixed,
+	}
+
+	// Documents don't keep a changelog unless enabled
+	plain := New(WithTimestamp(func() time.Time { return t1 }))
+	require.NoError(t, plain.AddStatement(stmt))
+	require.Nil(t, plain.Changelog)
+
+	doc := New(WithTimestamp(func() time.Time { return t1 }), WithAuthor("Example Inc."), WithTooling("vexctl"), WithChangelog())
+	doc.ID = "https://example.com/vex/1"
+	require.NoError(t, doc.AddStatement(stmt))
+	require.Len(t, doc.Changelog, 1)
+	require.Equal(t, ChangeAddStatement, doc.Changelog[0].Action)
+	require.Equal(t, "Example Inc.", doc.Changelog[0].Author)
+	require.Equal(t, "vexctl", doc.Changelog[0].Tooling)
+	require.Equal(t, "Added statement about CVE-2023-1111 on pkg:oci/app: fixed", doc.Changelog[0].Summary)
+
+	// Invalid statements are not recorded
+	require.Error(t, doc.AddStatement(Statement{Status: "bogus"}))
+	require.Len(t, doc.Changelog, 1)
+
+	// The changelog is valid in the schema and survives parsing
+	var b bytes.Buffer
+	require.NoError(t, doc.ToJSON(&b))
+	require.NoError(t, Validate(b.Bytes()))
+	parsed, err := Parse(b.Bytes())
+	require.NoError(t, err)
+	require.Len(t, parsed.Changelog, 1)
+
+	updated := parsed.Clone()
+	updated.Statements[0].Status = StatusAffected
+	updated.Statements[0].ActionStatement = "Update"
+	require.NoError(t, parsed.ApplyPatch(Diff(parsed.Clone(), updated)))
+	require.Len(t, parsed.Changelog, 2)
+	require.Equal(t, ChangeApplyPatch, parsed.Changelog[1].Action)
+	require.Len(t, doc.Changelog, 1)
+
+	merged, err := MergeDocuments([]*VEX{&plain, parsed})
+	require.NoError(t, err)
+	require.Len(t, merged.Changelog, 3)
+	require.Equal(t, ChangeMerge, merged.Changelog[2].Action)
+	require.Len(t, parsed.Changelog, 2)
+
+	merged, err = MergeDocuments([]*VEX{&plain})
+	require.NoError(t, err)
+	require.Nil(t, merged.Changelog)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ClairEnrichmentType is the type of the enrichment records rendered for
+// clair. It follows clair's convention of a media type naming the enricher.
+const ClairEnrichmentType = "message/vnd.clair.map.vulnerability; enricher=openvex"
+
+// ClairFeed is a set of enrichment records that a clair enricher or updater
+// can load. Each record is tagged with the vulnerability and the package purl
+// it applies to, so clair can look the VEX data up when it reports a finding.
+type ClairFeed struct {
+	Type string `json:"type"`
+
+	// Fingerprint identifies the contents of the feed. Updaters compare it
+	// to skip loading a feed that did not change.
+	Fingerprint string `json:"fingerprint"`
+
+	Records []ClairEnrichmentRecord `json:"records"`
+}
+
+// ClairEnrichmentRecord is the latest VEX data about a vulnerability in a
+// package. Tags holds the vulnerability ID and the package purl.
+type ClairEnrichmentRecord struct {
+	Tags       []string        `json:"tags"`
+	Enrichment ClairEnrichment `json:"enrichment"`
+}
+
+// ClairEnrichment is the VEX data rendered in an enrichment record.
+type ClairEnrichment struct {
+	Vulnerability   string        `json:"vulnerability"`
+	Package         string        `json:"package"`
+	Status          Status        `json:"status"`
+	Justification   Justification `json:"justification,omitempty"`
+	ImpactStatement string        `json:"impact_statement,omitempty"`
+	ActionStatement string        `json:"action_statement,omitempty"`
+	StatusNotes     string        `json:"status_notes,omitempty"`
+	Timestamp       *time.Time    `json:"timestamp,omitempty"`
+	Document        string        `json:"document,omitempty"`
+}
+
+// ClairFeedUpdate holds the changes between two versions of a feed so an
+// updater can apply them incrementally.
+type ClairFeedUpdate struct {
+	Type                string `json:"type"`
+	Fingerprint         string `json:"fingerprint"`
+	PreviousFingerprint string `json:"previous_fingerprint"`
+
+	// Updated holds the records that are new or whose data changed
+	Updated []ClairEnrichmentRecord `json:"updated"`
+
+	// Removed holds the tags of the records no longer in the feed
+	Removed [][]string `json:"removed"`
+}
+
+// ToClairFeed renders the latest statement about each vulnerability and
+// package in the document as a clair enrichment record. All statuses are
+// included so clair can both suppress not_affected and fixed findings and
+// annotate the affected ones. Components without a purl are skipped.
+func (vexDoc *VEX) ToClairFeed() (*ClairFeed, error) {
+	feed := &ClairFeed{
+		Type:    ClairEnrichmentType,
+		Records: []ClairEnrichmentRecord{},
+	}
+	for _, ps := range vexDoc.latestPackageStatements() {
+		ts := ps.statement.Timestamp
+		if ts == nil {
+			ts = vexDoc.Timestamp
+		}
+		feed.Records = append(feed.Records, ClairEnrichmentRecord{
+			Tags: []string{ps.vulnerability, ps.purl},
+			Enrichment: ClairEnrichment{
+				Vulnerability:   ps.vulnerability,
+				Package:         ps.purl,
+				Status:          ps.statement.Status,
+				Justification:   ps.statement.Justification,
+				ImpactStatement: ps.statement.ImpactStatement,
+				ActionStatement: ps.statement.ActionStatement,
+				StatusNotes:     ps.statement.StatusNotes,
+				Timestamp:       ts,
+				Document:        vexDoc.ID,
+			},
+		})
+	}
+
+	fp, err := clairFingerprint(feed.Records)
+	if err != nil {
+		return nil, err
+	}
+	feed.Fingerprint = fp
+	return feed, nil
+}
+
+// UpdateFrom returns the changes needed to bring a consumer holding the
+// previous feed to this one. A nil previous feed produces an update holding
+// all records.
+func (feed *ClairFeed) UpdateFrom(previous *ClairFeed) (*ClairFeedUpdate, error) {
+	update := &ClairFeedUpdate{
+		Type:        feed.Type,
+		Fingerprint: feed.Fingerprint,
+		Updated:     []ClairEnrichmentRecord{},
+		Removed:     [][]string{},
+	}
+
+	old := map[string]ClairEnrichmentRecord{}
+	if previous != nil {
+		update.PreviousFingerprint = previous.Fingerprint
+		for _, r := range previous.Records {
+			old[clairRecordKey(&r)] = r
+		}
+	}
+
+	for _, r := range feed.Records {
+		key := clairRecordKey(&r)
+		prev, ok := old[key]
+		delete(old, key)
+		if ok {
+			same, err := clairRecordsEqual(&prev, &r)
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+		}
+		update.Updated = append(update.Updated, r)
+	}
+
+	// Walk the previous records again to keep the removals in feed order
+	if previous != nil {
+		for _, r := range previous.Records {
+			if _, ok := old[clairRecordKey(&r)]; ok {
+				update.Removed = append(update.Removed, r.Tags)
+			}
+		}
+	}
+	return update, nil
+}
+
+// ToJSON writes the feed as JSON.
+func (feed *ClairFeed) ToJSON(w io.Writer) error {
+	return writeClairJSON(w, feed)
+}
+
+// ToJSON writes the update as JSON.
+func (update *ClairFeedUpdate) ToJSON(w io.Writer) error {
+	return writeClairJSON(w, update)
+}
+
+func writeClairJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding clair feed: %w", err)
+	}
+	return nil
+}
+
+func clairRecordKey(r *ClairEnrichmentRecord) string {
+	return r.Enrichment.Vulnerability + "\x00" + r.Enrichment.Package
+}
+
+// clairRecordsEqual compares the data of two records, ignoring the document
+// that issued them as the fingerprint does.
+func clairRecordsEqual(a, b *ClairEnrichmentRecord) (bool, error) {
+	fa, err := clairFingerprint([]ClairEnrichmentRecord{*a})
+	if err != nil {
+		return false, err
+	}
+	fb, err := clairFingerprint([]ClairEnrichmentRecord{*b})
+	if err != nil {
+		return false, err
+	}
+	return fa == fb, nil
+}
+
+// clairFingerprint hashes the records of a feed. The document ID is left
+// out so re-issuing a document with the same data does not force a reload.
+func clairFingerprint(records []ClairEnrichmentRecord) (string, error) {
+	h := sha256.New()
+	for i := range records {
+		e := records[i].Enrichment
+		e.Document = ""
+		data, err := json.Marshal(e)
+		if err != nil {
+			return "", fmt.Errorf("marshaling clair record: %w", err)
+		}
+		h.Write(data)
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
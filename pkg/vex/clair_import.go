@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/openvex/go-vex/pkg/clair"
+)
+
+// clairDistributionTypes maps the IDs of the distributions known to clair to
+// the purl type of their packages.
+var clairDistributionTypes = map[string]string{
+	"alpine":        packageurl.TypeApk,
+	"wolfi":         packageurl.TypeApk,
+	"chainguard":    packageurl.TypeApk,
+	"debian":        packageurl.TypeDebian,
+	"ubuntu":        packageurl.TypeDebian,
+	"rhel":          packageurl.TypeRPM,
+	"centos":        packageurl.TypeRPM,
+	"fedora":        packageurl.TypeRPM,
+	"rocky":         packageurl.TypeRPM,
+	"almalinux":     packageurl.TypeRPM,
+	"ol":            packageurl.TypeRPM,
+	"amzn":          packageurl.TypeRPM,
+	"sles":          packageurl.TypeRPM,
+	"opensuse-leap": packageurl.TypeRPM,
+	"photon":        packageurl.TypeRPM,
+}
+
+// clairRepositoryTypes maps the names of the language package repositories
+// known to clair to the purl type of their packages.
+var clairRepositoryTypes = map[string]string{
+	"pypi":     packageurl.TypePyPi,
+	"maven":    packageurl.TypeMaven,
+	"npm":      packageurl.TypeNPM,
+	"go":       packageurl.TypeGolang,
+	"rubygems": packageurl.TypeGem,
+}
+
+// FromClairReport reads a clair vulnerability report and returns a skeleton
+// document to triage its findings. It has an under_investigation statement
+// for each vulnerability reported. The product is the manifest, identified
+// by its digest, and the vulnerable packages are its subcomponents,
+// identified by a purl built from the distribution or language repository
+// they were installed from.
+func FromClairReport(r io.Reader) (*VEX, error) {
+	report, err := clair.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if report.ManifestHash == "" {
+		return nil, fmt.Errorf("report does not have a manifest hash")
+	}
+
+	product := Component{ID: report.ManifestHash}
+	if algo, digest, ok := strings.Cut(report.ManifestHash, ":"); ok {
+		product.Hashes = map[Algorithm]Hash{hashAlgorithm(algo): Hash(digest)}
+	}
+
+	purls := map[string][]string{}
+	order := []string{}
+	for pkgID, vulnIDs := range report.PackageVulnerabilities {
+		purl := clairPackagePurl(report, pkgID)
+		if purl == "" {
+			return nil, fmt.Errorf("package %q is not in the report", pkgID)
+		}
+		for _, vulnID := range vulnIDs {
+			v, ok := report.Vulnerabilities[vulnID]
+			if !ok {
+				return nil, fmt.Errorf("vulnerability %q is not in the report", vulnID)
+			}
+			name := v.Name
+			if name == "" {
+				name = v.ID
+			}
+			if _, ok := purls[name]; !ok {
+				order = append(order, name)
+			}
+			purls[name] = append(purls[name], purl)
+		}
+	}
+
+	sort.Strings(order)
+
+	doc := New()
+	doc.Tooling = "clair"
+	for _, name := range order {
+		p := Product{Component: product}
+		sort.Strings(purls[name])
+		for i, purl := range purls[name] {
+			if i > 0 && purls[name][i-1] == purl {
+				continue
+			}
+			p.Subcomponents = append(p.Subcomponents, Subcomponent{Component: Component{
+				ID:          purl,
+				Identifiers: map[IdentifierType]string{PURL: purl},
+			}})
+		}
+		doc.Statements = append(doc.Statements, Statement{
+			Vulnerability: Vulnerability{Name: VulnerabilityID(name)},
+			Products:      []Product{p},
+			Status:        StatusUnderInvestigation,
+		})
+	}
+
+	if _, err := doc.GenerateCanonicalID(); err != nil {
+		return nil, fmt.Errorf("generating document ID: %w", err)
+	}
+	return &doc, nil
+}
+
+// clairPackagePurl returns the purl of a package of the report. Packages
+// that were not installed from a known distribution or repository get a
+// generic purl. It returns an empty string if the package is not in the
+// report.
+func clairPackagePurl(report *clair.VulnerabilityReport, pkgID string) string {
+	pkg, ok := report.Packages[pkgID]
+	if !ok {
+		return ""
+	}
+
+	purlType := packageurl.TypeGeneric
+	namespace, name := "", pkg.Name
+	qualifiers := map[string]string{}
+	if pkg.Arch != "" {
+		qualifiers["arch"] = pkg.Arch
+	}
+
+	for _, env := range report.Environments[pkgID] {
+		if d, ok := report.Distributions[env.DistributionID]; ok {
+			if t, ok := clairDistributionTypes[d.DID]; ok {
+				purlType, namespace = t, d.DID
+				if d.VersionID != "" {
+					qualifiers["distro"] = d.DID + "-" + d.VersionID
+				}
+				break
+			}
+		}
+		for _, repoID := range env.RepositoryIDs {
+			if t, ok := clairRepositoryTypes[report.Repositories[repoID].Name]; ok {
+				purlType = t
+			}
+		}
+		if purlType != packageurl.TypeGeneric {
+			break
+		}
+	}
+
+	switch purlType {
+	case packageurl.TypeMaven:
+		if group, artifact, ok := strings.Cut(name, ":"); ok {
+			namespace, name = group, artifact
+		}
+	case packageurl.TypeGolang, packageurl.TypeNPM:
+		if i := strings.LastIndex(name, "/"); i > 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	}
+
+	return packageurl.NewPackageURL(
+		purlType, namespace, name, pkg.Version,
+		packageurl.QualifiersFromMap(qualifiers), "",
+	).ToString()
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromClairReport(t *testing.T) {
+	f, err := os.Open("testdata/clair-report.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	doc, err := FromClairReport(f)
+	require.NoError(t, err)
+	require.Equal(t, "clair", doc.Tooling)
+	require.Len(t, doc.Statements, 3)
+
+	// Vulnerability records of the same name are merged
+	s := doc.Statements[0]
+	require.Equal(t, VulnerabilityID("30"), s.Vulnerability.Name)
+	require.Equal(t, "pkg:generic/vendored@1.0", s.Products[0].Subcomponents[0].ID)
+
+	s = doc.Statements[1]
+	require.Equal(t, VulnerabilityID("CVE-2023-1111"), s.Vulnerability.Name)
+	require.Equal(t, StatusUnderInvestigation, s.Status)
+	require.Equal(t, "sha256:fc84b5febd328eccaa913807716887b3eb5ed08bc22cc6933a9ebf82766725e3", s.Products[0].ID)
+	require.Equal(t, Hash("fc84b5febd328eccaa913807716887b3eb5ed08bc22cc6933a9ebf82766725e3"), s.Products[0].Hashes[SHA256])
+	require.Len(t, s.Products[0].Subcomponents, 2)
+	require.Equal(t, "pkg:apk/alpine/libcrypto3@3.0.8-r0?arch=x86_64&distro=alpine-3.18", s.Products[0].Subcomponents[0].ID)
+	require.Equal(t, "pkg:apk/alpine/openssl@3.0.8-r0?arch=x86_64&distro=alpine-3.18", s.Products[0].Subcomponents[1].ID)
+
+	s = doc.Statements[2]
+	require.Equal(t, "pkg:pypi/requests@2.28.0", s.Products[0].Subcomponents[0].ID)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+
+	for name, data := range map[string]string{
+		"invalid json":          `{"manifest_hash": `,
+		"no manifest":           `{"packages": {}}`,
+		"unknown package":       `{"manifest_hash": "sha256:abc", "package_vulnerabilities": {"1": ["1"]}}`,
+		"unknown vulnerability": `{"manifest_hash": "sha256:abc", "packages": {"1": {"id": "1", "name": "a"}}, "package_vulnerabilities": {"1": ["1"]}}`,
+	} {
+		_, err := FromClairReport(strings.NewReader(data))
+		require.Error(t, err, name)
+	}
+}
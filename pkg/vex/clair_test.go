@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClairFeed(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.ID = "https://example.com/vex-1"
+	doc.Timestamp = &ts
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/curl@8.0.0"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		},
+		{
+			Vulnerability:   Vulnerability{Name: "CVE-2023-2222"},
+			Products:        []Product{{Component: Component{ID: "pkg:apk/wolfi/openssl@3.0.8"}}},
+			Status:          StatusAffected,
+			ActionStatement: "Upgrade to 3.0.9",
+		},
+	}
+
+	feed, err := doc.ToClairFeed()
+	require.NoError(t, err)
+	require.Equal(t, ClairEnrichmentType, feed.Type)
+	require.Len(t, feed.Records, 2)
+	require.Equal(t, []string{"CVE-2023-1111", "pkg:apk/wolfi/curl@8.0.0"}, feed.Records[0].Tags)
+	require.Equal(t, StatusNotAffected, feed.Records[0].Enrichment.Status)
+	require.Equal(t, &ts, feed.Records[0].Enrichment.Timestamp)
+	require.Equal(t, "https://example.com/vex-1", feed.Records[1].Enrichment.Document)
+
+	var buf bytes.Buffer
+	require.NoError(t, feed.ToJSON(&buf))
+	decoded := &ClairFeed{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	require.Equal(t, feed.Fingerprint, decoded.Fingerprint)
+
+	// A first update holds everything
+	full, err := feed.UpdateFrom(nil)
+	require.NoError(t, err)
+	require.Len(t, full.Updated, 2)
+	require.Empty(t, full.Removed)
+
+	// Re-issuing the document with the same data does not change the feed
+	doc.ID = "https://example.com/vex-2"
+	same, err := doc.ToClairFeed()
+	require.NoError(t, err)
+	require.Equal(t, feed.Fingerprint, same.Fingerprint)
+	update, err := same.UpdateFrom(feed)
+	require.NoError(t, err)
+	require.Empty(t, update.Updated)
+	require.Empty(t, update.Removed)
+
+	// Change one statement, drop the other and add a new one
+	ts2 := ts.Add(time.Hour)
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/openssl@3.0.8"}}},
+			Status:        StatusFixed,
+			Timestamp:     &ts2,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-3333"},
+			Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/zlib@1.2.13"}}},
+			Status:        StatusUnderInvestigation,
+		},
+	}
+	next, err := doc.ToClairFeed()
+	require.NoError(t, err)
+	require.NotEqual(t, feed.Fingerprint, next.Fingerprint)
+
+	update, err = next.UpdateFrom(feed)
+	require.NoError(t, err)
+	require.Equal(t, feed.Fingerprint, update.PreviousFingerprint)
+	require.Equal(t, next.Fingerprint, update.Fingerprint)
+	require.Len(t, update.Updated, 2)
+	require.Equal(t, StatusFixed, update.Updated[0].Enrichment.Status)
+	require.Equal(t, "CVE-2023-3333", update.Updated[1].Enrichment.Vulnerability)
+	require.Equal(t, [][]string{{"CVE-2023-1111", "pkg:apk/wolfi/curl@8.0.0"}}, update.Removed)
+}
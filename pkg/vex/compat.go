@@ -6,20 +6,85 @@ SPDX-License-Identifier: Apache-2.0
 package vex
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type legacyParser func([]byte) (*VEX, error)
 
+// ParseCompat parses an OpenVEX document of any supported spec version. The
+// spec version is detected from the document context, documents written
+// against older versions of the spec are upconverted to the current structs.
+func ParseCompat(data []byte) (*VEX, error) {
+	return ParseCompatWithOptions(&ParseOptions{}, data)
+}
+
+// ParseCompatWithOptions parses an OpenVEX document of any supported spec
+// version like ParseCompat, performing the checks specified in the options.
+// Documents of older versions are checked after being upconverted, except
+// for the context which is checked as found in the data. Strict parsing only
+// applies to documents of the current version.
+func ParseCompatWithOptions(opts *ParseOptions, data []byte) (*VEX, error) {
+	documentContextLocator, err := parseContext(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if documentContextLocator == "" {
+		return nil, fmt.Errorf("%w: document does not have an OpenVEX context", ErrInvalidContext)
+	}
+
+	version, err := ContextVersion(documentContextLocator)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == SpecVersion {
+		doc, err := ParseWithOptions(opts, data)
+		if err != nil {
+			return nil, err
+		}
+		doc.SourceVersion = version
+		return doc, nil
+	}
+
+	parser := getLegacyVersionParser(version)
+	if parser == nil {
+		return nil, fmt.Errorf("%w: unable to get parser for version %s", ErrInvalidContext, version)
+	}
+
+	if opts.ValidateSchema {
+		if err := ValidateVersion(version, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", errMsgParse, err)
+		}
+	}
+
+	doc, err := parser(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing document: %w", err)
+	}
+	doc.SourceVersion = version
+
+	if err := opts.checkDocument(doc, documentContextLocator); err != nil {
+		return nil, err
+	}
+	if opts.OnWarning != nil && version == "0.0.1" {
+		warnings001(data, opts.OnWarning)
+	}
+	return doc, nil
+}
+
 // getLegacyVersionParser returns a parser that can read older OpenVEX formats. The
 // project will have a version skew policy and try to support older versions
 // up to a point. If a version is not supported, this function returns nil.
 func getLegacyVersionParser(version string) legacyParser {
 	switch version {
-	case "v0.0.1":
+	case "0.0.1":
 		return parse001
 	default:
 		return nil
@@ -42,9 +107,9 @@ var parse001 = func(data []byte) (*VEX, error) {
 	newVex.AuthorRole = oldVex.AuthorRole
 	newVex.ID = oldVex.ID
 	newVex.Tooling = oldVex.Tooling
-	ver, err := strconv.Atoi(oldVex.Version)
-	if err == nil {
-		newVex.Version = ver
+	newVex.Supplier = oldVex.Supplier
+	if oldVex.Version > 0 {
+		newVex.Version = int(oldVex.Version)
 	}
 
 	// Transcode the statements
@@ -58,6 +123,13 @@ var parse001 = func(data []byte) (*VEX, error) {
 		newStmt.ImpactStatement = oldStmt.ImpactStatement
 		newStmt.Timestamp = oldStmt.Timestamp
 
+		// Early documents used impact to explain why a product was not
+		// affected, often as free text in the justification field.
+		if newStmt.ImpactStatement == "" {
+			newStmt.ImpactStatement = oldStmt.Impact
+		}
+		upgradeJustification001(&newStmt)
+
 		// Add the vulnerability
 		newStmt.Vulnerability = Vulnerability{
 			Name:        VulnerabilityID(oldStmt.Vulnerability),
@@ -97,7 +169,7 @@ type vex001 struct {
 	Author     string         `json:"author"`
 	AuthorRole string         `json:"role"`
 	Timestamp  *time.Time     `json:"timestamp"`
-	Version    string         `json:"version"`
+	Version    version001     `json:"version"`
 	Tooling    string         `json:"tooling,omitempty"`
 	Supplier   string         `json:"supplier,omitempty"`
 	Statements []statement001 `json:"statements"`
@@ -113,6 +185,209 @@ type statement001 struct {
 	StatusNotes              string     `json:"status_notes,omitempty"`
 	Justification            string     `json:"justification,omitempty"`
 	ImpactStatement          string     `json:"impact_statement,omitempty"`
+	Impact                   string     `json:"impact,omitempty"`
 	ActionStatement          string     `json:"action_statement,omitempty"`
 	ActionStatementTimestamp *time.Time `json:"action_statement_timestamp,omitempty"`
 }
+
+// version001 reads the document version which was written both as a string
+// and as a number in v0.0.1 documents. Values that are not integers are
+// ignored and the document gets the default version.
+type version001 int
+
+func (v *version001) UnmarshalJSON(data []byte) error {
+	if ver, err := strconv.Atoi(strings.Trim(string(data), `"`)); err == nil {
+		*v = version001(ver)
+	}
+	return nil
+}
+
+// upgradeJustification001 moves free text written in the justification
+// field of v0.0.1 statements to the impact statement, or to the status
+// notes when the statement already has one.
+func upgradeJustification001(stmt *Statement) {
+	if stmt.Justification == "" || stmt.Justification.Valid() {
+		return
+	}
+	if stmt.ImpactStatement == "" {
+		stmt.ImpactStatement = string(stmt.Justification)
+	} else {
+		stmt.StatusNotes = strings.TrimSpace(stmt.StatusNotes + "\n" + string(stmt.Justification))
+	}
+	stmt.Justification = ""
+}
+
+// versionConverter translates a document in place between two adjacent
+// versions of the spec.
+type versionConverter func(*VEX) error
+
+// upgraders maps a spec version to the converter that translates documents
+// to the next version. downgraders maps a version to the converter that
+// translates documents to the previous one.
+var (
+	upgraders = map[string]versionConverter{
+		"0.0.1": upgrade001,
+	}
+	downgraders = map[string]versionConverter{
+		"0.2.0": downgrade020,
+	}
+)
+
+// UpgradeTo returns a copy of the document translated to a newer version of
+// the OpenVEX specification. The conversion steps through every version in
+// between, applying the changes in field names and semantics of each one. The
+// source version is the one returned by SpecVersion.
+func (vexDoc *VEX) UpgradeTo(version string) (*VEX, error) {
+	from, to, err := vexDoc.conversionRange(version)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf(
+			"cannot upgrade from OpenVEX %s to older version %s",
+			specVersions[from], specVersions[to],
+		)
+	}
+
+	doc := vexDoc.Clone()
+	for i := from; i < to; i++ {
+		if err := upgraders[specVersions[i]](doc); err != nil {
+			return nil, fmt.Errorf("upgrading to OpenVEX %s: %w", specVersions[i+1], err)
+		}
+	}
+	setConvertedVersion(doc, specVersions[to])
+	return doc, nil
+}
+
+// DowngradeTo returns a copy of the document translated to an older version
+// of the OpenVEX specification. Data that cannot be expressed in the older
+// version is dropped. Use ToJSONVersion to serialize the result in the
+// format of the target version.
+func (vexDoc *VEX) DowngradeTo(version string) (*VEX, error) {
+	from, to, err := vexDoc.conversionRange(version)
+	if err != nil {
+		return nil, err
+	}
+	if from < to {
+		return nil, fmt.Errorf(
+			"cannot downgrade from OpenVEX %s to newer version %s",
+			specVersions[from], specVersions[to],
+		)
+	}
+
+	doc := vexDoc.Clone()
+	for i := from; i > to; i-- {
+		if err := downgraders[specVersions[i]](doc); err != nil {
+			return nil, fmt.Errorf("downgrading to OpenVEX %s: %w", specVersions[i-1], err)
+		}
+	}
+	setConvertedVersion(doc, specVersions[to])
+	return doc, nil
+}
+
+// conversionRange returns the positions in specVersions of the document
+// version and the target version.
+func (vexDoc *VEX) conversionRange(version string) (from, to int, err error) {
+	source, err := vexDoc.SpecVersion()
+	if err != nil {
+		return 0, 0, fmt.Errorf("detecting document version: %w", err)
+	}
+	to = slices.Index(specVersions, strings.TrimPrefix(version, "v"))
+	if to == -1 {
+		return 0, 0, fmt.Errorf("unsupported OpenVEX version %q", version)
+	}
+	from = slices.Index(specVersions, source)
+	if from == -1 {
+		return 0, 0, fmt.Errorf("unsupported OpenVEX document version %q", source)
+	}
+	return from, to, nil
+}
+
+// setConvertedVersion points the document context to version after a
+// conversion.
+func setConvertedVersion(doc *VEX, version string) {
+	doc.Context = fmt.Sprintf("%s/v%s", Context, version)
+	doc.SourceVersion = ""
+}
+
+// upgrade001 translates a v0.0.1 document to v0.2.0. Justifications written
+// as free text are moved to the impact statement.
+func upgrade001(doc *VEX) error {
+	for i := range doc.Statements {
+		upgradeJustification001(&doc.Statements[i])
+	}
+	return nil
+}
+
+// downgrade020 translates a v0.2.0 document to v0.0.1. Products are plain
+// identifiers in v0.0.1 and subcomponents apply to all the products of a
+// statement, so the subcomponents of every product are merged.
+func downgrade020(doc *VEX) error {
+	doc.LastUpdated = nil
+	doc.TransparencyLog = nil
+	doc.Changelog = nil
+	doc.Previous = ""
+
+	for i := range doc.Statements {
+		stmt := &doc.Statements[i]
+		stmt.ID = ""
+		stmt.LastUpdated = nil
+		stmt.Vulnerability = Vulnerability{
+			Name:        stmt.Vulnerability.Name,
+			Description: stmt.Vulnerability.Description,
+		}
+		stmt.SSVC = nil
+		stmt.References = nil
+		stmt.Fix = nil
+
+		subcomponents := []Subcomponent{}
+		seen := map[string]struct{}{}
+		for _, p := range stmt.Products {
+			if p.ID == "" {
+				return fmt.Errorf("statement #%d: product without an @id cannot be expressed in v0.0.1", i)
+			}
+			for _, sc := range p.Subcomponents {
+				if _, ok := seen[sc.ID]; ok || sc.ID == "" {
+					continue
+				}
+				seen[sc.ID] = struct{}{}
+				subcomponents = append(subcomponents, Subcomponent{Component: Component{ID: sc.ID}})
+			}
+		}
+		for j := range stmt.Products {
+			stmt.Products[j] = Product{
+				Component:     Component{ID: stmt.Products[j].ID},
+				Subcomponents: slices.Clone(subcomponents),
+			}
+		}
+	}
+	return nil
+}
+
+// warnings001 reports the fields of a v0.0.1 document which are converted
+// by parse001 as they are not used anymore.
+func warnings001(data []byte, warn func(Warning)) {
+	raw := struct {
+		Version    json.RawMessage `json:"version"`
+		Statements []statement001  `json:"statements"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(raw.Version), []byte(`"`)) {
+		warn(Warning{Path: "/version", Message: "the document version is a string, it must be an integer"})
+	}
+	for i := range raw.Statements {
+		path := fmt.Sprintf("/statements/%d", i)
+		if raw.Statements[i].Impact != "" {
+			warn(Warning{Path: path + "/impact", Message: "impact is deprecated, use impact_statement"})
+		}
+		if j := Justification(raw.Statements[i].Justification); j != "" && !j.Valid() {
+			warn(Warning{
+				Path:    path + "/justification",
+				Message: fmt.Sprintf("%q is not a valid justification, it was moved to the impact statement", j),
+			})
+		}
+	}
+}
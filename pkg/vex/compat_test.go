@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package vex
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"testing"
@@ -64,3 +65,131 @@ func TestParse001(t *testing.T) {
 		require.NoError(t, err, msg)
 	}
 }
+
+func TestParse001LegacyFields(t *testing.T) {
+	data, err := os.ReadFile("testdata/v0.0.1-legacy-fields.json")
+	require.NoError(t, err)
+
+	doc, err := ParseCompat(data)
+	require.NoError(t, err)
+	require.Equal(t, ContextLocator(), doc.Context)
+	require.Equal(t, 2, doc.Version)
+	require.Equal(t, "Wolfi", doc.Supplier)
+	require.Len(t, doc.Statements, 2)
+
+	// impact is upconverted to the impact statement
+	require.Equal(t, "The vulnerable parser is not compiled in", doc.Statements[0].ImpactStatement)
+	require.Equal(t, "Heap overflow in the widget parser", doc.Statements[0].Vulnerability.Description)
+	require.Len(t, doc.Statements[0].Products[0].Subcomponents, 1)
+
+	// Free text justifications are moved to the impact statement
+	require.Empty(t, doc.Statements[1].Justification)
+	require.Equal(t, "We checked and the code is never reached", doc.Statements[1].ImpactStatement)
+
+	for i := range doc.Statements {
+		require.NoError(t, doc.Statements[i].Validate())
+	}
+}
+
+func TestParseCompat(t *testing.T) {
+	for path, version := range map[string]int{
+		"testdata/v0.0.1.json":           1,
+		"testdata/v0.0.1-noversion.json": 1,
+		"testdata/v0.2.0.json":           1,
+	} {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		doc, err := ParseCompat(data)
+		require.NoError(t, err, path)
+		require.Equal(t, version, doc.Version, path)
+		require.NotEmpty(t, doc.Statements, path)
+	}
+
+	_, err := ParseCompat([]byte(`{"@context": "https://openvex.dev/ns/v9.9.9"}`))
+	require.Error(t, err)
+	_, err = ParseCompat([]byte(`{"@context": "https://example.com"}`))
+	require.Error(t, err)
+}
+
+func TestUpgradeTo(t *testing.T) {
+	doc := New(WithContext("0.0.1"), WithStatements(Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-12345"},
+		Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/git@2.39.0-r1"}}},
+		Status:        StatusNotAffected,
+		Justification: "We checked and the code is never reached",
+	}))
+
+	upgraded, err := doc.UpgradeTo(SpecVersion)
+	require.NoError(t, err)
+	require.Equal(t, ContextLocator(), upgraded.Context)
+	require.Empty(t, upgraded.Statements[0].Justification)
+	require.Equal(t, "We checked and the code is never reached", upgraded.Statements[0].ImpactStatement)
+	require.NoError(t, upgraded.Statements[0].Validate())
+
+	// The original document is not modified
+	require.Equal(t, Justification("We checked and the code is never reached"), doc.Statements[0].Justification)
+
+	_, err = upgraded.UpgradeTo("0.0.1")
+	require.Error(t, err)
+	_, err = doc.UpgradeTo("9.9.9")
+	require.Error(t, err)
+
+	// Documents of unknown versions are not converted
+	unknown := New()
+	unknown.SourceVersion = "0.1.0"
+	_, err = unknown.UpgradeTo(SpecVersion)
+	require.ErrorContains(t, err, "unsupported OpenVEX document version")
+	_, err = unknown.DowngradeTo("0.0.1")
+	require.ErrorContains(t, err, "unsupported OpenVEX document version")
+}
+
+func TestDowngradeTo(t *testing.T) {
+	doc := New(WithStatements(Statement{
+		ID:            "https://example.com/statement/1",
+		Vulnerability: Vulnerability{Name: "CVE-2023-12345", Aliases: []VulnerabilityID{"GHSA-1234"}},
+		Products: []Product{
+			{
+				Component:     Component{ID: "pkg:oci/app", Hashes: map[Algorithm]Hash{SHA256: "abc"}},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/a@v1.0.0"}}},
+			},
+			{
+				Component:     Component{ID: "pkg:oci/app2"},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/b@v1.0.0"}}},
+			},
+		},
+		Status: StatusFixed,
+	}))
+
+	downgraded, err := doc.DowngradeTo("v0.0.1")
+	require.NoError(t, err)
+	version, err := downgraded.SpecVersion()
+	require.NoError(t, err)
+	require.Equal(t, "0.0.1", version)
+
+	stmt := downgraded.Statements[0]
+	require.Empty(t, stmt.ID)
+	require.Empty(t, stmt.Vulnerability.Aliases)
+	require.Nil(t, stmt.Products[0].Hashes)
+	// Subcomponents apply to all the products in v0.0.1
+	for _, p := range stmt.Products {
+		require.Len(t, p.Subcomponents, 2)
+	}
+
+	// Downgrade and upgrade round trip through the serialized format
+	var buf bytes.Buffer
+	require.NoError(t, downgraded.ToJSONVersion(&buf, "0.0.1"))
+	parsed, err := ParseCompat(buf.Bytes())
+	require.NoError(t, err)
+	upgraded, err := parsed.UpgradeTo(SpecVersion)
+	require.NoError(t, err)
+	require.Equal(t, downgraded.Statements, upgraded.Statements)
+
+	_, err = downgraded.DowngradeTo(SpecVersion)
+	require.Error(t, err)
+
+	doc.Statements[0].Products = append(doc.Statements[0].Products, Product{
+		Component: Component{Hashes: map[Algorithm]Hash{SHA256: "def"}},
+	})
+	_, err = doc.DowngradeTo("0.0.1")
+	require.Error(t, err)
+}
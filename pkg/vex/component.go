@@ -5,7 +5,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package vex
 
-import "strings"
+import (
+	"encoding/json"
+	"strings"
+)
 
 // Component abstracts the common construct shared by product and subcomponents
 // allowing OpenVEX statements to point to a piece of software by referencing it
@@ -31,6 +34,10 @@ type Component struct {
 	// Supplier is an optional machine-readable identifier for the supplier of
 	// the component. Valid examples include email address or IRIs.
 	Supplier string `json:"supplier,omitempty"`
+
+	// RawExtensions holds the fields of the serialized component which are
+	// not part of the spec, keyed by name. See VEX.RawExtensions.
+	RawExtensions map[string]json.RawMessage `json:"-" yaml:"-"`
 }
 
 // Matches returns true if one of the components identifiers match a string.
@@ -70,3 +77,22 @@ func (c *Component) Matches(identifier string) bool {
 
 	return false
 }
+
+// Clone returns a deep copy of the component.
+func (c *Component) Clone() Component {
+	ret := *c
+	if c.Hashes != nil {
+		ret.Hashes = make(map[Algorithm]Hash, len(c.Hashes))
+		for k, v := range c.Hashes {
+			ret.Hashes[k] = v
+		}
+	}
+	if c.Identifiers != nil {
+		ret.Identifiers = make(map[IdentifierType]string, len(c.Identifiers))
+		for k, v := range c.Identifiers {
+			ret.Identifiers[k] = v
+		}
+	}
+	ret.RawExtensions = cloneRawExtensions(c.RawExtensions)
+	return ret
+}
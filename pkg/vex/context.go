@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// specVersions lists the published versions of the OpenVEX specification,
+// oldest first.
+var specVersions = []string{"0.0.1", SpecVersion}
+
+// SpecVersions returns the published versions of the OpenVEX specification
+// known to this library, oldest first.
+func SpecVersions() []string {
+	return slices.Clone(specVersions)
+}
+
+// ContextVersion returns the spec version a context locator points to. Both
+// the http and https schemes are accepted, as are locators with a trailing
+// slash or without the "v" in the version. The bare namespace URL was used by
+// the first drafts of the spec and is read as version 0.0.1.
+func ContextVersion(locator string) (string, error) {
+	trimmed := strings.TrimSuffix(strings.TrimSpace(locator), "/")
+	if strings.HasPrefix(trimmed, "http://") {
+		trimmed = "https://" + strings.TrimPrefix(trimmed, "http://")
+	}
+
+	if !strings.HasPrefix(trimmed, Context) {
+		return "", fmt.Errorf("%w: %q is not an OpenVEX context", ErrInvalidContext, locator)
+	}
+
+	version := strings.TrimPrefix(trimmed, Context)
+	if version == "" {
+		return specVersions[0], nil
+	}
+
+	if !strings.HasPrefix(version, "/") {
+		return "", fmt.Errorf("%w: %q is not an OpenVEX context", ErrInvalidContext, locator)
+	}
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "/"), "v")
+	if !slices.Contains(specVersions, version) {
+		return "", fmt.Errorf("%w: unsupported OpenVEX version %q", ErrInvalidContext, version)
+	}
+	return version, nil
+}
+
+// ContextLocatorFor returns the context locator of a published version of
+// the OpenVEX specification. The version may be prefixed with a "v".
+func ContextLocatorFor(version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+	if !slices.Contains(specVersions, version) {
+		return "", fmt.Errorf("unsupported OpenVEX version %q", version)
+	}
+	return fmt.Sprintf("%s/v%s", Context, version), nil
+}
+
+// DetectSpecVersion returns the spec version of a serialized OpenVEX
+// document as read from its context.
+func DetectSpecVersion(data []byte) (string, error) {
+	locator, err := parseContext(data)
+	if err != nil {
+		return "", err
+	}
+	if locator == "" {
+		return "", fmt.Errorf("%w: document does not have an OpenVEX context", ErrInvalidContext)
+	}
+	return ContextVersion(locator)
+}
+
+// SpecVersion returns the version of the OpenVEX specification the document
+// was published with. This is the SourceVersion recorded by ParseCompat or,
+// when not set, the version the document context points to.
+func (vexDoc *VEX) SpecVersion() (string, error) {
+	if vexDoc.SourceVersion != "" {
+		return vexDoc.SourceVersion, nil
+	}
+	return ContextVersion(vexDoc.Context)
+}
+
+// ToJSONVersion serializes the document in the format of a version of the
+// OpenVEX specification and writes it to the passed writer. The document
+// context is set to the locator of that version. Documents serialized to
+// v0.0.1 lose data which cannot be expressed in that format, such as product
+// identifiers and hashes other than the @id and per product subcomponents.
+// The document itself is not modified.
+func (vexDoc *VEX) ToJSONVersion(w io.Writer, version string) error {
+	return vexDoc.Encode(w, &EncodeOptions{Version: version})
+}
+
+// inVersion returns the value to serialize to write the document in the
+// format of a version of the spec.
+func (vexDoc *VEX) inVersion(version string) (any, error) {
+	locator, err := ContextLocatorFor(version)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimPrefix(version, "v") == SpecVersion {
+		doc := *vexDoc
+		doc.Context = locator
+		return &doc, nil
+	}
+	return to001(vexDoc, locator), nil
+}
+
+// to001 transcodes a document into the v0.0.1 structs. It is the inverse of
+// parse001.
+func to001(vexDoc *VEX, locator string) *vex001 {
+	oldVex := &vex001{
+		Context:    locator,
+		ID:         vexDoc.ID,
+		Author:     vexDoc.Author,
+		AuthorRole: vexDoc.AuthorRole,
+		Timestamp:  vexDoc.Timestamp,
+		Version:    version001(vexDoc.Version),
+		Tooling:    vexDoc.Tooling,
+		Supplier:   vexDoc.Supplier,
+		Statements: make([]statement001, 0, len(vexDoc.Statements)),
+	}
+
+	for i := range vexDoc.Statements {
+		stmt := &vexDoc.Statements[i]
+		oldStmt := statement001{
+			Vulnerability:            string(stmt.Vulnerability.Name),
+			VulnDescription:          stmt.Vulnerability.Description,
+			Timestamp:                stmt.Timestamp,
+			Status:                   string(stmt.Status),
+			StatusNotes:              stmt.StatusNotes,
+			Justification:            string(stmt.Justification),
+			ImpactStatement:          stmt.ImpactStatement,
+			ActionStatement:          stmt.ActionStatement,
+			ActionStatementTimestamp: stmt.ActionStatementTimestamp,
+		}
+		for _, p := range stmt.Products {
+			if p.ID != "" && !slices.Contains(oldStmt.Products, p.ID) {
+				oldStmt.Products = append(oldStmt.Products, p.ID)
+			}
+			for _, sc := range p.Subcomponents {
+				if sc.ID != "" && !slices.Contains(oldStmt.Subcomponents, sc.ID) {
+					oldStmt.Subcomponents = append(oldStmt.Subcomponents, sc.ID)
+				}
+			}
+		}
+		oldVex.Statements = append(oldVex.Statements, oldStmt)
+	}
+	return oldVex
+}
+
+// ContextPin points to a local copy of a JSON-LD context definition and
+// records its expected digest.
+type ContextPin struct {
+	// Path is the location of the local copy of the context definition.
+	Path string
+
+	// SHA256 is the hex encoded SHA-256 digest of the local copy.
+	SHA256 string
+}
+
+// Verify checks the local copy of the context against the pinned digest.
+func (pin *ContextPin) Verify() error {
+	data, err := os.ReadFile(pin.Path)
+	if err != nil {
+		return fmt.Errorf("reading pinned context: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), pin.SHA256) {
+		return fmt.Errorf("pinned context %s does not match its digest", pin.Path)
+	}
+	return nil
+}
+
+// checkContext rejects a document context not allowed by the options.
+func (opts *ParseOptions) checkContext(locator string) error {
+	if opts.AllowedContexts != nil && !slices.Contains(opts.AllowedContexts, locator) {
+		return &ValidationError{Path: "/@context", Err: fmt.Errorf("%w: context %q is not allowed", ErrInvalidContext, locator)}
+	}
+	if opts.PinnedContexts == nil {
+		return nil
+	}
+	pin, ok := opts.PinnedContexts[locator]
+	if !ok {
+		return &ValidationError{Path: "/@context", Err: fmt.Errorf("%w: context %q is not pinned", ErrInvalidContext, locator)}
+	}
+	if err := pin.Verify(); err != nil {
+		return &ValidationError{Path: "/@context", Err: err}
+	}
+	return nil
+}
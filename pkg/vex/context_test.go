@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextVersion(t *testing.T) {
+	for name, tc := range map[string]struct {
+		locator  string
+		expected string
+		mustErr  bool
+	}{
+		"current":       {"https://openvex.dev/ns/v0.2.0", "0.2.0", false},
+		"v0.0.1":        {"https://openvex.dev/ns/v0.0.1", "0.0.1", false},
+		"bare":          {"https://openvex.dev/ns", "0.0.1", false},
+		"trailing":      {"https://openvex.dev/ns/v0.2.0/", "0.2.0", false},
+		"http":          {"http://openvex.dev/ns/v0.2.0", "0.2.0", false},
+		"no v":          {"https://openvex.dev/ns/0.2.0", "0.2.0", false},
+		"unknown":       {"https://openvex.dev/ns/v9.9.9", "", true},
+		"other context": {"https://example.com/ns/v0.2.0", "", true},
+		"suffix":        {"https://openvex.dev/nsv0.2.0", "", true},
+		"empty":         {"", "", true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			version, err := ContextVersion(tc.locator)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, version)
+		})
+	}
+}
+
+func TestContextLocatorFor(t *testing.T) {
+	locator, err := ContextLocatorFor("v0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, "https://openvex.dev/ns/v0.0.1", locator)
+
+	locator, err = ContextLocatorFor(SpecVersion)
+	require.NoError(t, err)
+	require.Equal(t, ContextLocator(), locator)
+
+	_, err = ContextLocatorFor("1.0.0")
+	require.Error(t, err)
+}
+
+func TestSpecVersion(t *testing.T) {
+	data, err := os.ReadFile("testdata/v0.0.1.json")
+	require.NoError(t, err)
+
+	version, err := DetectSpecVersion(data)
+	require.NoError(t, err)
+	require.Equal(t, "0.0.1", version)
+
+	// Upconverted documents keep the version they were read from
+	doc, err := ParseCompat(data)
+	require.NoError(t, err)
+	require.Equal(t, ContextLocator(), doc.Context)
+	version, err = doc.SpecVersion()
+	require.NoError(t, err)
+	require.Equal(t, "0.0.1", version)
+
+	newDoc := New()
+	version, err = newDoc.SpecVersion()
+	require.NoError(t, err)
+	require.Equal(t, SpecVersion, version)
+
+	_, err = DetectSpecVersion([]byte(`{"@context": "https://example.com"}`))
+	require.Error(t, err)
+}
+
+func TestToJSONVersion(t *testing.T) {
+	data, err := os.ReadFile("testdata/v0.0.1.json")
+	require.NoError(t, err)
+	doc, err := ParseCompat(data)
+	require.NoError(t, err)
+
+	// Write the document back in the v0.0.1 format
+	var buf bytes.Buffer
+	require.NoError(t, doc.ToJSONVersion(&buf, "0.0.1"))
+	version, err := DetectSpecVersion(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "0.0.1", version)
+	require.Contains(t, buf.String(), `"vulnerability": "CVE-2023-12345"`)
+
+	roundTrip, err := ParseCompat(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, doc.Statements, roundTrip.Statements)
+	require.Equal(t, doc.Metadata, roundTrip.Metadata)
+
+	// Current version
+	buf.Reset()
+	require.NoError(t, doc.ToJSONVersion(&buf, SpecVersion))
+	current, err := Parse(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, ContextLocator(), current.Context)
+	require.Len(t, current.Statements, 1)
+	require.Len(t, current.Statements[0].Products, 2)
+
+	require.Error(t, doc.ToJSONVersion(&buf, "0.1.0"))
+}
+
+func TestParseContextAllowList(t *testing.T) {
+	data := []byte(`{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1", "statements": []}`)
+
+	_, err := ParseWithOptions(&ParseOptions{AllowedContexts: []string{ContextLocator()}}, data)
+	require.NoError(t, err)
+
+	_, err = ParseWithOptions(&ParseOptions{AllowedContexts: []string{"https://openvex.dev/ns/v0.0.1"}}, data)
+	require.Error(t, err)
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "/@context", verr.Path)
+
+	// An empty list allows no contexts
+	_, err = ParseWithOptions(&ParseOptions{AllowedContexts: []string{}}, data)
+	require.Error(t, err)
+}
+
+func TestParseContextPins(t *testing.T) {
+	data := []byte(`{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1", "statements": []}`)
+	contextDef := []byte(`{"@context": {"@vocab": "https://openvex.dev/ns/v0.2.0#"}}`)
+	path := filepath.Join(t.TempDir(), "context.jsonld")
+	require.NoError(t, os.WriteFile(path, contextDef, os.FileMode(0o644)))
+	sum := sha256.Sum256(contextDef)
+
+	pins := map[string]ContextPin{
+		ContextLocator(): {Path: path, SHA256: hex.EncodeToString(sum[:])},
+	}
+	_, err := ParseWithOptions(&ParseOptions{PinnedContexts: pins}, data)
+	require.NoError(t, err)
+
+	// The context must be pinned
+	_, err = ParseWithOptions(&ParseOptions{PinnedContexts: map[string]ContextPin{}}, data)
+	require.Error(t, err)
+
+	// The local copy must match the digest
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), os.FileMode(0o644)))
+	_, err = ParseWithOptions(&ParseOptions{PinnedContexts: pins}, data)
+	require.Error(t, err)
+
+	pin := ContextPin{Path: filepath.Join(t.TempDir(), "missing"), SHA256: hex.EncodeToString(sum[:])}
+	require.Error(t, pin.Verify())
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/openvex/go-vex/pkg/csaf"
+)
+
+// csafStatuses maps the CSAF product status groups to OpenVEX statuses. The
+// recommended group has no OpenVEX equivalent and is skipped.
+var csafStatuses = map[string]Status{
+	"known_not_affected":  StatusNotAffected,
+	"fixed":               StatusFixed,
+	"first_fixed":         StatusFixed,
+	"under_investigation": StatusUnderInvestigation,
+	"known_affected":      StatusAffected,
+	"first_affected":      StatusAffected,
+	"last_affected":       StatusAffected,
+}
+
+// csafStatusOrder is the order in which status groups are converted
+var csafStatusOrder = []string{
+	"known_not_affected", "fixed", "first_fixed", "under_investigation",
+	"known_affected", "first_affected", "last_affected",
+}
+
+// FromCSAF reads a CSAF VEX document and converts it into an OpenVEX
+// document. The product IDs referenced by the vulnerabilities are resolved
+// through the product tree (branches, full product names and relationships)
+// to components identified by their purl or CPE. Products defined by a
+// relationship are converted into a product with the referenced component as
+// a subcomponent.
+//
+// Justifications are read from the CSAF flags, impact statements from the
+// impact threats and action statements from the remediations. Fixed
+// products record their release as the fix and the URLs of the vendor_fix
+// remediations as fix references.
+func FromCSAF(r io.Reader) (*VEX, error) {
+	csafDoc, err := csaf.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := &csafResolver{
+		products:      csafDoc.ProductsByID(),
+		relationships: csafDoc.RelationshipsByID(),
+		groups:        csafDoc.GroupsByID(),
+	}
+
+	version := 1
+	if v, err := strconv.Atoi(csafDoc.Document.Tracking.Version); err == nil && v > 0 {
+		version = v
+	}
+	timestamp := csafDoc.Document.Tracking.CurrentReleaseDate
+	doc := &VEX{
+		Metadata: Metadata{
+			Context:   ContextLocator(),
+			ID:        csafDoc.Document.Tracking.ID,
+			Author:    csafDoc.Document.Publisher.Name,
+			Timestamp: &timestamp,
+			Version:   version,
+		},
+		Statements: []Statement{},
+	}
+	if csafDoc.Document.Publisher.Category != "" {
+		doc.AuthorRole = csafDoc.Document.Publisher.Category
+	}
+	if !csafDoc.Document.Tracking.InitialReleaseDate.IsZero() &&
+		csafDoc.Document.Tracking.InitialReleaseDate.Before(timestamp) {
+		initial := csafDoc.Document.Tracking.InitialReleaseDate
+		doc.Timestamp = &initial
+		doc.LastUpdated = &timestamp
+	}
+
+	for i := range csafDoc.Vulnerabilities {
+		stmts, err := resolver.statements(&csafDoc.Vulnerabilities[i])
+		if err != nil {
+			return nil, fmt.Errorf("converting vulnerability #%d: %w", i, err)
+		}
+		doc.Statements = append(doc.Statements, stmts...)
+	}
+	return doc, nil
+}
+
+// csafResolver resolves CSAF product references
+type csafResolver struct {
+	products      map[string]csaf.Product
+	relationships map[string]csaf.Relationship
+	groups        map[string][]string
+}
+
+// statements converts a CSAF vulnerability into OpenVEX statements. Products
+// sharing the same status and details are grouped in a single statement.
+func (cr *csafResolver) statements(v *csaf.Vulnerability) ([]Statement, error) {
+	for group := range v.ProductStatus {
+		if _, ok := csafStatuses[group]; !ok && group != "recommended" {
+			return nil, fmt.Errorf("unknown product status %q", group)
+		}
+	}
+
+	vuln := Vulnerability{Name: VulnerabilityID(v.CVE)}
+	for _, id := range v.IDs {
+		if vuln.Name == "" {
+			vuln.Name = VulnerabilityID(id.Text)
+			continue
+		}
+		vuln.Aliases = append(vuln.Aliases, VulnerabilityID(id.Text))
+	}
+	if vuln.Name == "" {
+		return nil, fmt.Errorf("vulnerability has no identifier")
+	}
+	for _, n := range v.Notes {
+		if n.Category == "description" {
+			vuln.Description = n.Text
+			break
+		}
+	}
+
+	flags := map[string]Justification{}
+	for _, f := range v.Flags {
+		for _, pid := range cr.expand(f.ProductIDs, f.GroupIDs) {
+			flags[pid] = Justification(f.Label)
+		}
+	}
+	impacts := map[string]string{}
+	for _, t := range v.Threats {
+		if t.Category != "impact" {
+			continue
+		}
+		for _, pid := range t.ProductIDs {
+			impacts[pid] = t.Details
+		}
+	}
+	actions := map[string][]string{}
+	fixes := map[string][]Reference{}
+	for _, r := range v.Remediations {
+		for _, pid := range cr.expand(r.ProductIDs, r.GroupIDs) {
+			actions[pid] = append(actions[pid], r.Details)
+			if r.Category == "vendor_fix" && r.URL != "" {
+				fixes[pid] = append(fixes[pid], Reference{URL: r.URL, Type: ReferenceFix})
+			}
+		}
+	}
+
+	stmts := []Statement{}
+	index := map[string]int{}
+	for _, group := range csafStatusOrder {
+		status := csafStatuses[group]
+		for _, pid := range v.ProductStatus[group] {
+			stmt := Statement{Vulnerability: vuln, Status: status}
+			switch status {
+			case StatusNotAffected:
+				stmt.Justification = flags[pid]
+				stmt.ImpactStatement = impacts[pid]
+			case StatusAffected:
+				stmt.ActionStatement = strings.Join(actions[pid], "\n")
+				if stmt.ActionStatement == "" {
+					stmt.ActionStatement = NoActionStatementMsg
+				}
+			case StatusFixed:
+				stmt.References = fixes[pid]
+				stmt.Fix = cr.fix(pid)
+			}
+
+			key := fmt.Sprintf("%s|%s|%q|%q|%v", stmt.Status, stmt.Justification, stmt.ImpactStatement, stmt.ActionStatement, stmt.References)
+			if i, ok := index[key]; ok {
+				stmts[i].Products = append(stmts[i].Products, cr.product(pid))
+				if stmt.Fix != nil {
+					if stmts[i].Fix == nil {
+						stmts[i].Fix = &Fix{}
+					}
+					stmts[i].Fix.merge(stmt.Fix)
+				}
+				continue
+			}
+			stmt.Products = []Product{cr.product(pid)}
+			index[key] = len(stmts)
+			stmts = append(stmts, stmt)
+		}
+	}
+
+	return stmts, nil
+}
+
+// fix returns the fix of a product listed as fixed, which is the release of
+// the product itself. Products with no purl or CPE have no fix data.
+func (cr *csafResolver) fix(pid string) *Fix {
+	c := cr.product(pid).Component
+	if c.ID == pid {
+		return nil
+	}
+	f := &Fix{Products: []string{c.ID}}
+	if purl, err := packageurl.FromString(c.Identifiers[PURL]); err == nil && purl.Version != "" {
+		f.Versions = []string{purl.Version}
+	} else if version := cpeVersion(c.ID); version != "" {
+		f.Versions = []string{version}
+	}
+	return f
+}
+
+// cpeVersion returns the version of a CPE 2.3 name, if it has one.
+func cpeVersion(cpe string) string {
+	parts := strings.Split(cpe, ":")
+	if len(parts) < 6 || parts[0] != "cpe" || parts[1] != "2.3" {
+		return ""
+	}
+	if v := parts[5]; v != "*" && v != "-" {
+		return v
+	}
+	return ""
+}
+
+// expand returns the product IDs plus the products in the groups
+func (cr *csafResolver) expand(productIDs, groupIDs []string) []string {
+	ids := append([]string{}, productIDs...)
+	for _, g := range groupIDs {
+		ids = append(ids, cr.groups[g]...)
+	}
+	return ids
+}
+
+// product resolves a CSAF product ID into an OpenVEX product
+func (cr *csafResolver) product(pid string) Product {
+	if r, ok := cr.relationships[pid]; ok {
+		return Product{
+			Component: cr.component(r.RelatesToProductRef),
+			Subcomponents: []Subcomponent{
+				{Component: cr.component(r.ProductRef)},
+			},
+		}
+	}
+	return Product{Component: cr.component(pid)}
+}
+
+// component resolves a CSAF product ID into a component identified by its
+// purl or CPE. The product ID is used when the product has no identifiers.
+func (cr *csafResolver) component(pid string) Component {
+	p, ok := cr.products[pid]
+	if !ok {
+		return Component{ID: pid}
+	}
+
+	c := Component{}
+	if purl := p.IdentificationHelper["purl"]; purl != "" {
+		c.ID = purl
+		c.Identifiers = map[IdentifierType]string{PURL: purl}
+	}
+	if cpe := p.IdentificationHelper["cpe"]; cpe != "" {
+		if c.Identifiers == nil {
+			c.Identifiers = map[IdentifierType]string{}
+		}
+		if strings.HasPrefix(cpe, "cpe:2.3:") {
+			c.Identifiers[CPE23] = cpe
+		} else {
+			c.Identifiers[CPE22] = cpe
+		}
+		if c.ID == "" {
+			c.ID = cpe
+		}
+	}
+	if c.ID == "" {
+		c.ID = pid
+	}
+	return c
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromCSAF(t *testing.T) {
+	f, err := os.Open("testdata/csaf-product-tree.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	doc, err := FromCSAF(f)
+	require.NoError(t, err)
+	require.Equal(t, "2023-EVD-UC-02", doc.ID)
+	require.Equal(t, "Example Company", doc.Author)
+	require.Equal(t, 3, doc.Version)
+	require.True(t, doc.Timestamp.Before(*doc.LastUpdated))
+
+	require.Len(t, doc.Statements, 3)
+
+	// Products sharing the same details are grouped
+	na := doc.Statements[0]
+	require.Equal(t, StatusNotAffected, na.Status)
+	require.Equal(t, "CVE-2023-1234", string(na.Vulnerability.Name))
+	require.Equal(t, []VulnerabilityID{"GHSA-abcd-efgh-ijkl"}, na.Vulnerability.Aliases)
+	require.Equal(t, "A flaw in libexample.", na.Vulnerability.Description)
+	require.Equal(t, VulnerableCodeNotInExecutePath, na.Justification)
+	require.Equal(t, "The vulnerable function is never called.", na.ImpactStatement)
+	require.Len(t, na.Products, 2)
+
+	// Relationships resolve into products with subcomponents
+	require.Equal(t, "pkg:oci/widget@sha256%3A0123456789abcdef", na.Products[0].ID)
+	require.Len(t, na.Products[0].Subcomponents, 1)
+	require.Equal(t, "pkg:golang/example.com/libexample@v1.2.3", na.Products[0].Subcomponents[0].ID)
+
+	// Products without identifiers keep their product ID
+	require.Equal(t, "LEGACY", na.Products[1].ID)
+
+	fixed := doc.Statements[1]
+	require.Equal(t, StatusFixed, fixed.Status)
+	require.Equal(t, "pkg:golang/example.com/libexample@v1.2.3", fixed.Products[0].ID)
+	require.Equal(t, "pkg:golang/example.com/libexample@v1.2.3", fixed.Products[0].Identifiers[PURL])
+	require.Equal(t, &Fix{
+		Versions: []string{"v1.2.3"},
+		Products: []string{"pkg:golang/example.com/libexample@v1.2.3"},
+	}, fixed.Fix)
+	require.Equal(t, []Reference{
+		{URL: "https://github.com/example/libexample/releases/tag/v1.2.3", Type: ReferenceFix},
+	}, fixed.References)
+
+	affected := doc.Statements[2]
+	require.Equal(t, StatusAffected, affected.Status)
+	require.Equal(t, "Upgrade to Widget 2.1.", affected.ActionStatement)
+	require.Equal(t, "cpe:2.3:a:example:widget:2.0:*:*:*:*:*:*:*", affected.Products[0].ID)
+	require.Equal(t, "cpe:2.3:a:example:widget:2.0:*:*:*:*:*:*:*", affected.Products[0].Identifiers[CPE23])
+
+	for i := range doc.Statements {
+		require.NoError(t, doc.Statements[i].Validate())
+	}
+
+	// The matching functions work on the imported data
+	require.Len(t, doc.Matches("CVE-2023-1234", "pkg:oci/widget@sha256%3A0123456789abcdef", []string{"pkg:golang/example.com/libexample@v1.2.3"}), 1)
+}
+
+func TestFromCSAFErrors(t *testing.T) {
+	_, err := FromCSAF(strings.NewReader(`{"document":`))
+	require.Error(t, err)
+
+	_, err = FromCSAF(strings.NewReader(`{"vulnerabilities":[{"cve":"CVE-2023-1234","product_status":{"bogus":["A"]}}]}`))
+	require.Error(t, err)
+
+	_, err = FromCSAF(strings.NewReader(`{"vulnerabilities":[{"product_status":{"fixed":["A"]}}]}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// CVSS severity ratings
+const (
+	CVSSSeverityNone     = "NONE"
+	CVSSSeverityLow      = "LOW"
+	CVSSSeverityMedium   = "MEDIUM"
+	CVSSSeverityHigh     = "HIGH"
+	CVSSSeverityCritical = "CRITICAL"
+)
+
+// ErrCVSSScoreUnsupported is returned when computing the score of a CVSS
+// version whose scoring is not implemented. Scores of those vectors must be
+// taken from their source.
+var ErrCVSSScoreUnsupported = errors.New("computing the score of this CVSS version is not supported")
+
+// CVSS is a Common Vulnerability Scoring System assessment of the
+// vulnerability, recorded in the document so the severity travels with the
+// impact assessment.
+type CVSS struct {
+	// Version is the CVSS version: 3.0, 3.1 or 4.0
+	Version string `json:"version"`
+
+	// Vector is the CVSS vector string, such as
+	// CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H
+	Vector string `json:"vector"`
+
+	// BaseScore is the base score, from 0 to 10
+	BaseScore float64 `json:"base_score"`
+
+	// BaseSeverity is the severity rating of the base score, one of the
+	// CVSSSeverity constants
+	BaseSeverity string `json:"base_severity,omitempty"`
+
+	// Source identifies who scored the vulnerability
+	Source string `json:"source,omitempty"`
+}
+
+// NewCVSS parses a CVSS vector and returns it with its base score and
+// severity. Scores are computed for CVSS 3.0 and 3.1 vectors, CVSS 4.0
+// vectors are validated and ErrCVSSScoreUnsupported is returned with them
+// so the score can be set from the source of the vector.
+func NewCVSS(vector string) (CVSS, error) {
+	v, err := ParseCVSSVector(vector)
+	if err != nil {
+		return CVSS{}, err
+	}
+	ret := CVSS{Version: v.Version, Vector: vector}
+	score, err := v.BaseScore()
+	if err != nil {
+		return ret, err
+	}
+	ret.BaseScore = score
+	ret.BaseSeverity = CVSSSeverity(score)
+	return ret, nil
+}
+
+// CVSSSeverity returns the qualitative severity rating of a score.
+func CVSSSeverity(score float64) string {
+	switch {
+	case score <= 0:
+		return CVSSSeverityNone
+	case score < 4:
+		return CVSSSeverityLow
+	case score < 7:
+		return CVSSSeverityMedium
+	case score < 9:
+		return CVSSSeverityHigh
+	default:
+		return CVSSSeverityCritical
+	}
+}
+
+// CVSSVector is a parsed CVSS vector.
+type CVSSVector struct {
+	// Version is the CVSS version of the vector
+	Version string
+
+	// Metrics maps the metric abbreviations to their values, for example
+	// AV to N
+	Metrics map[string]string
+}
+
+// cvssBaseMetrics are the mandatory metrics of each version and their
+// allowed values
+var cvssBaseMetrics = map[string]map[string][]string{
+	"3": {
+		"AV": {"N", "A", "L", "P"},
+		"AC": {"L", "H"},
+		"PR": {"N", "L", "H"},
+		"UI": {"N", "R"},
+		"S":  {"U", "C"},
+		"C":  {"H", "L", "N"},
+		"I":  {"H", "L", "N"},
+		"A":  {"H", "L", "N"},
+	},
+	"4.0": {
+		"AV": {"N", "A", "L", "P"},
+		"AC": {"L", "H"},
+		"AT": {"N", "P"},
+		"PR": {"N", "L", "H"},
+		"UI": {"N", "P", "A"},
+		"VC": {"H", "L", "N"},
+		"VI": {"H", "L", "N"},
+		"VA": {"H", "L", "N"},
+		"SC": {"H", "L", "N"},
+		"SI": {"H", "L", "N"},
+		"SA": {"H", "L", "N"},
+	},
+}
+
+// ParseCVSSVector parses a CVSS 3.0, 3.1 or 4.0 vector string. All the
+// base metrics must be present with valid values, other metrics are kept
+// without checking them.
+func ParseCVSSVector(vector string) (*CVSSVector, error) {
+	prefix, rest, ok := strings.Cut(vector, "/")
+	version, found := strings.CutPrefix(prefix, "CVSS:")
+	if !ok || !found {
+		return nil, fmt.Errorf("invalid CVSS vector %q: missing version prefix", vector)
+	}
+	key := version
+	switch version {
+	case "3.0", "3.1":
+		key = "3"
+	case "4.0":
+	default:
+		return nil, fmt.Errorf("invalid CVSS vector %q: unsupported version %s", vector, version)
+	}
+
+	v := &CVSSVector{Version: version, Metrics: map[string]string{}}
+	for _, part := range strings.Split(rest, "/") {
+		metric, value, ok := strings.Cut(part, ":")
+		if !ok || metric == "" || value == "" {
+			return nil, fmt.Errorf("invalid CVSS vector %q: malformed metric %q", vector, part)
+		}
+		if _, dup := v.Metrics[metric]; dup {
+			return nil, fmt.Errorf("invalid CVSS vector %q: metric %s is repeated", vector, metric)
+		}
+		v.Metrics[metric] = value
+	}
+	for metric, values := range cvssBaseMetrics[key] {
+		value, ok := v.Metrics[metric]
+		if !ok {
+			return nil, fmt.Errorf("invalid CVSS vector %q: missing base metric %s", vector, metric)
+		}
+		if !slices.Contains(values, value) {
+			return nil, fmt.Errorf("invalid CVSS vector %q: invalid value %s for metric %s", vector, value, metric)
+		}
+	}
+	return v, nil
+}
+
+// BaseScore computes the base score of a CVSS 3.0 or 3.1 vector. Other
+// versions return ErrCVSSScoreUnsupported.
+func (v *CVSSVector) BaseScore() (float64, error) {
+	if v.Version != "3.0" && v.Version != "3.1" {
+		return 0, fmt.Errorf("CVSS %s: %w", v.Version, ErrCVSSScoreUnsupported)
+	}
+	m := v.Metrics
+	changed := m["S"] == "C"
+
+	cia := map[string]float64{"H": 0.56, "L": 0.22, "N": 0}
+	iss := 1 - (1-cia[m["C"]])*(1-cia[m["I"]])*(1-cia[m["A"]])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+
+	pr := map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	if changed {
+		pr["L"], pr["H"] = 0.68, 0.5
+	}
+	exploitability := 8.22 *
+		map[string]float64{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2}[m["AV"]] *
+		map[string]float64{"L": 0.77, "H": 0.44}[m["AC"]] *
+		pr[m["PR"]] *
+		map[string]float64{"N": 0.85, "R": 0.62}[m["UI"]]
+
+	score := impact + exploitability
+	if changed {
+		score *= 1.08
+	}
+	return cvssRoundUp(v.Version, min(score, 10)), nil
+}
+
+// cvssRoundUp rounds up to one decimal as defined by each version. CVSS 3.1
+// avoids floating point errors rounding up exact values.
+func cvssRoundUp(version string, x float64) float64 {
+	if version == "3.0" {
+		return math.Ceil(x*10) / 10
+	}
+	i := int64(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
+
+// HighestCVSS returns the CVSS assessment of the vulnerability with the
+// highest base score or nil if it has none.
+func (v *Vulnerability) HighestCVSS() *CVSS {
+	var ret *CVSS
+	for i := range v.CVSS {
+		if ret == nil || v.CVSS[i].BaseScore > ret.BaseScore {
+			ret = &v.CVSS[i]
+		}
+	}
+	return ret
+}
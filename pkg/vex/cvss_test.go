@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCVSS(t *testing.T) {
+	for _, tc := range []struct {
+		vector   string
+		score    float64
+		severity string
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10.0, CVSSSeverityCritical},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, CVSSSeverityCritical},
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", 5.9, CVSSSeverityMedium},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", 7.8, CVSSSeverityHigh},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N", 5.4, CVSSSeverityMedium},
+		{"CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.6, CVSSSeverityLow},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0, CVSSSeverityNone},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:U/C:H/I:H/A:H/E:H", 8.8, CVSSSeverityHigh},
+	} {
+		t.Run(tc.vector, func(t *testing.T) {
+			c, err := NewCVSS(tc.vector)
+			require.NoError(t, err)
+			require.Equal(t, tc.vector[5:8], c.Version)
+			require.Equal(t, tc.score, c.BaseScore)
+			require.Equal(t, tc.severity, c.BaseSeverity)
+		})
+	}
+
+	// CVSS 4.0 vectors are validated but not scored
+	c, err := NewCVSS("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+	require.ErrorIs(t, err, ErrCVSSScoreUnsupported)
+	require.Equal(t, "4.0", c.Version)
+
+	for _, vector := range []string{
+		"",
+		"AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:2.0/AV:N/AC:L/Au:N/C:P/I:P/A:P",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",
+		"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:3.1/AV:N/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:3.1/AV:N/AC/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H",
+	} {
+		_, err := NewCVSS(vector)
+		require.Error(t, err, vector)
+		require.NotErrorIs(t, err, ErrCVSSScoreUnsupported, vector)
+	}
+}
+
+func TestVulnerabilityCVSS(t *testing.T) {
+	v := Vulnerability{Name: "CVE-2021-44228"}
+	require.Nil(t, v.HighestCVSS())
+
+	low, err := NewCVSS("CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N")
+	require.NoError(t, err)
+	high, err := NewCVSS("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H")
+	require.NoError(t, err)
+	v.CVSS = []CVSS{low, high}
+	require.Equal(t, 10.0, v.HighestCVSS().BaseScore)
+
+	doc := New(WithID("https://example.com/vex/1"))
+	doc.Statements = []Statement{{
+		Vulnerability: v,
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusFixed,
+	}}
+	data, err := json.Marshal(&doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	require.Equal(t, v.CVSS, parsed.Statements[0].Vulnerability.CVSS)
+
+	// Base scores outside of [0, 10] are rejected
+	doc.Statements[0].Vulnerability.CVSS[1].BaseScore = 99
+	require.Error(t, doc.Validate())
+}
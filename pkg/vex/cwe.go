@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var cweRegexp = regexp.MustCompile(`^CWE-[1-9][0-9]*$`)
+
+// ValidCWE returns true if id is a CWE ID, such as CWE-502.
+func ValidCWE(id string) bool {
+	return cweRegexp.MatchString(id)
+}
+
+// Validate checks the fields of the vulnerability with a constrained
+// syntax, its CWE IDs and references. It returns a *ValidationError pointing to the first invalid
+// value.
+func (v *Vulnerability) Validate() error {
+	for i, cwe := range v.CWEs {
+		if !ValidCWE(cwe) {
+			return &ValidationError{
+				Path: fmt.Sprintf("/cwes/%d", i),
+				Err:  fmt.Errorf("invalid CWE ID %q, must be like CWE-502", cwe),
+			}
+		}
+	}
+	return validateReferences(v.References)
+}
+
+// HasCWE returns true if the vulnerability is tagged with the CWE. IDs are
+// compared ignoring case.
+func (v *Vulnerability) HasCWE(cwe string) bool {
+	for _, c := range v.CWEs {
+		if strings.EqualFold(c, cwe) {
+			return true
+		}
+	}
+	return false
+}
+
+// StatementsByCWE returns the statements whose vulnerability is tagged with
+// the CWE, in document order.
+func (vexDoc *VEX) StatementsByCWE(cwe string) []Statement {
+	ret := []Statement{}
+	for i := range vexDoc.Statements {
+		if vexDoc.Statements[i].Vulnerability.HasCWE(cwe) {
+			ret = append(ret, vexDoc.Statements[i])
+		}
+	}
+	return ret
+}
+
+// CWEs returns the number of statements tagged with each CWE in the
+// document, to slice the statements by weakness class.
+func (vexDoc *VEX) CWEs() map[string]int {
+	ret := map[string]int{}
+	for i := range vexDoc.Statements {
+		for _, cwe := range vexDoc.Statements[i].Vulnerability.CWEs {
+			ret[strings.ToUpper(cwe)]++
+		}
+	}
+	return ret
+}
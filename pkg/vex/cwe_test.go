@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidCWE(t *testing.T) {
+	for _, id := range []string{"CWE-502", "CWE-79", "CWE-1"} {
+		require.True(t, ValidCWE(id), id)
+	}
+	for _, id := range []string{"", "CWE-", "CWE-0", "CWE-079", "cwe-79", "502", "CWE-502a", "CAPEC-66"} {
+		require.False(t, ValidCWE(id), id)
+	}
+}
+
+func TestStatementsByCWE(t *testing.T) {
+	doc := New(WithID("https://example.com/vex/1"))
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111", CWEs: []string{"CWE-502", "CWE-20"}},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusFixed,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222", CWEs: []string{"CWE-79"}},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusFixed,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-3333", CWEs: []string{"CWE-502"}},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/web"}}},
+			Status:        StatusFixed,
+		},
+	}
+
+	s := doc.StatementsByCWE("CWE-502")
+	require.Len(t, s, 2)
+	require.Equal(t, VulnerabilityID("CVE-2023-1111"), s[0].Vulnerability.Name)
+	require.Equal(t, VulnerabilityID("CVE-2023-3333"), s[1].Vulnerability.Name)
+	require.Len(t, doc.StatementsByCWE("cwe-79"), 1)
+	require.Empty(t, doc.StatementsByCWE("CWE-89"))
+	require.Equal(t, map[string]int{"CWE-502": 2, "CWE-20": 1, "CWE-79": 1}, doc.CWEs())
+
+	data, err := json.Marshal(&doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+
+	// Invalid IDs fail validation
+	doc.Statements[1].Vulnerability.CWEs = []string{"CWE-79", "XSS"}
+	err = doc.Statements[1].Validate()
+	require.Error(t, err)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.Equal(t, "/vulnerability/cwes/1", verr.Path)
+
+	data, err = json.Marshal(&doc)
+	require.NoError(t, err)
+	require.Error(t, Validate(data))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/cyclonedx"
+)
+
+// cycloneDXStatuses maps the CycloneDX analysis states to OpenVEX statuses.
+var cycloneDXStatuses = map[string]Status{
+	"resolved":               StatusFixed,
+	"resolved_with_pedigree": StatusFixed,
+	"exploitable":            StatusAffected,
+	"in_triage":              StatusUnderInvestigation,
+	"false_positive":         StatusNotAffected,
+	"not_affected":           StatusNotAffected,
+}
+
+// cycloneDXJustifications maps the CycloneDX analysis justifications to
+// OpenVEX justifications.
+var cycloneDXJustifications = map[string]Justification{
+	"code_not_present":                VulnerableCodeNotPresent,
+	"code_not_reachable":              VulnerableCodeNotInExecutePath,
+	"requires_configuration":          VulnerableCodeCannotBeControlledByAdversary,
+	"requires_dependency":             ComponentNotPresent,
+	"requires_environment":            VulnerableCodeCannotBeControlledByAdversary,
+	"protected_by_compiler":           InlineMitigationsAlreadyExist,
+	"protected_at_runtime":            InlineMitigationsAlreadyExist,
+	"protected_at_perimeter":          InlineMitigationsAlreadyExist,
+	"protected_by_mitigating_control": InlineMitigationsAlreadyExist,
+}
+
+// FromCycloneDX reads a CycloneDX document with vulnerability analysis data
+// and converts it into an OpenVEX document. Each vulnerability becomes a
+// statement covering the components it affects, which are resolved through
+// their bom-ref to components identified by their purl. References that
+// don't point to a component in the document, such as BOM-Links, are kept
+// as the product ID.
+//
+// Vulnerabilities without an analysis are converted as under investigation.
+// The analysis detail is the impact statement of not_affected statements
+// and the status notes of the rest. The recommendation, or the responses if
+// there is none, is the action statement of affected statements.
+func FromCycloneDX(r io.Reader) (*VEX, error) {
+	cdx, err := cyclonedx.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := New()
+	doc.ID = cdx.SerialNumber
+	if cdx.Version > 0 {
+		doc.Version = cdx.Version
+	}
+	if cdx.Metadata.Timestamp != nil {
+		ts := *cdx.Metadata.Timestamp
+		doc.Timestamp = &ts
+	}
+	authors := []string{}
+	for _, a := range cdx.Metadata.Authors {
+		if a.Name != "" {
+			authors = append(authors, a.Name)
+		}
+	}
+	switch {
+	case len(authors) > 0:
+		doc.Author = strings.Join(authors, ", ")
+	case cdx.Metadata.Supplier != nil && cdx.Metadata.Supplier.Name != "":
+		doc.Author = cdx.Metadata.Supplier.Name
+	}
+
+	for i := range cdx.Vulnerabilities {
+		stmt, err := cycloneDXStatement(cdx, &cdx.Vulnerabilities[i])
+		if err != nil {
+			return nil, fmt.Errorf("converting vulnerability #%d: %w", i, err)
+		}
+		doc.Statements = append(doc.Statements, stmt)
+	}
+	return &doc, nil
+}
+
+// cycloneDXStatement converts a CycloneDX vulnerability into a statement.
+func cycloneDXStatement(cdx *cyclonedx.Document, v *cyclonedx.Vulnerability) (Statement, error) {
+	if v.ID == "" {
+		return Statement{}, fmt.Errorf("vulnerability has no id")
+	}
+
+	stmt := Statement{
+		Vulnerability: Vulnerability{
+			Name:        VulnerabilityID(v.ID),
+			Description: v.Description,
+		},
+		Status: StatusUnderInvestigation,
+	}
+	for _, ref := range v.References {
+		if ref.ID != "" && ref.ID != v.ID {
+			stmt.Vulnerability.Aliases = append(stmt.Vulnerability.Aliases, VulnerabilityID(ref.ID))
+		}
+	}
+	for _, cwe := range v.CWEs {
+		stmt.Vulnerability.CWEs = append(stmt.Vulnerability.CWEs, fmt.Sprintf("CWE-%d", cwe))
+	}
+	for _, a := range v.Advisories {
+		// Advisories with URLs that are not valid references are skipped
+		stmt.Vulnerability.AddReference(ReferenceAdvisory, a.URL) //nolint:errcheck
+	}
+
+	if t := v.Analysis; t != nil {
+		if t.State != "" {
+			status, ok := cycloneDXStatuses[t.State]
+			if !ok {
+				return Statement{}, fmt.Errorf("unknown analysis state %q", t.State)
+			}
+			stmt.Status = status
+		}
+		switch {
+		case t.LastUpdated != nil:
+			ts := *t.LastUpdated
+			stmt.Timestamp = &ts
+		case t.FirstIssued != nil:
+			ts := *t.FirstIssued
+			stmt.Timestamp = &ts
+		}
+
+		if stmt.Status == StatusNotAffected {
+			stmt.Justification = cycloneDXJustifications[t.Justification]
+			stmt.ImpactStatement = t.Detail
+			if stmt.Justification == "" && stmt.ImpactStatement == "" && t.State == "false_positive" {
+				stmt.ImpactStatement = "The vulnerability was reported as a false positive."
+			}
+		} else {
+			stmt.StatusNotes = t.Detail
+		}
+		if stmt.Status == StatusAffected {
+			stmt.ActionStatement = v.Recommendation
+			if stmt.ActionStatement == "" && len(t.Response) > 0 {
+				stmt.ActionStatement = "Response: " + strings.Join(t.Response, ", ")
+			}
+		}
+	}
+
+	for _, a := range v.Affects {
+		if a.Ref == "" {
+			continue
+		}
+		component := Component{ID: a.Ref}
+		if c := cdx.ComponentByRef(a.Ref); c != nil {
+			component = cycloneDXComponent(cdx, c)
+		}
+		stmt.Products = append(stmt.Products, Product{Component: component})
+	}
+	return stmt, nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromCycloneDX(t *testing.T) {
+	f, err := os.Open("testdata/vex.cdx.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	doc, err := FromCycloneDX(f)
+	require.NoError(t, err)
+	require.Equal(t, "urn:uuid:0f7a7b4e-2bd2-4c47-8a53-1f3a8b0e7d20", doc.ID)
+	require.Equal(t, "Example Security Team", doc.Author)
+	require.Equal(t, 2, doc.Version)
+	require.Equal(t, "2023-06-01T10:00:00Z", doc.Timestamp.Format("2006-01-02T15:04:05Z07:00"))
+	require.Len(t, doc.Statements, 3)
+
+	na := doc.Statements[0]
+	require.Equal(t, StatusNotAffected, na.Status)
+	require.Equal(t, VulnerableCodeNotInExecutePath, na.Justification)
+	require.Equal(t, "X.400 addresses are never processed.", na.ImpactStatement)
+	require.Equal(t, []VulnerabilityID{"GHSA-x4qr-2fvf-3mr5"}, na.Vulnerability.Aliases)
+	require.Equal(t, []string{"CWE-843"}, na.Vulnerability.CWEs)
+	require.Len(t, na.Vulnerability.References, 1)
+	require.Equal(t, ReferenceAdvisory, na.Vulnerability.References[0].Type)
+	require.NotNil(t, na.Timestamp)
+	require.Len(t, na.Products, 2)
+	require.Equal(t, "pkg:apk/wolfi/openssl@3.0.8", na.Products[0].ID)
+	require.Equal(t, "pkg:oci/example-image@sha256%3Aabcdef", na.Products[1].ID)
+
+	// BOM-Links to other documents are kept as the product ID
+	affected := doc.Statements[1]
+	require.Equal(t, StatusAffected, affected.Status)
+	require.Equal(t, "Upgrade to openssl 3.0.9", affected.ActionStatement)
+	require.Equal(t, "urn:cdx:0f7a7b4e-2bd2-4c47-8a53-1f3a8b0e7d21/1#openssl", affected.Products[0].ID)
+
+	// Vulnerabilities without analysis are under investigation
+	require.Equal(t, StatusUnderInvestigation, doc.Statements[2].Status)
+
+	for i := range doc.Statements {
+		require.NoError(t, doc.Statements[i].Validate())
+	}
+}
+
+func TestFromCycloneDXErrors(t *testing.T) {
+	_, err := FromCycloneDX(strings.NewReader(`{"bomFormat": "SPDX"}`))
+	require.Error(t, err)
+
+	_, err = FromCycloneDX(strings.NewReader(`{"bomFormat": "CycloneDX", "vulnerabilities": [{"analysis": {"state": "not_affected"}}]}`))
+	require.Error(t, err)
+
+	_, err = FromCycloneDX(strings.NewReader(`{"bomFormat": "CycloneDX", "vulnerabilities": [{"id": "CVE-2023-1234", "analysis": {"state": "bogus"}}]}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Finding identifies a vulnerability reported by a scanner on a product and,
+// optionally, some of its subcomponents.
+type Finding struct {
+	Vulnerability string   `json:"vulnerability"`
+	Product       string   `json:"product"`
+	Subcomponents []string `json:"subcomponents,omitempty"`
+}
+
+// DecisionRecord is a machine-readable record of an automated decision to
+// suppress a finding based on a VEX statement. Records are meant to be stored
+// as an audit trail of the suppressions performed by a filtering tool.
+type DecisionRecord struct {
+	// Finding is the scanner finding that was suppressed
+	Finding Finding `json:"finding"`
+
+	// StatementID is the @id of the statement that triggered the decision
+	StatementID string `json:"statement_id,omitempty"`
+
+	// Status and Justification are copied from the statement
+	Status        Status        `json:"status"`
+	Justification Justification `json:"justification,omitempty"`
+
+	// DocumentID is the @id of the VEX document containing the statement
+	DocumentID string `json:"document_id,omitempty"`
+
+	// DocumentHash is the canonical hash of the VEX document
+	DocumentHash string `json:"document_hash,omitempty"`
+
+	// PolicyVersion is an opaque string identifying the policy in effect
+	// when the decision was made.
+	PolicyVersion string `json:"policy_version,omitempty"`
+
+	// Timestamp is the time when the decision was made
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// DecisionWriter writes DecisionRecords to an io.Writer in JSON Lines format,
+// one record per line. It is safe for concurrent use.
+type DecisionWriter struct {
+	// PolicyVersion is recorded in every record written
+	PolicyVersion string
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewDecisionWriter returns a new DecisionWriter that writes to w.
+func NewDecisionWriter(w io.Writer) *DecisionWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &DecisionWriter{enc: enc}
+}
+
+// Record writes a decision record noting that finding was suppressed by
+// statement stmt of document doc.
+func (dw *DecisionWriter) Record(doc *VEX, stmt *Statement, finding Finding) error {
+	if stmt == nil {
+		return fmt.Errorf("unable to record decision: %w", ErrNoStatement)
+	}
+
+	rec := DecisionRecord{
+		Finding:       finding,
+		StatementID:   stmt.ID,
+		Status:        stmt.Status,
+		Justification: stmt.Justification,
+		PolicyVersion: dw.PolicyVersion,
+		Timestamp:     time.Now().UTC(),
+	}
+
+	if doc != nil && doc.Timestamp != nil {
+		hash, err := doc.CanonicalHash()
+		if err != nil {
+			return fmt.Errorf("hashing document: %w", err)
+		}
+		rec.DocumentHash = hash
+	}
+	if doc != nil {
+		rec.DocumentID = doc.ID
+	}
+
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	if err := dw.enc.Encode(&rec); err != nil {
+		return fmt.Errorf("encoding decision record: %w", err)
+	}
+	return nil
+}
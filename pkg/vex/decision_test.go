@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecisionWriter(t *testing.T) {
+	ts := time.Date(2023, 4, 17, 20, 34, 58, 0, time.UTC)
+	doc := &VEX{
+		Metadata: Metadata{ID: "https://example.com/vex-1", Timestamp: &ts, Version: 1},
+		Statements: []Statement{
+			{
+				ID:            "https://example.com/vex-1#stmt-1",
+				Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+				Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/bash@1.0.0"}}},
+				Status:        StatusNotAffected,
+				Justification: ComponentNotPresent,
+			},
+		},
+	}
+	hash, err := doc.CanonicalHash()
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	dw := NewDecisionWriter(&b)
+	dw.PolicyVersion = "v1"
+
+	findings := []Finding{
+		{Vulnerability: "CVE-2023-1234", Product: "pkg:apk/wolfi/bash@1.0.0"},
+		{Vulnerability: "CVE-2023-1234", Product: "pkg:apk/wolfi/bash@1.0.0", Subcomponents: []string{"pkg:golang/a@1"}},
+	}
+	for _, f := range findings {
+		require.NoError(t, dw.Record(doc, &doc.Statements[0], f))
+	}
+	require.Error(t, dw.Record(doc, nil, findings[0]))
+
+	scanner := bufio.NewScanner(&b)
+	i := 0
+	for scanner.Scan() {
+		rec := DecisionRecord{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec))
+		require.Equal(t, findings[i], rec.Finding)
+		require.Equal(t, "https://example.com/vex-1#stmt-1", rec.StatementID)
+		require.Equal(t, StatusNotAffected, rec.Status)
+		require.Equal(t, ComponentNotPresent, rec.Justification)
+		require.Equal(t, "https://example.com/vex-1", rec.DocumentID)
+		require.Equal(t, hash, rec.DocumentHash)
+		require.Equal(t, "v1", rec.PolicyVersion)
+		require.False(t, rec.Timestamp.IsZero())
+		i++
+	}
+	require.Equal(t, 2, i)
+
+	// The hash follows changes to the document
+	b.Reset()
+	doc.Statements[0].Status = StatusFixed
+	require.NoError(t, dw.Record(doc, &doc.Statements[0], findings[0]))
+	rec := DecisionRecord{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &rec))
+	changed, err := doc.CanonicalHash()
+	require.NoError(t, err)
+	require.NotEqual(t, hash, changed)
+	require.Equal(t, changed, rec.DocumentHash)
+}
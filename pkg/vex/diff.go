@@ -0,0 +1,287 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiffReport describes the differences between two versions of a document.
+// It marshals to JSON for tools and String renders it for people. It is
+// also the patch format read by ApplyPatch.
+type DiffReport struct {
+	// Metadata lists the document fields that changed
+	Metadata []FieldChange `json:"metadata"`
+
+	// Added and Removed are the statements only in the new or the old
+	// document
+	Added   []Statement `json:"added"`
+	Removed []Statement `json:"removed"`
+
+	// Changed are the statements present in both documents with different
+	// fields
+	Changed []StatementChange `json:"changed"`
+
+	// StatusChanges lists the vulnerability and product pairs whose
+	// effective status changed
+	StatusChanges []StatusChange `json:"status_changes"`
+}
+
+// FieldChange is a field with different values in the documents compared.
+// Values are rendered as strings, structured values as JSON.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// StatementChange is a statement present in both documents with changes.
+type StatementChange struct {
+	Old    Statement     `json:"old"`
+	New    Statement     `json:"new"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// StatusChange is a change in the effective status of a vulnerability in a
+// product. Pairs without a statement in one of the documents have the
+// NoStatement status there. Justification is the one of the new effective
+// statement, if any.
+type StatusChange struct {
+	Vulnerability string        `json:"vulnerability"`
+	Product       string        `json:"product"`
+	Old           Status        `json:"old"`
+	New           Status        `json:"new"`
+	Justification Justification `json:"justification,omitempty"`
+}
+
+// Diff compares two versions of a document. Statements are paired by their
+// @id or, if they have none, by their vulnerability and products; several
+// statements with the same key are paired in order. Paired statements are
+// compared field by field, including their extension and unknown fields.
+// Effective statuses are compared for every vulnerability and product ID in
+// either document.
+func Diff(a, b *VEX) *DiffReport {
+	report := &DiffReport{
+		Metadata:      diffMetadata(&a.Metadata, &b.Metadata),
+		Added:         []Statement{},
+		Removed:       []Statement{},
+		Changed:       []StatementChange{},
+		StatusChanges: []StatusChange{},
+	}
+
+	pending := map[string][]int{}
+	for i := range b.Statements {
+		k := diffKey(&b.Statements[i])
+		pending[k] = append(pending[k], i)
+	}
+	paired := make([]bool, len(b.Statements))
+	for i := range a.Statements {
+		old := &a.Statements[i]
+		k := diffKey(old)
+		if len(pending[k]) == 0 {
+			report.Removed = append(report.Removed, *old)
+			continue
+		}
+		j := pending[k][0]
+		pending[k] = pending[k][1:]
+		paired[j] = true
+		if fields := diffStatement(old, &b.Statements[j]); len(fields) > 0 {
+			report.Changed = append(report.Changed, StatementChange{Old: *old, New: b.Statements[j], Fields: fields})
+		}
+	}
+	for j := range b.Statements {
+		if !paired[j] {
+			report.Added = append(report.Added, b.Statements[j])
+		}
+	}
+
+	report.StatusChanges = diffStatuses(a, b)
+	return report
+}
+
+// Empty returns true if the documents compared are equivalent.
+func (d *DiffReport) Empty() bool {
+	return len(d.Metadata) == 0 && len(d.Added) == 0 && len(d.Removed) == 0 &&
+		len(d.Changed) == 0 && len(d.StatusChanges) == 0
+}
+
+// String renders the report as text.
+func (d *DiffReport) String() string {
+	var b strings.Builder
+	for _, f := range d.Metadata {
+		fmt.Fprintf(&b, "~ %s: %q -> %q\n", f.Field, f.Old, f.New)
+	}
+	for i := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", diffSummary(&d.Removed[i]))
+	}
+	for i := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", diffSummary(&d.Added[i]))
+	}
+	for i := range d.Changed {
+		fmt.Fprintf(&b, "~ %s\n", diffSummary(&d.Changed[i].New))
+		for _, f := range d.Changed[i].Fields {
+			fmt.Fprintf(&b, "    %s: %q -> %q\n", f.Field, f.Old, f.New)
+		}
+	}
+	for _, s := range d.StatusChanges {
+		fmt.Fprintf(&b, "! %s on %s: %s -> %s\n", s.Vulnerability, s.Product, s.Old, s.New)
+	}
+	return b.String()
+}
+
+func diffSummary(s *Statement) string {
+	products := []string{}
+	for i := range s.Products {
+		products = append(products, s.Products[i].ID)
+	}
+	return fmt.Sprintf("%s [%s] %s", s.Vulnerability.Name, strings.Join(products, ", "), s.Status)
+}
+
+// diffKey returns the key pairing statements across documents.
+func diffKey(s *Statement) string {
+	if s.ID != "" {
+		return "@id " + s.ID
+	}
+	products := []string{}
+	for i := range s.Products {
+		p := &s.Products[i]
+		id := p.ID
+		subs := []string{}
+		for j := range p.Subcomponents {
+			subs = append(subs, p.Subcomponents[j].ID)
+		}
+		sort.Strings(subs)
+		if len(subs) > 0 {
+			id += "(" + strings.Join(subs, ",") + ")"
+		}
+		products = append(products, id)
+	}
+	sort.Strings(products)
+	return string(s.Vulnerability.Name) + " " + strings.Join(products, " ")
+}
+
+func diffMetadata(a, b *Metadata) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, FieldChange{Field: field, Old: from, New: to})
+		}
+	}
+	add("@context", a.Context, b.Context)
+	add("@id", a.ID, b.ID)
+	add("author", a.Author, b.Author)
+	add("role", a.AuthorRole, b.AuthorRole)
+	add("timestamp", diffTime(a.Timestamp), diffTime(b.Timestamp))
+	add("last_updated", diffTime(a.LastUpdated), diffTime(b.LastUpdated))
+	add("version", strconv.Itoa(a.Version), strconv.Itoa(b.Version))
+	add("tooling", a.Tooling, b.Tooling)
+	add("supplier", a.Supplier, b.Supplier)
+	return changes
+}
+
+func diffStatement(a, b *Statement) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, FieldChange{Field: field, Old: from, New: to})
+		}
+	}
+	add("@id", a.ID, b.ID)
+	add("vulnerability", diffJSON(a.Vulnerability), diffJSON(b.Vulnerability))
+	add("timestamp", diffTime(a.Timestamp), diffTime(b.Timestamp))
+	add("last_updated", diffTime(a.LastUpdated), diffTime(b.LastUpdated))
+	add("products", diffJSON(a.Products), diffJSON(b.Products))
+	add("status", string(a.Status), string(b.Status))
+	add("status_notes", a.StatusNotes, b.StatusNotes)
+	add("justification", string(a.Justification), string(b.Justification))
+	add("impact_statement", a.ImpactStatement, b.ImpactStatement)
+	add("action_statement", a.ActionStatement, b.ActionStatement)
+	add("action_statement_timestamp", diffTime(a.ActionStatementTimestamp), diffTime(b.ActionStatementTimestamp))
+	add("ssvc", diffOptionalJSON(a.SSVC), diffOptionalJSON(b.SSVC))
+	add("references", diffOptionalJSON(a.References), diffOptionalJSON(b.References))
+	add("fix", diffOptionalJSON(a.Fix), diffOptionalJSON(b.Fix))
+	add("extensions", diffOptionalJSON(a.RawExtensions), diffOptionalJSON(b.RawExtensions))
+	return changes
+}
+
+// diffStatuses compares the effective status of every vulnerability and
+// product ID mentioned in either document.
+func diffStatuses(a, b *VEX) []StatusChange {
+	type pair struct{ vuln, product string }
+	pairs := []pair{}
+	seen := map[pair]bool{}
+	for _, doc := range []*VEX{a, b} {
+		for i := range doc.Statements {
+			s := &doc.Statements[i]
+			for j := range s.Products {
+				p := pair{string(s.Vulnerability.Name), s.Products[j].ID}
+				if p.vuln == "" || p.product == "" || seen[p] {
+					continue
+				}
+				seen[p] = true
+				pairs = append(pairs, p)
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].vuln != pairs[j].vuln {
+			return pairs[i].vuln < pairs[j].vuln
+		}
+		return pairs[i].product < pairs[j].product
+	})
+
+	// The index does not reorder the statements like EffectiveStatement
+	idxA, idxB := NewIndex(a), NewIndex(b)
+	changes := []StatusChange{}
+	for _, p := range pairs {
+		from, to := NoStatement, NoStatement
+		var justification Justification
+		if s := idxA.EffectiveStatement(p.product, p.vuln); s != nil {
+			from = s.Status
+		}
+		if s := idxB.EffectiveStatement(p.product, p.vuln); s != nil {
+			to = s.Status
+			justification = s.Justification
+		}
+		if from != to {
+			changes = append(changes, StatusChange{
+				Vulnerability: p.vuln, Product: p.product, Old: from, New: to, Justification: justification,
+			})
+		}
+	}
+	return changes
+}
+
+func diffTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func diffJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// diffOptionalJSON renders an optional value as JSON, or as an empty string
+// when it is not set.
+func diffOptionalJSON(v any) string {
+	switch s := diffJSON(v); s {
+	case "null", "[]", "{}":
+		return ""
+	default:
+		return s
+	}
+}
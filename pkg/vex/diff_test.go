@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+
+	a := New()
+	a.ID = "https://example.com/vex/1"
+	a.Timestamp = &t1
+	a.Version = 1
+	a.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusUnderInvestigation,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusFixed,
+		},
+	}
+
+	b := New()
+	b.ID = a.ID
+	b.Timestamp = &t2
+	b.Version = 2
+	b.Statements = []Statement{
+		{
+			Vulnerability:   Vulnerability{Name: "CVE-2023-1111"},
+			Products:        []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:          StatusAffected,
+			ActionStatement: "Update the base image",
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-3333"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		},
+	}
+
+	d := Diff(&a, &b)
+	require.False(t, d.Empty())
+	require.Equal(t, []FieldChange{
+		{Field: "timestamp", Old: "2023-06-01T00:00:00Z", New: "2023-06-02T00:00:00Z"},
+		{Field: "version", Old: "1", New: "2"},
+	}, d.Metadata)
+
+	require.Len(t, d.Removed, 1)
+	require.Equal(t, VulnerabilityID("CVE-2023-2222"), d.Removed[0].Vulnerability.Name)
+	require.Len(t, d.Added, 1)
+	require.Equal(t, VulnerabilityID("CVE-2023-3333"), d.Added[0].Vulnerability.Name)
+
+	require.Len(t, d.Changed, 1)
+	require.Equal(t, []FieldChange{
+		{Field: "status", Old: "under_investigation", New: "affected"},
+		{Field: "action_statement", Old: "", New: "Update the base image"},
+	}, d.Changed[0].Fields)
+
+	require.Equal(t, []StatusChange{
+		{Vulnerability: "CVE-2023-1111", Product: "pkg:oci/app", Old: StatusUnderInvestigation, New: StatusAffected},
+		{Vulnerability: "CVE-2023-2222", Product: "pkg:oci/app", Old: StatusFixed, New: NoStatement},
+		{Vulnerability: "CVE-2023-3333", Product: "pkg:oci/app", Old: NoStatement, New: StatusNotAffected, Justification: ComponentNotPresent},
+	}, d.StatusChanges)
+
+	require.Equal(t, `~ timestamp: "2023-06-01T00:00:00Z" -> "2023-06-02T00:00:00Z"
+~ version: "1" -> "2"
+- CVE-2023-2222 [pkg:oci/app] fixed
++ CVE-2023-3333 [pkg:oci/app] not_affected
+~ CVE-2023-1111 [pkg:oci/app] affected
+    status: "under_investigation" -> "affected"
+    action_statement: "" -> "Update the base image"
+! CVE-2023-1111 on pkg:oci/app: under_investigation -> affected
+! CVE-2023-2222 on pkg:oci/app: fixed -> no_statement
+! CVE-2023-3333 on pkg:oci/app: no_statement -> not_affected
+`, d.String())
+
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"status_changes":[{"vulnerability":"CVE-2023-1111"`)
+
+	// The documents are not reordered
+	require.Equal(t, VulnerabilityID("CVE-2023-3333"), b.Statements[1].Vulnerability.Name)
+
+	require.True(t, Diff(&a, &a).Empty())
+	require.Empty(t, Diff(&a, &a).String())
+}
+
+func TestDiffStatementFields(t *testing.T) {
+	base := Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusNotAffected,
+		Justification: ComponentNotPresent,
+	}
+	for field, change := range map[string]func(*Statement){
+		"ssvc": func(s *Statement) {
+			s.SSVC = &SSVC{Exploitation: SSVCExploitationActive, Automatable: "yes", TechnicalImpact: "total", MissionImpact: "high"}
+		},
+		"references": func(s *Statement) {
+			s.References = []Reference{{URL: "https://example.com/advisories/1", Type: ReferenceAdvisory}}
+		},
+		"fix": func(s *Statement) {
+			s.Status = StatusFixed
+			s.Justification = ""
+			s.Fix = &Fix{Versions: []string{"1.2.3"}}
+		},
+		"extensions": func(s *Statement) {
+			s.RawExtensions = map[string]json.RawMessage{"x-ticket": json.RawMessage(`"SEC-1234"`)}
+		},
+	} {
+		a, b := New(), New()
+		a.Statements = []Statement{base.Clone()}
+		b.Statements = []Statement{base.Clone()}
+		change(&b.Statements[0])
+
+		d := Diff(&a, &b)
+		require.False(t, d.Empty(), field)
+		require.Len(t, d.Changed, 1, field)
+		fields := d.Changed[0].Fields
+		require.Equal(t, field, fields[len(fields)-1].Field)
+		require.Empty(t, fields[len(fields)-1].Old, field)
+
+		// Patches are not applied over statements with other values
+		patch := Diff(&b, &a)
+		require.Error(t, a.ApplyPatch(patch), field)
+		require.NoError(t, b.ApplyPatch(patch), field)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+// EPSSScore is the Exploit Prediction Scoring System score of a
+// vulnerability published by FIRST: the probability of it being exploited
+// in the next 30 days and how it ranks among all the scored
+// vulnerabilities. The epss package annotates documents with them.
+//
+// https://www.first.org/epss/
+type EPSSScore struct {
+	// Score is the probability of exploitation, from 0 to 1
+	Score float64 `json:"score"`
+
+	// Percentile is the proportion of vulnerabilities with a lower score
+	Percentile float64 `json:"percentile,omitempty"`
+
+	// Date is the day the score was computed, as YYYY-MM-DD
+	Date string `json:"date,omitempty"`
+}
+
+// StatementsAboveEPSS returns the statements whose vulnerability has an
+// EPSS score of at least threshold, in document order. Vulnerabilities
+// without a score are left out.
+func (vexDoc *VEX) StatementsAboveEPSS(threshold float64) []Statement {
+	ret := []Statement{}
+	for i := range vexDoc.Statements {
+		epss := vexDoc.Statements[i].Vulnerability.EPSS
+		if epss != nil && epss.Score >= threshold {
+			ret = append(ret, vexDoc.Statements[i])
+		}
+	}
+	return ret
+}
+
+// MaxEPSS returns the highest EPSS score of the vulnerabilities in the
+// document and false if none has a score.
+func (vexDoc *VEX) MaxEPSS() (float64, bool) {
+	found := false
+	highest := 0.0
+	for i := range vexDoc.Statements {
+		if epss := vexDoc.Statements[i].Vulnerability.EPSS; epss != nil {
+			highest = max(highest, epss.Score)
+			found = true
+		}
+	}
+	return highest, found
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementsAboveEPSS(t *testing.T) {
+	doc := New(WithID("https://example.com/vex/1"))
+	_, ok := doc.MaxEPSS()
+	require.False(t, ok)
+
+	doc.Statements = []Statement{
+		{Vulnerability: Vulnerability{Name: "CVE-2023-1111", EPSS: &EPSSScore{Score: 0.97, Percentile: 0.999}}, Status: StatusAffected},
+		{Vulnerability: Vulnerability{Name: "CVE-2023-2222", EPSS: &EPSSScore{Score: 0.5}}, Status: StatusAffected},
+		{Vulnerability: Vulnerability{Name: "CVE-2023-3333", EPSS: &EPSSScore{Score: 0.01}}, Status: StatusAffected},
+		{Vulnerability: Vulnerability{Name: "CVE-2023-4444"}, Status: StatusAffected},
+	}
+
+	above := doc.StatementsAboveEPSS(0.5)
+	require.Len(t, above, 2)
+	require.Equal(t, VulnerabilityID("CVE-2023-1111"), above[0].Vulnerability.Name)
+	require.Equal(t, VulnerabilityID("CVE-2023-2222"), above[1].Vulnerability.Name)
+	require.Len(t, doc.StatementsAboveEPSS(0), 3)
+
+	highest, ok := doc.MaxEPSS()
+	require.True(t, ok)
+	require.Equal(t, 0.97, highest)
+
+	// Scores are kept through serialization and validate
+	doc.Statements[0].Products = []Product{{Component: Component{ID: "pkg:oci/app"}}}
+	doc.Statements[0].ActionStatement = "Update"
+	doc.Statements = doc.Statements[:1]
+	data, err := json.Marshal(&doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+	require.Contains(t, string(data), `"epss":{"score":0.97,"percentile":0.999}`)
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	require.Equal(t, doc.Statements[0].Vulnerability.EPSS, parsed.Statements[0].Vulnerability.EPSS)
+
+	// Scores outside of [0, 1] are rejected
+	doc.Statements[0].Vulnerability.EPSS = &EPSSScore{Score: 7.5}
+	require.Error(t, doc.Validate())
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors wrapped by the functions in this package. Check for them
+// with errors.Is to tell failure causes apart.
+var (
+	// ErrNoStatement is returned when an operation requires a statement
+	// that is missing or can't be found in the document.
+	ErrNoStatement = errors.New("no matching statement")
+
+	// ErrInvalidContext is returned when a document has no OpenVEX context
+	// or its context does not point to a supported version of the spec.
+	ErrInvalidContext = errors.New("invalid OpenVEX context")
+
+	// ErrMalformedPurl is returned when a package URL can't be parsed.
+	ErrMalformedPurl = errors.New("malformed purl")
+
+	// ErrVersionConflict is returned when the version of a document does
+	// not match the one expected.
+	ErrVersionConflict = errors.New("version conflict")
+)
+
+// ValidationError is returned by the parsing and validation functions to
+// point to the offending field in a document. Path is a JSON pointer (RFC 6901)
+// relative to the root of the validated element, for example
+// /statements/3/products/0/@id. An empty Path refers to the element itself.
+type ValidationError struct {
+	Path string
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %v", path, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// withPathPrefix returns err prefixing its path with prefix if it is a
+// *ValidationError. Any other error is wrapped in a new ValidationError.
+func withPathPrefix(prefix string, err error) *ValidationError {
+	var verr *ValidationError
+	if errors.As(err, &verr) && verr == err {
+		return &ValidationError{Path: prefix + verr.Path, Err: verr.Err}
+	}
+	return &ValidationError{Path: prefix, Err: err}
+}
+
+// validationErrorFromJSON wraps an error returned by the JSON decoder in a
+// ValidationError. The path is taken from the field reported by the decoder on
+// type mismatches. Note that older Go versions do not include array indices
+// in the field, so the path is a best effort.
+func validationErrorFromJSON(err error) *ValidationError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &ValidationError{Path: "/" + strings.ReplaceAll(typeErr.Field, ".", "/"), Err: err}
+	}
+	return &ValidationError{Err: err}
+}
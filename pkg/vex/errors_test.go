@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrorPaths(t *testing.T) {
+	product := []Product{{Component: Component{ID: "pkg:apk/wolfi/bash@1.0.0"}}}
+	for m, tc := range map[string]struct {
+		fn    func() error
+		path  string
+		paths []string
+	}{
+		"parse type error": {
+			fn: func() error {
+				_, err := Parse([]byte(`{"statements": [{"vulnerability": {"name": 1}}]}`))
+				return err
+			},
+			// Older Go versions do not report array indices
+			paths: []string{"/statements/0/vulnerability/name", "/statements/vulnerability/name"},
+		},
+		"parse syntax error": {
+			fn: func() error {
+				_, err := Parse([]byte(`{"statements": [`))
+				return err
+			},
+			path: "",
+		},
+		"statement validation": {
+			fn: func() error {
+				return Statement{Status: StatusFixed, ImpactStatement: "fixed"}.Validate()
+			},
+			path: "/impact_statement",
+		},
+		"add statement": {
+			fn: func() error {
+				doc := New()
+				doc.Statements = append(doc.Statements, Statement{})
+				return doc.AddStatement(Statement{
+					Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+					Products:      product,
+					Status:        StatusAffected,
+				})
+			},
+			path: "/statements/1/action_statement",
+		},
+		"schema validation": {
+			fn: func() error {
+				return Validate([]byte(`{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1",
+				"author": "John Doe", "timestamp": "2023-01-01T00:00:00Z", "version": 1,
+				"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "status": "wontfix"}]}`))
+			},
+			path: "/statements/0/status",
+		},
+		"invalid purls": {
+			fn: func() error {
+				_, err := ParseWithOptions(&ParseOptions{ValidatePurls: true}, []byte(
+					`{"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "products": [{"@id": "pkg:/golang"}]}]}`,
+				))
+				return err
+			},
+			path: "/statements/0/products/0/@id",
+		},
+		"missing justification": {
+			fn: func() error {
+				_, err := ParseWithOptions(&ParseOptions{EnforceJustification: true}, []byte(
+					`{"statements": [{}, {"vulnerability": {"name": "CVE-2023-1234"}, "status": "not_affected"}]}`,
+				))
+				return err
+			},
+			path: "/statements/1/justification",
+		},
+		"statement missing justification": {
+			fn: func() error {
+				return Statement{Status: StatusNotAffected}.Validate()
+			},
+			path: "/justification",
+		},
+		"add statement missing justification": {
+			fn: func() error {
+				doc := New()
+				return doc.AddStatement(Statement{
+					Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+					Products:      product,
+					Status:        StatusNotAffected,
+				})
+			},
+			path: "/statements/0/justification",
+		},
+	} {
+		err := tc.fn()
+		require.Error(t, err, m)
+		var verr *ValidationError
+		require.True(t, errors.As(err, &verr), m)
+		if tc.paths != nil {
+			require.Contains(t, tc.paths, verr.Path, m)
+			continue
+		}
+		require.Equal(t, tc.path, verr.Path, m)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	for m, tc := range map[string]struct {
+		fn     func() error
+		target error
+	}{
+		"no statement": {
+			fn: func() error {
+				return NewDecisionWriter(io.Discard).Record(&VEX{}, nil, Finding{})
+			},
+			target: ErrNoStatement,
+		},
+		"missing context": {
+			fn: func() error {
+				_, err := ParseCompat([]byte(`{"statements": []}`))
+				return err
+			},
+			target: ErrInvalidContext,
+		},
+		"unsupported context version": {
+			fn: func() error {
+				_, err := ContextVersion("https://openvex.dev/ns/v9.9.9")
+				return err
+			},
+			target: ErrInvalidContext,
+		},
+		"malformed purl": {
+			fn: func() error {
+				_, err := ParseWithOptions(&ParseOptions{ValidatePurls: true}, []byte(
+					`{"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "products": [{"@id": "pkg:/golang"}]}]}`,
+				))
+				return err
+			},
+			target: ErrMalformedPurl,
+		},
+		"declared version": {
+			fn: func() error {
+				return ValidateVersion("0.0.1", []byte(`{"@context": "https://openvex.dev/ns/v0.2.0"}`))
+			},
+			target: ErrVersionConflict,
+		},
+	} {
+		err := tc.fn()
+		require.ErrorIs(t, err, tc.target, m)
+	}
+}
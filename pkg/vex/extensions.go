@@ -0,0 +1,453 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExtensionPrefix is the prefix of the names of extension properties.
+// Documents and statements can carry extension properties to record data not
+// covered by the spec, such as internal ticket IDs or approvals. They are
+// accepted by the JSON schema and the strict parser.
+const ExtensionPrefix = "x-"
+
+// The JSON fields of the types that keep unknown fields.
+var (
+	vexFields       = jsonFields(reflect.TypeOf(VEX{}))
+	statementFields = jsonFields(reflect.TypeOf(Statement{}))
+	componentFields = jsonFields(reflect.TypeOf(Component{}))
+	productFields   = jsonFields(reflect.TypeOf(Product{}))
+)
+
+// jsonFields returns the types of the fields of a struct type keyed by
+// their name as encoded by encoding/json, including the fields of embedded
+// structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			maps.Copy(fields, jsonFields(f.Type))
+			continue
+		}
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// unknownFields returns the fields of a JSON object whose names are not
+// in known. It returns nil if all the fields are known.
+func unknownFields(data []byte, known map[string]reflect.Type) (map[string]json.RawMessage, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	for name := range known {
+		delete(raw, name)
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// appendFields adds fields to the serialized JSON object in data, sorted by
+// name. Fields which clash with the known fields are skipped as the struct
+// fields take precedence.
+func appendFields(data []byte, fields map[string]json.RawMessage, known map[string]reflect.Type) ([]byte, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if _, ok := known[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(bytes.TrimSpace(data), []byte("}")))
+	for _, name := range names {
+		if !json.Valid(fields[name]) {
+			return nil, fmt.Errorf("extension field %q is not valid JSON", name)
+		}
+		if !bytes.HasSuffix(bytes.TrimSpace(buf.Bytes()), []byte("{")) {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(fields[name])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalJSON encodes v without escaping HTML characters. The escaping is
+// left to the caller encoding the value, json.Marshal escapes the output of
+// MarshalJSON methods while the encoder in ToJSON keeps it as is.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// unmarshalList decodes the elements of a JSON array one by one so the
+// errors of types with their own UnmarshalJSON keep the path of the value.
+func unmarshalList[T any](raw []json.RawMessage, field string) ([]T, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	ret := make([]T, len(raw))
+	for i := range raw {
+		if err := json.Unmarshal(raw[i], &ret[i]); err != nil {
+			return nil, prefixTypeError(err, fmt.Sprintf("%s.%d", field, i))
+		}
+	}
+	return ret, nil
+}
+
+// prefixTypeError prepends prefix to the field of JSON type errors.
+func prefixTypeError(err error, prefix string) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+	ret := *typeErr
+	ret.Field = prefix
+	if typeErr.Field != "" {
+		ret.Field += "." + typeErr.Field
+	}
+	return &ret
+}
+
+// checkUnknownFields returns a ValidationError pointing to the first field
+// in data which is not defined in the type t or the types it contains. It
+// is used by the strict parser, as json.Decoder.DisallowUnknownFields has no
+// effect on types that implement their own UnmarshalJSON. Values which don't
+// have the JSON type expected by t are skipped, those errors are left to the
+// decoder.
+func checkUnknownFields(data []byte, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return nil
+		}
+		obj := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil
+		}
+		fields := jsonFields(t)
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ft, ok := fields[name]
+			if !ok && strings.HasPrefix(name, ExtensionPrefix) &&
+				(t == reflect.TypeOf(VEX{}) || t == reflect.TypeOf(Statement{})) {
+				continue
+			}
+			if !ok {
+				return &ValidationError{Path: path + "/" + name, Err: fmt.Errorf("unknown field %q", name)}
+			}
+			if err := checkUnknownFields(obj[name], ft, path+"/"+name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		list := []json.RawMessage{}
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil
+		}
+		for i := range list {
+			if err := checkUnknownFields(list[i], t.Elem(), fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		obj := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil
+		}
+		for key, value := range obj {
+			if err := checkUnknownFields(value, t.Elem(), path+"/"+key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalJSON serializes the document including its RawExtensions.
+func (vexDoc VEX) MarshalJSON() ([]byte, error) {
+	type vexAlias VEX
+	data, err := marshalJSON(vexAlias(vexDoc))
+	if err != nil {
+		return nil, err
+	}
+	return appendFields(data, vexDoc.RawExtensions, vexFields)
+}
+
+// UnmarshalJSON reads a document, keeping the fields not defined in the spec
+// in RawExtensions.
+func (vexDoc *VEX) UnmarshalJSON(data []byte) error {
+	type vexAlias VEX
+	aux := struct {
+		*vexAlias
+		Statements []json.RawMessage `json:"statements"`
+	}{vexAlias: (*vexAlias)(vexDoc)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Statements != nil {
+		statements, err := unmarshalList[Statement](aux.Statements, "statements")
+		if err != nil {
+			return err
+		}
+		vexDoc.Statements = statements
+	}
+	ext, err := unknownFields(data, vexFields)
+	if err != nil {
+		return err
+	}
+	vexDoc.RawExtensions = ext
+	return nil
+}
+
+// MarshalJSON serializes the statement including its RawExtensions.
+func (stmt Statement) MarshalJSON() ([]byte, error) {
+	type statementAlias Statement
+	data, err := marshalJSON(statementAlias(stmt))
+	if err != nil {
+		return nil, err
+	}
+	return appendFields(data, stmt.RawExtensions, statementFields)
+}
+
+// UnmarshalJSON reads a statement, keeping the fields not defined in the
+// spec in RawExtensions.
+func (stmt *Statement) UnmarshalJSON(data []byte) error {
+	type statementAlias Statement
+	aux := struct {
+		*statementAlias
+		Products []json.RawMessage `json:"products"`
+	}{statementAlias: (*statementAlias)(stmt)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Products != nil {
+		products, err := unmarshalList[Product](aux.Products, "products")
+		if err != nil {
+			return err
+		}
+		stmt.Products = products
+	}
+	ext, err := unknownFields(data, statementFields)
+	if err != nil {
+		return err
+	}
+	stmt.RawExtensions = ext
+	return nil
+}
+
+// MarshalJSON serializes the component including its RawExtensions.
+func (c Component) MarshalJSON() ([]byte, error) {
+	type componentAlias Component
+	data, err := marshalJSON(componentAlias(c))
+	if err != nil {
+		return nil, err
+	}
+	return appendFields(data, c.RawExtensions, componentFields)
+}
+
+// UnmarshalJSON reads a component, keeping the fields not defined in the
+// spec in RawExtensions.
+func (c *Component) UnmarshalJSON(data []byte) error {
+	return c.unmarshalJSON(data, componentFields)
+}
+
+// unmarshalJSON reads a component from a JSON object which may have the
+// fields of a type embedding it, passed as known.
+func (c *Component) unmarshalJSON(data []byte, known map[string]reflect.Type) error {
+	type componentAlias Component
+	if err := json.Unmarshal(data, (*componentAlias)(c)); err != nil {
+		return err
+	}
+	ext, err := unknownFields(data, known)
+	if err != nil {
+		return err
+	}
+	c.RawExtensions = ext
+	return nil
+}
+
+// MarshalJSON serializes the product. It is needed as Product would
+// otherwise be encoded with the promoted methods of Component, dropping the
+// subcomponents.
+func (p Product) MarshalJSON() ([]byte, error) {
+	data, err := p.Component.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Subcomponents) == 0 {
+		return data, nil
+	}
+	subcomponents, err := marshalJSON(p.Subcomponents)
+	if err != nil {
+		return nil, err
+	}
+	return appendFields(data, map[string]json.RawMessage{
+		"subcomponents": subcomponents,
+	}, componentFields)
+}
+
+// UnmarshalJSON reads a product and its subcomponents.
+func (p *Product) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		Subcomponents []json.RawMessage `json:"subcomponents"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Subcomponents != nil {
+		subcomponents, err := unmarshalList[Subcomponent](aux.Subcomponents, "subcomponents")
+		if err != nil {
+			return err
+		}
+		p.Subcomponents = subcomponents
+	}
+	return p.Component.unmarshalJSON(data, productFields)
+}
+
+// SetExtension sets the extension property name of the document to the JSON
+// encoding of value. The name must start with ExtensionPrefix.
+func (vexDoc *VEX) SetExtension(name string, value any) error {
+	return setExtension(&vexDoc.RawExtensions, name, value)
+}
+
+// Extension decodes the extension property name of the document into the
+// value pointed to by v. It returns false if the document does not have the
+// property.
+func (vexDoc *VEX) Extension(name string, v any) (bool, error) {
+	return getExtension(vexDoc.RawExtensions, name, v)
+}
+
+// Extensions returns the sorted names of the extension properties of the
+// document.
+func (vexDoc *VEX) Extensions() []string {
+	return extensionNames(vexDoc.RawExtensions)
+}
+
+// DeleteExtension removes the extension property name from the document.
+func (vexDoc *VEX) DeleteExtension(name string) {
+	delete(vexDoc.RawExtensions, name)
+}
+
+// SetExtension sets the extension property name of the statement to the
+// JSON encoding of value. The name must start with ExtensionPrefix.
+func (stmt *Statement) SetExtension(name string, value any) error {
+	return setExtension(&stmt.RawExtensions, name, value)
+}
+
+// Extension decodes the extension property name of the statement into the
+// value pointed to by v. It returns false if the statement does not have the
+// property.
+func (stmt *Statement) Extension(name string, v any) (bool, error) {
+	return getExtension(stmt.RawExtensions, name, v)
+}
+
+// Extensions returns the sorted names of the extension properties of the
+// statement.
+func (stmt *Statement) Extensions() []string {
+	return extensionNames(stmt.RawExtensions)
+}
+
+// DeleteExtension removes the extension property name from the statement.
+func (stmt *Statement) DeleteExtension(name string) {
+	delete(stmt.RawExtensions, name)
+}
+
+func setExtension(fields *map[string]json.RawMessage, name string, value any) error {
+	if !strings.HasPrefix(name, ExtensionPrefix) || name == ExtensionPrefix {
+		return fmt.Errorf("extension name %q must start with %q", name, ExtensionPrefix)
+	}
+	data, err := marshalJSON(value)
+	if err != nil {
+		return fmt.Errorf("encoding extension %s: %w", name, err)
+	}
+	if *fields == nil {
+		*fields = map[string]json.RawMessage{}
+	}
+	(*fields)[name] = data
+	return nil
+}
+
+func getExtension(fields map[string]json.RawMessage, name string, v any) (bool, error) {
+	data, ok := fields[name]
+	if !ok || !strings.HasPrefix(name, ExtensionPrefix) {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return true, fmt.Errorf("decoding extension %s: %w", name, err)
+	}
+	return true, nil
+}
+
+func extensionNames(fields map[string]json.RawMessage) []string {
+	names := []string{}
+	for name := range fields {
+		if strings.HasPrefix(name, ExtensionPrefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cloneRawExtensions returns a deep copy of the extension fields.
+func cloneRawExtensions(fields map[string]json.RawMessage) map[string]json.RawMessage {
+	if fields == nil {
+		return nil
+	}
+	ret := make(map[string]json.RawMessage, len(fields))
+	for name, value := range fields {
+		ret[name] = bytes.Clone(value)
+	}
+	return ret
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const extensionsDoc = `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex-1",
+  "author": "John Doe",
+  "timestamp": "2023-01-08T18:02:03Z",
+  "version": 1,
+  "statements": [
+    {
+      "vulnerability": {"name": "CVE-2023-1234"},
+      "products": [
+        {
+          "@id": "pkg:oci/app?arch=amd64&os=linux",
+          "build": {"id": 42},
+          "subcomponents": [{"@id": "pkg:golang/a@v1.0.0", "scope": "runtime"}]
+        }
+      ],
+      "status": "not_affected",
+      "justification": "component_not_present",
+      "ticket": "SEC-1234"
+    }
+  ],
+  "x-approved": true
+}`
+
+func TestRawExtensions(t *testing.T) {
+	doc, err := Parse([]byte(extensionsDoc))
+	require.NoError(t, err)
+
+	require.Equal(t, json.RawMessage(`true`), doc.RawExtensions["x-approved"])
+	require.Len(t, doc.RawExtensions, 1)
+	stmt := doc.Statements[0]
+	require.Equal(t, json.RawMessage(`"SEC-1234"`), stmt.RawExtensions["ticket"])
+	require.Len(t, stmt.RawExtensions, 1)
+	require.Equal(t, json.RawMessage(`{"id": 42}`), stmt.Products[0].RawExtensions["build"])
+	require.Len(t, stmt.Products[0].RawExtensions, 1)
+	require.Equal(t, json.RawMessage(`"runtime"`), stmt.Products[0].Subcomponents[0].RawExtensions["scope"])
+	require.Nil(t, stmt.Vulnerability.Aliases)
+
+	// Unknown fields are written back
+	var buf bytes.Buffer
+	require.NoError(t, doc.ToJSON(&buf))
+	require.JSONEq(t, extensionsDoc, buf.String())
+	require.Contains(t, buf.String(), "arch=amd64&os=linux")
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, extensionsDoc, string(data))
+
+	// Copies get their own map
+	clone := doc.Clone()
+	clone.RawExtensions["x-approved"] = json.RawMessage(`false`)
+	clone.Statements[0].Products[0].RawExtensions["build"] = json.RawMessage(`{}`)
+	require.Equal(t, json.RawMessage(`true`), doc.RawExtensions["x-approved"])
+	require.Equal(t, json.RawMessage(`{"id": 42}`), doc.Statements[0].Products[0].RawExtensions["build"])
+}
+
+func TestRawExtensionsMarshal(t *testing.T) {
+	// Known fields take precedence over extensions
+	c := Component{
+		ID: "pkg:golang/a@v1.0.0",
+		RawExtensions: map[string]json.RawMessage{
+			"@id":    json.RawMessage(`"other"`),
+			"z-note": json.RawMessage(`"note"`),
+			"a-note": json.RawMessage(`1`),
+		},
+	}
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.Equal(t, `{"@id":"pkg:golang/a@v1.0.0","a-note":1,"z-note":"note"}`, string(data))
+
+	data, err = json.Marshal(Component{RawExtensions: map[string]json.RawMessage{"a": json.RawMessage(`1`)}})
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1}`, string(data))
+
+	_, err = json.Marshal(Component{RawExtensions: map[string]json.RawMessage{"a": json.RawMessage(`{`)}})
+	require.Error(t, err)
+
+	// Products keep their subcomponents
+	data, err = json.Marshal(Product{
+		Component:     Component{ID: "pkg:oci/app"},
+		Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/a@v1.0.0"}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"@id":"pkg:oci/app","subcomponents":[{"@id":"pkg:golang/a@v1.0.0"}]}`, string(data))
+}
+
+func TestParseStrictUnknownFields(t *testing.T) {
+	_, err := ParseStrict([]byte(extensionsDoc))
+	require.Error(t, err)
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "/statements/0/products/0/build", verr.Path)
+
+	_, err = ParseStrict([]byte(`{"statements": [{"vulnerability": {"name": "CVE-2023-1234", "nmae": "x"}}]}`))
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "/statements/0/vulnerability/nmae", verr.Path)
+}
+
+func TestUnmarshalTypeErrorPath(t *testing.T) {
+	_, err := Parse([]byte(`{"statements": [{}, {"products": [{"subcomponents": [{"hashes": []}]}]}]}`))
+	require.Error(t, err)
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "/statements/1/products/0/subcomponents/0/hashes", verr.Path)
+}
+
+func TestExtensions(t *testing.T) {
+	type approval struct {
+		By     string `json:"by"`
+		Ticket string `json:"ticket"`
+	}
+
+	doc := New(WithID("https://example.com/vex-1"))
+	require.NoError(t, doc.SetExtension("x-approval", approval{By: "secteam", Ticket: "SEC-1234"}))
+	require.Error(t, doc.SetExtension("approval", true))
+	require.Error(t, doc.SetExtension("x-", true))
+
+	var got approval
+	ok, err := doc.Extension("x-approval", &got)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, approval{By: "secteam", Ticket: "SEC-1234"}, got)
+
+	ok, err = doc.Extension("x-missing", &got)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	var wrongType int
+	_, err = doc.Extension("x-approval", &wrongType)
+	require.Error(t, err)
+
+	stmt := Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusUnderInvestigation,
+		RawExtensions: map[string]json.RawMessage{"unknown": json.RawMessage(`1`)},
+	}
+	require.NoError(t, stmt.SetExtension("x-ticket", "SEC-1234"))
+	require.NoError(t, stmt.SetExtension("x-priority", 2))
+	require.Equal(t, []string{"x-priority", "x-ticket"}, stmt.Extensions())
+	var ticket string
+	ok, err = stmt.Extension("x-ticket", &ticket)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "SEC-1234", ticket)
+	stmt.DeleteExtension("x-priority")
+	require.Equal(t, []string{"x-ticket"}, stmt.Extensions())
+
+	// Extension properties pass schema validation and the strict parser
+	delete(stmt.RawExtensions, "unknown")
+	doc.Statements = append(doc.Statements, stmt)
+	var buf bytes.Buffer
+	require.NoError(t, doc.ToJSON(&buf))
+	require.NoError(t, Validate(buf.Bytes()))
+	parsed, err := ParseStrict(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, []string{"x-approval"}, parsed.Extensions())
+	require.Equal(t, []string{"x-ticket"}, parsed.Statements[0].Extensions())
+
+	// Other unknown fields are still rejected
+	doc.Statements[0].RawExtensions["ticket"] = json.RawMessage(`"SEC-1234"`)
+	buf.Reset()
+	require.NoError(t, doc.ToJSON(&buf))
+	require.Error(t, Validate(buf.Bytes()))
+	_, err = ParseStrict(buf.Bytes())
+	require.Error(t, err)
+}
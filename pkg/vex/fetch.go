@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxFetchSize limits the size of the documents fetched
+const maxFetchSize = 16 << 20
+
+// Fetch downloads a VEX document from a URL. See FetchContext.
+func Fetch(url string) (*VEX, error) {
+	return FetchContext(context.Background(), url)
+}
+
+// FetchContext downloads a VEX document from a URL using the default HTTP
+// client. The document may be in any of the formats read by ParseAny. The
+// request is aborted when the context is done.
+func FetchContext(ctx context.Context, url string) (*VEX, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: server returned %s", url, res.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxFetchSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", url, err)
+	}
+	if len(data) > maxFetchSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, maxFetchSize)
+	}
+	return ParseAny(data)
+}
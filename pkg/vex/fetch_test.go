@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchContext(t *testing.T) {
+	data, err := os.ReadFile("testdata/v0.2.0.json")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vex.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	doc, err := Fetch(srv.URL + "/vex.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, doc.Statements)
+
+	_, err = FetchContext(context.Background(), srv.URL+"/missing.json")
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = FetchContext(ctx, srv.URL+"/vex.json")
+	require.ErrorIs(t, err, context.Canceled)
+}
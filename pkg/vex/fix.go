@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// Fix records where a vulnerability was fixed in a statement with the fixed
+// status, so consumers don't have to dig the versions out of the status
+// notes. The links to the fix itself, such as commits or release notes, are
+// the statement references of type ReferenceFix.
+type Fix struct {
+	// Versions lists the versions of the products including the fix
+	Versions []string `json:"versions,omitempty"`
+
+	// Products lists the identifiers, such as purls, of the fixed releases
+	Products []string `json:"products,omitempty"`
+}
+
+// Validate checks that the fix lists some version or product and that the
+// products identified by purls have valid ones.
+func (f *Fix) Validate() error {
+	if len(f.Versions) == 0 && len(f.Products) == 0 {
+		return &ValidationError{Err: fmt.Errorf("fix must list fixed versions or products")}
+	}
+	for i, v := range f.Versions {
+		if strings.TrimSpace(v) == "" {
+			return &ValidationError{Path: fmt.Sprintf("/versions/%d", i), Err: fmt.Errorf("empty fixed version")}
+		}
+	}
+	for i, p := range f.Products {
+		if strings.TrimSpace(p) == "" {
+			return &ValidationError{Path: fmt.Sprintf("/products/%d", i), Err: fmt.Errorf("empty fixed product")}
+		}
+		if strings.HasPrefix(p, "pkg:") {
+			if _, err := packageurl.FromString(p); err != nil {
+				return &ValidationError{Path: fmt.Sprintf("/products/%d", i), Err: fmt.Errorf("%w %q: %w", ErrMalformedPurl, p, err)}
+			}
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the fix.
+func (f *Fix) Clone() *Fix {
+	return &Fix{
+		Versions: slices.Clone(f.Versions),
+		Products: slices.Clone(f.Products),
+	}
+}
+
+// merge adds the versions and products of other that f does not have.
+func (f *Fix) merge(other *Fix) {
+	for _, v := range other.Versions {
+		if !slices.Contains(f.Versions, v) {
+			f.Versions = append(f.Versions, v)
+		}
+	}
+	for _, p := range other.Products {
+		if !slices.Contains(f.Products, p) {
+			f.Products = append(f.Products, p)
+		}
+	}
+}
+
+// FixedVersions returns the versions in which the effective statement about
+// the vulnerability in the product says it was fixed. It returns nil if the
+// vulnerability is not fixed in the product or the versions are not known.
+func (vexDoc *VEX) FixedVersions(product, vulnID string) []string {
+	s := NewIndex(vexDoc).EffectiveStatement(product, vulnID)
+	if s == nil || s.Status != StatusFixed || s.Fix == nil {
+		return nil
+	}
+	return slices.Clone(s.Fix.Versions)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFix(t *testing.T) {
+	doc := New(WithID("https://example.com/vex/1"))
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:golang/example.com/lib"}}},
+			Status:        StatusFixed,
+			Fix: &Fix{
+				Versions: []string{"v1.2.3", "v1.1.9"},
+				Products: []string{"pkg:golang/example.com/lib@v1.2.3"},
+			},
+			References: []Reference{{URL: "https://github.com/example/lib/commit/abc", Type: ReferenceFix}},
+		},
+	}
+	require.NoError(t, doc.Statements[0].Validate())
+	require.Equal(t, []string{"v1.2.3", "v1.1.9"}, doc.FixedVersions("pkg:golang/example.com/lib@v1.0.0", "CVE-2023-1111"))
+	require.Nil(t, doc.FixedVersions("pkg:golang/example.com/lib", "CVE-2023-2222"))
+
+	data, err := json.Marshal(&doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	require.Equal(t, doc.Statements[0].Fix, parsed.Statements[0].Fix)
+
+	clone := doc.Statements[0].Clone()
+	clone.Fix.Versions[0] = "v9"
+	require.Equal(t, "v1.2.3", doc.Statements[0].Fix.Versions[0])
+
+	for _, tc := range []struct {
+		fix  Fix
+		path string
+	}{
+		{Fix{}, "/fix"},
+		{Fix{Versions: []string{""}}, "/fix/versions/0"},
+		{Fix{Products: []string{"pkg:golang/example.com/lib@v1.2.3", "pkg:"}}, "/fix/products/1"},
+	} {
+		stmt := doc.Statements[0].Clone()
+		stmt.Fix = &tc.fix
+		var verr *ValidationError
+		require.ErrorAs(t, stmt.Validate(), &verr)
+		require.Equal(t, tc.path, verr.Path)
+	}
+
+	// Only fixed statements have a fix
+	stmt := doc.Statements[0].Clone()
+	stmt.Status = StatusUnderInvestigation
+	require.Error(t, stmt.Validate())
+}
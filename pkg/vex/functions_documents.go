@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 type MergeOptions struct {
@@ -118,6 +119,10 @@ func MergeDocumentsWithOptions(mergeOpts *MergeOptions, docs []*VEX) (*VEX, erro
 	SortStatements(ss, *newDoc.Metadata.Timestamp)
 
 	newDoc.Statements = ss
+	if changelog := mergeChangelogs(docs); changelog != nil {
+		newDoc.Changelog = changelog
+		newDoc.RecordChange(ChangeMerge, fmt.Sprintf("Merged %d statements from %d documents", len(ss), len(docs)))
+	}
 
 	return &newDoc, nil
 }
@@ -137,3 +142,74 @@ func SortDocuments(docs []*VEX) []*VEX {
 	})
 	return docs
 }
+
+// FilterOptions selects the statements kept when deriving a document with
+// Filter. Empty fields don't filter.
+type FilterOptions struct {
+	Products        []string  // Product IDs to keep
+	Vulnerabilities []string  // IDs of vulnerabilities to keep
+	Statuses        []Status  // Statuses to keep
+	Since           time.Time // Keep statements issued at or after this time
+	Until           time.Time // Keep statements issued at or before this time
+}
+
+// Filter returns a new document holding the statements that match the
+// filter options, eg to share the statements about a product with a
+// customer. The new document keeps the author data of the original one but
+// gets a new timestamp, version and ID generated from its contents.
+// Statements without a timestamp inherit the one of the original document so
+// their place in the VEX history is preserved. The statements are deep copies,
+// the original document is never modified.
+func (vexDoc *VEX) Filter(opts FilterOptions) *VEX {
+	q := vexDoc.Query()
+	if len(opts.Products) > 0 {
+		q.Where(func(s *Statement) bool {
+			for _, p := range opts.Products {
+				if s.MatchesProduct(p, "") {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if len(opts.Vulnerabilities) > 0 {
+		q.Where(func(s *Statement) bool {
+			for _, id := range opts.Vulnerabilities {
+				if s.Vulnerability.Matches(id) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if len(opts.Statuses) > 0 {
+		q.Status(opts.Statuses...)
+	}
+	if !opts.Since.IsZero() {
+		q.Since(opts.Since)
+	}
+	if !opts.Until.IsZero() {
+		q.Until(opts.Until)
+	}
+
+	newDoc := New()
+	newDoc.Author = vexDoc.Author
+	newDoc.AuthorRole = vexDoc.AuthorRole
+	newDoc.Tooling = vexDoc.Tooling
+	newDoc.Supplier = vexDoc.Supplier
+
+	matches := q.Statements()
+	for i := range matches {
+		s := matches[i].Clone()
+		if s.Timestamp == nil && vexDoc.Timestamp != nil {
+			ts := *vexDoc.Timestamp
+			s.Timestamp = &ts
+		}
+		newDoc.Statements = append(newDoc.Statements, s)
+	}
+
+	// The canonical ID can only fail to generate on documents without a
+	// timestamp and New always sets one.
+	newDoc.GenerateCanonicalID() //nolint:errcheck
+	return &newDoc
+}
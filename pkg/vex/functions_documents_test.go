@@ -6,7 +6,9 @@ SPDX-License-Identifier: Apache-2.0
 package vex
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -95,3 +97,70 @@ func TestMergeDocumentsWithOptions(t *testing.T) {
 		require.Equal(t, doc.Statements, tc.expectedDoc.Statements)
 	}
 }
+
+func TestFilter(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	doc := New()
+	doc.ID = "https://example.com/vex/all"
+	doc.Author = "Example Inc."
+	doc.Timestamp = &t1
+	doc.Version = 7
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app-a"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		},
+		{
+			Vulnerability:   Vulnerability{Name: "CVE-2023-2222"},
+			Products:        []Product{{Component: Component{ID: "pkg:oci/app-a"}}},
+			Status:          StatusAffected,
+			ActionStatement: "Upgrade",
+			Timestamp:       &t2,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app-b"}}},
+			Status:        StatusFixed,
+		},
+	}
+
+	filtered := doc.Filter(FilterOptions{Products: []string{"pkg:oci/app-a"}})
+	require.Len(t, filtered.Statements, 2)
+	require.Equal(t, "Example Inc.", filtered.Author)
+	require.Equal(t, 1, filtered.Version)
+	require.NotEqual(t, doc.ID, filtered.ID)
+	require.NotEmpty(t, filtered.ID)
+
+	// Timestamps are cascaded from the original document
+	require.Equal(t, t1, *filtered.Statements[0].Timestamp)
+	require.Nil(t, doc.Statements[0].Timestamp)
+
+	// Statements are copies of the original ones
+	filtered.Statements[1].Products[0].ID = "pkg:oci/changed"
+	require.Equal(t, "pkg:oci/app-a", doc.Statements[1].Products[0].ID)
+
+	filtered = doc.Filter(FilterOptions{
+		Vulnerabilities: []string{"CVE-2023-1111"},
+		Statuses:        []Status{StatusFixed, StatusAffected},
+	})
+	require.Len(t, filtered.Statements, 1)
+	require.Equal(t, StatusFixed, filtered.Statements[0].Status)
+
+	filtered = doc.Filter(FilterOptions{Since: t2})
+	require.Len(t, filtered.Statements, 1)
+	require.Equal(t, "CVE-2023-2222", string(filtered.Statements[0].Vulnerability.Name))
+
+	filtered = doc.Filter(FilterOptions{Until: t1})
+	require.Len(t, filtered.Statements, 2)
+
+	// Empty options keep every statement
+	require.Len(t, doc.Filter(FilterOptions{}).Statements, 3)
+
+	// The derived document is valid
+	data, err := json.Marshal(filtered)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+}
@@ -7,10 +7,14 @@ package vex
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
@@ -22,7 +26,13 @@ import (
 // object. If Load is unable to read the file or decode the document, it returns
 // an error.
 func Load(path string) (*VEX, error) {
-	data, err := os.ReadFile(path)
+	return LoadContext(context.Background(), path)
+}
+
+// LoadContext is Load with a context. Reading the file stops when the
+// context is done.
+func LoadContext(ctx context.Context, path string) (*VEX, error) {
+	data, err := readFileContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("loading VEX file: %w", err)
 	}
@@ -30,18 +40,187 @@ func Load(path string) (*VEX, error) {
 	return Parse(data)
 }
 
+// contextReader is a reader that fails once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// readFileContext reads a file, returning the context error if it is done
+// before the whole file is read.
+func readFileContext(ctx context.Context, path string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(&contextReader{ctx: ctx, r: f})
+}
+
 // Parse parses an OpenVEX document in the latest version from the data byte array.
 func Parse(data []byte) (*VEX, error) {
 	vexDoc := &VEX{}
 	if err := json.Unmarshal(data, vexDoc); err != nil {
-		return nil, fmt.Errorf("%s: %w", errMsgParse, err)
+		return nil, fmt.Errorf("%s: %w", errMsgParse, validationErrorFromJSON(err))
+	}
+	return vexDoc, nil
+}
+
+// ParseReader parses an OpenVEX document in the latest version like Parse,
+// decoding it as it is read from r.
+func ParseReader(r io.Reader) (*VEX, error) {
+	return ParseReaderWithOptions(&ParseOptions{}, r)
+}
+
+// ParseReaderWithOptions parses an OpenVEX document read from r performing
+// the checks specified in the options, see ParseWithOptions. Strict parsing
+// and schema validation need the raw document, so when enabled the data is
+// read in full before parsing it.
+func ParseReaderWithOptions(opts *ParseOptions, r io.Reader) (*VEX, error) {
+	if opts.Strict || opts.ValidateSchema {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading document: %w", err)
+		}
+		return ParseWithOptions(opts, data)
+	}
+
+	vexDoc := &VEX{}
+	if err := json.NewDecoder(r).Decode(vexDoc); err != nil {
+		return nil, fmt.Errorf("%s: %w", errMsgParse, validationErrorFromJSON(err))
+	}
+	if err := opts.checkDocument(vexDoc, vexDoc.Context); err != nil {
+		return nil, err
 	}
 	return vexDoc, nil
 }
 
+// ParseOptions controls the checks performed when parsing a document. The
+// zero value parses leniently, which is what Parse does.
+type ParseOptions struct {
+	// Strict makes the parser fail on fields unknown to the spec, values of
+	// the wrong type or trailing data after the document.
+	Strict bool
+
+	// EnforceJustification makes the parser reject statements with a
+	// not_affected status that do not have a justification or impact
+	// statement, as required by the spec. Leave it off when ingesting third
+	// party data that may not be fully compliant.
+	EnforceJustification bool
+
+	// ValidatePurls makes the parser check all product and subcomponent
+	// purls. If any is malformed, an *InvalidPurlsError is returned.
+	ValidatePurls bool
+
+	// AllowedContexts restricts the accepted @context values. Documents with
+	// a context not in the list are rejected. Values are compared as is, so
+	// list every spelling of a locator to be accepted.
+	AllowedContexts []string
+
+	// PinnedContexts maps context locators to verified local copies of the
+	// context definitions. When set, documents are only accepted if their
+	// context is pinned and its local copy matches the pinned digest. The
+	// parser never dereferences the context locators.
+	PinnedContexts map[string]ContextPin
+
+	// ValidateSchema makes the parser check the data against the validation
+	// profile of the spec version declared in the document context, see
+	// ValidateVersion, before decoding it.
+	ValidateSchema bool
+
+	// OnWarning is called with the issues found in documents which don't
+	// prevent reading them, such as the use of deprecated spec versions or
+	// fields. Use it to tell authors what to modernize.
+	OnWarning func(Warning)
+}
+
+// ParseWithOptions parses an OpenVEX document in the latest version from the
+// data byte array performing the checks specified in the options. Errors
+// returned wrap a *ValidationError pointing to the offending field.
+func ParseWithOptions(opts *ParseOptions, data []byte) (*VEX, error) {
+	if opts.ValidateSchema {
+		if err := ValidateDeclaredVersion(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", errMsgParse, err)
+		}
+	}
+
+	vexDoc := &VEX{}
+	if opts.Strict {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(vexDoc); err != nil {
+			return nil, fmt.Errorf("%s: %w", errMsgParse, validationErrorFromJSON(err))
+		}
+		if dec.More() {
+			return nil, fmt.Errorf("%s: %w", errMsgParse, &ValidationError{Err: errors.New("unexpected data after the document")})
+		}
+		if err := checkUnknownFields(data, reflect.TypeOf(VEX{}), ""); err != nil {
+			return nil, fmt.Errorf("%s: %w", errMsgParse, err)
+		}
+	} else if err := json.Unmarshal(data, vexDoc); err != nil {
+		return nil, fmt.Errorf("%s: %w", errMsgParse, validationErrorFromJSON(err))
+	}
+
+	if err := opts.checkDocument(vexDoc, vexDoc.Context); err != nil {
+		return nil, err
+	}
+	return vexDoc, nil
+}
+
+// checkDocument performs the checks of the options on a decoded document and
+// reports its warnings. The context locator is passed as read from the data,
+// as documents of older spec versions are upconverted.
+func (opts *ParseOptions) checkDocument(vexDoc *VEX, locator string) error {
+	if err := opts.checkContext(locator); err != nil {
+		return fmt.Errorf("%s: %w", errMsgParse, err)
+	}
+
+	if opts.EnforceJustification {
+		for i := range vexDoc.Statements {
+			if err := vexDoc.Statements[i].ValidateJustification(); err != nil {
+				return fmt.Errorf("%s: %w", errMsgParse, withPathPrefix(fmt.Sprintf("/statements/%d", i), err))
+			}
+		}
+	}
+
+	if opts.ValidatePurls {
+		if err := vexDoc.ValidatePurls(); err != nil {
+			return fmt.Errorf("%s: %w", errMsgParse, err)
+		}
+	}
+
+	opts.contextWarnings(locator)
+	opts.documentWarnings(vexDoc)
+	return nil
+}
+
+// ParseStrict parses an OpenVEX document in the latest version like Parse but
+// fails if the data contains fields unknown to the spec, values of the wrong
+// type or trailing data after the document. Use it to catch typos that would
+// otherwise be silently dropped.
+func ParseStrict(data []byte) (*VEX, error) {
+	return ParseWithOptions(&ParseOptions{Strict: true}, data)
+}
+
 // OpenYAML opens a VEX file in YAML format.
 func OpenYAML(path string) (*VEX, error) {
-	data, err := os.ReadFile(path)
+	return OpenYAMLContext(context.Background(), path)
+}
+
+// OpenYAMLContext is OpenYAML with a context. Reading the file stops when the
+// context is done.
+func OpenYAMLContext(ctx context.Context, path string) (*VEX, error) {
+	data, err := readFileContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("opening YAML file: %w", err)
 	}
@@ -54,7 +233,13 @@ func OpenYAML(path string) (*VEX, error) {
 
 // OpenJSON opens an OpenVEX file in JSON format.
 func OpenJSON(path string) (*VEX, error) {
-	data, err := os.ReadFile(path)
+	return OpenJSONContext(context.Background(), path)
+}
+
+// OpenJSONContext is OpenJSON with a context. Reading the file stops when the
+// context is done.
+func OpenJSONContext(ctx context.Context, path string) (*VEX, error) {
+	data, err := readFileContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("opening JSON file: %w", err)
 	}
@@ -75,7 +260,8 @@ func parseContext(rawDoc []byte) (string, error) {
 		return "", fmt.Errorf("parsing context from json data: %w", err)
 	}
 
-	if strings.HasPrefix(pd.Context, Context) {
+	if strings.HasPrefix(pd.Context, Context) ||
+		strings.HasPrefix(pd.Context, "http://"+strings.TrimPrefix(Context, "https://")) {
 		return pd.Context, nil
 	}
 	return "", nil
@@ -83,7 +269,13 @@ func parseContext(rawDoc []byte) (string, error) {
 
 // Open tries to autodetect the vex format and open it
 func Open(path string) (*VEX, error) {
-	data, err := os.ReadFile(path)
+	return OpenContext(context.Background(), path)
+}
+
+// OpenContext is Open with a context. Reading the file stops when the context
+// is done.
+func OpenContext(ctx context.Context, path string) (*VEX, error) {
+	data, err := readFileContext(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("opening VEX file: %w", err)
 	}
@@ -93,34 +285,14 @@ func Open(path string) (*VEX, error) {
 		return nil, err
 	}
 
-	if documentContextLocator == ContextLocator() {
-		return Parse(data)
-	} else if documentContextLocator != "" {
-		version := strings.TrimPrefix(documentContextLocator, Context)
-		version = strings.TrimPrefix(version, "/")
-
-		// If version is nil, then we assume v0.0.1
-		if version == "" {
-			version = "v0.0.1"
-		}
-
-		parser := getLegacyVersionParser(version)
-		if parser == nil {
-			return nil, fmt.Errorf("unable to get parser for version %s", version)
-		}
-
-		doc, err := parser(data)
-		if err != nil {
-			return nil, fmt.Errorf("parsing document: %w", err)
-		}
-
-		return doc, nil
+	if documentContextLocator != "" {
+		return ParseCompat(data)
 	}
 
 	if bytes.Contains(data, []byte(`"csaf_version"`)) {
 		slog.Info("Abriendo CSAF")
 
-		doc, err := OpenCSAF(path, []string{})
+		doc, err := parseCSAF(data, []string{})
 		if err != nil {
 			return nil, fmt.Errorf("attempting to open csaf doc: %w", err)
 		}
@@ -130,9 +302,58 @@ func Open(path string) (*VEX, error) {
 	return nil, fmt.Errorf("unable to detect document format reading %s", path)
 }
 
+// ParseAny parses a VEX document in any of the supported formats and returns
+// it as OpenVEX. The format is detected from the data: OpenVEX documents of
+// any spec version are read with ParseCompat, CSAF documents are converted
+// with FromCSAF and CycloneDX documents with FromCycloneDX.
+func ParseAny(data []byte) (*VEX, error) {
+	documentContextLocator, err := parseContext(data)
+	if err != nil {
+		return nil, err
+	}
+	if documentContextLocator != "" {
+		return ParseCompat(data)
+	}
+
+	probe := struct {
+		Document struct {
+			CSAFVersion string `json:"csaf_version"`
+		} `json:"document"`
+		BOMFormat string `json:"bomFormat"`
+	}{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("detecting document format: %w", err)
+	}
+
+	switch {
+	case probe.Document.CSAFVersion != "":
+		return FromCSAF(bytes.NewReader(data))
+	case probe.BOMFormat != "":
+		return FromCycloneDX(bytes.NewReader(data))
+	default:
+		return nil, errors.New("unable to detect document format")
+	}
+}
+
 // OpenCSAF opens a CSAF document and builds a VEX object from it.
 func OpenCSAF(path string, products []string) (*VEX, error) {
-	csafDoc, err := csaf.Open(path)
+	return OpenCSAFContext(context.Background(), path, products)
+}
+
+// OpenCSAFContext is OpenCSAF with a context. Reading the file stops when the
+// context is done.
+func OpenCSAFContext(ctx context.Context, path string, products []string) (*VEX, error) {
+	data, err := readFileContext(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("opening csaf doc: %w", err)
+	}
+	return parseCSAF(data, products)
+}
+
+// parseCSAF builds a VEX object from the data of a CSAF document. If products
+// is not empty, only the statements of the listed products are kept.
+func parseCSAF(data []byte, products []string) (*VEX, error) {
+	csafDoc, err := csaf.Decode(bytes.NewReader(data))
 	if err != nil {
 		return nil, fmt.Errorf("opening csaf doc: %w", err)
 	}
@@ -221,9 +442,15 @@ func OpenCSAF(path string, products []string) (*VEX, error) {
 // MergeFilesWithOptions opens a list of vex documents and after parsing them
 // merges them into a single file using the specified merge options.
 func MergeFilesWithOptions(mergeOpts *MergeOptions, filePaths []string) (*VEX, error) {
+	return MergeFilesContext(context.Background(), mergeOpts, filePaths)
+}
+
+// MergeFilesContext is MergeFilesWithOptions with a context. Opening the
+// files stops when the context is done.
+func MergeFilesContext(ctx context.Context, mergeOpts *MergeOptions, filePaths []string) (*VEX, error) {
 	vexDocs := []*VEX{}
 	for i := range filePaths {
-		doc, err := Open(filePaths[i])
+		doc, err := OpenContext(ctx, filePaths[i])
 		if err != nil {
 			return nil, fmt.Errorf("opening %s: %w", filePaths[i], err)
 		}
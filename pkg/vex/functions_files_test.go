@@ -6,9 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 package vex
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -51,6 +53,106 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseReader(t *testing.T) {
+	data, err := os.ReadFile("testdata/v0.2.0.json")
+	require.NoError(t, err)
+	expected, err := Parse(data)
+	require.NoError(t, err)
+
+	f, err := os.Open("testdata/v0.2.0.json")
+	require.NoError(t, err)
+	defer f.Close()
+	doc, err := ParseReader(f)
+	require.NoError(t, err)
+	require.Equal(t, expected, doc)
+
+	_, err = ParseReader(strings.NewReader(`{"statements": [`))
+	require.Error(t, err)
+
+	_, err = ParseReaderWithOptions(&ParseOptions{Strict: true}, strings.NewReader(`{"unknown": true}`))
+	require.Error(t, err)
+
+	_, err = ParseReaderWithOptions(&ParseOptions{ValidatePurls: true}, strings.NewReader(
+		`{"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "products": [{"@id": "pkg:/golang"}]}]}`,
+	))
+	require.ErrorIs(t, err, ErrMalformedPurl)
+}
+
+func TestParseStrict(t *testing.T) {
+	for m, tc := range map[string]struct {
+		data      string
+		shouldErr bool
+	}{
+		"valid": {
+			data: `{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1", "author": "John Doe",
+			"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "status": "not_affected", "justification": "component_not_present"}]}`,
+		},
+		"unknown field": {
+			data: `{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1", "author": "John Doe",
+			"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "status": "not_affected", "justifcation": "component_not_present"}]}`,
+			shouldErr: true,
+		},
+		"wrong type": {
+			data:      `{"@context": "https://openvex.dev/ns/v0.2.0", "version": "1"}`,
+			shouldErr: true,
+		},
+		"trailing data": {
+			data:      `{"@context": "https://openvex.dev/ns/v0.2.0"}{"@context": "https://openvex.dev/ns/v0.2.0"}`,
+			shouldErr: true,
+		},
+	} {
+		doc, err := ParseStrict([]byte(tc.data))
+		if tc.shouldErr {
+			require.Error(t, err, m)
+			continue
+		}
+		require.NoError(t, err, m)
+		require.Len(t, doc.Statements, 1, m)
+
+		// The lenient parser accepts all documents without trailing data
+		_, err = Parse([]byte(tc.data))
+		require.NoError(t, err, m)
+	}
+}
+
+func TestParseEnforceJustification(t *testing.T) {
+	data := []byte(`{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1", "author": "John Doe",
+	"statements": [
+		{"vulnerability": {"name": "CVE-2023-1234"}, "status": "not_affected", "justification": "component_not_present"},
+		{"vulnerability": {"name": "CVE-2023-1235"}, "status": "not_affected"}
+	]}`)
+
+	// Lenient mode accepts the document
+	doc, err := ParseWithOptions(&ParseOptions{}, data)
+	require.NoError(t, err)
+	require.Len(t, doc.Statements, 2)
+
+	_, err = ParseWithOptions(&ParseOptions{EnforceJustification: true}, data)
+	require.Error(t, err)
+	require.ErrorIs(t, err, ErrMissingJustification)
+}
+
+func TestParseValidatePurls(t *testing.T) {
+	data := []byte(`{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1", "author": "John Doe",
+	"statements": [
+		{"vulnerability": {"name": "CVE-2023-1234"}, "status": "fixed", "products": [
+			{"@id": "pkg:apk/wolfi/bash@1.0.0", "subcomponents": [{"@id": "pkg:/golang"}]},
+			{"@id": "https://example.com/product", "identifiers": {"purl": "pkg:oci/curl?arch=amd64;os=linux"}}
+		]}
+	]}`)
+
+	_, err := ParseWithOptions(&ParseOptions{}, data)
+	require.NoError(t, err)
+
+	_, err = ParseWithOptions(&ParseOptions{ValidatePurls: true}, data)
+	require.Error(t, err)
+	var perr *InvalidPurlsError
+	require.ErrorAs(t, err, &perr)
+	require.Len(t, perr.Purls, 2)
+	require.Equal(t, "/statements/0/products/0/subcomponents/0/@id", perr.Purls[0].Path)
+	require.Equal(t, "/statements/0/products/1/identifiers/purl", perr.Purls[1].Path)
+}
+
 func TestLoadYAML(t *testing.T) {
 	vexDoc, err := OpenYAML("testdata/vex.yaml")
 	require.NoError(t, err)
@@ -104,3 +206,62 @@ func TestOpen(t *testing.T) {
 		require.NotNil(t, doc, m)
 	}
 }
+
+func TestOpenContext(t *testing.T) {
+	doc, err := OpenContext(context.Background(), "testdata/v0.2.0.json")
+	require.NoError(t, err)
+	require.NotNil(t, doc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = OpenContext(ctx, "testdata/v0.2.0.json")
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = LoadContext(ctx, "testdata/v0.2.0.json")
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = MergeFilesContext(ctx, &MergeOptions{}, []string{"testdata/v0.2.0.json"})
+	require.ErrorIs(t, err, context.Canceled)
+
+	for name, open := range map[string]func(context.Context) (*VEX, error){
+		"YAML": func(ctx context.Context) (*VEX, error) { return OpenYAMLContext(ctx, "testdata/vex.yaml") },
+		"JSON": func(ctx context.Context) (*VEX, error) { return OpenJSONContext(ctx, "testdata/v0.2.0.json") },
+		"CSAF": func(ctx context.Context) (*VEX, error) { return OpenCSAFContext(ctx, "testdata/csaf.json", []string{}) },
+	} {
+		doc, err := open(context.Background())
+		require.NoError(t, err, name)
+		require.NotEmpty(t, doc.Statements, name)
+		_, err = open(ctx)
+		require.ErrorIs(t, err, context.Canceled, name)
+	}
+
+	// CSAF documents are read once and converted like OpenCSAF does
+	expected, err := OpenCSAF("testdata/csaf.json", []string{})
+	require.NoError(t, err)
+	doc, err = OpenContext(context.Background(), "testdata/csaf.json")
+	require.NoError(t, err)
+	require.Equal(t, expected, doc)
+}
+
+func TestParseAny(t *testing.T) {
+	for m, tc := range map[string]struct {
+		path       string
+		statements int
+	}{
+		"OpenVEX v0.0.1":   {"testdata/v0.0.1.json", 1},
+		"OpenVEX v0.2.0":   {"testdata/v0.2.0.json", 5},
+		"CSAF document":    {"testdata/csaf-product-tree.json", 3},
+		"CycloneDX VEX":    {"testdata/vex.cdx.json", 3},
+		"CycloneDX no VEX": {"testdata/sbom.cdx.json", 0},
+	} {
+		data, err := os.ReadFile(tc.path)
+		require.NoError(t, err, m)
+		doc, err := ParseAny(data)
+		require.NoError(t, err, m)
+		require.Len(t, doc.Statements, tc.statements, m)
+		require.Equal(t, ContextLocator(), doc.Context, m)
+	}
+
+	_, err := ParseAny([]byte(`{"spdxVersion": "SPDX-2.3"}`))
+	require.Error(t, err)
+	_, err = ParseAny([]byte(`not json`))
+	require.Error(t, err)
+}
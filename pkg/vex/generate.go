@@ -0,0 +1,371 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/cyclonedx"
+	"github.com/openvex/go-vex/pkg/spdx"
+)
+
+// SBOM is the inventory of components Generate cross-references scanner
+// findings with.
+type SBOM struct {
+	// Product is the component the SBOM describes. Its ID is empty if the
+	// SBOM does not say.
+	Product Component
+
+	// Components are the components listed in the SBOM
+	Components []Component
+
+	// Timestamp is when the SBOM was created, if known
+	Timestamp *time.Time
+}
+
+// ReadSBOM reads an SPDX 2.3 or CycloneDX JSON document.
+func ReadSBOM(r io.Reader) (*SBOM, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading SBOM: %w", err)
+	}
+
+	probe := struct {
+		SPDXVersion string `json:"spdxVersion"`
+		BOMFormat   string `json:"bomFormat"`
+	}{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("decoding SBOM: %w", err)
+	}
+
+	switch {
+	case probe.SPDXVersion != "":
+		doc, err := spdx.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return SBOMFromSPDX(doc), nil
+	case probe.BOMFormat != "":
+		doc, err := cyclonedx.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return SBOMFromCycloneDX(doc), nil
+	default:
+		return nil, errors.New("unrecognized SBOM format")
+	}
+}
+
+// SBOMFromSPDX returns the inventory of an SPDX document. The product is
+// the first package the document describes.
+func SBOMFromSPDX(doc *spdx.Document) *SBOM {
+	sbom := &SBOM{Components: []Component{}}
+	if !doc.CreationInfo.Created.IsZero() {
+		created := doc.CreationInfo.Created
+		sbom.Timestamp = &created
+	}
+
+	describes := doc.Describes()
+	for i := range doc.Packages {
+		p := &doc.Packages[i]
+		c := spdxComponent(doc, p, p.ID)
+		for _, cs := range p.Checksums {
+			if c.Hashes == nil {
+				c.Hashes = map[Algorithm]Hash{}
+			}
+			c.Hashes[hashAlgorithm(cs.Algorithm)] = Hash(cs.Value)
+		}
+		if len(describes) > 0 && p.ID == describes[0] {
+			sbom.Product = c
+			continue
+		}
+		sbom.Components = append(sbom.Components, c)
+	}
+	return sbom
+}
+
+// SBOMFromCycloneDX returns the inventory of a CycloneDX document. The
+// product is the metadata component.
+func SBOMFromCycloneDX(doc *cyclonedx.Document) *SBOM {
+	sbom := &SBOM{
+		Components: []Component{},
+		Timestamp:  doc.Metadata.Timestamp,
+	}
+	if doc.Metadata.Component != nil {
+		sbom.Product = cycloneDXComponent(doc, doc.Metadata.Component)
+	}
+	for _, c := range doc.AllComponents() {
+		sbom.Components = append(sbom.Components, cycloneDXComponent(doc, c))
+	}
+	return sbom
+}
+
+// cycloneDXComponent returns the component for a CycloneDX component,
+// identified by its purl or, if it has none, by the document serial number
+// and its bom-ref.
+func cycloneDXComponent(doc *cyclonedx.Document, c *cyclonedx.Component) Component {
+	ret := Component{Identifiers: map[IdentifierType]string{}}
+	if c.PURL != "" {
+		ret.Identifiers[PURL] = c.PURL
+	}
+	if c.CPE != "" {
+		ret.Identifiers[CPE23] = c.CPE
+	}
+	switch {
+	case c.PURL != "":
+		ret.ID = c.PURL
+	case doc.SerialNumber != "" && c.BOMRef != "":
+		ret.ID = doc.SerialNumber + "#" + c.BOMRef
+	case c.BOMRef != "":
+		ret.ID = c.BOMRef
+	default:
+		ret.ID = c.Name
+	}
+	if len(ret.Identifiers) == 0 {
+		ret.Identifiers = nil
+	}
+	for _, h := range c.Hashes {
+		if ret.Hashes == nil {
+			ret.Hashes = map[Algorithm]Hash{}
+		}
+		ret.Hashes[hashAlgorithm(h.Algorithm)] = Hash(h.Content)
+	}
+	return ret
+}
+
+// hashAlgorithm returns the algorithm for the names used in SBOMs, such as
+// SHA256, SHA-256 or SHA3-256.
+func hashAlgorithm(name string) Algorithm {
+	n := strings.ToLower(name)
+	switch {
+	case n == "sha1" || n == "sha-1":
+		return SHA1
+	case strings.HasPrefix(n, "sha") && !strings.HasPrefix(n, "sha-") && !strings.HasPrefix(n, "sha3"):
+		return Algorithm("sha-" + n[3:])
+	}
+	return Algorithm(n)
+}
+
+// GeneratePolicy controls the document created by Generate.
+type GeneratePolicy struct {
+	Author     string
+	AuthorRole string
+
+	// StatusNotes are set in every statement
+	StatusNotes string
+
+	// SkipUnknownComponents drops products and subcomponents not listed in
+	// the SBOM. By default they are included with the identifier reported
+	// by the scanner.
+	SkipUnknownComponents bool
+
+	// Ignore lists vulnerabilities to leave out
+	Ignore []string
+}
+
+// Generate creates an initial document to start triaging the findings of a
+// scanner. It has an under_investigation statement for each vulnerability
+// and product reported, listing the subcomponents of all the findings.
+// Findings without a product are about the product the SBOM describes.
+//
+// Products and subcomponents are looked up in the SBOM by any of their
+// identifiers or hashes so the statements carry all of them.
+func Generate(sbom *SBOM, findings []Finding, policy *GeneratePolicy) (*VEX, error) {
+	if sbom == nil {
+		sbom = &SBOM{}
+	}
+	if policy == nil {
+		policy = &GeneratePolicy{}
+	}
+
+	resolved := map[string]*Component{}
+	resolve := func(id string) *Component {
+		if c, ok := resolved[id]; ok {
+			return c
+		}
+		var ret *Component
+		if sbom.Product.ID != "" && sbom.Product.Matches(id) {
+			ret = &sbom.Product
+		} else {
+			for i := range sbom.Components {
+				if sbom.Components[i].Matches(id) {
+					ret = &sbom.Components[i]
+					break
+				}
+			}
+		}
+		if ret == nil && !policy.SkipUnknownComponents {
+			ret = &Component{ID: id}
+			if strings.HasPrefix(id, "pkg:") {
+				ret.Identifiers = map[IdentifierType]string{PURL: id}
+			}
+		}
+		resolved[id] = ret
+		return ret
+	}
+
+	type key struct {
+		vulnerability string
+		product       *Component
+	}
+	type entry struct {
+		subcomponents []*Component
+		// wholeProduct is set when a finding has no subcomponents
+		wholeProduct bool
+	}
+	entries := map[key]*entry{}
+	order := []key{}
+
+findings:
+	for i, f := range findings {
+		if f.Vulnerability == "" {
+			return nil, fmt.Errorf("finding %d has no vulnerability", i)
+		}
+		for _, id := range policy.Ignore {
+			if f.Vulnerability == id {
+				continue findings
+			}
+		}
+
+		product := &sbom.Product
+		if f.Product != "" {
+			product = resolve(f.Product)
+		}
+		if product == nil {
+			continue
+		}
+		if product.ID == "" {
+			return nil, fmt.Errorf("finding %d has no product and the SBOM does not describe one", i)
+		}
+
+		subcomponents := []*Component{}
+		for _, id := range f.Subcomponents {
+			if c := resolve(id); c != nil && c != product {
+				subcomponents = append(subcomponents, c)
+			}
+		}
+		// Drop findings whose subcomponents are all unknown rather than
+		// turning them into findings about the whole product
+		if len(f.Subcomponents) > 0 && len(subcomponents) == 0 {
+			continue
+		}
+
+		k := key{f.Vulnerability, product}
+		e, ok := entries[k]
+		if !ok {
+			e = &entry{}
+			entries[k] = e
+			order = append(order, k)
+		}
+		if len(f.Subcomponents) == 0 {
+			e.wholeProduct = true
+		}
+		for _, c := range subcomponents {
+			if !slices.Contains(e.subcomponents, c) {
+				e.subcomponents = append(e.subcomponents, c)
+			}
+		}
+	}
+
+	doc := New()
+	if policy.Author != "" {
+		doc.Author = policy.Author
+	}
+	if policy.AuthorRole != "" {
+		doc.AuthorRole = policy.AuthorRole
+	}
+
+	for _, k := range order {
+		e := entries[k]
+		p := Product{Component: *k.product}
+		// A finding about the whole product covers all its subcomponents
+		if !e.wholeProduct {
+			for _, c := range e.subcomponents {
+				p.Subcomponents = append(p.Subcomponents, Subcomponent{Component: *c})
+			}
+		}
+		doc.Statements = append(doc.Statements, Statement{
+			Vulnerability: Vulnerability{Name: VulnerabilityID(k.vulnerability)},
+			Products:      []Product{p},
+			Status:        StatusUnderInvestigation,
+			StatusNotes:   policy.StatusNotes,
+		})
+	}
+
+	if _, err := doc.GenerateCanonicalID(); err != nil {
+		return nil, fmt.Errorf("generating document ID: %w", err)
+	}
+	return &doc, nil
+}
+
+// SkeletonTODO starts the status notes of the statements created by
+// Skeleton until they are triaged.
+const SkeletonTODO = "TODO:"
+
+// Skeleton returns a document to triage a list of open vulnerabilities in
+// a product. It has an under_investigation statement for each vulnerability
+// with status notes starting with SkeletonTODO, which PendingTriage looks
+// for to list the statements left to triage. The product can be a purl or
+// a digest such as sha256:abc...; the options configure the document as in
+// New.
+func Skeleton(product string, vulns []string, opts ...Option) (*VEX, error) {
+	if product == "" {
+		return nil, errors.New("a product identifier is required")
+	}
+	c := Component{ID: product}
+	switch {
+	case strings.HasPrefix(product, "pkg:"):
+		c.Identifiers = map[IdentifierType]string{PURL: product}
+	case strings.Contains(product, ":") && !strings.Contains(product, "/"):
+		algo, digest, _ := strings.Cut(product, ":")
+		c.Hashes = map[Algorithm]Hash{hashAlgorithm(algo): Hash(digest)}
+	}
+
+	doc := New(opts...)
+	seen := map[string]bool{}
+	for _, v := range vulns {
+		if v == "" {
+			return nil, errors.New("vulnerability identifiers can't be empty")
+		}
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		doc.Statements = append(doc.Statements, Statement{
+			Vulnerability: Vulnerability{Name: VulnerabilityID(v)},
+			Products:      []Product{{Component: c.Clone()}},
+			Status:        StatusUnderInvestigation,
+			StatusNotes: fmt.Sprintf(
+				"%s assess the impact of %s on %s and set the status, justification and statements",
+				SkeletonTODO, v, product,
+			),
+		})
+	}
+
+	if _, err := doc.GenerateCanonicalID(); err != nil {
+		return nil, fmt.Errorf("generating document ID: %w", err)
+	}
+	return &doc, nil
+}
+
+// PendingTriage returns the statements whose status notes still start with
+// SkeletonTODO.
+func (vexDoc *VEX) PendingTriage() []Statement {
+	ret := []Statement{}
+	for i := range vexDoc.Statements {
+		if strings.HasPrefix(vexDoc.Statements[i].StatusNotes, SkeletonTODO) {
+			ret = append(ret, vexDoc.Statements[i])
+		}
+	}
+	return ret
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSBOM(t *testing.T) {
+	f, err := os.Open("testdata/sbom.cdx.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	sbom, err := ReadSBOM(f)
+	require.NoError(t, err)
+	require.Equal(t, "pkg:oci/example-image@sha256%3Aabcdef", sbom.Product.ID)
+	require.NotNil(t, sbom.Timestamp)
+	require.Len(t, sbom.Components, 3)
+	require.Equal(t, Hash("a1b2c3"), sbom.Components[0].Hashes[SHA256])
+	require.Equal(t, "pkg:apk/wolfi/libcrypto3@3.0.8", sbom.Components[1].ID)
+	require.Equal(t, "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79#vendored", sbom.Components[2].ID)
+
+	f2, err := os.Open("testdata/spdx-annotations.spdx.json")
+	require.NoError(t, err)
+	defer f2.Close()
+	sbom, err = ReadSBOM(f2)
+	require.NoError(t, err)
+	require.NotEmpty(t, sbom.Components)
+
+	_, err = ReadSBOM(strings.NewReader(`{"hello": "world"}`))
+	require.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	f, err := os.Open("testdata/sbom.cdx.json")
+	require.NoError(t, err)
+	defer f.Close()
+	sbom, err := ReadSBOM(f)
+	require.NoError(t, err)
+
+	findings := []Finding{
+		{Vulnerability: "CVE-2023-1111", Subcomponents: []string{"pkg:apk/wolfi/openssl@3.0.8"}},
+		{Vulnerability: "CVE-2023-1111", Product: "pkg:oci/example-image@sha256%3Aabcdef", Subcomponents: []string{"a1b2c3", "pkg:apk/wolfi/libcrypto3@3.0.8"}},
+		{Vulnerability: "CVE-2023-2222", Subcomponents: []string{"pkg:golang/example.com/unknown@v1.0.0"}},
+		{Vulnerability: "CVE-2023-3333"},
+		{Vulnerability: "CVE-2023-3333", Subcomponents: []string{"pkg:apk/wolfi/openssl@3.0.8"}},
+		{Vulnerability: "CVE-2023-4444", Subcomponents: []string{"pkg:apk/wolfi/openssl@3.0.8"}},
+		{Vulnerability: "CVE-2023-5555", Product: "pkg:apk/wolfi/openssl@3.0.8"},
+	}
+	doc, err := Generate(sbom, findings, &GeneratePolicy{
+		Author:      "Example Inc.",
+		StatusNotes: "Reported by scanner",
+		Ignore:      []string{"CVE-2023-4444"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Example Inc.", doc.Author)
+	require.NotEmpty(t, doc.ID)
+	require.Len(t, doc.Statements, 4)
+
+	s := doc.Statements[0]
+	require.Equal(t, StatusUnderInvestigation, s.Status)
+	require.Equal(t, "Reported by scanner", s.StatusNotes)
+	require.Equal(t, "pkg:oci/example-image@sha256%3Aabcdef", s.Products[0].ID)
+	require.Len(t, s.Products[0].Subcomponents, 2)
+	require.Equal(t, Hash("a1b2c3"), s.Products[0].Subcomponents[0].Hashes[SHA256])
+
+	// Unknown components are included as reported
+	require.Equal(t, "pkg:golang/example.com/unknown@v1.0.0", doc.Statements[1].Products[0].Subcomponents[0].ID)
+
+	// Findings about the whole product have no subcomponents
+	require.Empty(t, doc.Statements[2].Products[0].Subcomponents)
+
+	// Products are looked up in the SBOM too
+	require.Equal(t, Hash("a1b2c3"), doc.Statements[3].Products[0].Hashes[SHA256])
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+
+	doc, err = Generate(sbom, findings, &GeneratePolicy{SkipUnknownComponents: true})
+	require.NoError(t, err)
+	require.Len(t, doc.Statements, 4)
+	require.Equal(t, VulnerabilityID("CVE-2023-3333"), doc.Statements[1].Vulnerability.Name)
+
+	_, err = Generate(&SBOM{}, []Finding{{Vulnerability: "CVE-2023-1111"}}, nil)
+	require.Error(t, err)
+	_, err = Generate(sbom, []Finding{{Product: "pkg:apk/wolfi/openssl@3.0.8"}}, nil)
+	require.Error(t, err)
+}
+
+func TestSkeleton(t *testing.T) {
+	doc, err := Skeleton(
+		"pkg:oci/app@sha256%3Aabc",
+		[]string{"CVE-2023-2222", "CVE-2023-1111", "CVE-2023-2222"},
+		WithAuthor("Example Inc."),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "Example Inc.", doc.Author)
+	require.NotEmpty(t, doc.ID)
+	require.Len(t, doc.Statements, 2)
+	for _, s := range doc.Statements {
+		require.Equal(t, StatusUnderInvestigation, s.Status)
+		require.True(t, strings.HasPrefix(s.StatusNotes, SkeletonTODO))
+		require.Contains(t, s.StatusNotes, string(s.Vulnerability.Name))
+		require.Equal(t, "pkg:oci/app@sha256%3Aabc", s.Products[0].Identifiers[PURL])
+	}
+
+	require.Len(t, doc.PendingTriage(), 2)
+	doc.Statements[0].Status = StatusNotAffected
+	doc.Statements[0].Justification = ComponentNotPresent
+	doc.Statements[0].StatusNotes = ""
+	require.Len(t, doc.PendingTriage(), 1)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+
+	doc, err = Skeleton("sha256:abc", []string{"CVE-2023-1111"})
+	require.NoError(t, err)
+	require.Equal(t, Hash("abc"), doc.Statements[0].Products[0].Hashes[SHA256])
+
+	_, err = Skeleton("", []string{"CVE-2023-1111"})
+	require.Error(t, err)
+	_, err = Skeleton("pkg:oci/app", []string{""})
+	require.Error(t, err)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/openvex/go-vex/pkg/govulncheck"
+)
+
+// FromGovulncheck reads the JSON output of govulncheck and returns a
+// document with a statement for each vulnerable module found in the
+// product, which identifies the scanned code. Modules are subcomponents
+// identified by their golang purl.
+//
+// Vulnerabilities whose symbols are called are affected. When govulncheck
+// analyzed the code down to the symbols, the rest are not_affected with the
+// vulnerable_code_not_in_execute_path justification. Scans at the package
+// or module level can't tell if the vulnerable code is reachable so their
+// findings are under_investigation.
+func FromGovulncheck(r io.Reader, product string) (*VEX, error) {
+	if product == "" {
+		return nil, fmt.Errorf("a product identifier is required")
+	}
+	report, err := govulncheck.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	scanLevel := govulncheck.ScanLevelSymbol
+	doc := New()
+	if report.Config != nil {
+		if report.Config.ScanLevel != "" {
+			scanLevel = report.Config.ScanLevel
+		}
+		if report.Config.ScannerName != "" {
+			doc.Tooling = report.Config.ScannerName
+			if report.Config.ScannerVersion != "" {
+				doc.Tooling += "@" + report.Config.ScannerVersion
+			}
+		}
+	}
+
+	aliases := map[string][]VulnerabilityID{}
+	for _, entry := range report.OSV {
+		for _, a := range entry.Aliases {
+			aliases[entry.ID] = append(aliases[entry.ID], VulnerabilityID(a))
+		}
+	}
+
+	// Findings are reported at several levels for each vulnerable module,
+	// keep the deepest one.
+	type key struct{ osv, module string }
+	type result struct {
+		version, fixed string
+		level          govulncheck.ScanLevel
+	}
+	results := map[key]*result{}
+	order := []key{}
+	for i := range report.Findings {
+		f := &report.Findings[i]
+		module, version := f.Module()
+		if f.OSV == "" || module == "" {
+			return nil, fmt.Errorf("finding %d does not identify a vulnerable module", i)
+		}
+		k := key{f.OSV, module}
+		res, ok := results[k]
+		if !ok {
+			res = &result{version: version, fixed: f.FixedVersion, level: govulncheck.ScanLevelModule}
+			results[k] = res
+			order = append(order, k)
+		}
+		if level := f.Level(); govulncheckLevelDepth(level) > govulncheckLevelDepth(res.level) {
+			res.level = level
+		}
+	}
+
+	for _, k := range order {
+		res := results[k]
+		stmt := Statement{
+			Vulnerability: Vulnerability{
+				Name:    VulnerabilityID(k.osv),
+				Aliases: aliases[k.osv],
+			},
+			Products: []Product{{
+				Component: Component{ID: product},
+				Subcomponents: []Subcomponent{{
+					Component: govulncheckModuleComponent(k.module, res.version),
+				}},
+			}},
+		}
+
+		switch {
+		case res.level == govulncheck.ScanLevelSymbol:
+			stmt.Status = StatusAffected
+			if res.fixed != "" {
+				stmt.ActionStatement = fmt.Sprintf("Update %s to %s", k.module, res.fixed)
+			} else {
+				stmt.ActionStatement = fmt.Sprintf("No fixed version of %s is available", k.module)
+			}
+		case scanLevel == govulncheck.ScanLevelSymbol:
+			stmt.Status = StatusNotAffected
+			stmt.Justification = VulnerableCodeNotInExecutePath
+			if res.level == govulncheck.ScanLevelPackage {
+				stmt.ImpactStatement = "govulncheck found the vulnerable package is imported but its vulnerable symbols are not called"
+			} else {
+				stmt.ImpactStatement = "govulncheck found the vulnerable module is required but the vulnerable packages are not imported"
+			}
+		default:
+			stmt.Status = StatusUnderInvestigation
+			stmt.StatusNotes = fmt.Sprintf("govulncheck scanned at the %s level and can't tell if the vulnerable symbols are called", scanLevel)
+		}
+		doc.Statements = append(doc.Statements, stmt)
+	}
+
+	if _, err := doc.GenerateCanonicalID(); err != nil {
+		return nil, fmt.Errorf("generating document ID: %w", err)
+	}
+	return &doc, nil
+}
+
+// govulncheckLevelDepth orders the scan levels from the shallowest to the
+// deepest.
+func govulncheckLevelDepth(level govulncheck.ScanLevel) int {
+	switch level {
+	case govulncheck.ScanLevelSymbol:
+		return 2
+	case govulncheck.ScanLevelPackage:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// govulncheckModuleComponent returns the component for a Go module. The
+// standard library is the stdlib module.
+func govulncheckModuleComponent(module, version string) Component {
+	purl := "pkg:golang/" + module
+	if version != "" {
+		purl += "@" + version
+	}
+	return Component{ID: purl, Identifiers: map[IdentifierType]string{PURL: purl}}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromGovulncheck(t *testing.T) {
+	f, err := os.Open("testdata/govulncheck.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	doc, err := FromGovulncheck(f, "pkg:golang/example.com/app@v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "govulncheck@v1.0.1", doc.Tooling)
+	require.NotEmpty(t, doc.ID)
+	require.Len(t, doc.Statements, 3)
+
+	// Called symbols
+	s := doc.Statements[0]
+	require.Equal(t, VulnerabilityID("GO-2023-1111"), s.Vulnerability.Name)
+	require.Equal(t, []VulnerabilityID{"CVE-2023-1111", "GHSA-aaaa-bbbb-cccc"}, s.Vulnerability.Aliases)
+	require.Equal(t, StatusAffected, s.Status)
+	require.Equal(t, "Update golang.org/x/net to v0.17.0", s.ActionStatement)
+	require.Equal(t, "pkg:golang/example.com/app@v1.0.0", s.Products[0].ID)
+	require.Equal(t, "pkg:golang/golang.org/x/net@v0.10.0", s.Products[0].Subcomponents[0].ID)
+
+	// Imported package
+	s = doc.Statements[1]
+	require.Equal(t, StatusNotAffected, s.Status)
+	require.Equal(t, VulnerableCodeNotInExecutePath, s.Justification)
+	require.Equal(t, "pkg:golang/stdlib@v1.21.0", s.Products[0].Subcomponents[0].ID)
+
+	// Required module
+	s = doc.Statements[2]
+	require.Equal(t, StatusNotAffected, s.Status)
+	require.Contains(t, s.ImpactStatement, "not imported")
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+}
+
+func TestFromGovulncheckScanLevel(t *testing.T) {
+	out := `{"config": {"scanner_name": "govulncheck", "scan_level": "package"}}
+{"finding": {"osv": "GO-2023-2222", "trace": [{"module": "stdlib", "version": "v1.21.0", "package": "net/http"}]}}`
+	doc, err := FromGovulncheck(strings.NewReader(out), "pkg:golang/example.com/app")
+	require.NoError(t, err)
+	require.Len(t, doc.Statements, 1)
+	require.Equal(t, StatusUnderInvestigation, doc.Statements[0].Status)
+
+	_, err = FromGovulncheck(strings.NewReader(out), "")
+	require.Error(t, err)
+	_, err = FromGovulncheck(strings.NewReader(`{"finding": {"osv": "GO-2023-2222", "trace": []}}`), "app")
+	require.Error(t, err)
+	_, err = FromGovulncheck(strings.NewReader(`{"finding": `), "app")
+	require.Error(t, err)
+}
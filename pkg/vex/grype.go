@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/package-url/packageurl-go"
+	"gopkg.in/yaml.v3"
+)
+
+// GrypeConfig is the fragment of a grype configuration file holding the
+// ignore rules. It can be written on its own as a .grype.yaml file.
+//
+// https://github.com/anchore/grype#specifying-matches-to-ignore
+type GrypeConfig struct {
+	Ignore []GrypeIgnoreRule `yaml:"ignore"`
+}
+
+// GrypeIgnoreRule instructs grype to drop the matches of a vulnerability
+// in a package.
+type GrypeIgnoreRule struct {
+	Vulnerability    string              `yaml:"vulnerability"`
+	Package          *GrypeIgnorePackage `yaml:"package,omitempty"`
+	VexStatus        string              `yaml:"vex-status,omitempty"`
+	VexJustification string              `yaml:"vex-justification,omitempty"`
+}
+
+// GrypeIgnorePackage scopes an ignore rule to a package. Location is a path
+// glob matched against the location where grype found the package.
+type GrypeIgnorePackage struct {
+	Name     string `yaml:"name,omitempty"`
+	Version  string `yaml:"version,omitempty"`
+	Type     string `yaml:"type,omitempty"`
+	Location string `yaml:"location,omitempty"`
+}
+
+// grypePackageTypes maps purl types to the package types reported by grype
+// when the names differ.
+var grypePackageTypes = map[string]string{
+	packageurl.TypeGolang:   "go-module",
+	packageurl.TypeMaven:    "java-archive",
+	packageurl.TypePyPi:     "python",
+	packageurl.TypeGem:      "gem",
+	packageurl.TypeNPM:      "npm",
+	packageurl.TypeCargo:    "rust-crate",
+	packageurl.TypeComposer: "php-composer",
+	packageurl.TypeNuget:    "dotnet",
+	packageurl.TypeDebian:   "deb",
+	packageurl.TypeRPM:      "rpm",
+	packageurl.TypeApk:      "apk",
+	packageurl.TypeHex:      "hex",
+}
+
+// ToGrypeConfig returns the ignore rules for the vulnerabilities that the
+// document marks as not_affected or fixed in a package. Only the latest
+// statement about each vulnerability and package is considered.
+//
+// Packages are matched by the name, version and type decoded from the purls
+// of the statement subcomponents or, when a product lists no subcomponents,
+// of the product itself. Components without a purl are skipped.
+// Purls don't record where a package is installed, callers can narrow the
+// rules further by setting the package Location.
+func (vexDoc *VEX) ToGrypeConfig() (*GrypeConfig, error) {
+	config := &GrypeConfig{Ignore: []GrypeIgnoreRule{}}
+	for _, s := range vexDoc.packageSuppressions() {
+		p, err := packageurl.FromString(s.purl)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrMalformedPurl, s.purl, err)
+		}
+
+		name := p.Name
+		if p.Namespace != "" && (p.Type == packageurl.TypeGolang || p.Type == packageurl.TypeNPM || p.Type == packageurl.TypeComposer) {
+			name = p.Namespace + "/" + p.Name
+		}
+		pkgType := p.Type
+		if t, ok := grypePackageTypes[p.Type]; ok {
+			pkgType = t
+		}
+		pkg := &GrypeIgnorePackage{
+			Name:    name,
+			Version: p.Version,
+			Type:    pkgType,
+		}
+
+		rule := GrypeIgnoreRule{
+			Vulnerability: s.vulnerability,
+			Package:       pkg,
+			VexStatus:     string(s.statement.Status),
+		}
+		if s.statement.Status == StatusNotAffected {
+			rule.VexJustification = string(s.statement.Justification)
+		}
+		config.Ignore = append(config.Ignore, rule)
+	}
+	return config, nil
+}
+
+// ToYAML writes the ignore rules as a grype configuration file.
+func (config *GrypeConfig) ToYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(config); err != nil {
+		return fmt.Errorf("encoding grype configuration: %w", err)
+	}
+	return enc.Close()
+}
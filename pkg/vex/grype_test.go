@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToGrypeConfig(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.Timestamp = &ts
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products: []Product{{
+				Component:     Component{ID: "pkg:oci/app"},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/example.com/lib@v1.0.0"}}},
+			}},
+			Status:        StatusNotAffected,
+			Justification: VulnerableCodeNotPresent,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:deb/debian/openssl@3.0.8?arch=amd64"}}},
+			Status:        StatusFixed,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-3333"},
+			Products:      []Product{{Component: Component{ID: "pkg:npm/left-pad@1.0.0"}}},
+			Status:        StatusAffected,
+		},
+	}
+
+	config, err := doc.ToGrypeConfig()
+	require.NoError(t, err)
+	require.Equal(t, []GrypeIgnoreRule{
+		{
+			Vulnerability:    "CVE-2023-1111",
+			Package:          &GrypeIgnorePackage{Name: "example.com/lib", Version: "v1.0.0", Type: "go-module"},
+			VexStatus:        "not_affected",
+			VexJustification: "vulnerable_code_not_present",
+		},
+		{
+			Vulnerability: "CVE-2023-2222",
+			Package:       &GrypeIgnorePackage{Name: "openssl", Version: "3.0.8", Type: "deb"},
+			VexStatus:     "fixed",
+		},
+	}, config.Ignore)
+
+	var buf bytes.Buffer
+	require.NoError(t, config.ToYAML(&buf))
+	require.Contains(t, buf.String(), "ignore:\n  - vulnerability: CVE-2023-1111\n    package:\n      name: example.com/lib\n")
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+// History returns the statements about the vulnerability in the product
+// ordered from oldest to newest, following the same rules as
+// EffectiveStatement: the last statement returned is the effective one.
+// Statements without a timestamp get the one of their document so the
+// history can be rendered as a timeline.
+func (vexDoc *VEX) History(vulnID, product string) []Statement {
+	return NewIndex(vexDoc).History(vulnID, product)
+}
+
+// History returns the statements about the vulnerability in the product
+// across all the indexed documents, ordered from oldest to newest. See
+// VEX.History.
+func (idx *Index) History(vulnID, product string) []Statement {
+	entries := idx.matches(vulnID, product, nil)
+	ret := make([]Statement, 0, len(entries))
+	for _, e := range entries {
+		s := *e.statement
+		if (s.Timestamp == nil || s.Timestamp.IsZero()) && !e.time.IsZero() {
+			t := e.time
+			s.Timestamp = &t
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+// StatusTimeline returns the statuses of a history, such as one returned by
+// History, dropping consecutive repetitions. For example a vulnerability
+// investigated, found to affect the product and then fixed has the
+// timeline under_investigation, affected, fixed.
+func StatusTimeline(history []Statement) []Status {
+	ret := []Status{}
+	for i := range history {
+		if len(ret) == 0 || ret[len(ret)-1] != history[i].Status {
+			ret = append(ret, history[i].Status)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+
+	statement := func(status Status, ts *time.Time) Statement {
+		s := Statement{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        status,
+			Timestamp:     ts,
+		}
+		if status == StatusNotAffected {
+			s.Justification = ComponentNotPresent
+		}
+		return s
+	}
+
+	doc := New()
+	doc.Timestamp = &t1
+	doc.Statements = []Statement{
+		statement(StatusFixed, &t3),
+		statement(StatusUnderInvestigation, nil),
+		statement(StatusAffected, &t2),
+		statement(StatusAffected, &t2),
+	}
+
+	history := doc.History("CVE-2023-1111", "pkg:oci/app@sha256%3Aabc")
+	require.Len(t, history, 4)
+	require.Equal(t, t1, *history[0].Timestamp)
+	require.Equal(t, []Status{StatusUnderInvestigation, StatusAffected, StatusFixed}, StatusTimeline(history))
+
+	// The document is not modified
+	require.Nil(t, doc.Statements[1].Timestamp)
+
+	// The last statement is the effective one
+	require.Equal(t, doc.EffectiveStatement("pkg:oci/app", "CVE-2023-1111").Status, history[len(history)-1].Status)
+
+	require.Empty(t, doc.History("CVE-2023-1111", "pkg:oci/other"))
+
+	// Across documents
+	t4 := t3.Add(time.Hour)
+	later := New()
+	later.Timestamp = &t4
+	later.Statements = []Statement{statement(StatusNotAffected, nil)}
+	history = NewIndex(&doc, &later).History("CVE-2023-1111", "pkg:oci/app")
+	require.Len(t, history, 5)
+	require.Equal(t, t4, *history[4].Timestamp)
+	require.Equal(t,
+		[]Status{StatusUnderInvestigation, StatusAffected, StatusFixed, StatusNotAffected},
+		StatusTimeline(history),
+	)
+}
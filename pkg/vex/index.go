@@ -0,0 +1,276 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"strings"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// Index is a lookup structure built once from one or more documents to
+// answer repeated queries without scanning all statements. Statements are
+// keyed by their vulnerability name, IRI and aliases and by the normalized
+// identifiers of their products, so a lookup only checks the few statements
+// that can match.
+//
+// The index keeps pointers to the indexed documents, changes to them after
+// building the index are not reflected in it.
+type Index struct {
+	entries map[string]map[string][]*indexEntry
+}
+
+type indexEntry struct {
+	statement *Statement
+	document  *VEX
+	time      time.Time
+	order     int
+}
+
+// NewIndex builds an index of the statements in the documents.
+func NewIndex(docs ...*VEX) *Index {
+	return NewIndexFunc(nil, docs...)
+}
+
+// NewIndexFunc builds an index of the statements in the documents for which
+// accept returns true, for example those accepted by a Policy. A nil accept
+// function indexes all the statements.
+func NewIndexFunc(accept func(doc *VEX, stmt *Statement) bool, docs ...*VEX) *Index {
+	idx := &Index{entries: map[string]map[string][]*indexEntry{}}
+	order := 0
+	for _, doc := range docs {
+		var docTime time.Time
+		if doc.Timestamp != nil {
+			docTime = *doc.Timestamp
+		}
+		for i := range doc.Statements {
+			s := &doc.Statements[i]
+			if accept != nil && !accept(doc, s) {
+				continue
+			}
+			entry := &indexEntry{statement: s, document: doc, time: docTime, order: order}
+			order++
+			if s.Timestamp != nil && !s.Timestamp.IsZero() {
+				entry.time = *s.Timestamp
+			}
+			for _, vuln := range vulnerabilityKeys(&s.Vulnerability) {
+				for _, product := range statementProductKeys(s) {
+					idx.add(vuln, product, entry)
+				}
+			}
+		}
+	}
+	return idx
+}
+
+func (idx *Index) add(vuln, product string, entry *indexEntry) {
+	products, ok := idx.entries[vuln]
+	if !ok {
+		products = map[string][]*indexEntry{}
+		idx.entries[vuln] = products
+	}
+	// Statements listing a product under several identifiers are only
+	// added once to each bucket.
+	if l := products[product]; len(l) > 0 && l[len(l)-1] == entry {
+		return
+	}
+	products[product] = append(products[product], entry)
+}
+
+// Matches returns the statements about the vulnerability in the product and
+// any of the subcomponents, ordered from oldest to newest.
+func (idx *Index) Matches(vulnID, product string, subcomponents []string) []Statement {
+	entries := idx.matches(vulnID, product, subcomponents)
+	ret := make([]Statement, 0, len(entries))
+	for _, e := range entries {
+		ret = append(ret, *e.statement)
+	}
+	return ret
+}
+
+// EffectiveStatement returns the latest statement about the vulnerability
+// in the product or nil if there is none.
+func (idx *Index) EffectiveStatement(product, vulnID string) *Statement {
+	entries := idx.matches(vulnID, product, nil)
+	if len(entries) == 0 {
+		return nil
+	}
+	return entries[len(entries)-1].statement
+}
+
+// Resolve returns the effective statement about the vulnerability in the
+// product across all the indexed documents together with the document it
+// comes from. Statements are ordered by their timestamp, or the one of
+// their document when they have none, and then by the order in which the
+// documents were indexed. Both values are nil if there is no statement.
+func (idx *Index) Resolve(product, vulnID string) (*Statement, *VEX) {
+	entries := idx.matches(vulnID, product, nil)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	e := entries[len(entries)-1]
+	return e.statement, e.document
+}
+
+// IndexMatch is a statement found in an index and the document it comes
+// from.
+type IndexMatch struct {
+	Statement *Statement
+	Document  *VEX
+}
+
+// ResolveAny returns the effective statement about any of the vulnerability
+// identifiers in any of the products and subcomponents, as in Matches,
+// together with the document it comes from. It is used to match scanner
+// findings which report a vulnerability under several identifiers in a
+// package that may be the product itself or a subcomponent of it. Both
+// values are nil if there is no statement.
+func (idx *Index) ResolveAny(vulnIDs, products, subcomponents []string) (*Statement, *VEX) {
+	entries := idx.matchAll(vulnIDs, products, subcomponents)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	e := entries[len(entries)-1]
+	return e.statement, e.document
+}
+
+// MatchAll returns the statements about any of the vulnerability identifiers
+// in any of the products and subcomponents, as in Matches, ordered from
+// oldest to newest. Each statement is returned once.
+func (idx *Index) MatchAll(vulnIDs, products, subcomponents []string) []IndexMatch {
+	entries := idx.matchAll(vulnIDs, products, subcomponents)
+	ret := make([]IndexMatch, 0, len(entries))
+	for _, e := range entries {
+		ret = append(ret, IndexMatch{Statement: e.statement, Document: e.document})
+	}
+	return ret
+}
+
+func (idx *Index) matchAll(vulnIDs, products, subcomponents []string) []*indexEntry {
+	seen := map[*indexEntry]struct{}{}
+	ret := []*indexEntry{}
+	for _, vulnID := range vulnIDs {
+		for _, product := range products {
+			for _, e := range idx.matches(vulnID, product, subcomponents) {
+				if _, ok := seen[e]; ok {
+					continue
+				}
+				seen[e] = struct{}{}
+				ret = append(ret, e)
+			}
+		}
+	}
+	sortIndexEntries(ret)
+	return ret
+}
+
+func (idx *Index) matches(vulnID, product string, subcomponents []string) []*indexEntry {
+	return idx.lookup(vulnID, product, normalizeProductKey(product), func(s *Statement) bool {
+		return s.Matches(vulnID, product, subcomponents)
+	})
+}
+
+// lookup returns the entries in the buckets of the vulnerability and product
+// for which match returns true, ordered from oldest to newest. productKey is
+// the normalized product identifier.
+func (idx *Index) lookup(vulnID, product, productKey string, match func(*Statement) bool) []*indexEntry {
+	products, ok := idx.entries[vulnID]
+	if !ok {
+		return nil
+	}
+
+	seen := map[*indexEntry]struct{}{}
+	ret := []*indexEntry{}
+	for _, key := range []string{product, productKey} {
+		for _, e := range products[key] {
+			if _, ok := seen[e]; ok {
+				continue
+			}
+			seen[e] = struct{}{}
+			if match(e.statement) {
+				ret = append(ret, e)
+			}
+		}
+	}
+
+	sortIndexEntries(ret)
+	return ret
+}
+
+// sortIndexEntries sorts entries from oldest to newest. It is an insertion
+// sort, the lists of matching entries are small.
+func sortIndexEntries(entries []*indexEntry) {
+	for i := 1; i < len(entries); i++ {
+		for j := i; j > 0 && indexEntryBefore(entries[j], entries[j-1]); j-- {
+			entries[j], entries[j-1] = entries[j-1], entries[j]
+		}
+	}
+}
+
+// indexEntryBefore orders entries by time and then by the order in which
+// they were indexed.
+func indexEntryBefore(a, b *indexEntry) bool {
+	if !a.time.Equal(b.time) {
+		return a.time.Before(b.time)
+	}
+	return a.order < b.order
+}
+
+// vulnerabilityKeys returns the identifiers of a vulnerability.
+func vulnerabilityKeys(v *Vulnerability) []string {
+	ret := vulnerabilityIDs(v)
+	if v.ID != "" {
+		ret = append(ret, v.ID)
+	}
+	return ret
+}
+
+// ProductKey returns the normalized form of a product identifier under
+// which statements are indexed. Stores keeping statements outside of memory
+// look up candidates by the identifier queried and its key and then check
+// them with Statement.Matches, as the Index does.
+func ProductKey(id string) string {
+	return normalizeProductKey(id)
+}
+
+// StatementKeys returns the vulnerability and product keys of a statement.
+func StatementKeys(s *Statement) (vulnerabilities, products []string) {
+	return vulnerabilityKeys(&s.Vulnerability), statementProductKeys(s)
+}
+
+// statementProductKeys returns the normalized identifiers of the products
+// of a statement.
+func statementProductKeys(s *Statement) []string {
+	ret := []string{}
+	for i := range s.Products {
+		c := &s.Products[i].Component
+		if c.ID != "" {
+			ret = append(ret, normalizeProductKey(c.ID))
+		}
+		for _, id := range c.Identifiers {
+			ret = append(ret, normalizeProductKey(id))
+		}
+		for _, h := range c.Hashes {
+			ret = append(ret, string(h))
+		}
+	}
+	return ret
+}
+
+// normalizeProductKey returns the key of a product identifier in the index.
+// Purls are reduced to their type, namespace and name as versions and
+// qualifiers match from generic to specific.
+func normalizeProductKey(id string) string {
+	if !strings.HasPrefix(id, "pkg:") {
+		return id
+	}
+	p, err := packageurl.FromString(id)
+	if err != nil {
+		return id
+	}
+	return "pkg:" + p.Type + "/" + p.Namespace + "/" + p.Name
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	doc1 := New()
+	doc1.Timestamp = &t1
+	doc1.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111", Aliases: []VulnerabilityID{"GHSA-aaaa-bbbb-cccc"}},
+			Products: []Product{{
+				Component:     Component{ID: "pkg:oci/app"},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/example.com/lib@v1.0.0"}}},
+			}},
+			Status: StatusUnderInvestigation,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products: []Product{{Component: Component{
+				ID:     "https://example.com/app",
+				Hashes: map[Algorithm]Hash{SHA256: "abc123"},
+			}}},
+			Status: StatusFixed,
+		},
+	}
+
+	doc2 := New()
+	doc2.Timestamp = &t2
+	doc2.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app@sha256%3Aabc"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		},
+	}
+
+	idx := NewIndex(&doc1, &doc2)
+
+	// Later documents override earlier ones
+	s := idx.EffectiveStatement("pkg:oci/app@sha256%3Aabc", "CVE-2023-1111")
+	require.NotNil(t, s)
+	require.Equal(t, StatusNotAffected, s.Status)
+
+	// Generic purls match more specific queries
+	s = idx.EffectiveStatement("pkg:oci/app@sha256%3Adef", "GHSA-aaaa-bbbb-cccc")
+	require.NotNil(t, s)
+	require.Equal(t, StatusUnderInvestigation, s.Status)
+
+	// Hashes and plain identifiers
+	require.Equal(t, StatusFixed, idx.EffectiveStatement("abc123", "CVE-2023-2222").Status)
+	require.Equal(t, StatusFixed, idx.EffectiveStatement("https://example.com/app", "CVE-2023-2222").Status)
+
+	require.Nil(t, idx.EffectiveStatement("pkg:oci/other", "CVE-2023-1111"))
+	require.Nil(t, idx.EffectiveStatement("pkg:oci/app", "CVE-2000-0000"))
+
+	matches := idx.Matches("CVE-2023-1111", "pkg:oci/app@sha256%3Aabc", []string{"pkg:golang/example.com/lib@v1.0.0"})
+	require.Len(t, matches, 2)
+	require.Equal(t, StatusUnderInvestigation, matches[0].Status)
+	require.Equal(t, StatusNotAffected, matches[1].Status)
+}
+
+func TestIndexResolve(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+
+	vendor := New(WithID("https://vendor.example.com/vex"))
+	vendor.Timestamp = &t1
+	vendor.Statements = []Statement{{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusUnderInvestigation,
+		Timestamp:     &t3,
+	}}
+
+	distro := New(WithID("https://distro.example.com/vex"))
+	distro.Timestamp = &t2
+	distro.Statements = []Statement{{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusNotAffected,
+		Justification: ComponentNotPresent,
+	}}
+
+	// The statement timestamp wins over the order of the documents
+	s, doc := NewIndex(&vendor, &distro).Resolve("pkg:oci/app", "CVE-2023-1111")
+	require.NotNil(t, s)
+	require.Equal(t, StatusUnderInvestigation, s.Status)
+	require.Equal(t, "https://vendor.example.com/vex", doc.ID)
+
+	vendor.Statements[0].Timestamp = nil
+	s, doc = NewIndex(&distro, &vendor).Resolve("pkg:oci/app", "CVE-2023-1111")
+	require.NotNil(t, s)
+	require.Equal(t, StatusNotAffected, s.Status)
+	require.Equal(t, "https://distro.example.com/vex", doc.ID)
+
+	s, doc = NewIndex(&distro, &vendor).Resolve("pkg:oci/other", "CVE-2023-1111")
+	require.Nil(t, s)
+	require.Nil(t, doc)
+}
+
+func TestIndexResolveAny(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+
+	doc := New(WithID("https://vendor.example.com/vex"))
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111", Aliases: []VulnerabilityID{"GHSA-xxxx-yyyy-zzzz"}},
+			Products: []Product{{
+				Component:     Component{ID: "pkg:oci/app"},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/example.com/lib"}}},
+			}},
+			Status:        StatusNotAffected,
+			Justification: VulnerableCodeNotInExecutePath,
+			Timestamp:     &t1,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:golang/example.com/lib@v1.0.0"}}},
+			Status:        StatusAffected,
+			Timestamp:     &t2,
+		},
+	}
+	idx := NewIndex(&doc)
+
+	// The package statement is the latest one
+	s, _ := idx.ResolveAny(
+		[]string{"GHSA-xxxx-yyyy-zzzz", "CVE-2023-1111"},
+		[]string{"pkg:oci/app", "pkg:golang/example.com/lib@v1.0.0"},
+		[]string{"pkg:golang/example.com/lib@v1.0.0"},
+	)
+	require.NotNil(t, s)
+	require.Equal(t, StatusAffected, s.Status)
+
+	// Matched through the subcomponent and the alias
+	s, d := idx.ResolveAny(
+		[]string{"GHSA-xxxx-yyyy-zzzz"},
+		[]string{"pkg:oci/app", "pkg:golang/example.com/lib@v1.0.0"},
+		[]string{"pkg:golang/example.com/lib@v1.0.0"},
+	)
+	require.NotNil(t, s)
+	require.Equal(t, StatusNotAffected, s.Status)
+	require.Equal(t, "https://vendor.example.com/vex", d.ID)
+
+	matches := idx.MatchAll(
+		[]string{"GHSA-xxxx-yyyy-zzzz", "CVE-2023-1111"},
+		[]string{"pkg:oci/app", "pkg:golang/example.com/lib@v1.0.0"},
+		[]string{"pkg:golang/example.com/lib@v1.0.0"},
+	)
+	require.Len(t, matches, 2)
+	require.Equal(t, &doc.Statements[0], matches[0].Statement)
+	require.Equal(t, &doc.Statements[1], matches[1].Statement)
+	require.Equal(t, &doc, matches[1].Document)
+
+	// Other subcomponents don't match
+	s, d = idx.ResolveAny(
+		[]string{"GHSA-xxxx-yyyy-zzzz"},
+		[]string{"pkg:oci/app"},
+		[]string{"pkg:golang/example.com/other@v1.0.0"},
+	)
+	require.Nil(t, s)
+	require.Nil(t, d)
+}
+
+func TestNewIndexFunc(t *testing.T) {
+	doc := New()
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusAffected,
+		},
+	}
+	idx := NewIndexFunc(func(_ *VEX, s *Statement) bool { return s.Status != StatusAffected }, &doc)
+	s, _ := idx.Resolve("pkg:oci/app", "CVE-2023-1111")
+	require.Equal(t, &doc.Statements[0], s)
+	s, _ = idx.Resolve("pkg:oci/app", "CVE-2023-2222")
+	require.Nil(t, s)
+}
+
+func TestStatementKeys(t *testing.T) {
+	s := Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111", Aliases: []VulnerabilityID{"GHSA-aaaa-bbbb-cccc"}},
+		Products: []Product{{Component: Component{
+			ID:     "pkg:oci/app@sha256%3Aabc?arch=amd64",
+			Hashes: map[Algorithm]Hash{SHA256: "abc"},
+		}}},
+	}
+	vulns, products := StatementKeys(&s)
+	require.ElementsMatch(t, []string{"CVE-2023-1111", "GHSA-aaaa-bbbb-cccc"}, vulns)
+	require.ElementsMatch(t, []string{"pkg:oci//app", "abc"}, products)
+	require.Equal(t, "pkg:oci//app", ProductKey("pkg:oci/app@sha256%3Adef"))
+	require.Equal(t, "https://example.com/app", ProductKey("https://example.com/app"))
+}
+
+func BenchmarkIndexEffectiveStatement(b *testing.B) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.Timestamp = &ts
+	for i := 0; i < 5000; i++ {
+		doc.Statements = append(doc.Statements, Statement{
+			Vulnerability: Vulnerability{Name: VulnerabilityID(fmt.Sprintf("CVE-2023-%d", i))},
+			Products:      []Product{{Component: Component{ID: fmt.Sprintf("pkg:apk/wolfi/pkg%d@1.0", i%100)}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		})
+	}
+	idx := NewIndex(&doc)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.EffectiveStatement(fmt.Sprintf("pkg:apk/wolfi/pkg%d@1.0", i%100), fmt.Sprintf("CVE-2023-%d", i%5000))
+	}
+}
@@ -0,0 +1,121 @@
+//go:build go1.23
+
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+)
+
+// All returns an iterator over the statements of the document in the order
+// they appear in it.
+func (vexDoc *VEX) All() iter.Seq[Statement] {
+	return func(yield func(Statement) bool) {
+		for i := range vexDoc.Statements {
+			if !yield(vexDoc.Statements[i]) {
+				return
+			}
+		}
+	}
+}
+
+// ForVulnerability returns an iterator over the statements about a
+// vulnerability, matched by its name, IRI or aliases. Statements are yielded
+// in document order, use StatementsByVulnerability to get them sorted
+// according to the VEX history.
+func (vexDoc *VEX) ForVulnerability(id string) iter.Seq[Statement] {
+	return func(yield func(Statement) bool) {
+		for i := range vexDoc.Statements {
+			if !vexDoc.Statements[i].Vulnerability.Matches(id) {
+				continue
+			}
+			if !yield(vexDoc.Statements[i]) {
+				return
+			}
+		}
+	}
+}
+
+// MatchesSeq returns an iterator over the statements matching the
+// vulnerability, product and any of the subcomponents in document order.
+func (vexDoc *VEX) MatchesSeq(vulnID, product string, subcomponents []string) iter.Seq[Statement] {
+	return func(yield func(Statement) bool) {
+		for i := range vexDoc.Statements {
+			if !vexDoc.Statements[i].Matches(vulnID, product, subcomponents) {
+				continue
+			}
+			if !yield(vexDoc.Statements[i]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the statements matching the query in
+// document order. Unlike Statements, the matches are not collected and
+// sorted before they are yielded.
+func (q *StatementQuery) All() iter.Seq[Statement] {
+	return func(yield func(Statement) bool) {
+		for i := range q.doc.Statements {
+			if !q.matches(&q.doc.Statements[i]) {
+				continue
+			}
+			if !yield(q.doc.Statements[i]) {
+				return
+			}
+		}
+	}
+}
+
+// MatchesSeq returns an iterator over the statements of the indexed
+// documents matching the vulnerability, product and any of the
+// subcomponents, from oldest to newest.
+func (idx *Index) MatchesSeq(vulnID, product string, subcomponents []string) iter.Seq[Statement] {
+	return func(yield func(Statement) bool) {
+		for _, e := range idx.matches(vulnID, product, subcomponents) {
+			if !yield(*e.statement) {
+				return
+			}
+		}
+	}
+}
+
+// MatchesConcurrent returns an iterator over the results of matching the
+// pairs across a pool of goroutines. Results are yielded as they are ready,
+// not in the order of the pairs. If the context is done before all the
+// pairs are matched, its error is yielded last with an empty result.
+func (idx *Index) MatchesConcurrent(ctx context.Context, pairs []QueryPair, opts *ConcurrentOptions) iter.Seq2[MatchResult, error] {
+	return func(yield func(MatchResult, error) bool) {
+		stopped := false
+		err := idx.MatchesConcurrentFunc(ctx, pairs, opts, func(r MatchResult) bool {
+			stopped = !yield(r, nil)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(MatchResult{}, err)
+		}
+	}
+}
+
+// All returns an iterator over the statements read by the decoder. Decoding
+// errors are yielded with a nil statement and end the iteration.
+func (d *StatementDecoder) All() iter.Seq2[*Statement, error] {
+	return func(yield func(*Statement, error) bool) {
+		for {
+			stmt, err := d.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(stmt, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+//go:build go1.23
+
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterators(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.Timestamp = &ts
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111", Aliases: []VulnerabilityID{"GHSA-aaaa-bbbb-cccc"}},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusAffected,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusFixed,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/other"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		},
+	}
+
+	require.Len(t, slices.Collect(doc.All()), 3)
+	require.Len(t, slices.Collect(doc.ForVulnerability("CVE-2023-1111")), 2)
+	require.Len(t, slices.Collect(doc.ForVulnerability("GHSA-aaaa-bbbb-cccc")), 1)
+	require.Len(t, slices.Collect(doc.MatchesSeq("CVE-2023-1111", "pkg:oci/other@1.0", nil)), 1)
+	require.Len(t, slices.Collect(doc.Query().Product("pkg:oci/app@1.0").All()), 2)
+	require.Len(t, slices.Collect(NewIndex(&doc).MatchesSeq("CVE-2023-1111", "pkg:oci/app@1.0", nil)), 1)
+
+	// Iteration stops early
+	n := 0
+	for range doc.All() {
+		n++
+		break
+	}
+	require.Equal(t, 1, n)
+}
+
+func TestStatementDecoderAll(t *testing.T) {
+	d := NewStatementDecoder(strings.NewReader(`{"statements": [{"status": "fixed"}, {"status": "affected"}], "version": 1}`))
+	statuses := []Status{}
+	for s, err := range d.All() {
+		require.NoError(t, err)
+		statuses = append(statuses, s.Status)
+	}
+	require.Equal(t, []Status{StatusFixed, StatusAffected}, statuses)
+
+	d = NewStatementDecoder(strings.NewReader(`{"statements": [{"status": 1}]}`))
+	for s, err := range d.All() {
+		require.Nil(t, s)
+		require.Error(t, err)
+	}
+}
+
+func TestIndexMatchesConcurrent(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.Timestamp = &ts
+	pairs := []QueryPair{}
+	for i := 0; i < 1000; i++ {
+		doc.Statements = append(doc.Statements, Statement{
+			Vulnerability: Vulnerability{Name: VulnerabilityID(fmt.Sprintf("CVE-2023-%d", i))},
+			Products:      []Product{{Component: Component{ID: fmt.Sprintf("pkg:apk/wolfi/pkg%d", i%10)}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		})
+		pairs = append(pairs, QueryPair{
+			Vulnerability: fmt.Sprintf("CVE-2023-%d", i),
+			Product:       fmt.Sprintf("pkg:apk/wolfi/pkg%d@1.0", i%20),
+		})
+	}
+	idx := NewIndex(&doc)
+
+	matched := 0
+	seen := map[string]bool{}
+	for r, err := range idx.MatchesConcurrent(context.Background(), pairs, &ConcurrentOptions{Workers: 4, ChunkSize: 7}) {
+		require.NoError(t, err)
+		seen[r.Pair.Vulnerability] = true
+		if r.Effective != nil {
+			matched++
+		}
+	}
+	require.Len(t, seen, len(pairs))
+	require.Equal(t, 500, matched)
+
+	// Stopping early
+	n := 0
+	for range idx.MatchesConcurrent(context.Background(), pairs, nil) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	require.Equal(t, 3, n)
+
+	// Cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var last error
+	for _, err := range idx.MatchesConcurrent(ctx, pairs, nil) {
+		last = err
+	}
+	require.ErrorIs(t, last, context.Canceled)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf16"
+)
+
+// CanonicalBytes returns the document serialized using the JSON
+// Canonicalization Scheme (JCS) defined in RFC 8785. Unlike the string hashed
+// by CanonicalHash, this serialization covers the whole document and can be
+// reproduced by any JCS implementation, in any language, from the same JSON
+// document.
+func (vexDoc *VEX) CanonicalBytes() ([]byte, error) {
+	data, err := json.Marshal(vexDoc)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling document: %w", err)
+	}
+	return CanonicalizeJSON(data)
+}
+
+// ContentHash returns the hex encoded SHA-256 hash of the document's
+// canonical serialization, see CanonicalBytes. Unlike CanonicalHash, it
+// changes with any edit to the document, including to impact and action
+// statements, notes and metadata.
+func (vexDoc *VEX) ContentHash() (string, error) {
+	data, err := vexDoc.CanonicalBytes()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// CanonicalizeJSON transforms a JSON document into its RFC 8785 canonical
+// form.
+func CanonicalizeJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+	var b bytes.Buffer
+	if err := writeCanonicalJSON(&b, v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func writeCanonicalJSON(b *bytes.Buffer, v any) error {
+	switch val := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		if val {
+			b.WriteString("true")
+		} else {
+			b.WriteString("false")
+		}
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return fmt.Errorf("invalid number %v", val)
+		}
+		// The encoding/json float format matches the ECMAScript number
+		// serialization required by the RFC
+		n, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Errorf("encoding number: %w", err)
+		}
+		b.Write(n)
+	case string:
+		writeCanonicalString(b, val)
+	case []any:
+		b.WriteByte('[')
+		for i := range val {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(b, val[i]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case map[string]any:
+		// Properties are sorted by their UTF-16 code units
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+
+		b.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeCanonicalString(b, k)
+			b.WriteByte(':')
+			if err := writeCanonicalJSON(b, val[k]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported JSON value of type %T", v)
+	}
+	return nil
+}
+
+// writeCanonicalString writes a JSON string escaping only the characters
+// required by RFC 8785.
+func writeCanonicalString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+}
+
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
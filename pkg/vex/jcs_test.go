@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	for name, tc := range map[string]struct {
+		input    string
+		expected string
+	}{
+		// Examples from RFC 8785 section 3.2
+		"rfc 8785 sample": {
+			input: `{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`,
+			expected: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		"utf-16 key order": {
+			input:    `{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`,
+			expected: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		"no html escaping": {
+			input:    `{"b": "<a & b>", "a": {"z": 1, "y": []}}`,
+			expected: `{"a":{"y":[],"z":1},"b":"<a & b>"}`,
+		},
+	} {
+		out, err := CanonicalizeJSON([]byte(tc.input))
+		require.NoError(t, err, name)
+		require.Equal(t, tc.expected, string(out), name)
+	}
+
+	_, err := CanonicalizeJSON([]byte(`{"a":`))
+	require.Error(t, err)
+}
+
+func TestCanonicalBytes(t *testing.T) {
+	doc := genTestDoc(t)
+	data, err := doc.CanonicalBytes()
+	require.NoError(t, err)
+
+	// Canonicalizing is idempotent and the output is a valid document
+	again, err := CanonicalizeJSON(data)
+	require.NoError(t, err)
+	require.Equal(t, data, again)
+
+	parsed := &VEX{}
+	require.NoError(t, json.Unmarshal(data, parsed))
+	data2, err := parsed.CanonicalBytes()
+	require.NoError(t, err)
+	require.Equal(t, data, data2)
+}
+
+func TestContentHash(t *testing.T) {
+	doc := genTestDoc(t)
+	doc.Statements = append(doc.Statements, Statement{
+		Vulnerability: Vulnerability{Name: "CVE-0000-0001"},
+		Status:        StatusUnderInvestigation,
+	})
+	order := []VulnerabilityID{}
+	for i := range doc.Statements {
+		order = append(order, doc.Statements[i].Vulnerability.Name)
+	}
+
+	hash, err := doc.ContentHash()
+	require.NoError(t, err)
+	canonical, err := doc.CanonicalHash()
+	require.NoError(t, err)
+
+	// Hashing leaves the statements in place
+	for i := range doc.Statements {
+		require.Equal(t, order[i], doc.Statements[i].Vulnerability.Name)
+	}
+
+	// Text left out of the canonical hash changes the content hash
+	doc.Statements[0].ImpactStatement = "The vulnerable code is not reachable"
+	edited, err := doc.ContentHash()
+	require.NoError(t, err)
+	require.NotEqual(t, hash, edited)
+	editedCanonical, err := doc.CanonicalHash()
+	require.NoError(t, err)
+	require.Equal(t, canonical, editedCanonical)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// JWSType is the value of the typ header in OpenVEX JWS tokens
+	JWSType = "openvex-canonical+jws"
+
+	jwsAlgEdDSA = "EdDSA"
+)
+
+// JWSAlgorithmer is implemented by signers and verifiers usable with JWS. It
+// returns the JWS "alg" value of the key, for example EdDSA.
+type JWSAlgorithmer interface {
+	JWSAlgorithm() string
+}
+
+// JWSSigner is a StatementSigner which can also sign documents as JWS.
+type JWSSigner interface {
+	StatementSigner
+	JWSAlgorithmer
+}
+
+// jwsHeader is the protected header of OpenVEX JWS tokens
+type jwsHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Type      string `json:"typ"`
+}
+
+// ToJWS signs the document and returns a compact JWS whose payload is the
+// canonical serialization hashed by SigningHash. The signature thus covers
+// exactly the same bytes as the hash, including impact and action statements
+// and notes. The signer must implement JWSAlgorithmer.
+func (vexDoc *VEX) ToJWS(signer StatementSigner) (string, error) {
+	alg, ok := signer.(JWSAlgorithmer)
+	if !ok {
+		return "", errors.New("signer does not support JWS")
+	}
+	header, err := json.Marshal(jwsHeader{Algorithm: alg.JWSAlgorithm(), KeyID: signer.KeyID(), Type: JWSType})
+	if err != nil {
+		return "", fmt.Errorf("marshaling JWS header: %w", err)
+	}
+	payload, err := vexDoc.signingBytes()
+	if err != nil {
+		return "", fmt.Errorf("canonicalizing document: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(payload)
+	sig, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("signing JWS: %w", err)
+	}
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
+
+// VerifyJWS checks a compact JWS produced by ToJWS: the signature must be
+// valid for the verifier's key and the payload must match the canonical
+// serialization of the document.
+func (vexDoc *VEX) VerifyJWS(token string, verifier StatementVerifier) error {
+	header, payload, err := parseJWS(token, verifier)
+	if err != nil {
+		return err
+	}
+	if header.KeyID != "" && header.KeyID != verifier.KeyID() {
+		return fmt.Errorf("JWS key %q does not match verifier key %q", header.KeyID, verifier.KeyID())
+	}
+	expected, err := vexDoc.signingBytes()
+	if err != nil {
+		return fmt.Errorf("canonicalizing document: %w", err)
+	}
+	if !bytes.Equal(payload, expected) {
+		return errors.New("JWS payload does not match the document")
+	}
+	return nil
+}
+
+// parseJWS decodes a compact JWS, checks its signature and returns the
+// header and payload.
+func parseJWS(token string, verifier StatementVerifier) (*jwsHeader, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, errors.New("invalid compact JWS, expected three parts")
+	}
+	enc := base64.RawURLEncoding
+	headerData, err := enc.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding JWS header: %w", err)
+	}
+	header := &jwsHeader{}
+	if err := json.Unmarshal(headerData, header); err != nil {
+		return nil, nil, fmt.Errorf("unmarshaling JWS header: %w", err)
+	}
+	if header.Type != JWSType {
+		return nil, nil, fmt.Errorf("unsupported JWS type %q", header.Type)
+	}
+	alg, ok := verifier.(JWSAlgorithmer)
+	if !ok {
+		return nil, nil, errors.New("verifier does not support JWS")
+	}
+	if header.Algorithm != alg.JWSAlgorithm() {
+		return nil, nil, fmt.Errorf("JWS algorithm %q does not match verifier algorithm %q", header.Algorithm, alg.JWSAlgorithm())
+	}
+	payload, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding JWS payload: %w", err)
+	}
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding JWS signature: %w", err)
+	}
+	if err := verifier.Verify([]byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, nil, fmt.Errorf("verifying JWS signature: %w", err)
+	}
+	return header, payload, nil
+}
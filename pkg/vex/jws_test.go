@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWS(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	doc, err := Open("testdata/v020-1.vex.json")
+	require.NoError(t, err)
+
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc.Timestamp = &ts
+
+	token, err := doc.ToJWS(NewED25519Signer("key", priv))
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	// The payload hashes to the signing hash
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	hash, err := doc.SigningHash()
+	require.NoError(t, err)
+	require.Equal(t, hash, fmt.Sprintf("%x", sha256.Sum256(payload)))
+
+	require.NoError(t, doc.VerifyJWS(token, NewED25519Verifier("key", pub)))
+	require.Error(t, doc.VerifyJWS(token, NewED25519Verifier("key", otherPub)))
+	require.Error(t, doc.VerifyJWS(token, NewED25519Verifier("other", pub)))
+	require.Error(t, doc.VerifyJWS(parts[0]+"."+parts[1], NewED25519Verifier("key", pub)))
+
+	// Any change to the document invalidates the token
+	changed := doc.Clone()
+	changed.Statements[0].ActionStatement = "Upgrade to a different version"
+	require.Error(t, changed.VerifyJWS(token, NewED25519Verifier("key", pub)))
+
+	doc.Statements[0].Status = StatusFixed
+	require.Error(t, doc.VerifyJWS(token, NewED25519Verifier("key", pub)))
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LintSeverity describes how serious a lint finding is.
+type LintSeverity string
+
+const (
+	// LintError flags a violation of the OpenVEX specification.
+	LintError LintSeverity = "error"
+
+	// LintWarning flags data that is valid but probably a mistake.
+	LintWarning LintSeverity = "warning"
+)
+
+// Stable identifiers of the lint rules. These will not change across versions
+// of the module so that CI systems can gate on them.
+const (
+	// LintRuleInvalidStatus flags statements with an unknown status value.
+	LintRuleInvalidStatus = "OVX001"
+
+	// LintRuleNotAffectedWithoutJustification flags not_affected statements
+	// without a justification or impact statement.
+	LintRuleNotAffectedWithoutJustification = "OVX002"
+
+	// LintRuleInvalidJustification flags unknown justification values.
+	LintRuleInvalidJustification = "OVX003"
+
+	// LintRuleAffectedWithoutAction flags affected statements without an
+	// action statement.
+	LintRuleAffectedWithoutAction = "OVX004"
+
+	// LintRuleStatementBeforeDocument flags statements dated before the
+	// document that contains them.
+	LintRuleStatementBeforeDocument = "OVX005"
+
+	// LintRuleDuplicateStatement flags statements repeated in the document.
+	LintRuleDuplicateStatement = "OVX006"
+
+	// LintRuleNoProducts flags statements that do not list any products.
+	LintRuleNoProducts = "OVX007"
+
+	// LintRuleNoVulnerability flags statements without a vulnerability name.
+	LintRuleNoVulnerability = "OVX008"
+)
+
+// LintFinding is a problem found when linting a document.
+type LintFinding struct {
+	// RuleID is the stable identifier of the rule that produced the finding
+	RuleID string `json:"rule_id"`
+
+	// Severity of the finding
+	Severity LintSeverity `json:"severity"`
+
+	// Path is a JSON pointer to the element of the document that triggered
+	// the finding.
+	Path string `json:"path"`
+
+	// Message is a human readable description of the problem
+	Message string `json:"message"`
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", f.RuleID, f.Severity, f.Path, f.Message)
+}
+
+// Lint runs semantic checks on the document that go beyond the JSON schema
+// and returns a list of findings. An empty list means the document is clean.
+func (vexDoc *VEX) Lint() []LintFinding {
+	findings := []LintFinding{}
+	seen := map[string]int{}
+
+	for i := range vexDoc.Statements {
+		stmt := &vexDoc.Statements[i]
+		path := fmt.Sprintf("/statements/%d", i)
+
+		findings = append(findings, lintStatement(stmt, path)...)
+
+		if stmt.Timestamp != nil && vexDoc.Timestamp != nil && stmt.Timestamp.Before(*vexDoc.Timestamp) {
+			findings = append(findings, LintFinding{
+				RuleID:   LintRuleStatementBeforeDocument,
+				Severity: LintWarning,
+				Path:     path + "/timestamp",
+				Message:  fmt.Sprintf("statement timestamp %s is before the document timestamp %s", stmt.Timestamp, vexDoc.Timestamp),
+			})
+		}
+
+		key := statementLintKey(stmt)
+		if j, ok := seen[key]; ok {
+			findings = append(findings, LintFinding{
+				RuleID:   LintRuleDuplicateStatement,
+				Severity: LintWarning,
+				Path:     path,
+				Message:  fmt.Sprintf("statement is a duplicate of /statements/%d", j),
+			})
+			continue
+		}
+		seen[key] = i
+	}
+
+	findings = append(findings, vexDoc.CheckTimestamps(DefaultTimestampTolerance)...)
+	return append(findings, vexDoc.lintTransitions()...)
+}
+
+// lintStatement runs the lint rules that only need to look at a statement
+func lintStatement(stmt *Statement, path string) []LintFinding {
+	findings := []LintFinding{}
+
+	if stmt.Vulnerability.Name == "" {
+		findings = append(findings, LintFinding{
+			RuleID:   LintRuleNoVulnerability,
+			Severity: LintError,
+			Path:     path + "/vulnerability/name",
+			Message:  "statement does not define a vulnerability name",
+		})
+	}
+
+	if len(stmt.Products) == 0 {
+		findings = append(findings, LintFinding{
+			RuleID:   LintRuleNoProducts,
+			Severity: LintWarning,
+			Path:     path + "/products",
+			Message:  "statement does not list any products",
+		})
+	}
+
+	if !stmt.Status.Valid() {
+		findings = append(findings, LintFinding{
+			RuleID:   LintRuleInvalidStatus,
+			Severity: LintError,
+			Path:     path + "/status",
+			Message:  fmt.Sprintf("invalid status value %q, must be one of [%s]", stmt.Status, strings.Join(Statuses(), ", ")),
+		})
+	}
+
+	if stmt.Justification != "" && !stmt.Justification.Valid() {
+		findings = append(findings, LintFinding{
+			RuleID:   LintRuleInvalidJustification,
+			Severity: LintError,
+			Path:     path + "/justification",
+			Message:  fmt.Sprintf("invalid justification value %q, must be one of [%s]", stmt.Justification, strings.Join(Justifications(), ", ")),
+		})
+	}
+
+	switch stmt.Status {
+	case StatusNotAffected:
+		if stmt.Justification == "" && stmt.ImpactStatement == "" {
+			findings = append(findings, LintFinding{
+				RuleID:   LintRuleNotAffectedWithoutJustification,
+				Severity: LintError,
+				Path:     path,
+				Message:  fmt.Sprintf("%s statement without justification or impact_statement", StatusNotAffected),
+			})
+		}
+	case StatusAffected:
+		if stmt.ActionStatement == "" {
+			findings = append(findings, LintFinding{
+				RuleID:   LintRuleAffectedWithoutAction,
+				Severity: LintError,
+				Path:     path,
+				Message:  fmt.Sprintf("%s statement without action_statement", StatusAffected),
+			})
+		}
+	}
+
+	return findings
+}
+
+// statementLintKey returns a string describing the statement's impact data
+// used to detect duplicates.
+func statementLintKey(stmt *Statement) string {
+	key := cstringFromVulnerability(stmt.Vulnerability)
+	key += fmt.Sprintf(":%s:%s", stmt.Status, stmt.Justification)
+	if stmt.Timestamp != nil {
+		key += fmt.Sprintf(":%d", stmt.Timestamp.Unix())
+	}
+	prods := []string{}
+	for _, p := range stmt.Products {
+		prodString := cstringFromComponent(p.Component)
+		for _, sc := range p.Subcomponents {
+			prodString += cstringFromComponent(sc.Component)
+		}
+		prods = append(prods, prodString)
+	}
+	sort.Strings(prods)
+	return key + strings.Join(prods, ":")
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	date1 := time.Date(2023, 4, 17, 20, 34, 58, 0, time.UTC)
+	date2 := time.Date(2023, 4, 18, 20, 34, 58, 0, time.UTC)
+	product := []Product{{Component: Component{
+		ID: "pkg:apk/wolfi/bash@1.0.0",
+		Identifiers: map[IdentifierType]string{
+			PURL:  "pkg:apk/wolfi/bash@1.0.0",
+			CPE23: "cpe:2.3:a:gnu:bash:1.0.0:*:*:*:*:*:*:*",
+		},
+	}}}
+
+	for m, tc := range map[string]struct {
+		stmts    []Statement
+		expected []string
+		paths    []string
+	}{
+		"clean": {
+			stmts: []Statement{
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusNotAffected, Justification: ComponentNotPresent},
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusAffected, ActionStatement: "Update", Timestamp: &date2},
+			},
+			expected: []string{},
+			paths:    []string{},
+		},
+		"not_affected without justification": {
+			stmts: []Statement{
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusNotAffected},
+			},
+			expected: []string{LintRuleNotAffectedWithoutJustification},
+			paths:    []string{"/statements/0"},
+		},
+		"invalid values": {
+			stmts: []Statement{
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: "wontfix", Justification: "because"},
+			},
+			expected: []string{LintRuleInvalidStatus, LintRuleInvalidJustification},
+			paths:    []string{"/statements/0/status", "/statements/0/justification"},
+		},
+		"statement before document": {
+			stmts: []Statement{
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusUnderInvestigation, Timestamp: &date1},
+			},
+			expected: []string{LintRuleStatementBeforeDocument},
+			paths:    []string{"/statements/0/timestamp"},
+		},
+		"duplicate statements": {
+			stmts: []Statement{
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusFixed},
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusFixed},
+			},
+			expected: []string{LintRuleDuplicateStatement},
+			paths:    []string{"/statements/1"},
+		},
+		"missing data": {
+			stmts: []Statement{
+				{Status: StatusAffected},
+			},
+			expected: []string{LintRuleNoVulnerability, LintRuleNoProducts, LintRuleAffectedWithoutAction},
+			paths:    []string{"/statements/0/vulnerability/name", "/statements/0/products", "/statements/0"},
+		},
+	} {
+		doc := &VEX{
+			Metadata:   Metadata{Timestamp: &date2},
+			Statements: tc.stmts,
+		}
+		rules := []string{}
+		paths := []string{}
+		for _, f := range doc.Lint() {
+			rules = append(rules, f.RuleID)
+			paths = append(paths, f.Path)
+		}
+		require.Equal(t, tc.expected, rules, m)
+		require.Equal(t, tc.paths, paths, m)
+	}
+}
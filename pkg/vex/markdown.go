@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarkdownChangelog renders the changes between two versions of a document
+// as a Markdown changelog for release notes, headed by the ID, version and
+// date of the new version. See DiffReport.Markdown.
+func MarkdownChangelog(a, b *VEX) string {
+	var sb strings.Builder
+	title := fmt.Sprintf("Version %d", b.Version)
+	if b.ID != "" {
+		title = fmt.Sprintf("%s version %d", markdownText(b.ID), b.Version)
+	}
+	date := b.LastUpdated
+	if date == nil || date.IsZero() {
+		date = b.Timestamp
+	}
+	if date != nil && !date.IsZero() {
+		title += " (" + date.UTC().Format("2006-01-02") + ")"
+	}
+	fmt.Fprintf(&sb, "## %s\n\n", title)
+	sb.WriteString(Diff(a, b).Markdown())
+	return sb.String()
+}
+
+// Markdown renders the report as a Markdown changelog with a section for
+// each kind of change, starting with the changes of effective status:
+//
+//	### Status changes
+//
+//	- CVE-2024-1234 on `pkg:oci/foo`: under_investigation → not_affected (component_not_present)
+//
+// Empty sections are left out, an empty report renders as a note saying
+// there are no changes. Values are escaped so they stay on their line and
+// do not split table cells when the changelog is pasted into one.
+func (d *DiffReport) Markdown() string {
+	if d.Empty() {
+		return "No changes.\n"
+	}
+
+	var sb strings.Builder
+	section := func(title string, n int, item func(i int)) {
+		if n == 0 {
+			return
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "### %s\n\n", title)
+		for i := 0; i < n; i++ {
+			item(i)
+		}
+	}
+
+	section("Status changes", len(d.StatusChanges), func(i int) {
+		s := &d.StatusChanges[i]
+		fmt.Fprintf(&sb, "- %s on %s: %s → %s", markdownText(s.Vulnerability), markdownCode(s.Product),
+			markdownText(string(s.Old)), markdownText(string(s.New)))
+		if s.Justification != "" {
+			fmt.Fprintf(&sb, " (%s)", markdownText(string(s.Justification)))
+		}
+		sb.WriteString("\n")
+	})
+	section("Added statements", len(d.Added), func(i int) {
+		fmt.Fprintf(&sb, "- %s\n", markdownStatement(&d.Added[i]))
+	})
+	section("Removed statements", len(d.Removed), func(i int) {
+		fmt.Fprintf(&sb, "- %s\n", markdownStatement(&d.Removed[i]))
+	})
+	section("Updated statements", len(d.Changed), func(i int) {
+		fmt.Fprintf(&sb, "- %s\n", markdownStatement(&d.Changed[i].New))
+		for _, f := range d.Changed[i].Fields {
+			fmt.Fprintf(&sb, "  - %s\n", markdownField(f))
+		}
+	})
+	section("Document", len(d.Metadata), func(i int) {
+		fmt.Fprintf(&sb, "- %s\n", markdownField(d.Metadata[i]))
+	})
+	return sb.String()
+}
+
+// markdownStatement summarizes a statement as its vulnerability, products
+// and status.
+func markdownStatement(s *Statement) string {
+	products := []string{}
+	for i := range s.Products {
+		products = append(products, markdownCode(s.Products[i].ID))
+	}
+	ret := fmt.Sprintf("%s on %s: %s", markdownText(string(s.Vulnerability.Name)),
+		strings.Join(products, ", "), markdownText(string(s.Status)))
+	if s.Justification != "" {
+		ret += fmt.Sprintf(" (%s)", markdownText(string(s.Justification)))
+	}
+	return ret
+}
+
+func markdownField(f FieldChange) string {
+	value := func(v string) string {
+		if v == "" {
+			return "_none_"
+		}
+		return markdownCode(v)
+	}
+	return fmt.Sprintf("%s: %s → %s", markdownText(f.Field), value(f.Old), value(f.New))
+}
+
+// markdownNewlines replaces line breaks with spaces.
+var markdownNewlines = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// markdownEscaper escapes pipes, which split table cells, and backslashes.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`)
+
+// markdownText escapes a value written as Markdown text: line breaks are
+// replaced with spaces and pipes are escaped.
+func markdownText(v string) string {
+	return markdownEscaper.Replace(markdownNewlines.Replace(v))
+}
+
+// markdownCode writes a value as an inline code span. Line breaks are
+// replaced with spaces and the span is delimited by a run of backticks
+// longer than any in the value, as backslashes do not escape them in code.
+func markdownCode(v string) string {
+	v = markdownNewlines.Replace(v)
+	fence := "`"
+	for strings.Contains(v, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(v, "`") || strings.HasSuffix(v, "`") {
+		v = " " + v + " "
+	}
+	return fence + v + fence
+}
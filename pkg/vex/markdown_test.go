@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownChangelog(t *testing.T) {
+	t1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+
+	a := New(WithID("https://example.com/vex/1"))
+	a.Timestamp = &t1
+	a.Version = 1
+	a.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2024-1234"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/foo"}}},
+			Status:        StatusUnderInvestigation,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2024-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/foo"}}},
+			Status:        StatusAffected,
+		},
+	}
+
+	b := a.Clone()
+	b.Version = 2
+	b.LastUpdated = &t2
+	b.Statements[0].Status = StatusNotAffected
+	b.Statements[0].Justification = ComponentNotPresent
+	b.Statements = b.Statements[:1]
+
+	require.Equal(t, "## https://example.com/vex/1 version 2 (2024-03-02)\n\n"+
+		"### Status changes\n\n"+
+		"- CVE-2024-1234 on `pkg:oci/foo`: under_investigation → not_affected (component_not_present)\n"+
+		"- CVE-2024-2222 on `pkg:oci/foo`: affected → no_statement\n\n"+
+		"### Removed statements\n\n"+
+		"- CVE-2024-2222 on `pkg:oci/foo`: affected\n\n"+
+		"### Updated statements\n\n"+
+		"- CVE-2024-1234 on `pkg:oci/foo`: not_affected (component_not_present)\n"+
+		"  - status: `under_investigation` → `not_affected`\n"+
+		"  - justification: _none_ → `component_not_present`\n\n"+
+		"### Document\n\n"+
+		"- last_updated: _none_ → `2024-03-02T00:00:00Z`\n"+
+		"- version: `1` → `2`\n",
+		MarkdownChangelog(&a, b))
+
+	require.Equal(t, "No changes.\n", Diff(&a, &a).Markdown())
+}
+
+func TestMarkdownEscaping(t *testing.T) {
+	a := New()
+	a.Statements = []Statement{{
+		Vulnerability:   Vulnerability{Name: "CVE-2024-1234|x"},
+		Products:        []Product{{Component: Component{ID: "pkg:oci/`foo`\nbar"}}},
+		Status:          StatusNotAffected,
+		Justification:   ComponentNotPresent,
+		ImpactStatement: "line one\nline two",
+	}}
+	b := a.Clone()
+	b.Statements[0].ImpactStatement = "a | b"
+
+	// Values stay on their line, pipes in text are escaped and code spans
+	// are fenced with more backticks than they contain
+	require.Equal(t, "### Updated statements\n\n"+
+		"- CVE-2024-1234\\|x on ``pkg:oci/`foo` bar``: not_affected (component_not_present)\n"+
+		"  - impact_statement: `line one line two` → `a | b`\n",
+		Diff(&a, b).Markdown())
+}
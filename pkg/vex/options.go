@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Environment variables read by DefaultsFromEnv
+const (
+	EnvAuthor     = "OPENVEX_AUTHOR"
+	EnvAuthorRole = "OPENVEX_AUTHOR_ROLE"
+	EnvSupplier   = "OPENVEX_SUPPLIER"
+	EnvTooling    = "OPENVEX_TOOLING"
+)
+
+// Defaults is the metadata set in every document created by New. Empty
+// fields leave the built in defaults in place.
+type Defaults struct {
+	Author     string `json:"author,omitempty" yaml:"author,omitempty"`
+	AuthorRole string `json:"role,omitempty" yaml:"role,omitempty"`
+	Supplier   string `json:"supplier,omitempty" yaml:"supplier,omitempty"`
+	Tooling    string `json:"tooling,omitempty" yaml:"tooling,omitempty"`
+}
+
+var defaults atomic.Pointer[Defaults]
+
+// SetDefaults sets the metadata New sets in new documents, so services
+// creating many documents can configure their identity in one place. Options
+// passed to New still take precedence. It is safe to call while documents
+// are being created.
+func SetDefaults(d Defaults) {
+	defaults.Store(&d)
+}
+
+// DefaultsFromEnv returns the defaults defined in the OPENVEX_AUTHOR,
+// OPENVEX_AUTHOR_ROLE, OPENVEX_SUPPLIER and OPENVEX_TOOLING environment
+// variables. They are not applied until passed to SetDefaults:
+//
+//	vex.SetDefaults(vex.DefaultsFromEnv())
+func DefaultsFromEnv() Defaults {
+	return Defaults{
+		Author:     os.Getenv(EnvAuthor),
+		AuthorRole: os.Getenv(EnvAuthorRole),
+		Supplier:   os.Getenv(EnvSupplier),
+		Tooling:    os.Getenv(EnvTooling),
+	}
+}
+
+// WithDefaults sets the non empty fields of d in the document.
+func WithDefaults(d Defaults) Option {
+	return func(doc *VEX) {
+		if d.Author != "" {
+			doc.Author = d.Author
+		}
+		if d.AuthorRole != "" {
+			doc.AuthorRole = d.AuthorRole
+		}
+		if d.Supplier != "" {
+			doc.Supplier = d.Supplier
+		}
+		if d.Tooling != "" {
+			doc.Tooling = d.Tooling
+		}
+	}
+}
+
+// Option configures a document created by New.
+type Option func(*VEX)
+
+// WithID sets the document @id. When not set, GenerateCanonicalID can
+// compute one from the document contents.
+func WithID(id string) Option {
+	return func(doc *VEX) {
+		doc.ID = id
+	}
+}
+
+// WithAuthor sets the document author.
+func WithAuthor(author string) Option {
+	return func(doc *VEX) {
+		doc.Author = author
+	}
+}
+
+// WithAuthorRole sets the role of the document author.
+func WithAuthorRole(role string) Option {
+	return func(doc *VEX) {
+		doc.AuthorRole = role
+	}
+}
+
+// WithTooling sets the tools used to generate the document.
+func WithTooling(tooling string) Option {
+	return func(doc *VEX) {
+		doc.Tooling = tooling
+	}
+}
+
+// WithSupplier sets the document supplier.
+func WithSupplier(supplier string) Option {
+	return func(doc *VEX) {
+		doc.Supplier = supplier
+	}
+}
+
+// WithTimestamp sets the document timestamp to the time returned by clock.
+// It takes precedence over SOURCE_DATE_EPOCH and is useful to create
+// reproducible documents in tests:
+//
+//	vex.New(vex.WithTimestamp(func() time.Time { return fixed }))
+func WithTimestamp(clock func() time.Time) Option {
+	return func(doc *VEX) {
+		t := clock()
+		doc.Timestamp = &t
+	}
+}
+
+// WithContext sets the document context to the locator of a version of the
+// OpenVEX specification, such as "0.2.0". The default is SpecVersion.
+func WithContext(version string) Option {
+	return func(doc *VEX) {
+		doc.Context = fmt.Sprintf("%s/v%s", Context, strings.TrimPrefix(version, "v"))
+	}
+}
+
+// WithStatements adds statements to the document.
+func WithStatements(stmts ...Statement) Option {
+	return func(doc *VEX) {
+		doc.Statements = append(doc.Statements, stmts...)
+	}
+}
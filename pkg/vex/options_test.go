@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOptions(t *testing.T) {
+	doc := New()
+	require.Equal(t, DefaultAuthor, doc.Author)
+	require.Equal(t, ContextLocator(), doc.Context)
+	require.Equal(t, 1, doc.Version)
+	require.NotNil(t, doc.Timestamp)
+
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc = New(
+		WithID("https://example.com/vex/1"),
+		WithAuthor("Example Inc."),
+		WithAuthorRole("Document Creator"),
+		WithTooling("vexctl"),
+		WithSupplier("Example Inc."),
+		WithTimestamp(func() time.Time { return ts }),
+		WithContext("v0.0.1"),
+		WithStatements(Statement{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusFixed,
+		}),
+	)
+	require.Equal(t, "https://example.com/vex/1", doc.ID)
+	require.Equal(t, "Example Inc.", doc.Author)
+	require.Equal(t, "Document Creator", doc.AuthorRole)
+	require.Equal(t, "vexctl", doc.Tooling)
+	require.Equal(t, "Example Inc.", doc.Supplier)
+	require.Equal(t, ts, *doc.Timestamp)
+	require.Equal(t, "https://openvex.dev/ns/v0.0.1", doc.Context)
+	require.Len(t, doc.Statements, 1)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+
+	// The clock takes precedence over SOURCE_DATE_EPOCH
+	t.Setenv("SOURCE_DATE_EPOCH", "1000")
+	doc = New(WithTimestamp(func() time.Time { return ts }))
+	require.Equal(t, ts, *doc.Timestamp)
+}
+
+func TestDefaults(t *testing.T) {
+	t.Cleanup(func() { SetDefaults(Defaults{}) })
+
+	t.Setenv(EnvAuthor, "Example Inc.")
+	t.Setenv(EnvAuthorRole, "")
+	t.Setenv(EnvSupplier, "Example Supplier")
+	t.Setenv(EnvTooling, "vexctl")
+	d := DefaultsFromEnv()
+	require.Equal(t, Defaults{Author: "Example Inc.", Supplier: "Example Supplier", Tooling: "vexctl"}, d)
+
+	// Defaults are not applied until set
+	require.Equal(t, DefaultAuthor, New().Author)
+
+	SetDefaults(d)
+	doc := New()
+	require.Equal(t, "Example Inc.", doc.Author)
+	require.Equal(t, DefaultRole, doc.AuthorRole)
+	require.Equal(t, "Example Supplier", doc.Supplier)
+	require.Equal(t, "vexctl", doc.Tooling)
+
+	// Options take precedence
+	require.Equal(t, "Someone Else", New(WithAuthor("Someone Else")).Author)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OSVScannerConfig captures the ignore entries of an osv-scanner
+// configuration file.
+//
+// https://google.github.io/osv-scanner/configuration/
+type OSVScannerConfig struct {
+	IgnoredVulns []OSVScannerIgnore
+}
+
+// OSVScannerIgnore is a vulnerability that osv-scanner should not report.
+type OSVScannerIgnore struct {
+	ID          string
+	Reason      string
+	IgnoreUntil *time.Time
+}
+
+// ToOSVScannerConfig returns the osv-scanner configuration ignoring the
+// vulnerabilities that the document marks as not_affected or fixed.
+//
+// osv-scanner ignores vulnerabilities in every package, so a vulnerability
+// is only listed when the latest statements about all the packages it is
+// reported in are not_affected or fixed. Packages are identified by the
+// purls of the statement subcomponents or, when a product lists no
+// subcomponents, of the product. Vulnerability aliases get their own entries.
+func (vexDoc *VEX) ToOSVScannerConfig() *OSVScannerConfig {
+	type ignore struct {
+		reasons    []string
+		suppressed bool
+	}
+	byVuln := map[string]*ignore{}
+	for _, ps := range vexDoc.latestPackageStatements() {
+		entry, ok := byVuln[ps.vulnerability]
+		if !ok {
+			entry = &ignore{suppressed: true}
+			byVuln[ps.vulnerability] = entry
+		}
+		if ps.statement.Status != StatusNotAffected && ps.statement.Status != StatusFixed {
+			entry.suppressed = false
+			continue
+		}
+		reason := suppressionReason(ps.statement)
+		if !slices.Contains(entry.reasons, reason) {
+			entry.reasons = append(entry.reasons, reason)
+		}
+	}
+
+	config := &OSVScannerConfig{IgnoredVulns: []OSVScannerIgnore{}}
+	for id, entry := range byVuln {
+		if !entry.suppressed {
+			continue
+		}
+		config.IgnoredVulns = append(config.IgnoredVulns, OSVScannerIgnore{
+			ID:     id,
+			Reason: strings.Join(entry.reasons, "; "),
+		})
+	}
+	sort.Slice(config.IgnoredVulns, func(i, j int) bool {
+		return config.IgnoredVulns[i].ID < config.IgnoredVulns[j].ID
+	})
+	return config
+}
+
+// SetExpiry sets the date when osv-scanner stops ignoring all entries.
+func (config *OSVScannerConfig) SetExpiry(t time.Time) {
+	for i := range config.IgnoredVulns {
+		until := t.UTC()
+		config.IgnoredVulns[i].IgnoreUntil = &until
+	}
+}
+
+// ToTOML writes the configuration as an osv-scanner.toml file.
+func (config *OSVScannerConfig) ToTOML(w io.Writer) error {
+	var sb strings.Builder
+	for i, v := range config.IgnoredVulns {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("[[IgnoredVulns]]\n")
+		sb.WriteString("id = " + tomlString(v.ID) + "\n")
+		if v.IgnoreUntil != nil {
+			sb.WriteString("ignoreUntil = " + v.IgnoreUntil.Format(time.RFC3339) + "\n")
+		}
+		if v.Reason != "" {
+			sb.WriteString("reason = " + tomlString(v.Reason) + "\n")
+		}
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("writing osv-scanner configuration: %w", err)
+	}
+	return nil
+}
+
+// tomlString quotes s as a TOML basic string. The escape sequences produced
+// by the JSON encoder are all valid in TOML.
+func tomlString(s string) string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		// Marshaling a string does not fail
+		return `""`
+	}
+	return string(data)
+}
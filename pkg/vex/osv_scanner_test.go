@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToOSVScannerConfig(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.Timestamp = &ts
+	doc.Statements = []Statement{
+		{
+			Vulnerability:   Vulnerability{Name: "GO-2022-0968", Aliases: []VulnerabilityID{"CVE-2022-27191"}},
+			Products:        []Product{{Component: Component{ID: "pkg:golang/golang.org/x/crypto@v0.0.0-20220214200702-86341886e292"}}},
+			Status:          StatusNotAffected,
+			Justification:   VulnerableCodeNotInExecutePath,
+			ImpactStatement: "No \"ssh\" servers are hosted",
+		},
+		{
+			Vulnerability: Vulnerability{Name: "GO-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:golang/example.com/a@v1.0.0"}}},
+			Status:        StatusFixed,
+		},
+		{
+			// Still affected in another package so it can't be ignored
+			Vulnerability: Vulnerability{Name: "GO-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:golang/example.com/b@v1.0.0"}}},
+			Status:        StatusAffected,
+		},
+	}
+
+	config := doc.ToOSVScannerConfig()
+	require.Equal(t, []OSVScannerIgnore{
+		{ID: "CVE-2022-27191", Reason: `not_affected: vulnerable_code_not_in_execute_path: No "ssh" servers are hosted`},
+		{ID: "GO-2022-0968", Reason: `not_affected: vulnerable_code_not_in_execute_path: No "ssh" servers are hosted`},
+	}, config.IgnoredVulns)
+
+	config.SetExpiry(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var buf bytes.Buffer
+	require.NoError(t, config.ToTOML(&buf))
+	require.Equal(t, `[[IgnoredVulns]]
+id = "CVE-2022-27191"
+ignoreUntil = 2024-01-01T00:00:00Z
+reason = "not_affected: vulnerable_code_not_in_execute_path: No \"ssh\" servers are hosted"
+
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+ignoreUntil = 2024-01-01T00:00:00Z
+reason = "not_affected: vulnerable_code_not_in_execute_path: No \"ssh\" servers are hosted"
+`, buf.String())
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+)
+
+// ApplyPatch applies the changes described in a DiffReport, such as one
+// returned by Diff(old, new), so updates to big documents can be shipped as
+// patches. The statements removed and changed must be in the document as
+// they are in the patch, otherwise the patch does not apply and the
+// document is left untouched.
+//
+// Metadata changes are applied if the document has the old value. The
+// version and the timestamps are not taken from the patch, the document is
+// updated with NextVersion instead, so transparency log entries are dropped
+// and fields not covered by the patch, such as extensions, are kept. Status
+// changes are informative and not checked.
+func (vexDoc *VEX) ApplyPatch(p *DiffReport) error {
+	next, err := vexDoc.NextVersion(func(next *VEX) error {
+		return next.applyPatch(p)
+	})
+	if err != nil {
+		return err
+	}
+	next.RecordChange(ChangeApplyPatch, fmt.Sprintf(
+		"Applied patch adding %d, removing %d and changing %d statements",
+		len(p.Added), len(p.Removed), len(p.Changed),
+	))
+	*vexDoc = *next
+	return nil
+}
+
+// applyPatch applies the statement and metadata changes of a patch to the
+// document.
+func (vexDoc *VEX) applyPatch(p *DiffReport) error {
+	statements := vexDoc.Statements
+
+	// find returns the index of a statement equal to s or -1
+	find := func(s *Statement) int {
+		k := diffKey(s)
+		for i := range statements {
+			if diffKey(&statements[i]) == k && len(diffStatement(&statements[i], s)) == 0 {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for i := range p.Removed {
+		j := find(&p.Removed[i])
+		if j < 0 {
+			return fmt.Errorf("applying patch: removed statement %s: %w", diffSummary(&p.Removed[i]), ErrNoStatement)
+		}
+		statements = append(statements[:j], statements[j+1:]...)
+	}
+	for i := range p.Changed {
+		j := find(&p.Changed[i].Old)
+		if j < 0 {
+			return fmt.Errorf("applying patch: changed statement %s: %w", diffSummary(&p.Changed[i].Old), ErrNoStatement)
+		}
+		statements[j] = p.Changed[i].New.Clone()
+	}
+	for i := range p.Added {
+		statements = append(statements, p.Added[i].Clone())
+	}
+
+	for _, f := range p.Metadata {
+		var field *string
+		switch f.Field {
+		case "@context":
+			field = &vexDoc.Context
+		case "@id":
+			field = &vexDoc.ID
+		case "author":
+			field = &vexDoc.Author
+		case "role":
+			field = &vexDoc.AuthorRole
+		case "tooling":
+			field = &vexDoc.Tooling
+		case "supplier":
+			field = &vexDoc.Supplier
+		default:
+			// Version and timestamps are set by NextVersion
+			continue
+		}
+		if *field != f.Old {
+			return fmt.Errorf("applying patch: %s is %q, patch expects %q", f.Field, *field, f.Old)
+		}
+		*field = f.New
+	}
+
+	vexDoc.Statements = statements
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatch(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	base := func() *VEX {
+		doc := New()
+		doc.ID = "https://example.com/vex/1"
+		doc.Author = "Example Inc."
+		doc.Timestamp = &t1
+		doc.Version = 3
+		doc.Statements = []Statement{
+			{
+				Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+				Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+				Status:        StatusUnderInvestigation,
+			},
+			{
+				Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+				Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+				Status:        StatusFixed,
+			},
+		}
+		return &doc
+	}
+
+	updated := base()
+	updated.Author = "Example Security Team"
+	updated.Statements[0].Status = StatusAffected
+	updated.Statements[0].ActionStatement = "Update the base image"
+	updated.Statements = append(updated.Statements[:1], Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-3333"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusNotAffected,
+		Justification: ComponentNotPresent,
+	})
+
+	// Patches survive a round trip through JSON
+	data, err := json.Marshal(Diff(base(), updated))
+	require.NoError(t, err)
+	patch := &DiffReport{}
+	require.NoError(t, json.Unmarshal(data, patch))
+
+	doc := base()
+	before := time.Now()
+	require.NoError(t, doc.ApplyPatch(patch))
+	require.Equal(t, 4, doc.Version)
+	require.Equal(t, "Example Security Team", doc.Author)
+	require.Equal(t, t1, *doc.Timestamp)
+	require.NotNil(t, doc.LastUpdated)
+	require.False(t, doc.LastUpdated.Before(before))
+
+	// The new version links to the patched one and drops its log entries
+	prev := base()
+	prev.Previous = "sha256:0000"
+	prev.TransparencyLog = []TransparencyLogEntry{{LogIndex: 1}}
+	doc = prev.Clone()
+	require.NoError(t, doc.ApplyPatch(patch))
+	require.Nil(t, doc.TransparencyLog)
+	require.NoError(t, VerifyChain([]*VEX{prev, doc}))
+	require.Len(t, prev.TransparencyLog, 1)
+
+	// Apart from the version and timestamps, the document is the new one
+	d := Diff(updated, doc)
+	require.Empty(t, d.Added)
+	require.Empty(t, d.Removed)
+	require.Empty(t, d.Changed)
+	require.Empty(t, d.StatusChanges)
+
+	// Patches don't apply twice and failures leave the document untouched
+	require.Error(t, doc.ApplyPatch(patch))
+	require.Equal(t, 4, doc.Version)
+	require.Len(t, doc.Statements, 2)
+
+	doc = base()
+	doc.Statements[1].StatusNotes = "Fixed in 1.2"
+	require.Error(t, doc.ApplyPatch(patch))
+	require.Equal(t, StatusUnderInvestigation, doc.Statements[0].Status)
+
+	doc = base()
+	doc.Author = "Someone Else"
+	require.Error(t, doc.ApplyPatch(patch))
+}
+
+func TestApplyPatchExtensions(t *testing.T) {
+	doc, err := Parse([]byte(`{
+		"@context": "https://openvex.dev/ns/v0.2.0",
+		"@id": "https://example.com/vex/1",
+		"author": "Example Inc.",
+		"timestamp": "2023-06-01T00:00:00Z",
+		"version": 1,
+		"x-approved": true,
+		"statements": [
+			{
+				"vulnerability": {"name": "CVE-2023-1111"},
+				"products": [{"@id": "pkg:oci/app"}],
+				"status": "under_investigation",
+				"ticket": "SEC-1234"
+			},
+			{
+				"vulnerability": {"name": "CVE-2023-2222"},
+				"products": [{"@id": "pkg:oci/app"}],
+				"status": "fixed",
+				"ticket": "SEC-5678"
+			}
+		]
+	}`))
+	require.NoError(t, err)
+
+	updated := doc.Clone()
+	updated.Statements[0].Status = StatusFixed
+	require.NoError(t, doc.ApplyPatch(Diff(doc, updated)))
+
+	require.Equal(t, json.RawMessage(`true`), doc.RawExtensions["x-approved"])
+	require.Equal(t, json.RawMessage(`"SEC-1234"`), doc.Statements[0].RawExtensions["ticket"])
+	require.Equal(t, json.RawMessage(`"SEC-5678"`), doc.Statements[1].RawExtensions["ticket"])
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"x-approved":true`)
+}
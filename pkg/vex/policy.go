@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrPolicyViolation is returned when a statement does not meet the quality
+// bar of a Policy.
+var ErrPolicyViolation = errors.New("statement rejected by policy")
+
+// PolicyRule is an additional check run by a policy on each statement. It
+// returns an error explaining why the statement is rejected. The cel package
+// builds rules from CEL expressions.
+type PolicyRule func(doc *VEX, stmt *Statement) error
+
+// Policy sets the conditions statements, usually from third parties, must
+// meet to be accepted by consumers. The zero value accepts all statements.
+type Policy struct {
+	// AllowedJustifications lists the justifications accepted in
+	// not_affected statements. When set, not_affected statements without
+	// a justification are rejected too.
+	AllowedJustifications []Justification
+
+	// MinImpactStatementLength is the minimum length of the impact
+	// statement of not_affected statements.
+	MinImpactStatementLength int
+
+	// RequireActionStatement rejects affected statements without an action
+	// statement.
+	RequireActionStatement bool
+
+	// Verifier, when set, requires the documents or each of their
+	// statements to be signed by its trusted keys.
+	Verifier *Verifier
+
+	// Rules are additional checks run on each statement.
+	Rules []PolicyRule
+}
+
+// Signatures holds the detached signatures of a document and its statements.
+type Signatures struct {
+	Document   []DocumentSignature
+	Statements []StatementSignature
+}
+
+// PolicyViolation records a statement rejected by a policy.
+type PolicyViolation struct {
+	// Statement is the rejected statement.
+	Statement *Statement
+
+	// Err explains why the statement was rejected. It wraps
+	// ErrPolicyViolation.
+	Err error
+}
+
+// CheckStatement checks a statement of a document against the policy,
+// except for its signatures. The returned error wraps ErrPolicyViolation.
+func (p *Policy) CheckStatement(doc *VEX, stmt *Statement) error {
+	if stmt.Status == StatusNotAffected {
+		if len(p.AllowedJustifications) > 0 && !slices.Contains(p.AllowedJustifications, stmt.Justification) {
+			if stmt.Justification == "" {
+				return fmt.Errorf("%w: not_affected statement has no justification", ErrPolicyViolation)
+			}
+			return fmt.Errorf("%w: justification %q is not allowed", ErrPolicyViolation, stmt.Justification)
+		}
+		if p.MinImpactStatementLength > 0 && len(strings.TrimSpace(stmt.ImpactStatement)) < p.MinImpactStatementLength {
+			return fmt.Errorf(
+				"%w: impact statement is shorter than %d characters",
+				ErrPolicyViolation, p.MinImpactStatementLength,
+			)
+		}
+	}
+	if p.RequireActionStatement && stmt.Status == StatusAffected && stmt.ActionStatement == "" {
+		return fmt.Errorf("%w: affected statement has no action statement", ErrPolicyViolation)
+	}
+	for _, rule := range p.Rules {
+		if err := rule(doc, stmt); err != nil {
+			return fmt.Errorf("%w: %w", ErrPolicyViolation, err)
+		}
+	}
+	return nil
+}
+
+// CheckDocument checks the statements of a document against the policy and
+// returns the ones rejected. When the policy has a Verifier, the statements
+// of documents not signed by enough trusted keys are only accepted if they
+// are signed themselves, as in VerifiedDocument. Document signatures cover
+// all the fields of the document, so statements edited after signing, for
+// example to pad their impact statement, are not accepted on the strength of
+// the document signature. sigs may be nil if the policy does not require
+// signatures.
+//
+// The document is not modified.
+func (p *Policy) CheckDocument(doc *VEX, sigs *Signatures) []PolicyViolation {
+	if sigs == nil {
+		sigs = &Signatures{}
+	}
+	signed := p.Verifier == nil || p.Verifier.VerifyDocument(doc.Clone(), sigs.Document) == nil
+
+	ret := []PolicyViolation{}
+	for i := range doc.Statements {
+		stmt := &doc.Statements[i]
+		if !signed {
+			if err := p.Verifier.VerifyStatement(stmt, sigs.Statements); err != nil {
+				ret = append(ret, PolicyViolation{Statement: stmt, Err: fmt.Errorf("%w: %w", ErrPolicyViolation, err)})
+				continue
+			}
+		}
+		if err := p.CheckStatement(doc, stmt); err != nil {
+			ret = append(ret, PolicyViolation{Statement: stmt, Err: err})
+		}
+	}
+	return ret
+}
+
+// Accepted returns a copy of the document holding only the statements
+// accepted by the policy, together with the violations of the rejected ones.
+func (p *Policy) Accepted(doc *VEX, sigs *Signatures) (*VEX, []PolicyViolation) {
+	violations := p.CheckDocument(doc, sigs)
+	rejected := map[*Statement]struct{}{}
+	for _, v := range violations {
+		rejected[v.Statement] = struct{}{}
+	}
+
+	accepted := doc.Clone()
+	accepted.Statements = []Statement{}
+	for i := range doc.Statements {
+		if _, ok := rejected[&doc.Statements[i]]; !ok {
+			accepted.Statements = append(accepted.Statements, doc.Statements[i].Clone())
+		}
+	}
+	return accepted, violations
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCheckStatement(t *testing.T) {
+	doc := New()
+	for name, tc := range map[string]struct {
+		policy  Policy
+		stmt    Statement
+		mustErr bool
+	}{
+		"zero policy": {
+			Policy{},
+			Statement{Status: StatusNotAffected},
+			false,
+		},
+		"allowed justification": {
+			Policy{AllowedJustifications: []Justification{ComponentNotPresent}},
+			Statement{Status: StatusNotAffected, Justification: ComponentNotPresent},
+			false,
+		},
+		"disallowed justification": {
+			Policy{AllowedJustifications: []Justification{ComponentNotPresent}},
+			Statement{Status: StatusNotAffected, Justification: InlineMitigationsAlreadyExist},
+			true,
+		},
+		"missing justification": {
+			Policy{AllowedJustifications: []Justification{ComponentNotPresent}},
+			Statement{Status: StatusNotAffected, ImpactStatement: "Not used"},
+			true,
+		},
+		"justifications only apply to not_affected": {
+			Policy{AllowedJustifications: []Justification{ComponentNotPresent}},
+			Statement{Status: StatusFixed},
+			false,
+		},
+		"short impact statement": {
+			Policy{MinImpactStatementLength: 20},
+			Statement{Status: StatusNotAffected, Justification: ComponentNotPresent, ImpactStatement: " Not used   "},
+			true,
+		},
+		"long impact statement": {
+			Policy{MinImpactStatementLength: 20},
+			Statement{Status: StatusNotAffected, Justification: ComponentNotPresent, ImpactStatement: "The vulnerable parser is not built"},
+			false,
+		},
+		"missing action statement": {
+			Policy{RequireActionStatement: true},
+			Statement{Status: StatusAffected},
+			true,
+		},
+		"action statement": {
+			Policy{RequireActionStatement: true},
+			Statement{Status: StatusAffected, ActionStatement: "Upgrade"},
+			false,
+		},
+		"rule": {
+			Policy{Rules: []PolicyRule{func(_ *VEX, s *Statement) error {
+				if s.Status == StatusUnderInvestigation {
+					return errors.New("under investigation")
+				}
+				return nil
+			}}},
+			Statement{Status: StatusUnderInvestigation},
+			true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.policy.CheckStatement(&doc, &tc.stmt)
+			if tc.mustErr {
+				require.ErrorIs(t, err, ErrPolicyViolation)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPolicyAccepted(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	signer := NewED25519Signer("a", priv)
+
+	doc := New()
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/image"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-5678"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/image"}}},
+			Status:        StatusNotAffected,
+			Justification: InlineMitigationsAlreadyExist,
+		},
+	}
+
+	policy := Policy{AllowedJustifications: []Justification{ComponentNotPresent}}
+	accepted, violations := policy.Accepted(&doc, nil)
+	require.Len(t, accepted.Statements, 1)
+	require.Equal(t, "CVE-2023-1234", string(accepted.Statements[0].Vulnerability.Name))
+	require.Len(t, violations, 1)
+	require.Equal(t, &doc.Statements[1], violations[0].Statement)
+	require.Len(t, doc.Statements, 2)
+
+	// Unsigned documents are rejected
+	policy.Verifier = NewVerifier(1, NewED25519Verifier("a", pub))
+	accepted, violations = policy.Accepted(&doc, nil)
+	require.Empty(t, accepted.Statements)
+	require.Len(t, violations, 2)
+	require.ErrorIs(t, violations[0].Err, ErrNotEnoughSignatures)
+	require.ErrorIs(t, violations[0].Err, ErrPolicyViolation)
+
+	sig, err := doc.Sign(signer)
+	require.NoError(t, err)
+	accepted, _ = policy.Accepted(&doc, &Signatures{Document: []DocumentSignature{*sig}})
+	require.Len(t, accepted.Statements, 1)
+
+	// Signed statements are accepted in unsigned documents
+	stmtSig, err := doc.Statements[0].Sign(signer)
+	require.NoError(t, err)
+	accepted, violations = policy.Accepted(&doc, &Signatures{Statements: []StatementSignature{*stmtSig}})
+	require.Len(t, accepted.Statements, 1)
+	require.Len(t, violations, 1)
+}
+
+func TestPolicySignedContent(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	doc := New()
+	doc.Statements = []Statement{
+		{
+			Vulnerability:   Vulnerability{Name: "CVE-2023-5678"},
+			Products:        []Product{{Component: Component{ID: "pkg:oci/image"}}},
+			Status:          StatusAffected,
+			ActionStatement: "Upgrade to 1.2",
+		},
+		{
+			Vulnerability:   Vulnerability{Name: "CVE-2023-1234"},
+			Products:        []Product{{Component: Component{ID: "pkg:oci/image"}}},
+			Status:          StatusNotAffected,
+			Justification:   ComponentNotPresent,
+			ImpactStatement: "Not shipped",
+		},
+	}
+	sig, err := doc.Sign(NewED25519Signer("a", priv))
+	require.NoError(t, err)
+	sigs := &Signatures{Document: []DocumentSignature{*sig}}
+
+	policy := Policy{
+		MinImpactStatementLength: 20,
+		RequireActionStatement:   true,
+		Verifier:                 NewVerifier(1, NewED25519Verifier("a", pub)),
+	}
+	violations := policy.CheckDocument(&doc, sigs)
+	require.Len(t, violations, 1)
+	require.Equal(t, &doc.Statements[1], violations[0].Statement)
+	require.ErrorContains(t, violations[0].Err, "impact statement")
+
+	// Checking does not reorder the statements
+	require.Equal(t, "CVE-2023-5678", string(doc.Statements[0].Vulnerability.Name))
+
+	// Statements edited after signing are not covered by the signature
+	for _, edit := range []func(*VEX){
+		func(d *VEX) { d.Statements[1].ImpactStatement = "The vulnerable code is not shipped" },
+		func(d *VEX) { d.Statements[0].ActionStatement = "Nothing to do" },
+	} {
+		edited := doc.Clone()
+		edit(edited)
+		violations := policy.CheckDocument(edited, sigs)
+		require.Len(t, violations, 2)
+		for _, v := range violations {
+			require.ErrorIs(t, v.Err, ErrNotEnoughSignatures)
+		}
+	}
+}
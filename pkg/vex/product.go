@@ -44,6 +44,18 @@ func (p *Product) Matches(identifier, subIdentifier string) bool {
 	return false
 }
 
+// Clone returns a deep copy of the product and its subcomponents.
+func (p *Product) Clone() Product {
+	ret := Product{Component: p.Component.Clone()}
+	if p.Subcomponents != nil {
+		ret.Subcomponents = make([]Subcomponent, len(p.Subcomponents))
+		for i := range p.Subcomponents {
+			ret.Subcomponents[i] = Subcomponent{Component: p.Subcomponents[i].Component.Clone()}
+		}
+	}
+	return ret
+}
+
 type (
 	IdentifierLocator string
 	IdentifierType    string
@@ -98,3 +98,28 @@ func TestProductMatches(t *testing.T) {
 		require.Equal(t, tc.mustMach, tc.sut.Matches(tc.product, tc.subcomponent), fmt.Sprintf("failed: %s", testCase))
 	}
 }
+
+func TestProductClone(t *testing.T) {
+	p := Product{
+		Component: Component{
+			ID:          "pkg:oci/app",
+			Hashes:      map[Algorithm]Hash{SHA256: "abc"},
+			Identifiers: map[IdentifierType]string{PURL: "pkg:oci/app"},
+		},
+		Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:apk/wolfi/openssl"}}},
+	}
+
+	c := p.Clone()
+	require.Equal(t, p, c)
+
+	c.Hashes[SHA256] = "changed"
+	c.Identifiers[PURL] = "changed"
+	c.Subcomponents[0].ID = "changed"
+	require.Equal(t, Hash("abc"), p.Hashes[SHA256])
+	require.Equal(t, "pkg:oci/app", p.Identifiers[PURL])
+	require.Equal(t, "pkg:apk/wolfi/openssl", p.Subcomponents[0].ID)
+
+	c = (&Product{}).Clone()
+	require.Nil(t, c.Subcomponents)
+	require.Nil(t, c.Hashes)
+}
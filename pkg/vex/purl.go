@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// InvalidPurl records a malformed purl found in a document.
+type InvalidPurl struct {
+	// Path is a JSON pointer to the field holding the purl
+	Path string
+
+	// Purl is the offending string
+	Purl string
+
+	// Err is the parsing error
+	Err error
+}
+
+// InvalidPurlsError is returned when a document contains malformed purls in
+// its product or subcomponent identifiers.
+type InvalidPurlsError struct {
+	Purls []InvalidPurl
+}
+
+func (e *InvalidPurlsError) Error() string {
+	msgs := make([]string, 0, len(e.Purls))
+	for _, p := range e.Purls {
+		msgs = append(msgs, fmt.Sprintf("%s: %q: %v", p.Path, p.Purl, p.Err))
+	}
+	return fmt.Sprintf("document has %d invalid purls: %s", len(e.Purls), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns a *ValidationError for each invalid purl.
+func (e *InvalidPurlsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Purls))
+	for _, p := range e.Purls {
+		err := p.Err
+		if !errors.Is(err, ErrMalformedPurl) {
+			err = fmt.Errorf("%w: %w", ErrMalformedPurl, err)
+		}
+		errs = append(errs, &ValidationError{Path: p.Path, Err: fmt.Errorf("%q: %w", p.Purl, err)})
+	}
+	return errs
+}
+
+// ValidatePurl returns an error if the string is not a valid package URL or
+// if it has more than MaxPurlQualifiers qualifiers.
+func ValidatePurl(purl string) error {
+	if n := purlQualifierCount(purl); n > MaxPurlQualifiers {
+		return fmt.Errorf("%w: purl has %d qualifiers, the maximum is %d", ErrMalformedPurl, n, MaxPurlQualifiers)
+	}
+	if _, err := packageurl.FromString(purl); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedPurl, err)
+	}
+	return nil
+}
+
+// ValidatePurls checks all the purls used to identify products and
+// subcomponents in the document. Component IDs are considered purls when they
+// start with "pkg:". If any purl is malformed, an *InvalidPurlsError listing
+// all of them is returned.
+func (vexDoc *VEX) ValidatePurls() error {
+	invalid := []InvalidPurl{}
+	for i := range vexDoc.Statements {
+		for j := range vexDoc.Statements[i].Products {
+			p := &vexDoc.Statements[i].Products[j]
+			path := fmt.Sprintf("/statements/%d/products/%d", i, j)
+			invalid = append(invalid, invalidComponentPurls(path, &p.Component)...)
+			for k := range p.Subcomponents {
+				invalid = append(invalid, invalidComponentPurls(
+					fmt.Sprintf("%s/subcomponents/%d", path, k), &p.Subcomponents[k].Component,
+				)...)
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		return &InvalidPurlsError{Purls: invalid}
+	}
+	return nil
+}
+
+// invalidComponentPurls returns the malformed purls in a component
+func invalidComponentPurls(path string, c *Component) []InvalidPurl {
+	ret := []InvalidPurl{}
+	if strings.HasPrefix(c.ID, "pkg:") {
+		if err := ValidatePurl(c.ID); err != nil {
+			ret = append(ret, InvalidPurl{Path: path + "/@id", Purl: c.ID, Err: err})
+		}
+	}
+	if purl, ok := c.Identifiers[PURL]; ok {
+		if err := ValidatePurl(purl); err != nil {
+			ret = append(ret, InvalidPurl{Path: path + "/identifiers/purl", Purl: purl, Err: err})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"slices"
+	"time"
+)
+
+// StatementQuery filters the statements of a document. Queries are built by
+// chaining filters, all of which must match for a statement to be returned:
+//
+//	stmts := doc.Query().
+//		Vulnerability("CVE-2023-1234").
+//		Product("pkg:oci/app@sha256%3A...").
+//		Status(vex.StatusAffected).
+//		Since(t).
+//		Statements()
+type StatementQuery struct {
+	doc          *VEX
+	product      string
+	subcomponent string
+	filters      []func(*Statement, time.Time) bool
+}
+
+// Query returns a new query over the statements of the document.
+func (vexDoc *VEX) Query() *StatementQuery {
+	return &StatementQuery{doc: vexDoc}
+}
+
+// Vulnerability keeps the statements about a vulnerability, matched by its
+// name, IRI or aliases.
+func (q *StatementQuery) Vulnerability(id string) *StatementQuery {
+	q.filters = append(q.filters, func(s *Statement, _ time.Time) bool {
+		return s.Vulnerability.Matches(id)
+	})
+	return q
+}
+
+// Product keeps the statements about a product. Purls match as in
+// Component.Matches: a generic purl in a statement matches a more specific
+// one in the query.
+func (q *StatementQuery) Product(id string) *StatementQuery {
+	q.product = id
+	return q
+}
+
+// Subcomponent keeps the statements about a subcomponent. Statements about
+// products that list no subcomponents apply to all of them and match too.
+func (q *StatementQuery) Subcomponent(id string) *StatementQuery {
+	q.subcomponent = id
+	return q
+}
+
+// Status keeps the statements with any of the statuses.
+func (q *StatementQuery) Status(statuses ...Status) *StatementQuery {
+	q.filters = append(q.filters, func(s *Statement, _ time.Time) bool {
+		return slices.Contains(statuses, s.Status)
+	})
+	return q
+}
+
+// Justification keeps the statements with any of the justifications.
+func (q *StatementQuery) Justification(justifications ...Justification) *StatementQuery {
+	q.filters = append(q.filters, func(s *Statement, _ time.Time) bool {
+		return slices.Contains(justifications, s.Justification)
+	})
+	return q
+}
+
+// Since keeps the statements issued at or after t. Statements without a
+// timestamp take the one of the document.
+func (q *StatementQuery) Since(t time.Time) *StatementQuery {
+	q.filters = append(q.filters, func(_ *Statement, st time.Time) bool {
+		return !st.Before(t)
+	})
+	return q
+}
+
+// Until keeps the statements issued at or before t. Statements without a
+// timestamp take the one of the document.
+func (q *StatementQuery) Until(t time.Time) *StatementQuery {
+	q.filters = append(q.filters, func(_ *Statement, st time.Time) bool {
+		return !st.After(t)
+	})
+	return q
+}
+
+// Where keeps the statements for which fn returns true.
+func (q *StatementQuery) Where(fn func(*Statement) bool) *StatementQuery {
+	q.filters = append(q.filters, func(s *Statement, _ time.Time) bool {
+		return fn(s)
+	})
+	return q
+}
+
+// Statements returns the statements matching the query ordered according to
+// the VEX history. The document is not modified.
+func (q *StatementQuery) Statements() []Statement {
+	var docTime time.Time
+	if q.doc.Timestamp != nil {
+		docTime = *q.doc.Timestamp
+	}
+
+	ret := []Statement{}
+	for i := range q.doc.Statements {
+		if q.matches(&q.doc.Statements[i]) {
+			ret = append(ret, q.doc.Statements[i])
+		}
+	}
+	SortStatements(ret, docTime)
+	return ret
+}
+
+// Latest returns the most recent statement matching the query or nil if
+// none matches. Statements without a timestamp take the one of the document.
+func (q *StatementQuery) Latest() *Statement {
+	var docTime time.Time
+	if q.doc.Timestamp != nil {
+		docTime = *q.doc.Timestamp
+	}
+	var latest *Statement
+	var latestTime time.Time
+	stmts := q.Statements()
+	for i := range stmts {
+		st := docTime
+		if stmts[i].Timestamp != nil && !stmts[i].Timestamp.IsZero() {
+			st = *stmts[i].Timestamp
+		}
+		if latest == nil || !st.Before(latestTime) {
+			latest, latestTime = &stmts[i], st
+		}
+	}
+	return latest
+}
+
+// Count returns the number of statements matching the query.
+func (q *StatementQuery) Count() int {
+	return len(q.Statements())
+}
+
+// matches returns true if the statement passes all the query filters.
+func (q *StatementQuery) matches(s *Statement) bool {
+	if (q.product != "" || q.subcomponent != "") && !q.matchesProduct(s) {
+		return false
+	}
+	st := time.Time{}
+	if q.doc.Timestamp != nil {
+		st = *q.doc.Timestamp
+	}
+	if s.Timestamp != nil && !s.Timestamp.IsZero() {
+		st = *s.Timestamp
+	}
+	for _, f := range q.filters {
+		if !f(s, st) {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *StatementQuery) matchesProduct(s *Statement) bool {
+	for i := range s.Products {
+		p := &s.Products[i]
+		if q.product != "" && !p.Component.Matches(q.product) {
+			continue
+		}
+		if q.subcomponent == "" || len(p.Subcomponents) == 0 {
+			return true
+		}
+		for j := range p.Subcomponents {
+			if p.Subcomponents[j].Matches(q.subcomponent) {
+				return true
+			}
+		}
+	}
+	return false
+}
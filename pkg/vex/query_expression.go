@@ -0,0 +1,416 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QueryExpression is a parsed statement query in the text form accepted by
+// VEX stores over the wire. Parsing and evaluating expressions here keeps
+// all implementations in agreement about their semantics.
+//
+// An expression combines terms with AND, OR, NOT and parentheses. AND binds
+// tighter than OR and keywords are case insensitive:
+//
+//	status=affected AND product~pkg:oci/*
+//	(vulnerability=CVE-2023-1234 OR vulnerability=CVE-2023-5678) AND NOT status=fixed
+//	timestamp>=2023-06-01 AND justification!=component_not_present
+//
+// A term is a field, an operator and a value. Values run until the next
+// space or closing parenthesis and may be double quoted to include them.
+// The fields are:
+//
+//   - status, justification: compared to the statement field.
+//   - vulnerability: matches the vulnerability name, IRI or aliases.
+//   - product, subcomponent: match any of the statement products or their
+//     subcomponents as in Component.Matches.
+//   - timestamp: compared to the statement timestamp, or the document one
+//     when the statement has none. Values are RFC 3339 times or dates.
+//
+// The operators are = and != for all fields, ~ and !~ to match a glob where
+// * matches any sequence of characters, and <, <=, > and >= for timestamps.
+type QueryExpression struct {
+	root queryNode
+}
+
+type queryNode interface {
+	eval(s *Statement, t time.Time) bool
+	String() string
+}
+
+type queryAnd struct{ left, right queryNode }
+
+func (n *queryAnd) eval(s *Statement, t time.Time) bool {
+	return n.left.eval(s, t) && n.right.eval(s, t)
+}
+
+func (n *queryAnd) String() string {
+	return "(" + n.left.String() + " AND " + n.right.String() + ")"
+}
+
+type queryOr struct{ left, right queryNode }
+
+func (n *queryOr) eval(s *Statement, t time.Time) bool {
+	return n.left.eval(s, t) || n.right.eval(s, t)
+}
+
+func (n *queryOr) String() string {
+	return "(" + n.left.String() + " OR " + n.right.String() + ")"
+}
+
+type queryNot struct{ node queryNode }
+
+func (n *queryNot) eval(s *Statement, t time.Time) bool {
+	return !n.node.eval(s, t)
+}
+
+func (n *queryNot) String() string {
+	return "NOT " + n.node.String()
+}
+
+type queryTerm struct {
+	field string
+	op    string
+	value string
+	glob  *regexp.Regexp
+	time  time.Time
+}
+
+func (n *queryTerm) String() string {
+	return n.field + n.op + strconv.Quote(n.value)
+}
+
+func (n *queryTerm) eval(s *Statement, t time.Time) bool {
+	negated := n.op == "!=" || n.op == "!~"
+	var ret bool
+	switch n.field {
+	case "status":
+		ret = n.matchString(string(s.Status))
+	case "justification":
+		ret = n.matchString(string(s.Justification))
+	case "vulnerability":
+		ret = n.matchVulnerability(&s.Vulnerability)
+	case "product":
+		for i := range s.Products {
+			if n.matchComponent(&s.Products[i].Component) {
+				ret = true
+				break
+			}
+		}
+	case "subcomponent":
+		for i := range s.Products {
+			for j := range s.Products[i].Subcomponents {
+				if n.matchComponent(&s.Products[i].Subcomponents[j].Component) {
+					ret = true
+					break
+				}
+			}
+		}
+	case "timestamp":
+		if s.Timestamp != nil && !s.Timestamp.IsZero() {
+			t = *s.Timestamp
+		}
+		switch n.op {
+		case "=":
+			return t.Equal(n.time)
+		case "!=":
+			return !t.Equal(n.time)
+		case "<":
+			return t.Before(n.time)
+		case "<=":
+			return !t.After(n.time)
+		case ">":
+			return t.After(n.time)
+		case ">=":
+			return !t.Before(n.time)
+		}
+	}
+	if negated {
+		return !ret
+	}
+	return ret
+}
+
+func (n *queryTerm) matchString(s string) bool {
+	if n.glob != nil {
+		return n.glob.MatchString(s)
+	}
+	return s == n.value
+}
+
+func (n *queryTerm) matchVulnerability(v *Vulnerability) bool {
+	if n.glob == nil {
+		return v.Matches(n.value)
+	}
+	if v.ID != "" && n.glob.MatchString(v.ID) {
+		return true
+	}
+	for _, id := range vulnerabilityIDs(v) {
+		if n.glob.MatchString(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *queryTerm) matchComponent(c *Component) bool {
+	if n.glob == nil {
+		return c.Matches(n.value)
+	}
+	if c.ID != "" && n.glob.MatchString(c.ID) {
+		return true
+	}
+	for _, id := range c.Identifiers {
+		if n.glob.MatchString(id) {
+			return true
+		}
+	}
+	for _, h := range c.Hashes {
+		if n.glob.MatchString(string(h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseQueryExpression parses a query expression.
+func ParseQueryExpression(expr string) (*QueryExpression, error) {
+	p := &queryParser{input: expr}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return &QueryExpression{root: root}, nil
+}
+
+// Matches returns true if the statement matches the expression. docTime is
+// used as the timestamp of statements that don't have one.
+func (e *QueryExpression) Matches(s *Statement, docTime time.Time) bool {
+	return e.root.eval(s, docTime)
+}
+
+// String returns the expression in a normalized form with explicit
+// parentheses and quoted values, suitable to send over the wire.
+func (e *QueryExpression) String() string {
+	s := e.root.String()
+	switch e.root.(type) {
+	case *queryAnd, *queryOr:
+		// Drop the parentheses around the whole expression
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Expression keeps the statements matching a query expression.
+func (q *StatementQuery) Expression(e *QueryExpression) *StatementQuery {
+	q.filters = append(q.filters, e.root.eval)
+	return q
+}
+
+var (
+	queryFields = map[string]bool{
+		"status": true, "justification": true, "vulnerability": true,
+		"product": true, "subcomponent": true, "timestamp": true,
+	}
+
+	// queryOperators is sorted so two character operators are tried first
+	queryOperators = []string{"!=", "!~", "<=", ">=", "=", "~", "<", ">"}
+)
+
+type queryParser struct {
+	input string
+	pos   int
+}
+
+func (p *queryParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("parsing query at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *queryParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// keyword consumes kw if it is the next word in the input.
+func (p *queryParser) keyword(kw string) bool {
+	p.skipSpace()
+	end := p.pos + len(kw)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], kw) {
+		return false
+	}
+	if end < len(p.input) && !unicode.IsSpace(rune(p.input[end])) && p.input[end] != '(' {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (queryNode, error) {
+	if p.keyword("NOT") {
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &queryNot{node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return node, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *queryParser) parseTerm() (queryNode, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || p.input[p.pos] == '_') {
+		p.pos++
+	}
+	field := strings.ToLower(p.input[start:p.pos])
+	if field == "" {
+		return nil, p.errorf("expected a field")
+	}
+	if !queryFields[field] {
+		p.pos = start
+		return nil, p.errorf("unknown field %q", field)
+	}
+
+	p.skipSpace()
+	op := ""
+	for _, o := range queryOperators {
+		if strings.HasPrefix(p.input[p.pos:], o) {
+			op = o
+			break
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("expected an operator after %s", field)
+	}
+	p.pos += len(op)
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	term := &queryTerm{field: field, op: op, value: value}
+
+	switch {
+	case field == "timestamp":
+		if op == "~" || op == "!~" {
+			return nil, p.errorf("operator %s is not supported for timestamps", op)
+		}
+		if term.time, err = parseQueryTime(value); err != nil {
+			return nil, p.errorf("invalid timestamp %q", value)
+		}
+	case op == "<" || op == "<=" || op == ">" || op == ">=":
+		return nil, p.errorf("operator %s is only supported for timestamps", op)
+	case op == "~" || op == "!~":
+		term.glob = globRegexp(value)
+	}
+	return term, nil
+}
+
+func (p *queryParser) parseValue() (string, error) {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		// Find the closing quote, skipping escaped characters
+		end := p.pos + 1
+		for ; end < len(p.input) && p.input[end] != '"'; end++ {
+			if p.input[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(p.input) {
+			return "", p.errorf("unterminated string")
+		}
+		value, err := strconv.Unquote(p.input[p.pos : end+1])
+		if err != nil {
+			return "", p.errorf("invalid string: %v", err)
+		}
+		p.pos = end + 1
+		return value, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && !unicode.IsSpace(rune(p.input[p.pos])) && p.input[p.pos] != ')' {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("expected a value")
+	}
+	return p.input[start:p.pos], nil
+}
+
+func parseQueryTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, s)
+}
+
+// globRegexp returns a regular expression matching a glob where * matches
+// any sequence of characters.
+func globRegexp(glob string) *regexp.Regexp {
+	parts := strings.Split(glob, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
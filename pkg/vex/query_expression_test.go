@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryExpression(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.Timestamp = &t1
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111", Aliases: []VulnerabilityID{"GHSA-aaaa-bbbb-cccc"}},
+			Products: []Product{{
+				Component:     Component{ID: "pkg:oci/app@sha256%3Aabc"},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/example.com/lib@v1.0.0"}}},
+			}},
+			Status:          StatusAffected,
+			ActionStatement: "Upgrade",
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/openssl@3.0.8"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+			Timestamp:     &t2,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-3333"},
+			Products:      []Product{{Component: Component{ID: "https://example.com/product"}}},
+			Status:        StatusFixed,
+		},
+	}
+
+	for expr, expected := range map[string][]string{
+		"status=affected":                                               {"CVE-2023-1111"},
+		"status != affected":                                            {"CVE-2023-2222", "CVE-2023-3333"},
+		"product~pkg:oci/*":                                             {"CVE-2023-1111"},
+		"status=affected AND product~pkg:oci/*":                         {"CVE-2023-1111"},
+		"product!~pkg:*":                                                {"CVE-2023-3333"},
+		"product=pkg:apk/wolfi/openssl@3.0.8?arch=x86_64":               {"CVE-2023-2222"},
+		`product="https://example.com/product"`:                         {"CVE-2023-3333"},
+		"subcomponent~pkg:golang/example.com/*":                         {"CVE-2023-1111"},
+		"vulnerability=GHSA-aaaa-bbbb-cccc":                             {"CVE-2023-1111"},
+		"vulnerability~GHSA-*":                                          {"CVE-2023-1111"},
+		"justification=component_not_present":                           {"CVE-2023-2222"},
+		"timestamp>=2023-06-15":                                         {"CVE-2023-2222"},
+		"timestamp<2023-06-15T00:00:00Z":                                {"CVE-2023-1111", "CVE-2023-3333"},
+		"status=fixed or STATUS=affected and NOT product~*":             {"CVE-2023-3333"},
+		"(status=fixed OR status=affected) AND NOT (product~pkg:oci/*)": {"CVE-2023-3333"},
+		"NOT(status=fixed)":                                             {"CVE-2023-1111", "CVE-2023-2222"},
+	} {
+		e, err := ParseQueryExpression(expr)
+		require.NoError(t, err, expr)
+
+		ids := []string{}
+		for _, s := range doc.Query().Expression(e).Statements() {
+			ids = append(ids, string(s.Vulnerability.Name))
+		}
+		require.Equal(t, expected, ids, expr)
+
+		// The normalized form parses to the same expression
+		e2, err := ParseQueryExpression(e.String())
+		require.NoError(t, err, e.String())
+		require.Equal(t, e.String(), e2.String())
+	}
+}
+
+func TestQueryExpressionString(t *testing.T) {
+	e, err := ParseQueryExpression(`status=affected and (product~pkg:oci/* or vulnerability="CVE 1")`)
+	require.NoError(t, err)
+	require.Equal(t, `status="affected" AND (product~"pkg:oci/*" OR vulnerability="CVE 1")`, e.String())
+
+	e, err = ParseQueryExpression(`NOT (status=fixed OR status=affected)`)
+	require.NoError(t, err)
+	require.Equal(t, `NOT (status="fixed" OR status="affected")`, e.String())
+}
+
+func TestQueryExpressionErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"color=red",
+		"status",
+		"status=",
+		"status>affected",
+		"timestamp~2023*",
+		"timestamp>yesterday",
+		"(status=fixed",
+		"status=fixed)",
+		"status=fixed AND",
+		`status="fixed`,
+	} {
+		_, err := ParseQueryExpression(expr)
+		require.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+	doc := New()
+	doc.Timestamp = &t1
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111", Aliases: []VulnerabilityID{"GHSA-aaaa-bbbb-cccc"}},
+			Products: []Product{{
+				Component:     Component{ID: "pkg:oci/app@sha256%3Aabc"},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/example.com/lib@v1.0.0"}}},
+			}},
+			Status: StatusUnderInvestigation,
+		},
+		{
+			Vulnerability:   Vulnerability{Name: "CVE-2023-1111"},
+			Products:        []Product{{Component: Component{ID: "pkg:oci/app@sha256%3Aabc"}}},
+			Status:          StatusAffected,
+			ActionStatement: "Upgrade",
+			Timestamp:       &t3,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/other"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+			Timestamp:     &t2,
+		},
+	}
+
+	require.Equal(t, 3, doc.Query().Count())
+	require.Equal(t, 1, doc.Query().Vulnerability("GHSA-aaaa-bbbb-cccc").Count())
+	require.Equal(t, 2, doc.Query().Vulnerability("CVE-2023-1111").Count())
+	require.Equal(t, 2, doc.Query().Product("pkg:oci/app@sha256%3Aabc?arch=amd64").Count())
+	require.Equal(t, 1, doc.Query().Product("pkg:oci/other@v1").Count())
+	require.Equal(t, 1, doc.Query().Product("pkg:oci/app@sha256%3Aabc").Subcomponent("pkg:golang/example.com/other@v1.0.0").Count())
+	require.Equal(t, 2, doc.Query().Product("pkg:oci/app@sha256%3Aabc").Subcomponent("pkg:golang/example.com/lib@v1.0.0").Count())
+	require.Equal(t, 1, doc.Query().Status(StatusAffected, StatusFixed).Count())
+	require.Equal(t, 1, doc.Query().Justification(ComponentNotPresent).Count())
+	require.Equal(t, 2, doc.Query().Since(t2).Count())
+	require.Equal(t, 2, doc.Query().Until(t2).Count())
+	require.Equal(t, 1, doc.Query().Where(func(s *Statement) bool { return s.ActionStatement != "" }).Count())
+
+	stmts := doc.Query().Vulnerability("CVE-2023-1111").Product("pkg:oci/app@sha256%3Aabc").Statements()
+	require.Len(t, stmts, 2)
+	require.Equal(t, StatusUnderInvestigation, stmts[0].Status)
+	require.Equal(t, StatusAffected, stmts[1].Status)
+
+	require.Equal(t, StatusAffected, doc.Query().Latest().Status)
+	require.Equal(t, StatusNotAffected, doc.Query().Until(t2).Latest().Status)
+	require.Nil(t, doc.Query().Vulnerability("CVE-2000-0000").Latest())
+
+	// The document is not modified
+	require.Equal(t, StatusUnderInvestigation, doc.Statements[0].Status)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ReferenceType tells what a reference points to.
+type ReferenceType string
+
+// Reference types
+const (
+	// ReferenceAdvisory is a security advisory, such as the vendor's
+	ReferenceAdvisory ReferenceType = "advisory"
+
+	// ReferenceFix is the change fixing the vulnerability, such as a commit
+	// or a release
+	ReferenceFix ReferenceType = "fix"
+
+	// ReferenceArticle is an article or blog post about the vulnerability
+	ReferenceArticle ReferenceType = "article"
+
+	// ReferenceReport is the report of the vulnerability, such as an issue
+	ReferenceReport ReferenceType = "report"
+)
+
+// ReferenceTypes returns the valid reference types.
+func ReferenceTypes() []string {
+	return []string{
+		string(ReferenceAdvisory),
+		string(ReferenceFix),
+		string(ReferenceArticle),
+		string(ReferenceReport),
+	}
+}
+
+// Valid returns true if the reference type is one of the defined types.
+func (t ReferenceType) Valid() bool {
+	switch t {
+	case ReferenceAdvisory, ReferenceFix, ReferenceArticle, ReferenceReport:
+		return true
+	default:
+		return false
+	}
+}
+
+// Reference is a link to more information about a vulnerability or a
+// statement, typed so consumers can find, for example, the fix of a
+// vulnerability without guessing from the URL.
+type Reference struct {
+	// URL is the location of the referenced resource
+	URL string `json:"url"`
+
+	// Type is what the reference points to
+	Type ReferenceType `json:"type"`
+}
+
+// UnmarshalJSON reads a reference. References recorded as plain URL strings
+// are read as articles.
+func (r *Reference) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*r = Reference{URL: s, Type: ReferenceArticle}
+		return nil
+	}
+	type reference Reference
+	return json.Unmarshal(data, (*reference)(r))
+}
+
+// Validate checks that the reference has an absolute http or https URL and
+// a valid type.
+func (r *Reference) Validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return &ValidationError{Path: "/url", Err: fmt.Errorf("invalid reference URL %q", r.URL)}
+	}
+	if !r.Type.Valid() {
+		return &ValidationError{
+			Path: "/type",
+			Err:  fmt.Errorf("invalid reference type %q, must be one of [%s]", r.Type, strings.Join(ReferenceTypes(), ", ")),
+		}
+	}
+	return nil
+}
+
+// AddReference validates a reference and adds it to the vulnerability
+// unless it is already there.
+func (v *Vulnerability) AddReference(t ReferenceType, u string) error {
+	refs, err := addReference(v.References, Reference{URL: u, Type: t})
+	if err != nil {
+		return err
+	}
+	v.References = refs
+	return nil
+}
+
+// AddReference validates a reference and adds it to the statement unless it
+// is already there.
+func (stmt *Statement) AddReference(t ReferenceType, u string) error {
+	refs, err := addReference(stmt.References, Reference{URL: u, Type: t})
+	if err != nil {
+		return err
+	}
+	stmt.References = refs
+	return nil
+}
+
+// ReferencesOfType returns the references of the statement and its
+// vulnerability of a type, those of the statement first. For example, the
+// fixes of a vulnerability:
+//
+//	fixes := stmt.ReferencesOfType(vex.ReferenceFix)
+func (stmt *Statement) ReferencesOfType(t ReferenceType) []Reference {
+	ret := []Reference{}
+	for _, refs := range [][]Reference{stmt.References, stmt.Vulnerability.References} {
+		for _, r := range refs {
+			if r.Type == t {
+				ret = append(ret, r)
+			}
+		}
+	}
+	return ret
+}
+
+func addReference(refs []Reference, r Reference) ([]Reference, error) {
+	if err := r.Validate(); err != nil {
+		return refs, err
+	}
+	for _, existing := range refs {
+		if existing == r {
+			return refs, nil
+		}
+	}
+	return append(refs, r), nil
+}
+
+// validateReferences validates a list of references, returning a
+// *ValidationError pointing to the first invalid one.
+func validateReferences(refs []Reference) error {
+	for i := range refs {
+		if err := refs[i].Validate(); err != nil {
+			return withPathPrefix(fmt.Sprintf("/references/%d", i), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferences(t *testing.T) {
+	stmt := Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusFixed,
+	}
+	require.NoError(t, stmt.Vulnerability.AddReference(ReferenceAdvisory, "https://example.com/advisories/1"))
+	require.NoError(t, stmt.Vulnerability.AddReference(ReferenceFix, "https://github.com/example/lib/commit/abc"))
+	require.NoError(t, stmt.AddReference(ReferenceFix, "https://example.com/releases/app-1.2.3"))
+	require.NoError(t, stmt.AddReference(ReferenceFix, "https://example.com/releases/app-1.2.3"))
+	require.Len(t, stmt.References, 1)
+
+	require.Error(t, stmt.AddReference(ReferenceFix, "example.com/releases"))
+	require.Error(t, stmt.AddReference(ReferenceFix, "ftp://example.com/releases"))
+	require.Error(t, stmt.AddReference("patch", "https://example.com/releases"))
+	require.Len(t, stmt.References, 1)
+
+	require.Equal(t, []Reference{
+		{URL: "https://example.com/releases/app-1.2.3", Type: ReferenceFix},
+		{URL: "https://github.com/example/lib/commit/abc", Type: ReferenceFix},
+	}, stmt.ReferencesOfType(ReferenceFix))
+	require.Len(t, stmt.ReferencesOfType(ReferenceAdvisory), 1)
+	require.Empty(t, stmt.ReferencesOfType(ReferenceReport))
+	require.NoError(t, stmt.Validate())
+
+	doc := New(WithID("https://example.com/vex/1"))
+	doc.Statements = []Statement{stmt}
+	data, err := json.Marshal(&doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	require.Equal(t, stmt.References, parsed.Statements[0].References)
+	require.Equal(t, stmt.Vulnerability.References, parsed.Statements[0].Vulnerability.References)
+
+	// Invalid references fail validation
+	stmt.Vulnerability.References = append(stmt.Vulnerability.References, Reference{URL: "https://example.com"})
+	var verr *ValidationError
+	require.ErrorAs(t, stmt.Validate(), &verr)
+	require.Equal(t, "/vulnerability/references/2/type", verr.Path)
+}
+
+func TestReferenceUnmarshalString(t *testing.T) {
+	v := Vulnerability{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "CVE-2023-1111",
+		"references": ["https://example.com/post", {"url": "https://example.com/fix", "type": "fix"}]
+	}`), &v))
+	require.Equal(t, []Reference{
+		{URL: "https://example.com/post", Type: ReferenceArticle},
+		{URL: "https://example.com/fix", Type: ReferenceFix},
+	}, v.References)
+}
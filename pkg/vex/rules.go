@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ValidationRule is a custom check that runs alongside the built-in schema
+// validation when calling (*VEX).Validate. Organizations can use rules to
+// enforce house policies such as requiring authors from an approved email
+// domain or restricting products to OCI purls.
+type ValidationRule interface {
+	// ID returns a unique, stable identifier for the rule
+	ID() string
+
+	// Validate returns an error if the document violates the rule. Return
+	// a *ValidationError to point to the offending field.
+	Validate(*VEX) error
+}
+
+// RuleViolation is returned by (*VEX).Validate when a document violates a
+// registered ValidationRule.
+type RuleViolation struct {
+	RuleID string
+	Err    error
+}
+
+func (e *RuleViolation) Error() string {
+	return fmt.Sprintf("rule %s: %v", e.RuleID, e.Err)
+}
+
+func (e *RuleViolation) Unwrap() error {
+	return e.Err
+}
+
+// validationRuleFunc wraps a function into a ValidationRule
+type validationRuleFunc struct {
+	id string
+	fn func(*VEX) error
+}
+
+func (r *validationRuleFunc) ID() string { return r.id }
+
+func (r *validationRuleFunc) Validate(doc *VEX) error { return r.fn(doc) }
+
+// NewValidationRule returns a ValidationRule from an identifier and a function.
+func NewValidationRule(id string, fn func(*VEX) error) ValidationRule {
+	return &validationRuleFunc{id: id, fn: fn}
+}
+
+var (
+	validationRulesMu sync.RWMutex
+	validationRules   = map[string]ValidationRule{}
+)
+
+// RegisterValidationRule adds a rule to the registry of custom rules run by
+// (*VEX).Validate. It returns an error if a rule with the same ID is already
+// registered.
+func RegisterValidationRule(rule ValidationRule) error {
+	validationRulesMu.Lock()
+	defer validationRulesMu.Unlock()
+	if _, ok := validationRules[rule.ID()]; ok {
+		return fmt.Errorf("validation rule %q is already registered", rule.ID())
+	}
+	validationRules[rule.ID()] = rule
+	return nil
+}
+
+// UnregisterValidationRule removes a rule from the registry.
+func UnregisterValidationRule(id string) {
+	validationRulesMu.Lock()
+	defer validationRulesMu.Unlock()
+	delete(validationRules, id)
+}
+
+// ValidationRules returns the registered custom rules sorted by ID.
+func ValidationRules() []ValidationRule {
+	validationRulesMu.RLock()
+	defer validationRulesMu.RUnlock()
+	rules := make([]ValidationRule, 0, len(validationRules))
+	for _, r := range validationRules {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID() < rules[j].ID() })
+	return rules
+}
+
+// runValidationRules runs the registered rules on the document and returns
+// the violations found.
+func runValidationRules(doc *VEX) []error {
+	errs := []error{}
+	for _, rule := range ValidationRules() {
+		if err := rule.Validate(doc); err != nil {
+			errs = append(errs, &RuleViolation{RuleID: rule.ID(), Err: err})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationRules(t *testing.T) {
+	authorRule := NewValidationRule("approved-author", func(doc *VEX) error {
+		if !strings.HasSuffix(doc.Author, "@example.com") {
+			return &ValidationError{Path: "/author", Err: fmt.Errorf("author %q is not from example.com", doc.Author)}
+		}
+		return nil
+	})
+	ociRule := NewValidationRule("oci-products", func(doc *VEX) error {
+		for i := range doc.Statements {
+			for j, p := range doc.Statements[i].Products {
+				if !strings.HasPrefix(p.ID, "pkg:oci/") {
+					return &ValidationError{
+						Path: fmt.Sprintf("/statements/%d/products/%d/@id", i, j),
+						Err:  errors.New("products must be OCI purls"),
+					}
+				}
+			}
+		}
+		return nil
+	})
+
+	for _, r := range []ValidationRule{authorRule, ociRule} {
+		require.NoError(t, RegisterValidationRule(r))
+		defer UnregisterValidationRule(r.ID())
+	}
+	require.Error(t, RegisterValidationRule(authorRule))
+	require.Len(t, ValidationRules(), 2)
+	require.Equal(t, "approved-author", ValidationRules()[0].ID())
+
+	ts := time.Date(2023, 4, 17, 20, 34, 58, 0, time.UTC)
+	doc := New()
+	doc.ID = "https://example.com/vex-1"
+	doc.Author = "john@example.org"
+	doc.Timestamp = &ts
+	doc.Statements = append(doc.Statements, Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+		Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/bash@1.0.0"}}},
+		Status:        StatusFixed,
+	})
+
+	err := doc.Validate()
+	require.Error(t, err)
+	var schemaErr *SchemaValidationError
+	require.False(t, errors.As(err, &schemaErr))
+	var violation *RuleViolation
+	require.True(t, errors.As(err, &violation))
+	require.Equal(t, "approved-author", violation.RuleID)
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "/author", verr.Path)
+	require.Contains(t, err.Error(), "oci-products")
+
+	doc.Author = "john@example.com"
+	doc.Statements[0].Products[0].ID = "pkg:oci/bash@sha256:47fed8868b46b060efb8699dc40e981a0c785650223e03602d8c4493fc75b68c"
+	require.NoError(t, doc.Validate())
+}
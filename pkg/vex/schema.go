@@ -0,0 +1,541 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jsonSchema is the OpenVEX JSON schema for the current spec version.
+//
+//go:embed schema/openvex_json_schema_0.2.0.json
+var jsonSchema []byte
+
+// jsonSchemaExtensions is the overlay adding the fields supported by the
+// library, such as transparency log entries, EPSS scores or fix data, to the
+// OpenVEX JSON schema of the current spec version.
+//
+//go:embed schema/openvex_extensions_0.2.0.json
+var jsonSchemaExtensions []byte
+
+// jsonSchema001 is the OpenVEX JSON schema for v0.0.1 of the spec.
+//
+//go:embed schema/openvex_json_schema_0.0.1.json
+var jsonSchema001 []byte
+
+// embeddedSchema is a JSON schema parsed on first use. The overlay, if any,
+// is merged into the schema before validating documents but is not part of
+// the published schema returned by JSONSchemaFor.
+type embeddedSchema struct {
+	data    []byte
+	overlay []byte
+	once    sync.Once
+	parsed  map[string]any
+	err     error
+}
+
+// schemas are the JSON schemas of each spec version, the validation
+// profiles used by ValidateVersion.
+var schemas = map[string]*embeddedSchema{
+	"0.0.1":     {data: jsonSchema001},
+	SpecVersion: {data: jsonSchema, overlay: jsonSchemaExtensions},
+}
+
+// JSONSchema returns a copy of the OpenVEX JSON schema embedded in the
+// package. It is the published schema of the spec, the validation functions
+// also accept the fields the library adds to documents.
+func JSONSchema() []byte {
+	return bytes.Clone(jsonSchema)
+}
+
+// JSONSchemaFor returns a copy of the embedded JSON schema of a version of
+// the OpenVEX specification.
+func JSONSchemaFor(version string) ([]byte, error) {
+	s, ok := schemas[strings.TrimPrefix(version, "v")]
+	if !ok {
+		return nil, fmt.Errorf("no JSON schema for OpenVEX version %q", version)
+	}
+	return bytes.Clone(s.data), nil
+}
+
+// SchemaValidationError is returned when a document does not conform to the
+// OpenVEX JSON schema. It collects all the violations found in the document,
+// each one as a *ValidationError pointing to the offending value.
+type SchemaValidationError struct {
+	Errors []*ValidationError
+}
+
+func (e *SchemaValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, ve := range e.Errors {
+		msgs = append(msgs, ve.Error())
+	}
+	return fmt.Sprintf("document does not conform to the OpenVEX schema: %s", strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual validation errors.
+func (e *SchemaValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, ve := range e.Errors {
+		errs = append(errs, ve)
+	}
+	return errs
+}
+
+// Validate checks the raw JSON data of an OpenVEX document against the
+// embedded JSON schema. If the data is not valid JSON an error is returned,
+// if the document violates the schema a *SchemaValidationError listing all
+// problems is returned. In both cases, the error wraps *ValidationErrors.
+func Validate(data []byte) error {
+	schema, err := loadSchema(SpecVersion)
+	if err != nil {
+		return err
+	}
+	return validateSchema(schema, data)
+}
+
+// ValidateVersion checks the raw JSON data of an OpenVEX document against the
+// validation profile of a version of the spec, that is the JSON schema of
+// exactly that version. The document context must declare the version.
+// Errors are returned as in Validate.
+func ValidateVersion(version string, data []byte) error {
+	version = strings.TrimPrefix(version, "v")
+	schema, err := loadSchema(version)
+	if err != nil {
+		return err
+	}
+
+	declared, err := DetectSpecVersion(data)
+	if err != nil {
+		return &ValidationError{Path: "/@context", Err: err}
+	}
+	if declared != version {
+		return &ValidationError{
+			Path: "/@context",
+			Err:  fmt.Errorf("%w: document declares OpenVEX %s, not %s", ErrVersionConflict, declared, version),
+		}
+	}
+	return validateSchema(schema, data)
+}
+
+// ValidateDeclaredVersion checks the raw JSON data of an OpenVEX document
+// against the validation profile of the spec version its context declares.
+func ValidateDeclaredVersion(data []byte) error {
+	version, err := DetectSpecVersion(data)
+	if err != nil {
+		return &ValidationError{Path: "/@context", Err: err}
+	}
+	return ValidateVersion(version, data)
+}
+
+// validateSchema checks the raw JSON data against a parsed schema.
+func validateSchema(schema map[string]any, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("decoding document: %w", validationErrorFromJSON(err))
+	}
+
+	v := schemaValidator{root: schema}
+	v.validate(schema, doc, "")
+	if len(v.errors) > 0 {
+		return &SchemaValidationError{Errors: v.errors}
+	}
+	return nil
+}
+
+// Validate serializes the document and checks it against the OpenVEX JSON
+// schema and any custom rules in the registry (see RegisterValidationRule).
+// Schema violations are returned as a *SchemaValidationError and each failed
+// rule as a *RuleViolation, joined in a single error.
+func (vexDoc *VEX) Validate() error {
+	data, err := json.Marshal(vexDoc)
+	if err != nil {
+		return fmt.Errorf("marshaling document: %w", err)
+	}
+	errs := []error{Validate(data)}
+	errs = append(errs, runValidationRules(vexDoc)...)
+	return errors.Join(errs...)
+}
+
+// loadSchema returns the parsed JSON schema of a spec version.
+func loadSchema(version string) (map[string]any, error) {
+	s, ok := schemas[version]
+	if !ok {
+		return nil, fmt.Errorf("no JSON schema for OpenVEX version %q", version)
+	}
+	s.once.Do(func() {
+		if err := json.Unmarshal(s.data, &s.parsed); err != nil {
+			s.err = fmt.Errorf("parsing embedded JSON schema: %w", err)
+			return
+		}
+		if s.overlay == nil {
+			return
+		}
+		var overlay map[string]any
+		if err := json.Unmarshal(s.overlay, &overlay); err != nil {
+			s.err = fmt.Errorf("parsing embedded JSON schema extensions: %w", err)
+			return
+		}
+		mergeSchema(s.parsed, overlay)
+	})
+	return s.parsed, s.err
+}
+
+// mergeSchema adds the keywords of an overlay schema to a schema. Objects are
+// merged recursively and other values are only set when missing, so the
+// overlay can add definitions and properties but not change the existing
+// ones.
+func mergeSchema(schema, overlay map[string]any) {
+	for k, ov := range overlay {
+		cur, ok := schema[k]
+		if !ok {
+			schema[k] = ov
+			continue
+		}
+		curMap, ok := cur.(map[string]any)
+		if !ok {
+			continue
+		}
+		if ovMap, ok := ov.(map[string]any); ok {
+			mergeSchema(curMap, ovMap)
+		}
+	}
+}
+
+// schemaValidator implements the subset of JSON schema (draft 2020-12) used
+// by the OpenVEX schema.
+type schemaValidator struct {
+	root   map[string]any
+	errors []*ValidationError
+}
+
+func (v *schemaValidator) addError(path, format string, args ...any) {
+	v.errors = append(v.errors, &ValidationError{Path: path, Err: fmt.Errorf(format, args...)})
+}
+
+// matches returns true if the instance validates against the schema without
+// recording any errors.
+func (v *schemaValidator) matches(schema map[string]any, inst any, path string) bool {
+	sub := schemaValidator{root: v.root}
+	sub.validate(schema, inst, path)
+	return len(sub.errors) == 0
+}
+
+func (v *schemaValidator) validate(schema map[string]any, inst any, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := v.resolve(ref)
+		if err != nil {
+			v.addError(path, "%s", err.Error())
+			return
+		}
+		v.validate(target, inst, path)
+	}
+
+	if t, ok := schema["type"].(string); ok && !schemaTypeMatches(t, inst) {
+		v.addError(path, "expected %s but got %s", t, schemaTypeOf(inst))
+		return
+	}
+
+	if c, ok := schema["const"]; ok && !schemaEqual(c, inst) {
+		v.addError(path, "value must be %v", c)
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if schemaEqual(e, inst) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			opts := make([]string, 0, len(enum))
+			for _, e := range enum {
+				opts = append(opts, fmt.Sprint(e))
+			}
+			v.addError(path, "invalid value %v, must be one of [%s]", inst, strings.Join(opts, ", "))
+		}
+	}
+
+	switch val := inst.(type) {
+	case string:
+		v.validateString(schema, val, path)
+	case json.Number:
+		v.validateNumber(schema, val, path)
+	case map[string]any:
+		v.validateObject(schema, val, path)
+	case []any:
+		v.validateArray(schema, val, path)
+	}
+
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, s := range allOf {
+			if sub, ok := s.(map[string]any); ok {
+				v.validate(sub, inst, path)
+			}
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		v.validateAnyOf(anyOf, inst, path)
+	}
+
+	if cond, ok := schema["if"].(map[string]any); ok {
+		if v.matches(cond, inst, path) {
+			if then, ok := schema["then"].(map[string]any); ok {
+				v.validate(then, inst, path)
+			}
+		} else if els, ok := schema["else"].(map[string]any); ok {
+			v.validate(els, inst, path)
+		}
+	}
+}
+
+func (v *schemaValidator) validateString(schema map[string]any, val, path string) {
+	if minLength, ok := schema["minLength"].(float64); ok && float64(len([]rune(val))) < minLength {
+		v.addError(path, "string must be at least %v characters long", minLength)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(val) {
+			v.addError(path, "%q does not match the pattern %s", val, pattern)
+		}
+	}
+
+	format, ok := schema["format"].(string)
+	if !ok {
+		return
+	}
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, val); err != nil {
+			v.addError(path, "%q is not a valid RFC3339 date-time", val)
+		}
+	case "uri", "iri":
+		u, err := url.Parse(val)
+		if err != nil || u.Scheme == "" {
+			v.addError(path, "%q is not a valid %s", val, strings.ToUpper(format))
+		}
+	}
+}
+
+func (v *schemaValidator) validateNumber(schema map[string]any, val json.Number, path string) {
+	f, err := val.Float64()
+	if err != nil {
+		return
+	}
+	if minimum, ok := schema["minimum"].(float64); ok && f < minimum {
+		v.addError(path, "value %s is lower than the minimum %v", val, minimum)
+	}
+	if maximum, ok := schema["maximum"].(float64); ok && f > maximum {
+		v.addError(path, "value %s is greater than the maximum %v", val, maximum)
+	}
+	if minimum, ok := schema["exclusiveMinimum"].(float64); ok && f <= minimum {
+		v.addError(path, "value %s must be greater than %v", val, minimum)
+	}
+	if maximum, ok := schema["exclusiveMaximum"].(float64); ok && f >= maximum {
+		v.addError(path, "value %s must be lower than %v", val, maximum)
+	}
+}
+
+func (v *schemaValidator) validateObject(schema map[string]any, obj map[string]any, path string) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			key, ok := r.(string)
+			if !ok {
+				continue
+			}
+			if _, ok := obj[key]; !ok {
+				v.addError(path, "missing required field %q", key)
+			}
+		}
+	}
+
+	props := map[string]any{}
+	if p, ok := schema["properties"].(map[string]any); ok {
+		props = p
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		propPath := path + "/" + escapePointer(k)
+		if ps, ok := props[k].(map[string]any); ok {
+			v.validate(ps, obj[k], propPath)
+			continue
+		}
+		if ps, ok := matchPatternProperty(schema, k); ok {
+			v.validate(ps, obj[k], propPath)
+			continue
+		}
+		if ap, ok := schema["additionalProperties"].(bool); ok && !ap {
+			v.addError(propPath, "unknown field %q", k)
+		}
+	}
+}
+
+// matchPatternProperty returns the schema of the first patternProperties
+// entry matching the name of a property.
+func matchPatternProperty(schema map[string]any, name string) (map[string]any, bool) {
+	patterns, ok := schema["patternProperties"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	keys := make([]string, 0, len(patterns))
+	for k := range patterns {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, pattern := range keys {
+		re, err := regexp.Compile(pattern)
+		if err != nil || !re.MatchString(name) {
+			continue
+		}
+		if ps, ok := patterns[pattern].(map[string]any); ok {
+			return ps, true
+		}
+	}
+	return nil, false
+}
+
+func (v *schemaValidator) validateArray(schema map[string]any, arr []any, path string) {
+	if minItems, ok := schema["minItems"].(float64); ok && float64(len(arr)) < minItems {
+		v.addError(path, "array must have at least %v items", minItems)
+	}
+
+	if unique, ok := schema["uniqueItems"].(bool); ok && unique {
+		seen := map[string]int{}
+		for i, item := range arr {
+			key, err := json.Marshal(item)
+			if err != nil {
+				continue
+			}
+			if j, ok := seen[string(key)]; ok {
+				v.addError(fmt.Sprintf("%s/%d", path, i), "item is a duplicate of item %d", j)
+				continue
+			}
+			seen[string(key)] = i
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range arr {
+			v.validate(items, item, fmt.Sprintf("%s/%d", path, i))
+		}
+	}
+}
+
+func (v *schemaValidator) validateAnyOf(anyOf []any, inst any, path string) {
+	// Alternatives that only list required fields get a friendlier message
+	fields := []string{}
+	for _, s := range anyOf {
+		sub, ok := s.(map[string]any)
+		if !ok {
+			continue
+		}
+		if v.matches(sub, inst, path) {
+			return
+		}
+		if req, ok := sub["required"].([]any); ok && len(sub) == 1 {
+			for _, r := range req {
+				fields = append(fields, fmt.Sprint(r))
+			}
+		} else {
+			fields = nil
+		}
+	}
+
+	if len(fields) > 0 {
+		v.addError(path, "at least one of [%s] must be defined", strings.Join(fields, ", "))
+		return
+	}
+	v.addError(path, "value does not match any of the allowed alternatives")
+}
+
+// resolve returns the subschema pointed to by a local reference
+func (v *schemaValidator) resolve(ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported schema reference %q", ref)
+	}
+	var node any = v.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unable to resolve schema reference %q", ref)
+		}
+		node = m[unescapePointer(part)]
+	}
+	target, ok := node.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unable to resolve schema reference %q", ref)
+	}
+	return target, nil
+}
+
+func schemaTypeOf(inst any) string {
+	switch val := inst.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", inst)
+	}
+}
+
+func schemaTypeMatches(t string, inst any) bool {
+	actual := schemaTypeOf(inst)
+	if t == "number" && actual == "integer" {
+		return true
+	}
+	return t == actual
+}
+
+func schemaEqual(a, b any) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ja, jb)
+}
+
+// escapePointer escapes a key to be used as a JSON pointer segment (RFC 6901)
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func unescapePointer(s string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchema(t *testing.T) {
+	for m, tc := range map[string]struct {
+		path      string
+		data      string
+		shouldErr bool
+		paths     []string
+	}{
+		"valid document": {path: "testdata/v0.2.0.json"},
+		"missing required fields": {
+			path:      "testdata/v020-1.vex.json",
+			shouldErr: true,
+			paths:     []string{"", ""},
+		},
+		"invalid status": {
+			data: `{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1",
+			"author": "John Doe", "timestamp": "2023-01-01T00:00:00Z", "version": 1,
+			"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "products": [{"@id": "pkg:apk/wolfi/bash@1.0.0"}], "status": "wontfix"}]}`,
+			shouldErr: true,
+			paths:     []string{"/statements/0/status"},
+		},
+		"not_affected without justification": {
+			data: `{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1",
+			"author": "John Doe", "timestamp": "2023-01-01T00:00:00Z", "version": 1,
+			"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "products": [{"@id": "pkg:apk/wolfi/bash@1.0.0"}], "status": "not_affected"}]}`,
+			shouldErr: true,
+			paths:     []string{"/statements/0"},
+		},
+		"unknown field": {
+			data: `{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1",
+			"author": "John Doe", "timestamp": "2023-01-01T00:00:00Z", "version": 1,
+			"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "products": [{"@id": "pkg:apk/wolfi/bash@1.0.0"}],
+			"status": "not_affected", "justifcation": "component_not_present"}]}`,
+			shouldErr: true,
+			paths:     []string{"/statements/0/justifcation", "/statements/0"},
+		},
+		"wrong types": {
+			data: `{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1",
+			"author": "John Doe", "timestamp": "yesterday", "version": "1",
+			"statements": [{"vulnerability": {"name": "CVE-2023-1234"}, "products": [{"@id": "pkg:apk/wolfi/bash@1.0.0"}], "status": "fixed"}]}`,
+			shouldErr: true,
+			paths:     []string{"/timestamp", "/version"},
+		},
+		"epss score out of range": {
+			data: `{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1",
+			"author": "John Doe", "timestamp": "2023-01-01T00:00:00Z", "version": 1,
+			"statements": [{"vulnerability": {"name": "CVE-2023-1234", "epss": {"score": 7.5, "percentile": -0.1}},
+			"products": [{"@id": "pkg:apk/wolfi/bash@1.0.0"}], "status": "fixed"}]}`,
+			shouldErr: true,
+			paths:     []string{"/statements/0/vulnerability/epss/percentile", "/statements/0/vulnerability/epss/score"},
+		},
+		"invalid json": {
+			data:      `{"@context": `,
+			shouldErr: true,
+		},
+	} {
+		data := []byte(tc.data)
+		if tc.path != "" {
+			var err error
+			data, err = os.ReadFile(tc.path)
+			require.NoError(t, err)
+		}
+
+		err := Validate(data)
+		if !tc.shouldErr {
+			require.NoError(t, err, m)
+			continue
+		}
+		require.Error(t, err, m)
+		if tc.paths == nil {
+			continue
+		}
+
+		var serr *SchemaValidationError
+		require.True(t, errors.As(err, &serr), m)
+		paths := []string{}
+		for _, e := range serr.Errors {
+			paths = append(paths, e.Path)
+		}
+		require.Equal(t, tc.paths, paths, m)
+	}
+}
+
+func TestValidateSchemaNumberBounds(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"inclusive": map[string]any{"type": "number", "minimum": 0.0, "maximum": 1.0},
+			"exclusive": map[string]any{"type": "number", "exclusiveMinimum": 0.0, "exclusiveMaximum": 1.0},
+		},
+	}
+	for data, paths := range map[string][]string{
+		`{"inclusive": 0, "exclusive": 0.5}`:   nil,
+		`{"inclusive": 1, "exclusive": 0.999}`: nil,
+		`{"inclusive": 1.5, "exclusive": 0.5}`: {"/inclusive"},
+		`{"inclusive": -1, "exclusive": 0.5}`:  {"/inclusive"},
+		`{"inclusive": 0.5, "exclusive": 0}`:   {"/exclusive"},
+		`{"inclusive": 0.5, "exclusive": 1}`:   {"/exclusive"},
+		`{"inclusive": 2, "exclusive": 2}`:     {"/exclusive", "/inclusive"},
+	} {
+		err := validateSchema(schema, []byte(data))
+		if paths == nil {
+			require.NoError(t, err, data)
+			continue
+		}
+		var serr *SchemaValidationError
+		require.True(t, errors.As(err, &serr), data)
+		got := []string{}
+		for _, e := range serr.Errors {
+			got = append(got, e.Path)
+		}
+		require.Equal(t, paths, got, data)
+	}
+}
+
+func TestVEXValidate(t *testing.T) {
+	doc := New()
+	require.Error(t, doc.Validate())
+
+	doc.ID = "https://example.com/vex-1"
+	ts := time.Date(2023, 4, 17, 20, 34, 58, 0, time.UTC)
+	doc.Timestamp = &ts
+	doc.Statements = append(doc.Statements, Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+		Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/bash@1.0.0"}}},
+		Status:        StatusAffected,
+	})
+
+	err := doc.Validate()
+	var serr *SchemaValidationError
+	require.True(t, errors.As(err, &serr))
+	require.Len(t, serr.Errors, 1)
+	require.Equal(t, "/statements/0", serr.Errors[0].Path)
+
+	doc.Statements[0].ActionStatement = "Update to 1.0.1"
+	require.NoError(t, doc.Validate())
+}
+
+func TestValidateVersion(t *testing.T) {
+	for m, tc := range map[string]struct {
+		path    string
+		version string
+		mustErr bool
+	}{
+		"v0.0.1":                {"testdata/v0.0.1.json", "0.0.1", false},
+		"v0.0.1 legacy fields":  {"testdata/v0.0.1-legacy-fields.json", "0.0.1", true},
+		"v0.0.1 bare context":   {"testdata/v0.0.1-noversion.json", "0.0.1", false},
+		"v0.2.0":                {"testdata/v0.2.0.json", "0.2.0", false},
+		"v0.2.0 against v0.0.1": {"testdata/v0.2.0.json", "0.0.1", true},
+		"v0.0.1 against v0.2.0": {"testdata/v0.0.1.json", "v0.2.0", true},
+		"unknown version":       {"testdata/v0.2.0.json", "9.9.9", true},
+	} {
+		data, err := os.ReadFile(tc.path)
+		require.NoError(t, err, m)
+		err = ValidateVersion(tc.version, data)
+		if tc.mustErr {
+			require.Error(t, err, m)
+			continue
+		}
+		require.NoError(t, err, m)
+	}
+
+	// The declared version selects the profile
+	data, err := os.ReadFile("testdata/v0.0.1.json")
+	require.NoError(t, err)
+	require.NoError(t, ValidateDeclaredVersion(data))
+	require.Error(t, Validate(data))
+
+	err = ValidateDeclaredVersion([]byte(`{"@context": "https://example.com/ns"}`))
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "/@context", verr.Path)
+
+	// Statements are checked with the rules of their version
+	err = ValidateVersion("0.0.1", []byte(`{"@context": "https://openvex.dev/ns/v0.0.1", "@id": "https://example.com/vex-1",
+		"author": "Jane", "timestamp": "2023-01-08T18:02:03Z", "version": "1",
+		"statements": [{"vulnerability": "CVE-2023-1234", "products": ["pkg:oci/app"], "status": "affected"}]}`))
+	require.Error(t, err)
+	var serr *SchemaValidationError
+	require.True(t, errors.As(err, &serr))
+	require.Equal(t, "/statements/0", serr.Errors[0].Path)
+}
+
+func TestParseValidateSchema(t *testing.T) {
+	for path, mustErr := range map[string]bool{
+		"testdata/v0.0.1.json":               false,
+		"testdata/v0.0.1-legacy-fields.json": true,
+		"testdata/v0.2.0.json":               false,
+	} {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		_, err = ParseCompatWithOptions(&ParseOptions{ValidateSchema: true}, data)
+		if mustErr {
+			require.Error(t, err, path)
+			continue
+		}
+		require.NoError(t, err, path)
+	}
+
+	schema, err := JSONSchemaFor("v0.0.1")
+	require.NoError(t, err)
+	require.Contains(t, string(schema), "openvex_json_schema_0.0.1.json")
+	_, err = JSONSchemaFor("0.1.0")
+	require.Error(t, err)
+}
+
+func TestJSONSchemaExtensions(t *testing.T) {
+	// The published schema does not include the library extensions
+	schema := string(JSONSchema())
+	for _, field := range []string{"transparency_log", "changelog", "epss", "ssvc", "^x-"} {
+		require.NotContains(t, schema, `"`+field+`"`, field)
+	}
+
+	// Documents using them validate
+	data := []byte(`{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1",
+	"author": "John Doe", "timestamp": "2023-01-01T00:00:00Z", "version": 1, "x-ticket": "SEC-1",
+	"transparency_log": [{"log_index": 1}],
+	"statements": [{"vulnerability": {"name": "CVE-2023-1234", "epss": {"score": 0.5}, "cwes": ["CWE-502"]},
+	"products": [{"@id": "pkg:apk/wolfi/bash@1.0.0"}], "status": "fixed", "fix": {"versions": ["1.0.1"]}}]}`)
+	require.NoError(t, Validate(data))
+
+	// Unknown fields are still rejected
+	data = []byte(`{"@context": "https://openvex.dev/ns/v0.2.0", "@id": "https://example.com/vex-1",
+	"author": "John Doe", "timestamp": "2023-01-01T00:00:00Z", "version": 1, "ticket": "SEC-1",
+	"statements": [{"vulnerability": {"name": "CVE-2023-1234"},
+	"products": [{"@id": "pkg:apk/wolfi/bash@1.0.0"}], "status": "fixed"}]}`)
+	require.Error(t, Validate(data))
+}
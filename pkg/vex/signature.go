@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// statementSignaturePrefix separates statement signatures from signatures
+// over other data made with the same key.
+const statementSignaturePrefix = "openvex-statement-v2:"
+
+// StatementSignature is a detached signature over a single statement. It is
+// keyed by the statement's canonical hash so signatures can be stored apart
+// from the document and still be matched to their statements.
+type StatementSignature struct {
+	// StatementHash is the canonical hash of the signed statement
+	StatementHash string `json:"statement_hash"`
+
+	// KeyID identifies the key used to sign the statement
+	KeyID string `json:"keyid"`
+
+	// Signature is the raw signature, encoded as base64 in JSON
+	Signature []byte `json:"sig"`
+}
+
+// StatementSigner signs statement hashes.
+type StatementSigner interface {
+	KeyID() string
+	Sign(data []byte) ([]byte, error)
+}
+
+// StatementVerifier checks statement signatures made by a StatementSigner.
+type StatementVerifier interface {
+	KeyID() string
+	Verify(data, sig []byte) error
+}
+
+type ed25519Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+func (s *ed25519Signer) KeyID() string { return s.keyID }
+
+func (s *ed25519Signer) JWSAlgorithm() string { return jwsAlgEdDSA }
+
+func (s *ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, data), nil
+}
+
+// NewED25519Signer returns a StatementSigner using an ed25519 private key.
+// It can also sign documents as JWS.
+func NewED25519Signer(keyID string, key ed25519.PrivateKey) JWSSigner {
+	return &ed25519Signer{keyID: keyID, key: key}
+}
+
+type ed25519Verifier struct {
+	keyID string
+	key   ed25519.PublicKey
+}
+
+func (v *ed25519Verifier) KeyID() string { return v.keyID }
+
+func (v *ed25519Verifier) JWSAlgorithm() string { return jwsAlgEdDSA }
+
+func (v *ed25519Verifier) Verify(data, sig []byte) error {
+	if !ed25519.Verify(v.key, data, sig) {
+		return errors.New("invalid ed25519 signature")
+	}
+	return nil
+}
+
+// NewED25519Verifier returns a StatementVerifier using an ed25519 public key.
+func NewED25519Verifier(keyID string, key ed25519.PublicKey) StatementVerifier {
+	return &ed25519Verifier{keyID: keyID, key: key}
+}
+
+// CanonicalHash returns the hex encoded SHA-256 hash of the statement's
+// canonical JSON serialization (RFC 8785), so it changes with any edit to the
+// statement, including to its identifier, vulnerability details and
+// extensions. Like the document's CanonicalHash, it does not change when
+// products are reordered or when the statement is moved to another document.
+func (stmt *Statement) CanonicalHash() (string, error) {
+	data, err := stmt.canonicalBytes()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// canonicalBytes returns the JCS serialization of the statement with its
+// products sorted by their own canonical serialization.
+func (stmt *Statement) canonicalBytes() ([]byte, error) {
+	s := stmt.Clone()
+	keys := make([]string, len(s.Products))
+	for i := range s.Products {
+		data, err := json.Marshal(&s.Products[i])
+		if err != nil {
+			return nil, fmt.Errorf("marshaling product #%d: %w", i, err)
+		}
+		cdata, err := CanonicalizeJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("canonicalizing product #%d: %w", i, err)
+		}
+		keys[i] = string(cdata)
+	}
+	order := make([]int, len(s.Products))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return keys[order[i]] < keys[order[j]] })
+	prods := make([]Product, 0, len(s.Products))
+	for _, i := range order {
+		prods = append(prods, s.Products[i])
+	}
+	s.Products = prods
+
+	data, err := json.Marshal(&s)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling statement: %w", err)
+	}
+	return CanonicalizeJSON(data)
+}
+
+// Sign returns a detached signature over the statement's canonical hash.
+func (stmt *Statement) Sign(signer StatementSigner) (*StatementSignature, error) {
+	hash, err := stmt.CanonicalHash()
+	if err != nil {
+		return nil, fmt.Errorf("computing statement hash: %w", err)
+	}
+	sig, err := signer.Sign([]byte(statementSignaturePrefix + hash))
+	if err != nil {
+		return nil, fmt.Errorf("signing statement: %w", err)
+	}
+	return &StatementSignature{StatementHash: hash, KeyID: signer.KeyID(), Signature: sig}, nil
+}
+
+// VerifySignature checks that sig is a valid signature over the statement
+// made by the verifier's key.
+func (stmt *Statement) VerifySignature(sig *StatementSignature, verifier StatementVerifier) error {
+	if sig.KeyID != verifier.KeyID() {
+		return fmt.Errorf("signature key %q does not match verifier key %q", sig.KeyID, verifier.KeyID())
+	}
+	hash, err := stmt.CanonicalHash()
+	if err != nil {
+		return fmt.Errorf("computing statement hash: %w", err)
+	}
+	if hash != sig.StatementHash {
+		return errors.New("signature does not cover this statement")
+	}
+	if err := verifier.Verify([]byte(statementSignaturePrefix+hash), sig.Signature); err != nil {
+		return fmt.Errorf("verifying signature: %w", err)
+	}
+	return nil
+}
+
+// SignStatements signs every statement in the document with signer and
+// returns the detached signatures.
+func (vexDoc *VEX) SignStatements(signer StatementSigner) ([]StatementSignature, error) {
+	sigs := make([]StatementSignature, 0, len(vexDoc.Statements))
+	for i := range vexDoc.Statements {
+		sig, err := vexDoc.Statements[i].Sign(signer)
+		if err != nil {
+			return nil, fmt.Errorf("statement #%d: %w", i, err)
+		}
+		sigs = append(sigs, *sig)
+	}
+	return sigs, nil
+}
+
+// TrustedStatements returns the statements in the document carrying a valid
+// signature from at least one of the verifiers. Statements without signatures
+// or signed only by unknown keys are left out.
+func (vexDoc *VEX) TrustedStatements(sigs []StatementSignature, verifiers ...StatementVerifier) ([]Statement, error) {
+	keys := map[string]StatementVerifier{}
+	for _, v := range verifiers {
+		keys[v.KeyID()] = v
+	}
+	byHash := map[string][]*StatementSignature{}
+	for i := range sigs {
+		if _, ok := keys[sigs[i].KeyID]; ok {
+			byHash[sigs[i].StatementHash] = append(byHash[sigs[i].StatementHash], &sigs[i])
+		}
+	}
+
+	trusted := []Statement{}
+	for i := range vexDoc.Statements {
+		hash, err := vexDoc.Statements[i].CanonicalHash()
+		if err != nil {
+			return nil, fmt.Errorf("statement #%d: computing hash: %w", i, err)
+		}
+		for _, sig := range byHash[hash] {
+			if vexDoc.Statements[i].VerifySignature(sig, keys[sig.KeyID]) == nil {
+				trusted = append(trusted, vexDoc.Statements[i])
+				break
+			}
+		}
+	}
+	return trusted, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementCanonicalHash(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	stmt := Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+		Timestamp:     &ts,
+		Products: []Product{
+			{Component: Component{ID: "pkg:apk/wolfi/git@2.41.0-r1"}},
+			{Component: Component{ID: "pkg:apk/wolfi/bash@5.2-r1"}},
+		},
+		Status:        StatusNotAffected,
+		Justification: ComponentNotPresent,
+	}
+	hash, err := stmt.CanonicalHash()
+	require.NoError(t, err)
+	require.Len(t, hash, 64)
+
+	// Product order does not change the hash
+	reordered := stmt
+	reordered.Products = []Product{stmt.Products[1], stmt.Products[0]}
+	hash2, err := reordered.CanonicalHash()
+	require.NoError(t, err)
+	require.Equal(t, hash, hash2)
+
+	// Changing the status does
+	changed := stmt
+	changed.Status = StatusAffected
+	changed.Justification = ""
+	hash3, err := changed.CanonicalHash()
+	require.NoError(t, err)
+	require.NotEqual(t, hash, hash3)
+
+	// So does any other field of the statement
+	for m, change := range map[string]func(*Statement){
+		"id":      func(s *Statement) { s.ID = "https://example.com/vex/1#stmt" },
+		"aliases": func(s *Statement) { s.Vulnerability.Aliases = []VulnerabilityID{"GHSA-xxxx-xxxx-xxxx"} },
+		"cwes":    func(s *Statement) { s.Vulnerability.CWEs = []string{"CWE-502"} },
+		"impact":  func(s *Statement) { s.ImpactStatement = "The code is not reachable" },
+		"subcomponent": func(s *Statement) {
+			s.Products[0].Subcomponents = []Subcomponent{{Component: Component{ID: "pkg:golang/example.com/lib@1.0.0"}}}
+		},
+	} {
+		changed := stmt.Clone()
+		change(&changed)
+		h, err := changed.CanonicalHash()
+		require.NoError(t, err, m)
+		require.NotEqual(t, hash, h, m)
+	}
+}
+
+func TestStatementSignatures(t *testing.T) {
+	pub1, priv1, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	pub2, priv2, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	doc := New()
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/image"}}},
+			Status:        StatusFixed,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-5678"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/image"}}},
+			Status:        StatusUnderInvestigation,
+		},
+	}
+
+	// Each team signs its own statement
+	sig1, err := doc.Statements[0].Sign(NewED25519Signer("team-a", priv1))
+	require.NoError(t, err)
+	sig2, err := doc.Statements[1].Sign(NewED25519Signer("team-b", priv2))
+	require.NoError(t, err)
+	sigs := []StatementSignature{*sig1, *sig2}
+
+	verifierA := NewED25519Verifier("team-a", pub1)
+	verifierB := NewED25519Verifier("team-b", pub2)
+
+	require.NoError(t, doc.Statements[0].VerifySignature(sig1, verifierA))
+	require.Error(t, doc.Statements[0].VerifySignature(sig1, verifierB))
+	require.Error(t, doc.Statements[1].VerifySignature(sig1, verifierA))
+
+	trusted, err := doc.TrustedStatements(sigs, verifierA)
+	require.NoError(t, err)
+	require.Len(t, trusted, 1)
+	require.Equal(t, "CVE-2023-1234", string(trusted[0].Vulnerability.Name))
+
+	trusted, err = doc.TrustedStatements(sigs, verifierA, verifierB)
+	require.NoError(t, err)
+	require.Len(t, trusted, 2)
+
+	// A key registered under the wrong ID does not verify
+	trusted, err = doc.TrustedStatements(sigs, NewED25519Verifier("team-a", pub2))
+	require.NoError(t, err)
+	require.Len(t, trusted, 0)
+
+	// Tampering with a statement invalidates its signature
+	doc.Statements[0].Status = StatusAffected
+	trusted, err = doc.TrustedStatements(sigs, verifierA, verifierB)
+	require.NoError(t, err)
+	require.Len(t, trusted, 1)
+
+	all, err := doc.SignStatements(NewED25519Signer("team-a", priv1))
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	trusted, err = doc.TrustedStatements(all, verifierA)
+	require.NoError(t, err)
+	require.Len(t, trusted, 2)
+}
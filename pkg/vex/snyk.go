@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+	"gopkg.in/yaml.v3"
+)
+
+// SnykPolicyVersion is the version of the .snyk policy format written by
+// ToSnykPolicy.
+const SnykPolicyVersion = "v1.25.0"
+
+// SnykPolicy captures the ignore section of a .snyk policy file.
+//
+// https://docs.snyk.io/snyk-cli/commands/ignore
+type SnykPolicy struct {
+	Version string `yaml:"version"`
+
+	// Ignore maps each vulnerability ID to a list of single entry maps
+	// keyed by the dependency path the rule applies to.
+	Ignore map[string][]map[string]SnykIgnoreRule `yaml:"ignore"`
+}
+
+// SnykIgnoreRule records why and until when snyk should ignore an issue.
+type SnykIgnoreRule struct {
+	Reason  string `yaml:"reason"`
+	Created string `yaml:"created,omitempty"`
+	Expires string `yaml:"expires,omitempty"`
+}
+
+// snykDateFormat is the timestamp format used in .snyk files
+const snykDateFormat = "2006-01-02T15:04:05.000Z"
+
+// ToSnykPolicy returns a snyk policy ignoring the vulnerabilities whose
+// latest statement about a package is not_affected. The justification and
+// impact statement become the reason of the rule.
+//
+// Rules are scoped to the package named in the purl of the statement
+// subcomponents or, when a product lists no subcomponents, of the product.
+// Snyk keys its rules by its own issue IDs so documents should list them
+// as vulnerability aliases, every alias gets its own entry.
+func (vexDoc *VEX) ToSnykPolicy() (*SnykPolicy, error) {
+	policy := &SnykPolicy{
+		Version: SnykPolicyVersion,
+		Ignore:  map[string][]map[string]SnykIgnoreRule{},
+	}
+	for _, s := range vexDoc.packageSuppressions() {
+		if s.statement.Status != StatusNotAffected {
+			continue
+		}
+		p, err := packageurl.FromString(s.purl)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrMalformedPurl, s.purl, err)
+		}
+
+		rule := SnykIgnoreRule{Reason: suppressionReason(s.statement)}
+		created := s.statement.Timestamp
+		if created == nil {
+			created = vexDoc.Timestamp
+		}
+		if created != nil {
+			rule.Created = created.UTC().Format(snykDateFormat)
+		}
+		policy.Ignore[s.vulnerability] = append(
+			policy.Ignore[s.vulnerability], map[string]SnykIgnoreRule{snykPackagePath(&p): rule},
+		)
+	}
+	return policy, nil
+}
+
+// SetExpiry sets the date when snyk stops ignoring all the issues.
+func (policy *SnykPolicy) SetExpiry(t time.Time) {
+	for _, rules := range policy.Ignore {
+		for _, entry := range rules {
+			for path, rule := range entry {
+				rule.Expires = t.UTC().Format(snykDateFormat)
+				entry[path] = rule
+			}
+		}
+	}
+}
+
+// ToYAML writes the policy as a .snyk file.
+func (policy *SnykPolicy) ToYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(policy); err != nil {
+		return fmt.Errorf("encoding snyk policy: %w", err)
+	}
+	return enc.Close()
+}
+
+// snykPackagePath returns the dependency path matching a package anywhere
+// in the dependency tree.
+func snykPackagePath(p *packageurl.PackageURL) string {
+	name := p.Name
+	switch {
+	case p.Namespace == "":
+	case p.Type == packageurl.TypeMaven:
+		name = p.Namespace + ":" + p.Name
+	default:
+		name = p.Namespace + "/" + p.Name
+	}
+	if p.Version != "" {
+		name += "@" + p.Version
+	}
+	return "* > " + name
+}
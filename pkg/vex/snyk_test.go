@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSnykPolicy(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.Timestamp = &ts
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{
+				Name:    "CVE-2020-8203",
+				Aliases: []VulnerabilityID{"SNYK-JS-LODASH-567746"},
+			},
+			Products: []Product{{
+				Component:     Component{ID: "pkg:npm/my-app@1.0.0"},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:npm/lodash@4.17.15"}}},
+			}},
+			Status:          StatusNotAffected,
+			Justification:   VulnerableCodeNotInExecutePath,
+			ImpactStatement: "zipObjectDeep is never called",
+		},
+		{
+			Vulnerability: Vulnerability{Name: "SNYK-JAVA-ORGAPACHELOGGINGLOG4J-2314720"},
+			Products:      []Product{{Component: Component{ID: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}}},
+			Status:        StatusNotAffected,
+			Justification: VulnerableCodeCannotBeControlledByAdversary,
+		},
+		{
+			// Fixed statements are not ignores
+			Vulnerability: Vulnerability{Name: "CVE-2023-3333"},
+			Products:      []Product{{Component: Component{ID: "pkg:npm/express@4.0.0"}}},
+			Status:        StatusFixed,
+		},
+	}
+
+	policy, err := doc.ToSnykPolicy()
+	require.NoError(t, err)
+	require.Len(t, policy.Ignore, 3)
+
+	rule := SnykIgnoreRule{
+		Reason:  "not_affected: vulnerable_code_not_in_execute_path: zipObjectDeep is never called",
+		Created: "2023-06-01T10:00:00.000Z",
+	}
+	require.Equal(t, []map[string]SnykIgnoreRule{{"* > lodash@4.17.15": rule}}, policy.Ignore["SNYK-JS-LODASH-567746"])
+	require.Equal(t, []map[string]SnykIgnoreRule{{"* > lodash@4.17.15": rule}}, policy.Ignore["CVE-2020-8203"])
+	require.Contains(t, policy.Ignore["SNYK-JAVA-ORGAPACHELOGGINGLOG4J-2314720"][0], "* > org.apache.logging.log4j:log4j-core@2.14.1")
+
+	policy.SetExpiry(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, "2024-01-01T00:00:00.000Z", policy.Ignore["CVE-2020-8203"][0]["* > lodash@4.17.15"].Expires)
+
+	var buf bytes.Buffer
+	require.NoError(t, policy.ToYAML(&buf))
+	require.Contains(t, buf.String(), "version: v1.25.0\nignore:\n  CVE-2020-8203:\n    - '* > lodash@4.17.15':\n        reason: ")
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/spdx"
+)
+
+// SPDXAnnotationPrefix marks SPDX annotation and relationship comments
+// carrying VEX data.
+//
+// The convention recognized by FromSPDX is a comment starting with the
+// prefix followed by key=value pairs separated by semicolons or new lines:
+//
+//	openvex: vulnerability=CVE-2023-1234; status=not_affected; justification=component_not_present
+//
+// The recognized keys are vulnerability and status, which are required, and
+// justification, impact_statement, action_statement and status_notes.
+//
+// When the comment is in an annotation of a package, the package is the
+// product. When it is in the comment of a relationship, the element
+// (spdxElementId) is the product and the related element is a subcomponent.
+// Document annotations must name the product with the product key and may
+// add a subcomponent key.
+const SPDXAnnotationPrefix = "openvex:"
+
+// FromSPDX reads an SPDX 2.3 JSON document and returns an OpenVEX document
+// holding a statement for each annotation or relationship comment following
+// the SPDXAnnotationPrefix convention. Products are identified by the purl
+// of the SPDX package or, if it has none, by the document namespace and the
+// package SPDXID.
+func FromSPDX(r io.Reader) (*VEX, error) {
+	sbom, err := spdx.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := map[string]*spdx.Package{}
+	for i := range sbom.Packages {
+		packages[sbom.Packages[i].ID] = &sbom.Packages[i]
+	}
+	componentFor := func(spdxID string) Component {
+		return spdxComponent(sbom, packages[spdxID], spdxID)
+	}
+
+	created := sbom.CreationInfo.Created
+	doc := New()
+	doc.Timestamp = &created
+	if len(sbom.CreationInfo.Creators) > 0 {
+		doc.Author = strings.Join(sbom.CreationInfo.Creators, ", ")
+	}
+
+	addStatement := func(path, comment string, date time.Time, product, subcomponent string) error {
+		fields, ok := parseSPDXVEXComment(comment)
+		if !ok {
+			return nil
+		}
+		if fields["product"] != "" {
+			product = fields["product"]
+		}
+		if fields["subcomponent"] != "" {
+			subcomponent = fields["subcomponent"]
+		}
+		if product == "" {
+			return fmt.Errorf("%s: annotation does not define a product", path)
+		}
+
+		stmt := Statement{
+			Vulnerability:   Vulnerability{Name: VulnerabilityID(fields["vulnerability"])},
+			Status:          Status(fields["status"]),
+			Justification:   Justification(fields["justification"]),
+			ImpactStatement: fields["impact_statement"],
+			ActionStatement: fields["action_statement"],
+			StatusNotes:     fields["status_notes"],
+			Products:        []Product{{Component: componentFor(product)}},
+		}
+		if !date.IsZero() {
+			stmt.Timestamp = &date
+		}
+		if subcomponent != "" {
+			stmt.Products[0].Subcomponents = []Subcomponent{{Component: componentFor(subcomponent)}}
+		}
+		if stmt.Vulnerability.Name == "" {
+			return fmt.Errorf("%s: annotation does not define a vulnerability", path)
+		}
+		if err := stmt.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		doc.Statements = append(doc.Statements, stmt)
+		return nil
+	}
+
+	for i := range sbom.Packages {
+		for j, a := range sbom.Packages[i].Annotations {
+			path := fmt.Sprintf("packages[%d].annotations[%d]", i, j)
+			if err := addStatement(path, a.Comment, a.Date, sbom.Packages[i].ID, ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i, a := range sbom.Annotations {
+		if err := addStatement(fmt.Sprintf("annotations[%d]", i), a.Comment, a.Date, "", ""); err != nil {
+			return nil, err
+		}
+	}
+	for i, rel := range sbom.Relationships {
+		path := fmt.Sprintf("relationships[%d]", i)
+		if err := addStatement(path, rel.Comment, time.Time{}, rel.Element, rel.RelatedElement); err != nil {
+			return nil, err
+		}
+	}
+
+	// Statements are ordered by vulnerability and time, so the document and
+	// its ID do not depend on where the annotations are in the SBOM
+	SortStatements(doc.Statements, *doc.Timestamp)
+	if _, err := doc.GenerateCanonicalID(); err != nil {
+		return nil, fmt.Errorf("generating document ID: %w", err)
+	}
+	return &doc, nil
+}
+
+// spdxComponent returns the component for an SPDX element. Packages are
+// identified by their purl if they have one. Other elements are identified
+// by the document namespace and their SPDXID. p is nil if the element is not
+// a package.
+func spdxComponent(sbom *spdx.Document, p *spdx.Package, spdxID string) Component {
+	if p != nil {
+		if purl := p.PackageURL(); purl != "" {
+			return Component{ID: purl, Identifiers: map[IdentifierType]string{PURL: purl}}
+		}
+	}
+	if sbom.DocumentNamespace != "" && strings.HasPrefix(spdxID, "SPDXRef-") {
+		return Component{ID: sbom.DocumentNamespace + "#" + spdxID}
+	}
+	return Component{ID: spdxID}
+}
+
+// parseSPDXVEXComment reads the key=value pairs of a comment following the
+// SPDXAnnotationPrefix convention. It returns false if the comment does not
+// carry VEX data.
+func parseSPDXVEXComment(comment string) (map[string]string, bool) {
+	comment = strings.TrimSpace(comment)
+	if !strings.HasPrefix(strings.ToLower(comment), SPDXAnnotationPrefix) {
+		return nil, false
+	}
+	comment = comment[len(SPDXAnnotationPrefix):]
+
+	fields := map[string]string{}
+	for _, pair := range strings.FieldsFunc(comment, func(r rune) bool { return r == ';' || r == '\n' }) {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		fields[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+	}
+	return fields, true
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromSPDX(t *testing.T) {
+	f, err := os.Open("testdata/spdx-annotations.spdx.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	doc, err := FromSPDX(f)
+	require.NoError(t, err)
+	require.Equal(t, "Organization: Example Inc.", doc.Author)
+	require.NotEmpty(t, doc.ID)
+	require.Len(t, doc.Statements, 3)
+
+	// Statements are sorted by vulnerability
+
+	// Package annotation
+	s := doc.Statements[0]
+	require.Equal(t, "CVE-2023-1111", string(s.Vulnerability.Name))
+	require.Equal(t, StatusNotAffected, s.Status)
+	require.Equal(t, ComponentNotPresent, s.Justification)
+	require.Equal(t, "pkg:oci/example-image@sha256%3Aabcdef", s.Products[0].ID)
+	require.NotNil(t, s.Timestamp)
+
+	// Relationship comment
+	s = doc.Statements[1]
+	require.Equal(t, StatusFixed, s.Status)
+	require.Equal(t, "pkg:oci/example-image@sha256%3Aabcdef", s.Products[0].ID)
+	require.Equal(t, "pkg:apk/wolfi/openssl@3.0.8", s.Products[0].Subcomponents[0].ID)
+
+	// Document annotation naming the product and a subcomponent without purl
+	s = doc.Statements[2]
+	require.Equal(t, StatusAffected, s.Status)
+	require.Equal(t, "Update to 1.1", s.ActionStatement)
+	require.Equal(t, "https://example.com/spdx/example-image-1.0#SPDXRef-Package-vendored", s.Products[0].Subcomponents[0].ID)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+}
+
+func TestFromSPDXErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"invalid json":   `{"packages": [`,
+		"invalid status": `{"packages":[{"SPDXID":"SPDXRef-A","annotations":[{"comment":"openvex: vulnerability=CVE-1; status=bogus"}]}]}`,
+		"no vuln":        `{"packages":[{"SPDXID":"SPDXRef-A","annotations":[{"comment":"openvex: status=fixed"}]}]}`,
+		"no product":     `{"annotations":[{"comment":"openvex: vulnerability=CVE-1; status=fixed"}]}`,
+	} {
+		_, err := FromSPDX(strings.NewReader(data))
+		require.Error(t, err, name)
+	}
+}
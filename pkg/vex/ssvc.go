@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SSVC decision point values
+const (
+	SSVCExploitationNone   = "none"
+	SSVCExploitationPoC    = "poc"
+	SSVCExploitationActive = "active"
+
+	SSVCAutomatableNo  = "no"
+	SSVCAutomatableYes = "yes"
+
+	SSVCTechnicalImpactPartial = "partial"
+	SSVCTechnicalImpactTotal   = "total"
+
+	SSVCMissionImpactLow    = "low"
+	SSVCMissionImpactMedium = "medium"
+	SSVCMissionImpactHigh   = "high"
+)
+
+// SSVCDecision is the outcome of the CISA SSVC decision tree.
+type SSVCDecision string
+
+// SSVC decisions, from the least to the most urgent
+const (
+	// SSVCTrack means the vulnerability is tracked and remediated within
+	// the standard update timelines
+	SSVCTrack SSVCDecision = "Track"
+
+	// SSVCTrackStar means the vulnerability has characteristics that
+	// require closer monitoring
+	SSVCTrackStar SSVCDecision = "Track*"
+
+	// SSVCAttend means the vulnerability requires attention from
+	// leadership and remediation sooner than standard timelines
+	SSVCAttend SSVCDecision = "Attend"
+
+	// SSVCAct means the vulnerability requires remediation as soon as
+	// possible
+	SSVCAct SSVCDecision = "Act"
+)
+
+// SSVC records the Stakeholder-Specific Vulnerability Categorization
+// decision points of a statement, so the impact assessment of the VEX
+// statement can feed SSVC based triage.
+//
+// https://www.cisa.gov/stakeholder-specific-vulnerability-categorization-ssvc
+type SSVC struct {
+	// Exploitation is the current state of exploitation: none, poc or
+	// active
+	Exploitation string `json:"exploitation"`
+
+	// Automatable tells if the exploitation can be automated: yes or no
+	Automatable string `json:"automatable"`
+
+	// TechnicalImpact is the control gained by exploiting the
+	// vulnerability: partial or total
+	TechnicalImpact string `json:"technical_impact"`
+
+	// MissionImpact is the impact on the mission and well-being of the
+	// organization: low, medium or high
+	MissionImpact string `json:"mission_impact"`
+
+	// Decision is the outcome recorded by the author, see Evaluate
+	Decision SSVCDecision `json:"decision,omitempty"`
+}
+
+// ssvcDecisionPoints are the values of each decision point in the order of
+// the tree
+var ssvcDecisionPoints = [][]string{
+	{SSVCExploitationNone, SSVCExploitationPoC, SSVCExploitationActive},
+	{SSVCAutomatableNo, SSVCAutomatableYes},
+	{SSVCTechnicalImpactPartial, SSVCTechnicalImpactTotal},
+	{SSVCMissionImpactLow, SSVCMissionImpactMedium, SSVCMissionImpactHigh},
+}
+
+// ssvcTree holds the decisions of the CISA tree indexed by the position of
+// the values of the decision points. Each line has the decisions for the
+// mission impacts of a combination of exploitation, automatable and
+// technical impact.
+var ssvcTree = []SSVCDecision{
+	// none
+	SSVCTrack, SSVCTrack, SSVCTrack, // no, partial
+	SSVCTrack, SSVCTrack, SSVCTrackStar, // no, total
+	SSVCTrack, SSVCTrack, SSVCAttend, // yes, partial
+	SSVCTrack, SSVCTrack, SSVCAttend, // yes, total
+	// poc
+	SSVCTrack, SSVCTrack, SSVCTrackStar, // no, partial
+	SSVCTrack, SSVCTrackStar, SSVCAttend, // no, total
+	SSVCTrack, SSVCTrack, SSVCAttend, // yes, partial
+	SSVCTrack, SSVCTrackStar, SSVCAttend, // yes, total
+	// active
+	SSVCTrack, SSVCTrack, SSVCAttend, // no, partial
+	SSVCTrack, SSVCAttend, SSVCAct, // no, total
+	SSVCAttend, SSVCAttend, SSVCAct, // yes, partial
+	SSVCAttend, SSVCAct, SSVCAct, // yes, total
+}
+
+// Evaluate returns the decision of the CISA SSVC tree for the decision
+// points. It returns an error if a decision point is missing or has an
+// invalid value.
+func (s *SSVC) Evaluate() (SSVCDecision, error) {
+	values := []struct{ name, value string }{
+		{"exploitation", s.Exploitation},
+		{"automatable", s.Automatable},
+		{"technical_impact", s.TechnicalImpact},
+		{"mission_impact", s.MissionImpact},
+	}
+	i := 0
+	for n, v := range values {
+		pos := slices.Index(ssvcDecisionPoints[n], v.value)
+		if pos < 0 {
+			return "", fmt.Errorf("invalid SSVC %s %q, must be one of %v", v.name, v.value, ssvcDecisionPoints[n])
+		}
+		i = i*len(ssvcDecisionPoints[n]) + pos
+	}
+	return ssvcTree[i], nil
+}
+
+// EvaluateSSVC evaluates the SSVC decision points of the statements that
+// have them and records the decisions in the statements. It returns an
+// error and leaves the document untouched if any statement has invalid
+// decision points.
+func (vexDoc *VEX) EvaluateSSVC() error {
+	decisions := make([]SSVCDecision, len(vexDoc.Statements))
+	for i := range vexDoc.Statements {
+		s := vexDoc.Statements[i].SSVC
+		if s == nil {
+			continue
+		}
+		d, err := s.Evaluate()
+		if err != nil {
+			return fmt.Errorf("statement %d: %w", i, err)
+		}
+		decisions[i] = d
+	}
+	for i := range vexDoc.Statements {
+		if vexDoc.Statements[i].SSVC != nil {
+			vexDoc.Statements[i].SSVC.Decision = decisions[i]
+		}
+	}
+	return nil
+}
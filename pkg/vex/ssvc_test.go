@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSVCEvaluate(t *testing.T) {
+	for _, tc := range []struct {
+		ssvc     SSVC
+		decision SSVCDecision
+	}{
+		{SSVC{Exploitation: "none", Automatable: "no", TechnicalImpact: "partial", MissionImpact: "low"}, SSVCTrack},
+		{SSVC{Exploitation: "none", Automatable: "no", TechnicalImpact: "total", MissionImpact: "high"}, SSVCTrackStar},
+		{SSVC{Exploitation: "poc", Automatable: "yes", TechnicalImpact: "total", MissionImpact: "medium"}, SSVCTrackStar},
+		{SSVC{Exploitation: "poc", Automatable: "no", TechnicalImpact: "total", MissionImpact: "high"}, SSVCAttend},
+		{SSVC{Exploitation: "active", Automatable: "no", TechnicalImpact: "total", MissionImpact: "high"}, SSVCAct},
+		{SSVC{Exploitation: "active", Automatable: "yes", TechnicalImpact: "partial", MissionImpact: "low"}, SSVCAttend},
+		{SSVC{Exploitation: "active", Automatable: "yes", TechnicalImpact: "total", MissionImpact: "high"}, SSVCAct},
+	} {
+		d, err := tc.ssvc.Evaluate()
+		require.NoError(t, err)
+		require.Equal(t, tc.decision, d, "%+v", tc.ssvc)
+	}
+
+	_, err := (&SSVC{Exploitation: "widespread", Automatable: "no", TechnicalImpact: "partial", MissionImpact: "low"}).Evaluate()
+	require.Error(t, err)
+	_, err = (&SSVC{Exploitation: "none", Automatable: "no", TechnicalImpact: "partial"}).Evaluate()
+	require.Error(t, err)
+}
+
+func TestEvaluateSSVC(t *testing.T) {
+	doc := New(WithID("https://example.com/vex/1"))
+	doc.Statements = []Statement{
+		{
+			Vulnerability:   Vulnerability{Name: "CVE-2023-1111"},
+			Products:        []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:          StatusAffected,
+			ActionStatement: "Update",
+			SSVC:            &SSVC{Exploitation: "active", Automatable: "yes", TechnicalImpact: "total", MissionImpact: "medium"},
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusFixed,
+		},
+	}
+	require.NoError(t, doc.EvaluateSSVC())
+	require.Equal(t, SSVCAct, doc.Statements[0].SSVC.Decision)
+	require.Nil(t, doc.Statements[1].SSVC)
+
+	data, err := json.Marshal(&doc)
+	require.NoError(t, err)
+	require.NoError(t, Validate(data))
+
+	clone := doc.Statements[0].Clone()
+	clone.SSVC.Decision = SSVCTrack
+	require.Equal(t, SSVCAct, doc.Statements[0].SSVC.Decision)
+
+	doc.Statements[1].SSVC = &SSVC{Exploitation: "none"}
+	require.Error(t, doc.EvaluateSSVC())
+}
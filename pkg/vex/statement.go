@@ -6,7 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 package vex
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -62,14 +65,50 @@ type Statement struct {
 	// SHOULD describe actions to remediate or mitigate [vul_id].
 	ActionStatement          string     `json:"action_statement,omitempty"`
 	ActionStatementTimestamp *time.Time `json:"action_statement_timestamp,omitempty"`
+
+	// SSVC optionally records the SSVC decision points of the statement.
+	SSVC *SSVC `json:"ssvc,omitempty"`
+
+	// References links to information about the statement, such as the
+	// fix of the vulnerability in the products.
+	References []Reference `json:"references,omitempty"`
+
+	// Fix records the fixed versions or releases of statements with the
+	// fixed status.
+	Fix *Fix `json:"fix,omitempty"`
+
+	// RawExtensions holds the fields of the serialized statement which are
+	// not part of the spec, keyed by name. See VEX.RawExtensions.
+	RawExtensions map[string]json.RawMessage `json:"-" yaml:"-"`
 }
 
-// Validate checks to see whether the given Statement is valid. If it's not, an
-// error is returned explaining the reason the Statement is invalid. Otherwise,
-// nil is returned.
+// Validate checks to see whether the given Statement is valid. If it's not, a
+// *ValidationError is returned explaining the reason the Statement is invalid
+// and pointing to the offending field. Otherwise, nil is returned.
 func (stmt Statement) Validate() error { //nolint:gocritic // turning off for rule hugeParam
+	if err := stmt.Vulnerability.Validate(); err != nil {
+		return withPathPrefix("/vulnerability", err)
+	}
+	if err := validateReferences(stmt.References); err != nil {
+		return err
+	}
+	if stmt.Fix != nil {
+		if stmt.Status != StatusFixed {
+			return &ValidationError{
+				Path: "/fix",
+				Err:  fmt.Errorf("fix should not be set when using status %q", stmt.Status),
+			}
+		}
+		if err := stmt.Fix.Validate(); err != nil {
+			return withPathPrefix("/fix", err)
+		}
+	}
+
 	if s := stmt.Status; !s.Valid() {
-		return fmt.Errorf("invalid status value %q, must be one of [%s]", s, strings.Join(Statuses(), ", "))
+		return &ValidationError{
+			Path: "/status",
+			Err:  fmt.Errorf("invalid status value %q, must be one of [%s]", s, strings.Join(Statuses(), ", ")),
+		}
 	}
 
 	switch s := stmt.Status; s {
@@ -78,65 +117,122 @@ func (stmt Statement) Validate() error { //nolint:gocritic // turning off for ru
 		j := stmt.Justification
 		is := stmt.ImpactStatement
 		if j == "" && is == "" {
-			return fmt.Errorf("either justification or impact statement must be defined when using status %q", s)
+			return &ValidationError{
+				Path: "/justification",
+				Err:  fmt.Errorf("either justification or impact statement must be defined when using status %q", s),
+			}
 		}
 
 		if j != "" && !j.Valid() {
-			return fmt.Errorf("invalid justification value %q, must be one of [%s]", j, strings.Join(Justifications(), ", "))
+			return &ValidationError{
+				Path: "/justification",
+				Err:  fmt.Errorf("invalid justification value %q, must be one of [%s]", j, strings.Join(Justifications(), ", ")),
+			}
 		}
 
 		// irrelevant fields should not be set
 		if v := stmt.ActionStatement; v != "" {
-			return fmt.Errorf("action statement should not be set when using status %q (was set to %q)", s, v)
+			return &ValidationError{
+				Path: "/action_statement",
+				Err:  fmt.Errorf("action statement should not be set when using status %q (was set to %q)", s, v),
+			}
 		}
 
 	case StatusAffected:
 		// irrelevant fields should not be set
 		if v := stmt.Justification; v != "" {
-			return fmt.Errorf("justification should not be set when using status %q (was set to %q)", s, v)
+			return &ValidationError{
+				Path: "/justification",
+				Err:  fmt.Errorf("justification should not be set when using status %q (was set to %q)", s, v),
+			}
 		}
 
 		if v := stmt.ImpactStatement; v != "" {
-			return fmt.Errorf("impact statement should not be set when using status %q (was set to %q)", s, v)
+			return &ValidationError{
+				Path: "/impact_statement",
+				Err:  fmt.Errorf("impact statement should not be set when using status %q (was set to %q)", s, v),
+			}
 		}
 
 		// action statement is now required
 		if v := stmt.ActionStatement; v == "" {
-			return fmt.Errorf("action statement must be set when using status %q", s)
+			return &ValidationError{
+				Path: "/action_statement",
+				Err:  fmt.Errorf("action statement must be set when using status %q", s),
+			}
 		}
 
 	case StatusUnderInvestigation:
 		// irrelevant fields should not be set
 		if v := stmt.Justification; v != "" {
-			return fmt.Errorf("justification should not be set when using status %q (was set to %q)", s, v)
+			return &ValidationError{
+				Path: "/justification",
+				Err:  fmt.Errorf("justification should not be set when using status %q (was set to %q)", s, v),
+			}
 		}
 
 		if v := stmt.ImpactStatement; v != "" {
-			return fmt.Errorf("impact statement should not be set when using status %q (was set to %q)", s, v)
+			return &ValidationError{
+				Path: "/impact_statement",
+				Err:  fmt.Errorf("impact statement should not be set when using status %q (was set to %q)", s, v),
+			}
 		}
 
 		if v := stmt.ActionStatement; v != "" {
-			return fmt.Errorf("action statement should not be set when using status %q (was set to %q)", s, v)
+			return &ValidationError{
+				Path: "/action_statement",
+				Err:  fmt.Errorf("action statement should not be set when using status %q (was set to %q)", s, v),
+			}
 		}
 
 	case StatusFixed:
 		// irrelevant fields should not be set
 		if v := stmt.Justification; v != "" {
-			return fmt.Errorf("justification should not be set when using status %q (was set to %q)", s, v)
+			return &ValidationError{
+				Path: "/justification",
+				Err:  fmt.Errorf("justification should not be set when using status %q (was set to %q)", s, v),
+			}
 		}
 
 		if v := stmt.ImpactStatement; v != "" {
-			return fmt.Errorf("impact statement should not be set when using status %q (was set to %q)", s, v)
+			return &ValidationError{
+				Path: "/impact_statement",
+				Err:  fmt.Errorf("impact statement should not be set when using status %q (was set to %q)", s, v),
+			}
 		}
 
 		if v := stmt.ActionStatement; v != "" {
-			return fmt.Errorf("action statement should not be set when using status %q (was set to %q)", s, v)
+			return &ValidationError{
+				Path: "/action_statement",
+				Err:  fmt.Errorf("action statement should not be set when using status %q (was set to %q)", s, v),
+			}
 		}
 	}
 
 	return nil
 }
 
+// ErrMissingJustification is wrapped in the *ValidationError returned by
+// ValidateJustification when a not_affected statement has neither a
+// justification nor an impact statement.
+var ErrMissingJustification = errors.New("not_affected statements must have a justification or impact statement")
+
+// ValidateJustification checks the justification requirements of the spec:
+// statements with a not_affected status must define a justification or an
+// impact statement and, when set, the justification must be a known value.
+func (stmt *Statement) ValidateJustification() error {
+	if stmt.Status == StatusNotAffected && stmt.Justification == "" && stmt.ImpactStatement == "" {
+		return &ValidationError{Path: "/justification", Err: ErrMissingJustification}
+	}
+	if j := stmt.Justification; j != "" && !j.Valid() {
+		return &ValidationError{
+			Path: "/justification",
+			Err:  fmt.Errorf("invalid justification value %q, must be one of [%s]", j, strings.Join(Justifications(), ", ")),
+		}
+	}
+	return nil
+}
+
 // SortStatements does an "in-place" sort of the given slice of VEX statements.
 //
 // The documentTimestamp parameter is needed because statements without timestamps inherit the timestamp of the document.
@@ -173,6 +269,79 @@ func SortStatements(stmts []Statement, documentTimestamp time.Time) {
 	})
 }
 
+// SortCanonical sorts the statements of the document in canonical order:
+// by vulnerability name, then by the identifiers of their products and
+// subcomponents and then by timestamp. Statements without a timestamp
+// inherit the one of the document. Ties are broken by status,
+// justification and statement ID, so the order does not depend on the order
+// the statements were added in. Unlike the SortStatements function, which
+// orders statements by time, it gives a stable order to serialize documents.
+func (vexDoc *VEX) SortCanonical() {
+	var t time.Time
+	if vexDoc.Timestamp != nil {
+		t = *vexDoc.Timestamp
+	}
+	sort.SliceStable(vexDoc.Statements, func(i, j int) bool {
+		return compareStatements(&vexDoc.Statements[i], &vexDoc.Statements[j], t) < 0
+	})
+}
+
+// compareStatements compares two statements in canonical order.
+func compareStatements(a, b *Statement, documentTimestamp time.Time) int {
+	if c := strings.Compare(string(a.Vulnerability.Name), string(b.Vulnerability.Name)); c != 0 {
+		return c
+	}
+	if c := slices.Compare(productKeys(a), productKeys(b)); c != 0 {
+		return c
+	}
+	if c := statementTime(a, documentTimestamp).Compare(statementTime(b, documentTimestamp)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(string(a.Status), string(b.Status)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(string(a.Justification), string(b.Justification)); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+// productKeys returns a sorted key for each product of the statement made
+// of its identifier and the sorted identifiers of its subcomponents.
+func productKeys(stmt *Statement) []string {
+	keys := make([]string, 0, len(stmt.Products))
+	for i := range stmt.Products {
+		subs := make([]string, 0, len(stmt.Products[i].Subcomponents))
+		for j := range stmt.Products[i].Subcomponents {
+			subs = append(subs, stmt.Products[i].Subcomponents[j].ID)
+		}
+		sort.Strings(subs)
+		keys = append(keys, strings.Join(append([]string{stmt.Products[i].ID}, subs...), " "))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EffectiveTime returns the time the statement takes effect: its timestamp
+// or, when it has none, the timestamp of the document. Zero timestamps count
+// as missing, as in Index. The zero time is returned when neither is set.
+func (stmt *Statement) EffectiveTime(doc *VEX) time.Time {
+	var t time.Time
+	if doc != nil && doc.Timestamp != nil {
+		t = *doc.Timestamp
+	}
+	return statementTime(stmt, t)
+}
+
+// statementTime returns the timestamp of the statement or the one of the
+// document if it has none.
+func statementTime(stmt *Statement, documentTimestamp time.Time) time.Time {
+	if stmt.Timestamp == nil || stmt.Timestamp.IsZero() {
+		return documentTimestamp
+	}
+	return *stmt.Timestamp
+}
+
 // Matches returns true if the statement matches the specified vulnerability
 // identifier, the VEX product and any of the identifiers from the received list.
 func (stmt *Statement) Matches(vuln, product string, subcomponents []string) bool {
@@ -206,3 +375,71 @@ func (stmt *Statement) MatchesProduct(identifier, subidentifier string) bool {
 	}
 	return false
 }
+
+// Clone returns a deep copy of the statement. Changes to the products,
+// aliases or timestamps of the copy don't affect the original.
+func (stmt *Statement) Clone() Statement {
+	ret := *stmt
+	ret.Vulnerability = stmt.Vulnerability.Clone()
+	ret.Timestamp = cloneTime(stmt.Timestamp)
+	ret.LastUpdated = cloneTime(stmt.LastUpdated)
+	ret.ActionStatementTimestamp = cloneTime(stmt.ActionStatementTimestamp)
+	if stmt.References != nil {
+		ret.References = append([]Reference{}, stmt.References...)
+	}
+	if stmt.Fix != nil {
+		ret.Fix = stmt.Fix.Clone()
+	}
+	if stmt.SSVC != nil {
+		ssvc := *stmt.SSVC
+		ret.SSVC = &ssvc
+	}
+	ret.RawExtensions = cloneRawExtensions(stmt.RawExtensions)
+	if stmt.Products != nil {
+		ret.Products = make([]Product, len(stmt.Products))
+		for i := range stmt.Products {
+			ret.Products[i] = stmt.Products[i].Clone()
+		}
+	}
+	return ret
+}
+
+// StampProducts uses the statement as a template and returns a copy of it
+// for each of the products, for example to record the same not_affected
+// status and justification for many images. The products of the template
+// are replaced in the copies. Statement IDs must be unique in a document so
+// the copies have none.
+func (stmt *Statement) StampProducts(products ...Product) []Statement {
+	ret := make([]Statement, 0, len(products))
+	for i := range products {
+		s := stmt.Clone()
+		s.ID = ""
+		s.Products = []Product{products[i].Clone()}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+// StampVulnerabilities uses the statement as a template and returns a copy
+// of it for each of the vulnerabilities, keeping the products of the
+// template. Statement IDs must be unique in a document so the copies have
+// none.
+func (stmt *Statement) StampVulnerabilities(vulns ...Vulnerability) []Statement {
+	ret := make([]Statement, 0, len(vulns))
+	for i := range vulns {
+		s := stmt.Clone()
+		s.ID = ""
+		s.Vulnerability = vulns[i].Clone()
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+// cloneTime returns a pointer to a copy of t, or nil if t is nil.
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementClone(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	stmt := Statement{
+		ID:            "https://example.com/vex/1#stmt-1",
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111", Aliases: []VulnerabilityID{"GHSA-aaaa-bbbb-cccc"}},
+		Timestamp:     &ts,
+		Products: []Product{{
+			Component: Component{
+				ID:          "pkg:oci/app",
+				Hashes:      map[Algorithm]Hash{SHA256: "abc"},
+				Identifiers: map[IdentifierType]string{PURL: "pkg:oci/app"},
+			},
+			Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:apk/wolfi/openssl"}}},
+		}},
+		Status:        StatusNotAffected,
+		Justification: ComponentNotPresent,
+	}
+
+	c := stmt.Clone()
+	require.Equal(t, stmt, c)
+
+	c.Vulnerability.Aliases[0] = "changed"
+	*c.Timestamp = ts.Add(time.Hour)
+	c.Products[0].Hashes[SHA256] = "changed"
+	c.Products[0].Identifiers[PURL] = "changed"
+	c.Products[0].Subcomponents[0].ID = "changed"
+	require.Equal(t, VulnerabilityID("GHSA-aaaa-bbbb-cccc"), stmt.Vulnerability.Aliases[0])
+	require.Equal(t, ts, *stmt.Timestamp)
+	require.Equal(t, Hash("abc"), stmt.Products[0].Hashes[SHA256])
+	require.Equal(t, "pkg:oci/app", stmt.Products[0].Identifiers[PURL])
+	require.Equal(t, "pkg:apk/wolfi/openssl", stmt.Products[0].Subcomponents[0].ID)
+
+	// Nil fields stay nil
+	c = (&Statement{}).Clone()
+	require.Nil(t, c.Products)
+	require.Nil(t, c.Timestamp)
+	require.Nil(t, c.Vulnerability.Aliases)
+}
+
+func TestStatementStamp(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	tmpl := Statement{
+		ID:            "https://example.com/vex/1#stmt-1",
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+		Timestamp:     &ts,
+		Products:      []Product{{Component: Component{ID: "pkg:oci/template"}}},
+		Status:        StatusNotAffected,
+		Justification: ComponentNotPresent,
+	}
+
+	stmts := tmpl.StampProducts(
+		Product{Component: Component{ID: "pkg:oci/app1"}},
+		Product{Component: Component{ID: "pkg:oci/app2"}},
+	)
+	require.Len(t, stmts, 2)
+	for i, s := range stmts {
+		require.Empty(t, s.ID)
+		require.Equal(t, tmpl.Vulnerability, s.Vulnerability)
+		require.Equal(t, ComponentNotPresent, s.Justification)
+		require.Len(t, s.Products, 1)
+		require.NoError(t, s.Validate(), i)
+	}
+	require.Equal(t, "pkg:oci/app1", stmts[0].Products[0].ID)
+	require.Equal(t, "pkg:oci/app2", stmts[1].Products[0].ID)
+	require.NotSame(t, stmts[0].Timestamp, stmts[1].Timestamp)
+
+	stmts = tmpl.StampVulnerabilities(Vulnerability{Name: "CVE-2023-2222"}, Vulnerability{Name: "CVE-2023-3333"})
+	require.Len(t, stmts, 2)
+	require.Equal(t, VulnerabilityID("CVE-2023-2222"), stmts[0].Vulnerability.Name)
+	require.Equal(t, VulnerabilityID("CVE-2023-3333"), stmts[1].Vulnerability.Name)
+	require.Equal(t, tmpl.Products, stmts[1].Products)
+	stmts[1].Products[0].ID = "changed"
+	require.Equal(t, "pkg:oci/template", tmpl.Products[0].ID)
+}
+
+func TestVEXSortStatements(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	stmt := func(vuln, product string, ts *time.Time, status Status) Statement {
+		return Statement{
+			Vulnerability: Vulnerability{Name: VulnerabilityID(vuln)},
+			Products:      []Product{{Component: Component{ID: product}}},
+			Timestamp:     ts,
+			Status:        status,
+		}
+	}
+	statements := []Statement{
+		stmt("CVE-2023-2222", "pkg:oci/b", nil, StatusAffected),
+		stmt("CVE-2023-1111", "pkg:oci/b", &t1, StatusFixed),
+		stmt("CVE-2023-1111", "pkg:oci/a", &t2, StatusFixed),
+		stmt("CVE-2023-1111", "pkg:oci/a", nil, StatusAffected),
+		stmt("CVE-2023-1111", "pkg:oci/a", &t1, StatusUnderInvestigation),
+	}
+	expected := []Statement{statements[3], statements[4], statements[2], statements[1], statements[0]}
+
+	// The order does not depend on the input order
+	for _, perm := range [][]int{{0, 1, 2, 3, 4}, {4, 3, 2, 1, 0}, {2, 0, 4, 1, 3}} {
+		doc := New()
+		doc.Timestamp = &t1
+		for _, i := range perm {
+			doc.Statements = append(doc.Statements, statements[i])
+		}
+		doc.SortCanonical()
+		require.Equal(t, expected, doc.Statements)
+	}
+
+	// Canonical serialization does not reorder the document
+	doc := New()
+	doc.Timestamp = &t1
+	doc.Statements = slices.Clone(statements)
+	var canonical bytes.Buffer
+	require.NoError(t, doc.Encode(&canonical, &EncodeOptions{Canonical: true}))
+	require.Equal(t, statements, doc.Statements)
+	parsed, err := Parse(canonical.Bytes())
+	require.NoError(t, err)
+	for i := range expected {
+		require.Equal(t, expected[i].Vulnerability.Name, parsed.Statements[i].Vulnerability.Name)
+		require.Equal(t, expected[i].Products[0].ID, parsed.Statements[i].Products[0].ID)
+		require.Equal(t, expected[i].Status, parsed.Statements[i].Status)
+	}
+}
+
+func TestStatementEffectiveTime(t *testing.T) {
+	docTime := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	stmtTime := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	zero := time.Time{}
+	doc := New()
+	doc.Timestamp = &docTime
+
+	require.Equal(t, stmtTime, (&Statement{Timestamp: &stmtTime}).EffectiveTime(&doc))
+	require.Equal(t, docTime, (&Statement{}).EffectiveTime(&doc))
+	// Zero timestamps are treated as missing
+	require.Equal(t, docTime, (&Statement{Timestamp: &zero}).EffectiveTime(&doc))
+	require.True(t, (&Statement{}).EffectiveTime(nil).IsZero())
+	doc.Timestamp = &zero
+	require.True(t, (&Statement{Timestamp: &zero}).EffectiveTime(&doc).IsZero())
+}
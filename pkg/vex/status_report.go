@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import "sort"
+
+// NoStatement is the classification used in status reports for
+// vulnerabilities that have no statement about a product. Note that this is
+// not a valid statement status.
+const NoStatement Status = "no_statement"
+
+// ProductStatusReport classifies a list of vulnerabilities by the effective
+// status they have on a product.
+type ProductStatusReport struct {
+	// Product is the product identifier the report was generated for
+	Product string `json:"product"`
+
+	// Vulnerabilities maps each queried vulnerability to its effective
+	// status on the product or NoStatement if the document has no data.
+	Vulnerabilities map[string]Status `json:"vulnerabilities"`
+
+	// Counts holds the number of vulnerabilities in each classification.
+	// All statuses and NoStatement are always present in the map.
+	Counts map[Status]int `json:"counts"`
+}
+
+// ProductStatus returns a report classifying each of the vulnerabilities in
+// vulnIDs into affected, not_affected, fixed, under_investigation or
+// no_statement based on the effective statement for the product.
+func (vexDoc *VEX) ProductStatus(product string, vulnIDs []string) *ProductStatusReport {
+	report := &ProductStatusReport{
+		Product:         product,
+		Vulnerabilities: map[string]Status{},
+		Counts: map[Status]int{
+			StatusNotAffected:        0,
+			StatusAffected:           0,
+			StatusFixed:              0,
+			StatusUnderInvestigation: 0,
+			NoStatement:              0,
+		},
+	}
+
+	for _, id := range vulnIDs {
+		if _, ok := report.Vulnerabilities[id]; ok {
+			continue
+		}
+		status := NoStatement
+		if s := vexDoc.EffectiveStatement(product, id); s != nil {
+			status = s.Status
+		}
+		report.Vulnerabilities[id] = status
+		report.Counts[status]++
+	}
+	return report
+}
+
+// ByStatus returns the sorted list of vulnerabilities in the report that
+// were classified with the specified status.
+func (report *ProductStatusReport) ByStatus(status Status) []string {
+	ret := []string{}
+	for id, s := range report.Vulnerabilities {
+		if s == status {
+			ret = append(ret, id)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
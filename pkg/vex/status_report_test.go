@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductStatus(t *testing.T) {
+	date1 := time.Date(2023, 4, 17, 20, 34, 58, 0, time.UTC)
+	date2 := time.Date(2023, 4, 18, 20, 34, 58, 0, time.UTC)
+	product := []Product{{Component: Component{ID: "pkg:apk/wolfi/bash@1.0.0"}}}
+	doc := &VEX{
+		Metadata: Metadata{Timestamp: &date1},
+		Statements: []Statement{
+			{Vulnerability: Vulnerability{Name: "CVE-2023-0001"}, Products: product, Status: StatusUnderInvestigation, Timestamp: &date1},
+			{Vulnerability: Vulnerability{Name: "CVE-2023-0001"}, Products: product, Status: StatusNotAffected, Timestamp: &date2},
+			{Vulnerability: Vulnerability{Name: "CVE-2023-0002"}, Products: product, Status: StatusAffected},
+			{Vulnerability: Vulnerability{Name: "CVE-2023-0003"}, Products: product, Status: StatusFixed},
+			{Vulnerability: Vulnerability{Name: "CVE-2023-0004"}, Products: product, Status: StatusUnderInvestigation},
+			{
+				Vulnerability: Vulnerability{Name: "CVE-2023-0005"},
+				Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/curl@1.0.0"}}},
+				Status:        StatusAffected,
+			},
+		},
+	}
+
+	report := doc.ProductStatus("pkg:apk/wolfi/bash@1.0.0", []string{
+		"CVE-2023-0001", "CVE-2023-0002", "CVE-2023-0003", "CVE-2023-0004", "CVE-2023-0005", "CVE-2023-0006", "CVE-2023-0001",
+	})
+
+	require.Equal(t, "pkg:apk/wolfi/bash@1.0.0", report.Product)
+	require.Len(t, report.Vulnerabilities, 6)
+	require.Equal(t, map[Status]int{
+		StatusNotAffected:        1,
+		StatusAffected:           1,
+		StatusFixed:              1,
+		StatusUnderInvestigation: 1,
+		NoStatement:              2,
+	}, report.Counts)
+	require.Equal(t, []string{"CVE-2023-0005", "CVE-2023-0006"}, report.ByStatus(NoStatement))
+	require.Equal(t, []string{"CVE-2023-0001"}, report.ByStatus(StatusNotAffected))
+}
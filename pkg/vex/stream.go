@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// StatementDecoder reads the statements of an OpenVEX JSON document one at
+// a time from a stream. Only the statement being decoded is kept in memory,
+// which keeps memory flat when reading very large documents.
+type StatementDecoder struct {
+	dec          *json.Decoder
+	metadata     map[string]json.RawMessage
+	started      bool
+	inStatements bool
+	done         bool
+	index        int
+	err          error
+}
+
+// NewStatementDecoder returns a decoder reading a document from r.
+func NewStatementDecoder(r io.Reader) *StatementDecoder {
+	return &StatementDecoder{
+		dec:      json.NewDecoder(r),
+		metadata: map[string]json.RawMessage{},
+	}
+}
+
+// Next returns the next statement in the document. It returns io.EOF when
+// the document has no more statements. Once Next fails, it keeps returning
+// the same error.
+func (d *StatementDecoder) Next() (*Statement, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	stmt, err := d.next()
+	if err != nil {
+		d.err = err
+		return nil, err
+	}
+	return stmt, nil
+}
+
+func (d *StatementDecoder) next() (*Statement, error) {
+	if d.done {
+		return nil, io.EOF
+	}
+	if !d.started {
+		if err := d.expectDelim('{'); err != nil {
+			return nil, err
+		}
+		d.started = true
+	}
+
+	for {
+		if d.inStatements {
+			if d.dec.More() {
+				stmt := &Statement{}
+				if err := d.dec.Decode(stmt); err != nil {
+					return nil, fmt.Errorf(
+						"%s: %w", errMsgParse, withPathPrefix(fmt.Sprintf("/statements/%d", d.index), validationErrorFromJSON(err)),
+					)
+				}
+				d.index++
+				return stmt, nil
+			}
+			if err := d.expectDelim(']'); err != nil {
+				return nil, err
+			}
+			d.inStatements = false
+			continue
+		}
+
+		if !d.dec.More() {
+			if err := d.expectDelim('}'); err != nil {
+				return nil, err
+			}
+			d.done = true
+			return nil, io.EOF
+		}
+
+		tok, err := d.dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%s: reading document: %w", errMsgParse, err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: unexpected token %v", errMsgParse, tok)
+		}
+
+		if key == "statements" {
+			tok, err := d.dec.Token()
+			if err != nil {
+				return nil, fmt.Errorf("%s: reading statements: %w", errMsgParse, err)
+			}
+			switch tok {
+			case json.Delim('['):
+				d.inStatements = true
+			case nil:
+			default:
+				return nil, fmt.Errorf("%s: %w", errMsgParse, &ValidationError{
+					Path: "/statements", Err: errors.New("statements must be an array"),
+				})
+			}
+			continue
+		}
+
+		raw := json.RawMessage{}
+		if err := d.dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("%s: reading %s: %w", errMsgParse, key, err)
+		}
+		d.metadata[key] = raw
+	}
+}
+
+func (d *StatementDecoder) expectDelim(delim json.Delim) error {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return fmt.Errorf("%s: reading document: %w", errMsgParse, err)
+	}
+	if tok != delim {
+		return fmt.Errorf("%s: expected %q but found %v", errMsgParse, delim, tok)
+	}
+	return nil
+}
+
+// Metadata returns the document metadata read so far. Fields may appear
+// after the statements in the document so the metadata is only complete
+// after Next returns io.EOF.
+func (d *StatementDecoder) Metadata() (*Metadata, error) {
+	data, err := json.Marshal(d.metadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling metadata: %w", err)
+	}
+	m := &Metadata{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%s: %w", errMsgParse, validationErrorFromJSON(err))
+	}
+	return m, nil
+}
+
+// DecodeStatements reads a document from r calling fn with each of its
+// statements as they are decoded. It stops at the first error returned by
+// fn. When the whole document has been read, it returns its metadata.
+func DecodeStatements(r io.Reader, fn func(*Statement) error) (*Metadata, error) {
+	d := NewStatementDecoder(r)
+	for {
+		stmt, err := d.Next()
+		if errors.Is(err, io.EOF) {
+			return d.Metadata()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(stmt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// StatementEncoder writes an OpenVEX JSON document to a stream. The document
+// metadata is written first and statements are appended as they are
+// produced, so generators don't need to hold the whole document in memory.
+// Close must be called to terminate the document.
+type StatementEncoder struct {
+	w      io.Writer
+	count  int
+	closed bool
+}
+
+// NewStatementEncoder writes the document metadata to w and returns an
+// encoder to write the statements.
+func NewStatementEncoder(w io.Writer, metadata *Metadata) (*StatementEncoder, error) {
+	data, err := marshalNoEscape(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling metadata: %w", err)
+	}
+	// Reopen the metadata object to append the statements array
+	data = append(data[:len(data)-1], []byte(`,"statements":[`)...)
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("writing metadata: %w", err)
+	}
+	return &StatementEncoder{w: w}, nil
+}
+
+// Encode appends a statement to the document.
+func (e *StatementEncoder) Encode(stmt *Statement) error {
+	if e.closed {
+		return errors.New("encoder is closed")
+	}
+	data, err := marshalNoEscape(stmt)
+	if err != nil {
+		return fmt.Errorf("marshaling statement %d: %w", e.count, err)
+	}
+	prefix := "\n"
+	if e.count > 0 {
+		prefix = ",\n"
+	}
+	if _, err := io.WriteString(e.w, prefix); err != nil {
+		return fmt.Errorf("writing statement %d: %w", e.count, err)
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return fmt.Errorf("writing statement %d: %w", e.count, err)
+	}
+	e.count++
+	return nil
+}
+
+// Close terminates the document. It does not close the underlying writer.
+func (e *StatementEncoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if _, err := io.WriteString(e.w, "\n]}\n"); err != nil {
+		return fmt.Errorf("closing document: %w", err)
+	}
+	return nil
+}
+
+// marshalNoEscape marshals v without escaping HTML characters, as ToJSON
+// does, and without the trailing newline added by the encoder.
+func marshalNoEscape(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementDecoder(t *testing.T) {
+	data, err := os.ReadFile("testdata/v0.2.0.json")
+	require.NoError(t, err)
+	expected, err := Parse(data)
+	require.NoError(t, err)
+
+	stmts := []Statement{}
+	meta, err := DecodeStatements(strings.NewReader(string(data)), func(s *Statement) error {
+		stmts = append(stmts, *s)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected.Metadata, *meta)
+	require.Equal(t, expected.Statements, stmts)
+}
+
+func TestStatementDecoderMetadataAfterStatements(t *testing.T) {
+	d := NewStatementDecoder(strings.NewReader(`{
+		"@context": "https://openvex.dev/ns/v0.2.0",
+		"statements": [
+			{"vulnerability": {"name": "CVE-2023-1111"}, "products": [{"@id": "pkg:oci/app"}], "status": "fixed"},
+			{"vulnerability": {"name": "CVE-2023-2222"}, "products": [{"@id": "pkg:oci/app"}], "status": "affected"}
+		],
+		"author": "Wolfi J Inkinson",
+		"version": 2
+	}`))
+
+	s, err := d.Next()
+	require.NoError(t, err)
+	require.Equal(t, StatusFixed, s.Status)
+
+	meta, err := d.Metadata()
+	require.NoError(t, err)
+	require.Equal(t, "https://openvex.dev/ns/v0.2.0", meta.Context)
+	require.Empty(t, meta.Author)
+
+	s, err = d.Next()
+	require.NoError(t, err)
+	require.Equal(t, "CVE-2023-2222", string(s.Vulnerability.Name))
+
+	_, err = d.Next()
+	require.ErrorIs(t, err, io.EOF)
+	_, err = d.Next()
+	require.ErrorIs(t, err, io.EOF)
+
+	meta, err = d.Metadata()
+	require.NoError(t, err)
+	require.Equal(t, "Wolfi J Inkinson", meta.Author)
+	require.Equal(t, 2, meta.Version)
+}
+
+func TestStatementDecoderErrors(t *testing.T) {
+	for name, doc := range map[string]string{
+		"not an object":     `[]`,
+		"statements object": `{"statements": {}}`,
+		"bad statement":     `{"statements": [{"status": "fixed"}, {"status": 1}]}`,
+		"truncated":         `{"statements": [{"status": "fixed"}`,
+	} {
+		_, err := DecodeStatements(strings.NewReader(doc), func(*Statement) error { return nil })
+		require.Error(t, err, name)
+	}
+
+	_, err := DecodeStatements(strings.NewReader(`{"statements": [{"status": "fixed"}, {"status": 1}]}`), func(*Statement) error { return nil })
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "/statements/1/status", verr.Path)
+
+	// Callback errors stop the decoding
+	stop := errors.New("stop")
+	n := 0
+	_, err = DecodeStatements(strings.NewReader(`{"statements": [{}, {}, {}]}`), func(*Statement) error {
+		n++
+		return stop
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, 1, n)
+}
+
+func TestStatementEncoder(t *testing.T) {
+	data, err := os.ReadFile("testdata/v0.2.0.json")
+	require.NoError(t, err)
+	expected, err := Parse(data)
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	enc, err := NewStatementEncoder(&buf, &expected.Metadata)
+	require.NoError(t, err)
+	for i := range expected.Statements {
+		require.NoError(t, enc.Encode(&expected.Statements[i]))
+	}
+	require.NoError(t, enc.Close())
+	require.Error(t, enc.Encode(&expected.Statements[0]))
+
+	doc, err := Parse([]byte(buf.String()))
+	require.NoError(t, err)
+	require.Equal(t, expected, doc)
+
+	// Documents without statements are valid too
+	buf.Reset()
+	enc, err = NewStatementEncoder(&buf, &expected.Metadata)
+	require.NoError(t, err)
+	require.NoError(t, enc.Close())
+	doc, err = Parse([]byte(buf.String()))
+	require.NoError(t, err)
+	require.Empty(t, doc.Statements)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+// ActiveStatements returns the statements of the document that are the
+// effective statement for at least one of the products they cover, in
+// document order. The rest have been superseded by newer statements and
+// are returned by SupersededStatements.
+func (vexDoc *VEX) ActiveStatements() []Statement {
+	return vexDoc.filterSuperseded(false)
+}
+
+// SupersededStatements returns the statements of the document overridden by
+// newer statements for all of the products they cover, in document order.
+// They are kept in the document, see SupersededBy to find out what
+// replaced them.
+func (vexDoc *VEX) SupersededStatements() []Statement {
+	return vexDoc.filterSuperseded(true)
+}
+
+// SupersededBy returns the statements of the document that override the
+// statement for some of its products, that is the effective statements for
+// those products when they are not the statement itself. The statement can
+// be a pointer into the document or a copy of one of its statements. The
+// list is empty if the statement is the effective one for all its products
+// or is not in the document.
+func (vexDoc *VEX) SupersededBy(stmt *Statement) []Statement {
+	idx := NewIndex(vexDoc)
+	target := vexDoc.findStatement(stmt)
+	ret := []Statement{}
+	if target == nil {
+		return ret
+	}
+	seen := map[*Statement]struct{}{}
+	for _, e := range idx.effectiveEntries(target) {
+		if e.statement == target {
+			continue
+		}
+		if _, ok := seen[e.statement]; ok {
+			continue
+		}
+		seen[e.statement] = struct{}{}
+		ret = append(ret, *e.statement)
+	}
+	return ret
+}
+
+// filterSuperseded returns the statements that are superseded or, if
+// superseded is false, the active ones.
+func (vexDoc *VEX) filterSuperseded(superseded bool) []Statement {
+	idx := NewIndex(vexDoc)
+	ret := []Statement{}
+	for i := range vexDoc.Statements {
+		s := &vexDoc.Statements[i]
+		active := false
+		entries := idx.effectiveEntries(s)
+		for _, e := range entries {
+			if e.statement == s {
+				active = true
+				break
+			}
+		}
+		// Statements without products to look up can't be overridden
+		if len(entries) == 0 {
+			active = true
+		}
+		if active != superseded {
+			ret = append(ret, *s)
+		}
+	}
+	return ret
+}
+
+// effectiveEntries returns the entry of the effective statement for each of
+// the products and subcomponents of a statement.
+func (idx *Index) effectiveEntries(s *Statement) []*indexEntry {
+	ret := []*indexEntry{}
+	last := func(entries []*indexEntry) {
+		if len(entries) > 0 {
+			ret = append(ret, entries[len(entries)-1])
+		}
+	}
+	for _, vuln := range vulnerabilityKeys(&s.Vulnerability) {
+		for i := range s.Products {
+			p := &s.Products[i]
+			if p.ID == "" {
+				continue
+			}
+			if len(p.Subcomponents) == 0 {
+				last(idx.matches(vuln, p.ID, nil))
+				continue
+			}
+			for j := range p.Subcomponents {
+				if p.Subcomponents[j].ID != "" {
+					last(idx.matches(vuln, p.ID, []string{p.Subcomponents[j].ID}))
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// findStatement returns a pointer to the statement of the document that is
+// or is equal to s, or nil if there is none.
+func (vexDoc *VEX) findStatement(s *Statement) *Statement {
+	for i := range vexDoc.Statements {
+		if &vexDoc.Statements[i] == s {
+			return s
+		}
+	}
+	k := diffKey(s)
+	for i := range vexDoc.Statements {
+		if diffKey(&vexDoc.Statements[i]) == k && len(diffStatement(&vexDoc.Statements[i], s)) == 0 {
+			return &vexDoc.Statements[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuperseded(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+
+	doc := New()
+	doc.Timestamp = &t1
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products: []Product{
+				{Component: Component{ID: "pkg:oci/app"}},
+				{Component: Component{ID: "pkg:oci/web"}},
+			},
+			Status: StatusUnderInvestigation,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+			Timestamp:     &t2,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusAffected,
+			Timestamp:     &t2,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusFixed,
+			Timestamp:     &t3,
+		},
+	}
+	original := doc.Clone()
+
+	active := doc.ActiveStatements()
+	require.Len(t, active, 3)
+	require.Equal(t, StatusUnderInvestigation, active[0].Status)
+	require.Equal(t, StatusNotAffected, active[1].Status)
+	require.Equal(t, StatusFixed, active[2].Status)
+
+	superseded := doc.SupersededStatements()
+	require.Len(t, superseded, 1)
+	require.Equal(t, StatusAffected, superseded[0].Status)
+
+	// Partially overridden statements list what overrides them
+	by := doc.SupersededBy(&doc.Statements[0])
+	require.Len(t, by, 1)
+	require.Equal(t, StatusNotAffected, by[0].Status)
+
+	// Copies of statements are found too
+	stmt := doc.Statements[2].Clone()
+	by = doc.SupersededBy(&stmt)
+	require.Len(t, by, 1)
+	require.Equal(t, StatusFixed, by[0].Status)
+
+	require.Empty(t, doc.SupersededBy(&doc.Statements[3]))
+	require.Empty(t, doc.SupersededBy(&Statement{Vulnerability: Vulnerability{Name: "CVE-2023-3333"}}))
+
+	// The document is not reordered
+	require.Equal(t, original.Statements, doc.Statements)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lint rules checking the coherence of the document timestamps.
+const (
+	// LintRuleUpdatedBeforeIssued flags documents or statements with a
+	// last_updated date earlier than their timestamp.
+	LintRuleUpdatedBeforeIssued = "OVX010"
+
+	// LintRuleFutureTimestamp flags timestamps in the future.
+	LintRuleFutureTimestamp = "OVX011"
+
+	// LintRuleMisplacedActionTimestamp flags action statement timestamps set
+	// on statements without an affected status.
+	LintRuleMisplacedActionTimestamp = "OVX012"
+)
+
+// DefaultTimestampTolerance is the clock skew allowed by Lint before flagging
+// a timestamp as being in the future.
+var DefaultTimestampTolerance = 5 * time.Minute
+
+// CheckTimestamps checks the coherence of the timestamps in the document and
+// its statements. Timestamps later than now plus the specified tolerance are
+// reported as being in the future.
+func (vexDoc *VEX) CheckTimestamps(tolerance time.Duration) []LintFinding {
+	limit := time.Now().Add(tolerance)
+	findings := checkTimestampPair("", vexDoc.Timestamp, vexDoc.LastUpdated, limit)
+
+	for i := range vexDoc.Statements {
+		stmt := &vexDoc.Statements[i]
+		path := fmt.Sprintf("/statements/%d", i)
+		findings = append(findings, checkTimestampPair(path, stmt.Timestamp, stmt.LastUpdated, limit)...)
+
+		if stmt.ActionStatementTimestamp == nil {
+			continue
+		}
+		if stmt.Status != StatusAffected {
+			findings = append(findings, LintFinding{
+				RuleID:   LintRuleMisplacedActionTimestamp,
+				Severity: LintError,
+				Path:     path + "/action_statement_timestamp",
+				Message:  fmt.Sprintf("action_statement_timestamp set on a statement with status %q", stmt.Status),
+			})
+		}
+		if stmt.ActionStatementTimestamp.After(limit) {
+			findings = append(findings, futureTimestampFinding(path+"/action_statement_timestamp", stmt.ActionStatementTimestamp))
+		}
+	}
+	return findings
+}
+
+// checkTimestampPair checks a timestamp and last_updated pair found in the
+// element at path.
+func checkTimestampPair(path string, issued, updated *time.Time, limit time.Time) []LintFinding {
+	findings := []LintFinding{}
+	if issued != nil && issued.After(limit) {
+		findings = append(findings, futureTimestampFinding(path+"/timestamp", issued))
+	}
+	if updated != nil && updated.After(limit) {
+		findings = append(findings, futureTimestampFinding(path+"/last_updated", updated))
+	}
+	if issued != nil && updated != nil && updated.Before(*issued) {
+		findings = append(findings, LintFinding{
+			RuleID:   LintRuleUpdatedBeforeIssued,
+			Severity: LintError,
+			Path:     path + "/last_updated",
+			Message:  fmt.Sprintf("last_updated %s is before timestamp %s", updated, issued),
+		})
+	}
+	return findings
+}
+
+func futureTimestampFinding(path string, t *time.Time) LintFinding {
+	return LintFinding{
+		RuleID:   LintRuleFutureTimestamp,
+		Severity: LintError,
+		Path:     path,
+		Message:  fmt.Sprintf("timestamp %s is in the future", t),
+	}
+}
+
+// ErrMissingTimestamp is returned by ResolveTimestampsWithOptions in strict
+// mode when neither a statement nor its document have a timestamp.
+var ErrMissingTimestamp = errors.New("statement and document have no timestamp")
+
+// ResolveTimestampsOptions controls how statement timestamps are resolved.
+type ResolveTimestampsOptions struct {
+	// Strict makes the resolution fail with ErrMissingTimestamp when a
+	// statement cannot inherit a timestamp as the document has none.
+	Strict bool
+
+	// PreferLastUpdated makes statements inherit the last_updated date of
+	// the document, when set, instead of its timestamp. Some consumers read
+	// statements added in later versions of a document as issued then.
+	PreferLastUpdated bool
+
+	// ActionStatementTimestamps also sets the action statement timestamp
+	// of affected statements that have an action statement but no timestamp
+	// for it to the statement timestamp.
+	ActionStatementTimestamps bool
+}
+
+// ResolveTimestamps sets the timestamp of the statements without one to the
+// timestamp of the document, as the spec says they inherit it. Statements
+// are left without a timestamp if the document has none.
+func (vexDoc *VEX) ResolveTimestamps() error {
+	return vexDoc.ResolveTimestampsWithOptions(&ResolveTimestampsOptions{})
+}
+
+// ResolveTimestampsWithOptions sets the timestamp of the statements without
+// one to the one they inherit from the document, following the options. Zero
+// timestamps are treated as missing. The document is modified in place. Errors wrap a *ValidationError pointing to
+// the first statement that could not be resolved, in which case no
+// statement is modified.
+func (vexDoc *VEX) ResolveTimestampsWithOptions(opts *ResolveTimestampsOptions) error {
+	inherited := vexDoc.Timestamp
+	if opts.PreferLastUpdated && vexDoc.LastUpdated != nil && !vexDoc.LastUpdated.IsZero() {
+		inherited = vexDoc.LastUpdated
+	}
+	if inherited != nil && inherited.IsZero() {
+		inherited = nil
+	}
+
+	if inherited == nil && opts.Strict {
+		for i := range vexDoc.Statements {
+			if ts := vexDoc.Statements[i].Timestamp; ts == nil || ts.IsZero() {
+				return &ValidationError{Path: fmt.Sprintf("/statements/%d/timestamp", i), Err: ErrMissingTimestamp}
+			}
+		}
+	}
+
+	for i := range vexDoc.Statements {
+		stmt := &vexDoc.Statements[i]
+		if (stmt.Timestamp == nil || stmt.Timestamp.IsZero()) && inherited != nil {
+			stmt.Timestamp = cloneTime(inherited)
+		}
+		if opts.ActionStatementTimestamps && stmt.Status == StatusAffected &&
+			stmt.ActionStatement != "" && (stmt.ActionStatementTimestamp == nil || stmt.ActionStatementTimestamp.IsZero()) {
+			stmt.ActionStatementTimestamp = cloneTime(stmt.Timestamp)
+		}
+	}
+	return nil
+}
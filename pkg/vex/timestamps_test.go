@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTimestamps(t *testing.T) {
+	date1 := time.Date(2023, 4, 17, 20, 34, 58, 0, time.UTC)
+	date2 := time.Date(2023, 4, 18, 20, 34, 58, 0, time.UTC)
+	future := time.Now().Add(time.Hour)
+	nearFuture := time.Now().Add(time.Minute)
+
+	for m, tc := range map[string]struct {
+		doc   *VEX
+		rules []string
+		paths []string
+	}{
+		"coherent": {
+			doc: &VEX{
+				Metadata: Metadata{Timestamp: &date1, LastUpdated: &date2},
+				Statements: []Statement{
+					{Status: StatusAffected, Timestamp: &date1, LastUpdated: &date2, ActionStatementTimestamp: &date2},
+					{Status: StatusFixed, Timestamp: &nearFuture},
+				},
+			},
+			rules: []string{},
+			paths: []string{},
+		},
+		"updated before issued": {
+			doc: &VEX{
+				Metadata: Metadata{Timestamp: &date2, LastUpdated: &date1},
+				Statements: []Statement{
+					{Status: StatusFixed, Timestamp: &date2, LastUpdated: &date1},
+				},
+			},
+			rules: []string{LintRuleUpdatedBeforeIssued, LintRuleUpdatedBeforeIssued},
+			paths: []string{"/last_updated", "/statements/0/last_updated"},
+		},
+		"future timestamps": {
+			doc: &VEX{
+				Metadata: Metadata{Timestamp: &future},
+				Statements: []Statement{
+					{Status: StatusAffected, Timestamp: &date1, ActionStatementTimestamp: &future},
+				},
+			},
+			rules: []string{LintRuleFutureTimestamp, LintRuleFutureTimestamp},
+			paths: []string{"/timestamp", "/statements/0/action_statement_timestamp"},
+		},
+		"misplaced action timestamp": {
+			doc: &VEX{
+				Metadata: Metadata{Timestamp: &date1},
+				Statements: []Statement{
+					{Status: StatusNotAffected, ActionStatementTimestamp: &date1},
+				},
+			},
+			rules: []string{LintRuleMisplacedActionTimestamp},
+			paths: []string{"/statements/0/action_statement_timestamp"},
+		},
+		"no timestamps": {
+			doc:   &VEX{Statements: []Statement{{Status: StatusFixed}}},
+			rules: []string{},
+			paths: []string{},
+		},
+	} {
+		rules := []string{}
+		paths := []string{}
+		for _, f := range tc.doc.CheckTimestamps(5 * time.Minute) {
+			rules = append(rules, f.RuleID)
+			paths = append(paths, f.Path)
+		}
+		require.Equal(t, tc.rules, rules, m)
+		require.Equal(t, tc.paths, paths, m)
+	}
+}
+
+func TestResolveTimestamps(t *testing.T) {
+	issued := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	own := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	newDoc := func() *VEX {
+		return &VEX{
+			Metadata: Metadata{Timestamp: &issued, LastUpdated: &updated},
+			Statements: []Statement{
+				{Status: StatusAffected, ActionStatement: "Update"},
+				{Status: StatusFixed, Timestamp: &own},
+			},
+		}
+	}
+
+	doc := newDoc()
+	require.NoError(t, doc.ResolveTimestamps())
+	require.Equal(t, issued, *doc.Statements[0].Timestamp)
+	require.Equal(t, own, *doc.Statements[1].Timestamp)
+	require.Nil(t, doc.Statements[0].ActionStatementTimestamp)
+
+	// Inherited timestamps are copies
+	*doc.Statements[0].Timestamp = own
+	require.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), issued)
+
+	doc = newDoc()
+	require.NoError(t, doc.ResolveTimestampsWithOptions(&ResolveTimestampsOptions{
+		PreferLastUpdated:         true,
+		ActionStatementTimestamps: true,
+	}))
+	require.Equal(t, updated, *doc.Statements[0].Timestamp)
+	require.Equal(t, updated, *doc.Statements[0].ActionStatementTimestamp)
+
+	// Without a document timestamp statements are left as they are, unless
+	// the resolution is strict
+	doc = newDoc()
+	doc.Timestamp = nil
+	require.NoError(t, doc.ResolveTimestamps())
+	require.Nil(t, doc.Statements[0].Timestamp)
+
+	err := doc.ResolveTimestampsWithOptions(&ResolveTimestampsOptions{Strict: true})
+	require.ErrorIs(t, err, ErrMissingTimestamp)
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "/statements/0/timestamp", verr.Path)
+
+	doc.Statements[0].Timestamp = &own
+	require.NoError(t, doc.ResolveTimestampsWithOptions(&ResolveTimestampsOptions{Strict: true}))
+
+	// Zero timestamps are treated as missing
+	zero := time.Time{}
+	doc = newDoc()
+	doc.Statements[0].Timestamp = &zero
+	doc.Statements[0].ActionStatementTimestamp = &zero
+	require.NoError(t, doc.ResolveTimestampsWithOptions(&ResolveTimestampsOptions{ActionStatementTimestamps: true}))
+	require.Equal(t, issued, *doc.Statements[0].Timestamp)
+	require.Equal(t, issued, *doc.Statements[0].ActionStatementTimestamp)
+
+	doc = newDoc()
+	doc.Timestamp = &zero
+	doc.LastUpdated = &zero
+	doc.Statements[0].Timestamp = &zero
+	require.NoError(t, doc.ResolveTimestampsWithOptions(&ResolveTimestampsOptions{PreferLastUpdated: true}))
+	require.True(t, doc.Statements[0].Timestamp.IsZero())
+	err = doc.ResolveTimestampsWithOptions(&ResolveTimestampsOptions{Strict: true})
+	require.ErrorIs(t, err, ErrMissingTimestamp)
+}
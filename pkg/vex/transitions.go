@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// LintRuleSuspiciousTransition flags status changes in a statement history
+// that make little sense without an explanation in the status notes.
+const LintRuleSuspiciousTransition = "OVX009"
+
+// suspiciousTransitions lists the status changes that require the later
+// statement to explain itself in its status_notes.
+var suspiciousTransitions = map[Status]map[Status]struct{}{
+	StatusFixed: {
+		StatusAffected:           {},
+		StatusUnderInvestigation: {},
+	},
+	StatusNotAffected: {
+		StatusUnderInvestigation: {},
+	},
+}
+
+// CheckTransitions looks at the history of statements about a vulnerability
+// and product and returns findings for nonsensical status transitions, for
+// example a product going from fixed back to under_investigation without any
+// status notes explaining why.
+func (vexDoc *VEX) CheckTransitions(vulnID, product string) []LintFinding {
+	indices := []int{}
+	for i := range vexDoc.Statements {
+		if vexDoc.Statements[i].Matches(vulnID, product, nil) {
+			indices = append(indices, i)
+		}
+	}
+	return vexDoc.checkTransitions(indices, fmt.Sprintf("%s on %s", vulnID, product))
+}
+
+// checkTransitions checks the history formed by the statements at the
+// specified indices. The subject is used to describe the history in messages.
+func (vexDoc *VEX) checkTransitions(indices []int, subject string) []LintFinding {
+	var docTime time.Time
+	if vexDoc.Timestamp != nil {
+		docTime = *vexDoc.Timestamp
+	}
+	stmtTime := func(i int) time.Time {
+		if ts := vexDoc.Statements[i].Timestamp; ts != nil && !ts.IsZero() {
+			return *ts
+		}
+		return docTime
+	}
+
+	sorted := make([]int, len(indices))
+	copy(sorted, indices)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return stmtTime(sorted[a]).Before(stmtTime(sorted[b]))
+	})
+
+	findings := []LintFinding{}
+	for n := 1; n < len(sorted); n++ {
+		prev := &vexDoc.Statements[sorted[n-1]]
+		cur := &vexDoc.Statements[sorted[n]]
+		if _, ok := suspiciousTransitions[prev.Status][cur.Status]; !ok {
+			continue
+		}
+		if cur.StatusNotes != "" {
+			continue
+		}
+		findings = append(findings, LintFinding{
+			RuleID:   LintRuleSuspiciousTransition,
+			Severity: LintWarning,
+			Path:     fmt.Sprintf("/statements/%d", sorted[n]),
+			Message: fmt.Sprintf(
+				"status of %s changes from %s to %s without status notes explaining why",
+				subject, prev.Status, cur.Status,
+			),
+		})
+	}
+	return findings
+}
+
+// lintTransitions checks the histories of every vulnerability and product
+// identifier pair found in the document.
+func (vexDoc *VEX) lintTransitions() []LintFinding {
+	type pair struct{ vuln, product string }
+	histories := map[pair][]int{}
+	keys := []pair{}
+	for i := range vexDoc.Statements {
+		for _, p := range vexDoc.Statements[i].Products {
+			if p.ID == "" {
+				continue
+			}
+			k := pair{string(vexDoc.Statements[i].Vulnerability.Name), p.ID}
+			if _, ok := histories[k]; !ok {
+				keys = append(keys, k)
+			}
+			histories[k] = append(histories[k], i)
+		}
+	}
+
+	findings := []LintFinding{}
+	for _, k := range keys {
+		findings = append(findings, vexDoc.checkTransitions(histories[k], fmt.Sprintf("%s on %s", k.vuln, k.product))...)
+	}
+	return findings
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTransitions(t *testing.T) {
+	date1 := time.Date(2023, 4, 17, 20, 34, 58, 0, time.UTC)
+	date2 := time.Date(2023, 4, 18, 20, 34, 58, 0, time.UTC)
+	date3 := time.Date(2023, 4, 19, 20, 34, 58, 0, time.UTC)
+	product := []Product{{Component: Component{ID: "pkg:apk/wolfi/bash@1.0.0"}}}
+
+	for m, tc := range map[string]struct {
+		stmts []Statement
+		paths []string
+	}{
+		"coherent history": {
+			stmts: []Statement{
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusUnderInvestigation, Timestamp: &date1},
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusAffected, Timestamp: &date2},
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusFixed, Timestamp: &date3},
+			},
+			paths: []string{},
+		},
+		"fixed then under investigation": {
+			stmts: []Statement{
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusUnderInvestigation, Timestamp: &date3},
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusFixed, Timestamp: &date2},
+			},
+			paths: []string{"/statements/0"},
+		},
+		"explained regression": {
+			stmts: []Statement{
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusFixed, Timestamp: &date1},
+				{
+					Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusAffected, Timestamp: &date2,
+					StatusNotes: "The fix was reverted", ActionStatement: "Update",
+				},
+			},
+			paths: []string{},
+		},
+		"other vulnerability": {
+			stmts: []Statement{
+				{Vulnerability: Vulnerability{Name: "CVE-2023-1234"}, Products: product, Status: StatusNotAffected, Timestamp: &date1},
+				{Vulnerability: Vulnerability{Name: "CVE-2023-9999"}, Products: product, Status: StatusUnderInvestigation, Timestamp: &date2},
+			},
+			paths: []string{},
+		},
+	} {
+		doc := &VEX{Metadata: Metadata{Timestamp: &date1}, Statements: tc.stmts}
+		paths := []string{}
+		for _, f := range doc.CheckTransitions("CVE-2023-1234", "pkg:apk/wolfi/bash@1.0.0") {
+			require.Equal(t, LintRuleSuspiciousTransition, f.RuleID)
+			paths = append(paths, f.Path)
+		}
+		require.Equal(t, tc.paths, paths, m)
+
+		lintPaths := []string{}
+		for _, f := range doc.Lint() {
+			if f.RuleID == LintRuleSuspiciousTransition {
+				lintPaths = append(lintPaths, f.Path)
+			}
+		}
+		require.Equal(t, tc.paths, lintPaths, m)
+	}
+}
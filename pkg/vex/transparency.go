@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// TransparencyLogEntry records a document signature uploaded to a
+// transparency log such as Rekor.
+type TransparencyLogEntry struct {
+	// LogIndex is the index of the entry in the log
+	LogIndex int64 `json:"log_index"`
+
+	// LogID identifies the log instance
+	LogID string `json:"log_id,omitempty"`
+
+	// UUID is the identifier of the entry in the log
+	UUID string `json:"uuid,omitempty"`
+
+	// IntegratedTime is the unix time at which the entry was added to the log
+	IntegratedTime int64 `json:"integrated_time,omitempty"`
+
+	// Body is the base64 encoded entry as stored in the log
+	Body string `json:"body,omitempty"`
+
+	// SignedEntryTimestamp is the base64 encoded signature of the log over
+	// the entry, its index and integration time. It is the log's promise
+	// that the entry is included.
+	SignedEntryTimestamp string `json:"signed_entry_timestamp,omitempty"`
+
+	// InclusionProof proves the entry is included in the log
+	InclusionProof *InclusionProof `json:"inclusion_proof,omitempty"`
+}
+
+// InclusionProof is an RFC 6962 Merkle tree inclusion proof.
+type InclusionProof struct {
+	// LogIndex is the index of the leaf in the tree
+	LogIndex int64 `json:"log_index"`
+
+	// TreeSize is the size of the tree the proof was computed for
+	TreeSize int64 `json:"tree_size"`
+
+	// RootHash is the hex encoded root hash of the tree
+	RootHash string `json:"root_hash"`
+
+	// Hashes are the hex encoded hashes of the audit path
+	Hashes []string `json:"hashes"`
+}
+
+// Clone returns a deep copy of the entry and its inclusion proof.
+func (e *TransparencyLogEntry) Clone() TransparencyLogEntry {
+	ret := *e
+	if e.InclusionProof != nil {
+		proof := *e.InclusionProof
+		proof.Hashes = slices.Clone(e.InclusionProof.Hashes)
+		ret.InclusionProof = &proof
+	}
+	return ret
+}
+
+// SignedData returns the bytes covered by the document signature.
+func (sig *DocumentSignature) SignedData() []byte {
+	return []byte(documentSignaturePrefix + sig.DocumentHash)
+}
+
+// VerifyInclusion checks that the entry is included in the log whose public
+// key is logKey. The signed entry timestamp must be a valid signature of the
+// log over the entry and the log ID must identify the key. The entry must
+// carry an inclusion proof consistent with the entry body.
+func (e *TransparencyLogEntry) VerifyInclusion(logKey crypto.PublicKey) error {
+	if err := e.verifySignedEntryTimestamp(logKey); err != nil {
+		return err
+	}
+	if e.InclusionProof == nil {
+		return errors.New("entry has no inclusion proof")
+	}
+	body, err := base64.StdEncoding.DecodeString(e.Body)
+	if err != nil {
+		return fmt.Errorf("decoding entry body: %w", err)
+	}
+	return e.InclusionProof.verify(body)
+}
+
+// verifySignedEntryTimestamp checks the signature of the log over the entry.
+// As in Rekor, the log signs the canonical JSON of the entry body,
+// integration time, log ID and index, and the log ID is the SHA-256 hash of
+// the DER encoded log key.
+func (e *TransparencyLogEntry) verifySignedEntryTimestamp(logKey crypto.PublicKey) error {
+	if e.SignedEntryTimestamp == "" {
+		return errors.New("entry has no signed entry timestamp")
+	}
+	der, err := x509.MarshalPKIXPublicKey(logKey)
+	if err != nil {
+		return fmt.Errorf("encoding log key: %w", err)
+	}
+	if id := sha256.Sum256(der); e.LogID != hex.EncodeToString(id[:]) {
+		return fmt.Errorf("entry was not recorded by the log of this key (log ID %q)", e.LogID)
+	}
+	sig, err := base64.StdEncoding.DecodeString(e.SignedEntryTimestamp)
+	if err != nil {
+		return fmt.Errorf("decoding signed entry timestamp: %w", err)
+	}
+	payload, err := json.Marshal(struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{e.Body, e.IntegratedTime, e.LogID, e.LogIndex})
+	if err != nil {
+		return fmt.Errorf("marshaling signed entry timestamp payload: %w", err)
+	}
+	if payload, err = CanonicalizeJSON(payload); err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(payload)
+	switch key := logKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], sig) {
+			return errors.New("invalid signed entry timestamp")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, sig) {
+			return errors.New("invalid signed entry timestamp")
+		}
+	default:
+		return fmt.Errorf("unsupported log key type %T", logKey)
+	}
+	return nil
+}
+
+// verify checks that the leaf data is included in the tree of the proof
+func (p *InclusionProof) verify(body []byte) error {
+	root, err := hex.DecodeString(p.RootHash)
+	if err != nil {
+		return fmt.Errorf("decoding root hash: %w", err)
+	}
+	path := make([][]byte, 0, len(p.Hashes))
+	for _, h := range p.Hashes {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return fmt.Errorf("decoding proof hash: %w", err)
+		}
+		path = append(path, b)
+	}
+
+	if p.LogIndex < 0 || p.LogIndex >= p.TreeSize {
+		return fmt.Errorf("leaf index %d out of range for tree of size %d", p.LogIndex, p.TreeSize)
+	}
+
+	// RFC 9162 section 2.1.3.2
+	fn, sn := uint64(p.LogIndex), uint64(p.TreeSize-1)
+	r := merkleLeafHash(body)
+	for _, h := range path {
+		if sn == 0 {
+			return errors.New("inclusion proof is too long")
+		}
+		if fn%2 == 1 || fn == sn {
+			r = merkleNodeHash(h, r)
+			for fn%2 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNodeHash(r, h)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return errors.New("inclusion proof is too short")
+	}
+	if !bytes.Equal(r, root) {
+		return errors.New("inclusion proof does not match the root hash")
+	}
+	return nil
+}
+
+func merkleLeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func merkleNodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
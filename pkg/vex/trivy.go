@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// trivyDateFormat is the date format trivy expects in expiration dates
+const trivyDateFormat = "2006-01-02"
+
+// TrivyIgnore captures the data of a trivy ignore file. It can be written
+// in both the trivyignore.yaml format and the plain .trivyignore format.
+//
+// https://aquasecurity.github.io/trivy/latest/docs/configuration/filtering/#trivyignore
+type TrivyIgnore struct {
+	Vulnerabilities []TrivyIgnoreEntry `yaml:"vulnerabilities"`
+}
+
+// TrivyIgnoreEntry is a vulnerability to be ignored by trivy. When PURLs
+// is not empty, trivy only ignores the vulnerability in those packages.
+type TrivyIgnoreEntry struct {
+	ID        string   `yaml:"id"`
+	PURLs     []string `yaml:"purls,omitempty"`
+	Statement string   `yaml:"statement,omitempty"`
+	ExpiredAt string   `yaml:"expired_at,omitempty"`
+}
+
+// ToTrivyIgnore returns the ignore entries for the vulnerabilities that the
+// document marks as not_affected or fixed. Only the latest statement about
+// each vulnerability and package is considered, so a later affected
+// statement cancels an earlier not_affected one.
+//
+// Entries are scoped to the purls of the subcomponents of the statement
+// products or, when a product lists no subcomponents, to the product itself.
+// Components without a purl can't be expressed in trivy's format and are
+// skipped. Vulnerability aliases get their own entries.
+func (vexDoc *VEX) ToTrivyIgnore() *TrivyIgnore {
+	byVuln := map[string]*TrivyIgnoreEntry{}
+	for _, s := range vexDoc.packageSuppressions() {
+		entry, ok := byVuln[s.vulnerability]
+		if !ok {
+			entry = &TrivyIgnoreEntry{ID: s.vulnerability, Statement: suppressionReason(s.statement)}
+			byVuln[s.vulnerability] = entry
+		}
+		entry.PURLs = append(entry.PURLs, s.purl)
+	}
+
+	ignore := &TrivyIgnore{Vulnerabilities: []TrivyIgnoreEntry{}}
+	for _, entry := range byVuln {
+		ignore.Vulnerabilities = append(ignore.Vulnerabilities, *entry)
+	}
+	sort.Slice(ignore.Vulnerabilities, func(i, j int) bool {
+		return ignore.Vulnerabilities[i].ID < ignore.Vulnerabilities[j].ID
+	})
+	return ignore
+}
+
+// SetExpiry sets the date when trivy stops ignoring all entries.
+func (ignore *TrivyIgnore) SetExpiry(t time.Time) {
+	for i := range ignore.Vulnerabilities {
+		ignore.Vulnerabilities[i].ExpiredAt = t.UTC().Format(trivyDateFormat)
+	}
+}
+
+// ToYAML writes the entries in the trivyignore.yaml format.
+func (ignore *TrivyIgnore) ToYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(ignore); err != nil {
+		return fmt.Errorf("encoding trivy ignore file: %w", err)
+	}
+	return enc.Close()
+}
+
+// ToText writes the entries in the plain .trivyignore format. Note that the
+// plain format can't scope entries to packages: the purls are only written
+// as comments and trivy ignores the vulnerabilities in every package.
+func (ignore *TrivyIgnore) ToText(w io.Writer) error {
+	var sb strings.Builder
+	for _, entry := range ignore.Vulnerabilities {
+		if entry.Statement != "" {
+			sb.WriteString("# " + strings.ReplaceAll(entry.Statement, "\n", " ") + "\n")
+		}
+		for _, purl := range entry.PURLs {
+			sb.WriteString("# " + purl + "\n")
+		}
+		sb.WriteString(entry.ID)
+		if entry.ExpiredAt != "" {
+			sb.WriteString(" exp:" + entry.ExpiredAt)
+		}
+		sb.WriteString("\n")
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("writing trivy ignore file: %w", err)
+	}
+	return nil
+}
+
+// packageStatement is the latest statement about a vulnerability in a
+// package.
+type packageStatement struct {
+	vulnerability string
+	purl          string
+	statement     *Statement
+}
+
+// latestPackageStatements returns the latest statement about each
+// vulnerability and package pair in the document, sorted by vulnerability
+// and purl. Vulnerability aliases are expanded into their own pairs.
+func (vexDoc *VEX) latestPackageStatements() []packageStatement {
+	docTime := time.Time{}
+	if vexDoc.Timestamp != nil {
+		docTime = *vexDoc.Timestamp
+	}
+	stmts := make([]Statement, len(vexDoc.Statements))
+	copy(stmts, vexDoc.Statements)
+	SortStatements(stmts, docTime)
+
+	type key struct{ vuln, purl string }
+	latest := map[key]*Statement{}
+	for i := range stmts {
+		for _, purl := range statementPurls(&stmts[i]) {
+			for _, id := range vulnerabilityIDs(&stmts[i].Vulnerability) {
+				latest[key{id, purl}] = &stmts[i]
+			}
+		}
+	}
+
+	ret := []packageStatement{}
+	for k, stmt := range latest {
+		ret = append(ret, packageStatement{vulnerability: k.vuln, purl: k.purl, statement: stmt})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].vulnerability != ret[j].vulnerability {
+			return ret[i].vulnerability < ret[j].vulnerability
+		}
+		return ret[i].purl < ret[j].purl
+	})
+	return ret
+}
+
+// packageSuppressions returns the vulnerability and package pairs that
+// scanners can suppress: those whose latest statement is not_affected or
+// fixed.
+func (vexDoc *VEX) packageSuppressions() []packageStatement {
+	ret := []packageStatement{}
+	for _, ps := range vexDoc.latestPackageStatements() {
+		if ps.statement.Status == StatusNotAffected || ps.statement.Status == StatusFixed {
+			ret = append(ret, ps)
+		}
+	}
+	return ret
+}
+
+// statementPurls returns the purls of the packages a statement applies to:
+// the subcomponents of its products or the products that list none.
+func statementPurls(stmt *Statement) []string {
+	ret := []string{}
+	for i := range stmt.Products {
+		if len(stmt.Products[i].Subcomponents) == 0 {
+			if purl := componentPurl(&stmt.Products[i].Component); purl != "" {
+				ret = append(ret, purl)
+			}
+			continue
+		}
+		for j := range stmt.Products[i].Subcomponents {
+			if purl := componentPurl(&stmt.Products[i].Subcomponents[j].Component); purl != "" {
+				ret = append(ret, purl)
+			}
+		}
+	}
+	return ret
+}
+
+// componentPurl returns the purl of a component from its identifiers or its
+// ID, or an empty string if it has none.
+func componentPurl(c *Component) string {
+	if purl := c.Identifiers[PURL]; purl != "" {
+		return purl
+	}
+	if strings.HasPrefix(c.ID, "pkg:") {
+		return c.ID
+	}
+	return ""
+}
+
+// vulnerabilityIDs returns the name and aliases of a vulnerability.
+func vulnerabilityIDs(v *Vulnerability) []string {
+	ret := []string{}
+	if v.Name != "" {
+		ret = append(ret, string(v.Name))
+	}
+	for _, a := range v.Aliases {
+		if a != "" {
+			ret = append(ret, string(a))
+		}
+	}
+	return ret
+}
+
+// suppressionReason returns the text explaining why a scanner ignores a
+// vulnerability based on a statement.
+func suppressionReason(stmt *Statement) string {
+	parts := []string{string(stmt.Status)}
+	if stmt.Justification != "" {
+		parts = append(parts, string(stmt.Justification))
+	}
+	if stmt.ImpactStatement != "" {
+		parts = append(parts, stmt.ImpactStatement)
+	}
+	return strings.Join(parts, ": ")
+}
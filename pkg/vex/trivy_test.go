@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTrivyIgnore(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	doc := New()
+	doc.Timestamp = &t1
+	doc.Statements = []Statement{
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111", Aliases: []VulnerabilityID{"GHSA-aaaa-bbbb-cccc"}},
+			Products: []Product{{
+				Component:     Component{ID: "pkg:oci/app"},
+				Subcomponents: []Subcomponent{{Component: Component{ID: "pkg:golang/example.com/lib@v1.0.0"}}},
+			}},
+			Status:        StatusNotAffected,
+			Justification: VulnerableCodeNotInExecutePath,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-2222"},
+			Products: []Product{
+				{Component: Component{ID: "pkg:apk/wolfi/openssl@3.0.8"}},
+				{Component: Component{ID: "no-purl"}},
+			},
+			Status: StatusFixed,
+		},
+		{
+			Vulnerability: Vulnerability{Name: "CVE-2023-3333"},
+			Products:      []Product{{Component: Component{ID: "pkg:apk/wolfi/curl@8.0.0"}}},
+			Status:        StatusNotAffected,
+			Justification: ComponentNotPresent,
+			Timestamp:     &t1,
+		},
+		{
+			// Later statement overrides the not_affected above
+			Vulnerability:   Vulnerability{Name: "CVE-2023-3333"},
+			Products:        []Product{{Component: Component{ID: "pkg:apk/wolfi/curl@8.0.0"}}},
+			Status:          StatusAffected,
+			ActionStatement: "Upgrade",
+			Timestamp:       &t2,
+		},
+	}
+
+	ignore := doc.ToTrivyIgnore()
+	require.Len(t, ignore.Vulnerabilities, 3)
+	require.Equal(t, TrivyIgnoreEntry{
+		ID:        "CVE-2023-1111",
+		PURLs:     []string{"pkg:golang/example.com/lib@v1.0.0"},
+		Statement: "not_affected: vulnerable_code_not_in_execute_path",
+	}, ignore.Vulnerabilities[0])
+	require.Equal(t, "CVE-2023-2222", ignore.Vulnerabilities[1].ID)
+	require.Equal(t, []string{"pkg:apk/wolfi/openssl@3.0.8"}, ignore.Vulnerabilities[1].PURLs)
+	require.Equal(t, "GHSA-aaaa-bbbb-cccc", ignore.Vulnerabilities[2].ID)
+
+	ignore.SetExpiry(time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	require.NoError(t, ignore.ToYAML(&buf))
+	require.Contains(t, buf.String(), "vulnerabilities:\n  - id: CVE-2023-1111\n    purls:\n      - pkg:golang/example.com/lib@v1.0.0\n")
+	require.Contains(t, buf.String(), "expired_at: \"2024-01-31\"")
+
+	buf.Reset()
+	require.NoError(t, ignore.ToText(&buf))
+	require.Contains(t, buf.String(), "# fixed\n# pkg:apk/wolfi/openssl@3.0.8\nCVE-2023-2222 exp:2024-01-31\n")
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// documentSignaturePrefix separates document signatures from statement
+// signatures made with the same key.
+const documentSignaturePrefix = "openvex-document-v2:"
+
+// DocumentSignature is a detached signature over the whole content of a
+// document, see SigningHash.
+type DocumentSignature struct {
+	// DocumentHash is the signing hash of the signed document
+	DocumentHash string `json:"document_hash"`
+
+	// KeyID identifies the key used to sign the document
+	KeyID string `json:"keyid"`
+
+	// Signature is the raw signature, encoded as base64 in JSON
+	Signature []byte `json:"sig"`
+}
+
+// SigningHash returns the hash covered by document signatures: the content
+// hash of the document without its transparency log, as the log entries of
+// a signature are recorded in the document after signing it. The document is
+// not modified.
+func (vexDoc *VEX) SigningHash() (string, error) {
+	data, err := vexDoc.signingBytes()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// signingBytes returns the canonical serialization hashed by SigningHash.
+func (vexDoc *VEX) signingBytes() ([]byte, error) {
+	doc := vexDoc.Clone()
+	doc.TransparencyLog = nil
+	return doc.CanonicalBytes()
+}
+
+// Sign returns a detached signature over the document's signing hash.
+func (vexDoc *VEX) Sign(signer StatementSigner) (*DocumentSignature, error) {
+	hash, err := vexDoc.SigningHash()
+	if err != nil {
+		return nil, fmt.Errorf("computing document hash: %w", err)
+	}
+	sig, err := signer.Sign([]byte(documentSignaturePrefix + hash))
+	if err != nil {
+		return nil, fmt.Errorf("signing document: %w", err)
+	}
+	return &DocumentSignature{DocumentHash: hash, KeyID: signer.KeyID(), Signature: sig}, nil
+}
+
+// ErrNotEnoughSignatures is returned when a document or statement does not
+// carry valid signatures from enough trusted keys.
+var ErrNotEnoughSignatures = errors.New("not enough valid signatures from trusted keys")
+
+// Verifier enforces a signing policy: documents and statements are only
+// trusted when signed by at least Threshold distinct keys of the set.
+type Verifier struct {
+	// Threshold is the number of distinct trusted keys required. Values
+	// under 1 are treated as 1.
+	Threshold int
+
+	keys map[string]StatementVerifier
+}
+
+// NewVerifier returns a verifier trusting the given keys and requiring
+// signatures from at least threshold of them.
+func NewVerifier(threshold int, keys ...StatementVerifier) *Verifier {
+	v := &Verifier{Threshold: threshold, keys: map[string]StatementVerifier{}}
+	for _, k := range keys {
+		v.keys[k.KeyID()] = k
+	}
+	return v
+}
+
+func (v *Verifier) threshold() int {
+	if v.Threshold < 1 {
+		return 1
+	}
+	return v.Threshold
+}
+
+// VerifyDocument checks that the document carries valid signatures from at
+// least Threshold trusted keys.
+func (v *Verifier) VerifyDocument(doc *VEX, sigs []DocumentSignature) error {
+	hash, err := doc.SigningHash()
+	if err != nil {
+		return fmt.Errorf("computing document hash: %w", err)
+	}
+	signers := map[string]struct{}{}
+	for i := range sigs {
+		key, ok := v.keys[sigs[i].KeyID]
+		if !ok || sigs[i].DocumentHash != hash {
+			continue
+		}
+		if key.Verify([]byte(documentSignaturePrefix+hash), sigs[i].Signature) == nil {
+			signers[sigs[i].KeyID] = struct{}{}
+		}
+	}
+	if len(signers) < v.threshold() {
+		return fmt.Errorf("document signed by %d of %d required keys: %w", len(signers), v.threshold(), ErrNotEnoughSignatures)
+	}
+	return nil
+}
+
+// VerifyStatement checks that the statement carries valid signatures from
+// at least Threshold trusted keys.
+func (v *Verifier) VerifyStatement(stmt *Statement, sigs []StatementSignature) error {
+	signers := map[string]struct{}{}
+	for i := range sigs {
+		key, ok := v.keys[sigs[i].KeyID]
+		if !ok {
+			continue
+		}
+		if stmt.VerifySignature(&sigs[i], key) == nil {
+			signers[sigs[i].KeyID] = struct{}{}
+		}
+	}
+	if len(signers) < v.threshold() {
+		return fmt.Errorf("statement signed by %d of %d required keys: %w", len(signers), v.threshold(), ErrNotEnoughSignatures)
+	}
+	return nil
+}
+
+// VerifiedDocument returns the document in verified mode: a copy holding
+// only the statements accepted by the policy, so that Matches and
+// EffectiveStatement on it never return untrusted statements.
+//
+// If the document signatures meet the threshold, all its statements are
+// accepted. Otherwise each statement is checked against the statement
+// signatures. An error wrapping ErrNotEnoughSignatures is returned when no
+// statement is accepted.
+func (v *Verifier) VerifiedDocument(doc *VEX, docSigs []DocumentSignature, stmtSigs []StatementSignature) (*VEX, error) {
+	verified := doc.Clone()
+	if err := v.VerifyDocument(doc, docSigs); err == nil {
+		return verified, nil
+	}
+
+	verified.Statements = []Statement{}
+	for i := range doc.Statements {
+		if v.VerifyStatement(&doc.Statements[i], stmtSigs) == nil {
+			verified.Statements = append(verified.Statements, doc.Statements[i].Clone())
+		}
+	}
+	if len(verified.Statements) == 0 && len(doc.Statements) > 0 {
+		return nil, fmt.Errorf("no statements could be verified: %w", ErrNotEnoughSignatures)
+	}
+	return verified, nil
+}
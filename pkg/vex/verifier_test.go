@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier(t *testing.T) {
+	type key struct {
+		signer   StatementSigner
+		verifier StatementVerifier
+	}
+	keys := map[string]key{}
+	for _, id := range []string{"a", "b", "c"} {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		keys[id] = key{NewED25519Signer(id, priv), NewED25519Verifier(id, pub)}
+	}
+
+	newDoc := func() *VEX {
+		doc := New()
+		doc.Statements = []Statement{
+			{
+				Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+				Products:      []Product{{Component: Component{ID: "pkg:oci/image"}}},
+				Status:        StatusFixed,
+			},
+			{
+				Vulnerability: Vulnerability{Name: "CVE-2023-5678"},
+				Products:      []Product{{Component: Component{ID: "pkg:oci/image"}}},
+				Status:        StatusUnderInvestigation,
+			},
+		}
+		return &doc
+	}
+
+	t.Run("document threshold", func(t *testing.T) {
+		doc := newDoc()
+		sigA, err := doc.Sign(keys["a"].signer)
+		require.NoError(t, err)
+		sigB, err := doc.Sign(keys["b"].signer)
+		require.NoError(t, err)
+
+		v := NewVerifier(2, keys["a"].verifier, keys["b"].verifier, keys["c"].verifier)
+		require.ErrorIs(t, v.VerifyDocument(doc, []DocumentSignature{*sigA}), ErrNotEnoughSignatures)
+		// The same key twice does not count as two signers
+		require.ErrorIs(t, v.VerifyDocument(doc, []DocumentSignature{*sigA, *sigA}), ErrNotEnoughSignatures)
+		require.NoError(t, v.VerifyDocument(doc, []DocumentSignature{*sigA, *sigB}))
+
+		verified, err := v.VerifiedDocument(doc, []DocumentSignature{*sigA, *sigB}, nil)
+		require.NoError(t, err)
+		require.Len(t, verified.Statements, 2)
+
+		// Untrusted keys are ignored
+		v = NewVerifier(1, keys["c"].verifier)
+		require.ErrorIs(t, v.VerifyDocument(doc, []DocumentSignature{*sigA, *sigB}), ErrNotEnoughSignatures)
+
+		// Modified documents fail verification
+		doc.Statements[0].Status = StatusAffected
+		v = NewVerifier(1, keys["a"].verifier)
+		require.ErrorIs(t, v.VerifyDocument(doc, []DocumentSignature{*sigA}), ErrNotEnoughSignatures)
+	})
+
+	t.Run("document content", func(t *testing.T) {
+		doc := newDoc()
+		doc.Statements[0], doc.Statements[1] = doc.Statements[1], doc.Statements[0]
+		sig, err := doc.Sign(keys["a"].signer)
+		require.NoError(t, err)
+		v := NewVerifier(1, keys["a"].verifier)
+
+		// Verifying does not reorder the statements
+		require.NoError(t, v.VerifyDocument(doc, []DocumentSignature{*sig}))
+		require.Equal(t, VulnerabilityID("CVE-2023-5678"), doc.Statements[0].Vulnerability.Name)
+
+		// Recording transparency log entries keeps the signature valid
+		doc.TransparencyLog = []TransparencyLogEntry{{LogIndex: 1}}
+		require.NoError(t, v.VerifyDocument(doc, []DocumentSignature{*sig}))
+
+		// Any other edit breaks it
+		for m, edit := range map[string]func(*VEX){
+			"action statement": func(d *VEX) { d.Statements[0].ActionStatement = "Update now" },
+			"impact statement": func(d *VEX) { d.Statements[1].ImpactStatement = "Not used" },
+			"status notes":     func(d *VEX) { d.Statements[0].StatusNotes = "Looking into it" },
+			"supplier":         func(d *VEX) { d.Supplier = "Someone else" },
+		} {
+			edited := doc.Clone()
+			edit(edited)
+			require.ErrorIs(t, v.VerifyDocument(edited, []DocumentSignature{*sig}), ErrNotEnoughSignatures, m)
+			_, err := v.VerifiedDocument(edited, []DocumentSignature{*sig}, nil)
+			require.ErrorIs(t, err, ErrNotEnoughSignatures, m)
+		}
+	})
+
+	t.Run("statement threshold", func(t *testing.T) {
+		doc := newDoc()
+		sigs := []StatementSignature{}
+		for _, id := range []string{"a", "b"} {
+			sig, err := doc.Statements[0].Sign(keys[id].signer)
+			require.NoError(t, err)
+			sigs = append(sigs, *sig)
+		}
+		sig, err := doc.Statements[1].Sign(keys["a"].signer)
+		require.NoError(t, err)
+		sigs = append(sigs, *sig)
+
+		v := NewVerifier(2, keys["a"].verifier, keys["b"].verifier)
+		require.NoError(t, v.VerifyStatement(&doc.Statements[0], sigs))
+		require.ErrorIs(t, v.VerifyStatement(&doc.Statements[1], sigs), ErrNotEnoughSignatures)
+
+		verified, err := v.VerifiedDocument(doc, nil, sigs)
+		require.NoError(t, err)
+		require.Len(t, verified.Statements, 1)
+		require.NotNil(t, verified.EffectiveStatement("pkg:oci/image", "CVE-2023-1234"))
+		require.Nil(t, verified.EffectiveStatement("pkg:oci/image", "CVE-2023-5678"))
+		require.Len(t, verified.Matches("CVE-2023-5678", "pkg:oci/image", nil), 0)
+
+		// The original document is untouched
+		require.Len(t, doc.Statements, 2)
+
+		_, err = NewVerifier(2, keys["c"].verifier).VerifiedDocument(doc, nil, sigs)
+		require.ErrorIs(t, err, ErrNotEnoughSignatures)
+	})
+}
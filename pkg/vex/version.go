@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Clone returns a deep copy of the document.
+func (vexDoc *VEX) Clone() *VEX {
+	ret := &VEX{Metadata: vexDoc.Metadata}
+	ret.Timestamp = cloneTime(vexDoc.Timestamp)
+	ret.LastUpdated = cloneTime(vexDoc.LastUpdated)
+	if vexDoc.TransparencyLog != nil {
+		ret.TransparencyLog = make([]TransparencyLogEntry, len(vexDoc.TransparencyLog))
+		for i := range vexDoc.TransparencyLog {
+			ret.TransparencyLog[i] = vexDoc.TransparencyLog[i].Clone()
+		}
+	}
+	if vexDoc.Changelog != nil {
+		ret.Changelog = make([]ChangelogEntry, len(vexDoc.Changelog))
+		copy(ret.Changelog, vexDoc.Changelog)
+	}
+	ret.RawExtensions = cloneRawExtensions(vexDoc.RawExtensions)
+	if vexDoc.Statements != nil {
+		ret.Statements = make([]Statement, len(vexDoc.Statements))
+		for i := range vexDoc.Statements {
+			ret.Statements[i] = vexDoc.Statements[i].Clone()
+		}
+	}
+	return ret
+}
+
+// NextVersion returns the next version of the document following the update
+// rules of the spec. The edits are applied to a copy of the document whose
+// version is then incremented and last_updated set to the current time. The
+// original timestamp is kept. If the document had a canonical ID, as set by
+// GenerateCanonicalID, a new one is generated from the edited statements.
+// Transparency log entries are dropped as they record the signatures of
+// the previous version. If the document links to a previous revision, the
+// new version links to it to continue the chain.
+//
+//	next, err := doc.NextVersion(func(d *vex.VEX) error {
+//		return d.AddStatement(stmt)
+//	})
+func (vexDoc *VEX) NextVersion(edits ...func(*VEX) error) (*VEX, error) {
+	next := vexDoc.Clone()
+	next.TransparencyLog = nil
+	if vexDoc.Previous != "" {
+		if err := next.LinkPrevious(vexDoc); err != nil {
+			return nil, err
+		}
+	}
+	for _, edit := range edits {
+		if err := edit(next); err != nil {
+			return nil, err
+		}
+	}
+	h, canonical := canonicalIDHash(vexDoc.ID)
+	if err := next.bumpVersion(h, canonical && next.ID == vexDoc.ID, time.Now()); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// bumpVersion records a new version of the document at now. If regenerateID
+// is set, the canonical ID is generated again with h.
+func (vexDoc *VEX) bumpVersion(h crypto.Hash, regenerateID bool, now time.Time) error {
+	vexDoc.Version++
+	vexDoc.LastUpdated = &now
+	if regenerateID {
+		vexDoc.ID = ""
+		if _, err := vexDoc.GenerateCanonicalIDWith(h); err != nil {
+			return fmt.Errorf("generating document ID: %w", err)
+		}
+	}
+	return nil
+}
+
+// canonicalIDHash returns the hash function of a canonical ID generated by
+// GenerateCanonicalIDWith and false if id is not a canonical ID.
+func canonicalIDHash(id string) (crypto.Hash, bool) {
+	suffix, ok := strings.CutPrefix(id, DefaultNamespace+"/public/vex-")
+	if !ok {
+		return 0, false
+	}
+	for h, algo := range canonicalHashAlgorithms {
+		if h == crypto.SHA256 {
+			continue
+		}
+		if hash, ok := strings.CutPrefix(suffix, string(algo)+"-"); ok && isHex(hash, h.Size()) {
+			return h, true
+		}
+	}
+	if isHex(suffix, crypto.SHA256.Size()) {
+		return crypto.SHA256, true
+	}
+	return 0, false
+}
+
+// isHex returns true if s is the hex encoding of size bytes.
+func isHex(s string, size int) bool {
+	if len(s) != size*2 {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Rollback returns the next version of the document with the statements
+// of an earlier version restored. Only the statements are restored, the
+// new version follows the document as NextVersion does so the version keeps
+// increasing, and a changelog entry records the rollback.
+func (vexDoc *VEX) Rollback(to *VEX) (*VEX, error) {
+	return vexDoc.NextVersion(func(next *VEX) error {
+		next.Statements = to.Clone().Statements
+		next.RecordChange(ChangeRollback, fmt.Sprintf("Restored the statements of version %d", to.Version))
+		return nil
+	})
+}
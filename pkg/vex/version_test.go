@@ -0,0 +1,162 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"crypto"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextVersion(t *testing.T) {
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	newDoc := func() *VEX {
+		doc := New()
+		doc.Timestamp = &t1
+		doc.Statements = []Statement{{
+			Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+			Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+			Status:        StatusUnderInvestigation,
+		}}
+		doc.TransparencyLog = []TransparencyLogEntry{{}}
+		return &doc
+	}
+	affected := func(d *VEX) error {
+		d.Statements[0].Status = StatusAffected
+		d.Statements[0].ActionStatement = "Update the base image"
+		return nil
+	}
+
+	for _, h := range []crypto.Hash{crypto.SHA256, crypto.SHA512} {
+		doc := newDoc()
+		id, err := doc.GenerateCanonicalIDWith(h)
+		require.NoError(t, err)
+
+		before := time.Now()
+		next, err := doc.NextVersion(affected)
+		require.NoError(t, err)
+		require.Equal(t, 2, next.Version)
+		require.Equal(t, t1, *next.Timestamp)
+		require.False(t, next.LastUpdated.Before(before))
+		require.Nil(t, next.TransparencyLog)
+
+		// The canonical ID follows the new statements with the same hash
+		require.NotEqual(t, id, next.ID)
+		nh, ok := canonicalIDHash(next.ID)
+		require.True(t, ok)
+		require.Equal(t, h, nh)
+
+		// The original is untouched
+		require.Equal(t, 1, doc.Version)
+		require.Equal(t, id, doc.ID)
+		require.Nil(t, doc.LastUpdated)
+		require.Equal(t, StatusUnderInvestigation, doc.Statements[0].Status)
+		require.Len(t, doc.TransparencyLog, 1)
+	}
+
+	// Other IDs are kept
+	doc := newDoc()
+	doc.ID = "https://example.com/vex/1"
+	next, err := doc.NextVersion(affected)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/vex/1", next.ID)
+
+	// Edits can fail
+	_, err = doc.NextVersion(func(*VEX) error { return errors.New("invalid") })
+	require.Error(t, err)
+}
+
+func TestCanonicalIDHash(t *testing.T) {
+	for id, expected := range map[string]crypto.Hash{
+		PublicNamespace + "/public/vex-" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef":                                         crypto.SHA256,
+		PublicNamespace + "/public/vex-sha-384-" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef": crypto.SHA384,
+		PublicNamespace + "/public/vex-0123":      0,
+		PublicNamespace + "/docs/vex-0123":        0,
+		"https://example.com/public/vex-0123abcd": 0,
+	} {
+		h, ok := canonicalIDHash(id)
+		require.Equal(t, expected != 0, ok, id)
+		require.Equal(t, expected, h, id)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	v1 := New(WithID("https://example.com/vex/1"), WithChangelog())
+	require.NoError(t, v1.AddStatement(Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusNotAffected,
+		Justification: ComponentNotPresent,
+	}))
+
+	v2, err := v1.NextVersion(func(d *VEX) error {
+		d.Statements[0].Status = StatusAffected
+		d.Statements[0].Justification = ""
+		d.Author = "Someone Else"
+		return nil
+	})
+	require.NoError(t, err)
+
+	v3, err := v2.Rollback(&v1)
+	require.NoError(t, err)
+	require.Equal(t, v2.Version+1, v3.Version)
+	require.Equal(t, "Someone Else", v3.Author)
+	require.Equal(t, v1.Statements, v3.Statements)
+	require.Equal(t, ChangeRollback, v3.Changelog[len(v3.Changelog)-1].Action)
+
+	// The restored statements are copies
+	v3.Statements[0].Products[0].ID = "pkg:oci/other"
+	require.Equal(t, "pkg:oci/app", v1.Statements[0].Products[0].ID)
+	require.Equal(t, StatusAffected, v2.Statements[0].Status)
+}
+
+func TestClone(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc := New()
+	doc.Timestamp = &ts
+	doc.LastUpdated = &ts
+	doc.TransparencyLog = []TransparencyLogEntry{{
+		LogIndex:       1,
+		InclusionProof: &InclusionProof{RootHash: "abc", Hashes: []string{"def"}},
+	}}
+	doc.Changelog = []ChangelogEntry{{Timestamp: ts, Action: ChangeAddStatement}}
+	doc.Statements = []Statement{{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1111"},
+		Products:      []Product{{Component: Component{ID: "pkg:oci/app"}}},
+		Status:        StatusUnderInvestigation,
+	}}
+	require.NoError(t, doc.SetExtension(ExtensionPrefix+"team", "platform"))
+	require.NoError(t, doc.Statements[0].SetExtension(ExtensionPrefix+"ticket", "SEC-1"))
+
+	clone := doc.Clone()
+	require.Equal(t, &doc, clone)
+
+	*clone.Timestamp = ts.Add(time.Hour)
+	*clone.LastUpdated = ts.Add(time.Hour)
+	clone.TransparencyLog[0].InclusionProof.Hashes[0] = "changed"
+	clone.TransparencyLog[0].InclusionProof.RootHash = "changed"
+	clone.Changelog[0].Action = ChangeMerge
+	clone.Statements[0].Products[0].ID = "changed"
+	clone.RawExtensions[ExtensionPrefix+"team"][1] = 'X'
+	clone.Statements[0].RawExtensions[ExtensionPrefix+"ticket"][1] = 'X'
+
+	require.Equal(t, ts, *doc.Timestamp)
+	require.Equal(t, ts, *doc.LastUpdated)
+	require.Equal(t, []string{"def"}, doc.TransparencyLog[0].InclusionProof.Hashes)
+	require.Equal(t, "abc", doc.TransparencyLog[0].InclusionProof.RootHash)
+	require.Equal(t, ChangeAddStatement, doc.Changelog[0].Action)
+	require.Equal(t, "pkg:oci/app", doc.Statements[0].Products[0].ID)
+	var team, ticket string
+	_, err := doc.Extension(ExtensionPrefix+"team", &team)
+	require.NoError(t, err)
+	require.Equal(t, "platform", team)
+	_, err = doc.Statements[0].Extension(ExtensionPrefix+"ticket", &ticket)
+	require.NoError(t, err)
+	require.Equal(t, "SEC-1", ticket)
+}
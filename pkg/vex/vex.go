@@ -6,18 +6,23 @@ SPDX-License-Identifier: Apache-2.0
 package vex
 
 import (
-	"crypto/sha256"
+	"crypto"
+	_ "crypto/sha256" // registers SHA-256
+	_ "crypto/sha512" // registers SHA-384 and SHA-512
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/package-url/packageurl-go"
+	_ "golang.org/x/crypto/sha3" // registers the SHA-3 family
 )
 
 const (
@@ -53,10 +58,21 @@ const (
 // documents and nodes. It is set to the OpenVEX public namespace by default.
 var DefaultNamespace = PublicNamespace
 
+// MaxPurlQualifiers is the maximum number of qualifiers a purl may carry to be
+// considered by the matching functions. Purls exceeding the limit are treated
+// as invalid and never match, this protects the matching hot path from
+// adversarial identifiers with thousands of qualifiers.
+var MaxPurlQualifiers = 64
+
 // The VEX type represents a VEX document and all of its contained information.
 type VEX struct {
 	Metadata
 	Statements []Statement `json:"statements"`
+
+	// RawExtensions holds the fields of the serialized document which are
+	// not part of the spec, keyed by name. They are kept when parsing and
+	// written back when marshaling so documents round trip without data loss.
+	RawExtensions map[string]json.RawMessage `json:"-" yaml:"-"`
 }
 
 // The Metadata type represents the metadata associated with a VEX document.
@@ -96,10 +112,33 @@ type Metadata struct {
 
 	// Supplier is an optional field.
 	Supplier string `json:"supplier,omitempty"`
+
+	// TransparencyLog records the transparency log entries of the document
+	// signatures. It is not part of the canonical hash so recording entries
+	// does not invalidate existing signatures.
+	TransparencyLog []TransparencyLogEntry `json:"transparency_log,omitempty"`
+
+	// Changelog records the changes made to the document. It is optional,
+	// see ChangelogEntry.
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Previous is the hash of the prior revision of the document, see
+	// LinkPrevious. It is optional and part of the hashes of the document,
+	// so the revisions form a tamper-evident chain checked by VerifyChain.
+	Previous string `json:"previous,omitempty"`
+
+	// SourceVersion is the version of the OpenVEX specification the document
+	// was read from. It is set by ParseCompat, which upconverts documents of
+	// older versions, and is never serialized.
+	SourceVersion string `json:"-"`
 }
 
-// New returns a new, initialized VEX document.
-func New() VEX {
+// New returns a new, initialized VEX document. The document is set up with
+// the defaults for the mandatory metadata fields and those configured with
+// SetDefaults, which the options can override:
+//
+//	doc := vex.New(vex.WithAuthor("Example Inc."), vex.WithTooling("vexctl"))
+func New(opts ...Option) VEX {
 	now := time.Now()
 	t, err := DateFromEnv()
 	if err != nil {
@@ -108,7 +147,7 @@ func New() VEX {
 	if t != nil {
 		now = *t
 	}
-	return VEX{
+	doc := VEX{
 		Metadata: Metadata{
 			Context:    ContextLocator(),
 			Author:     DefaultAuthor,
@@ -118,20 +157,86 @@ func New() VEX {
 		},
 		Statements: []Statement{},
 	}
+	if d := defaults.Load(); d != nil {
+		WithDefaults(*d)(&doc)
+	}
+	for _, opt := range opts {
+		opt(&doc)
+	}
+	return doc
 }
 
 // ToJSON serializes the VEX document to JSON and writes it to the passed writer.
 func (vexDoc *VEX) ToJSON(w io.Writer) error {
+	return vexDoc.Encode(w, &EncodeOptions{})
+}
+
+// EncodeOptions controls how Encode serializes a document. The zero value
+// writes indented JSON, as ToJSON does.
+type EncodeOptions struct {
+	// Compact writes the JSON without indentation.
+	Compact bool
+
+	// Version serializes the document in the format of a version of the
+	// OpenVEX specification, see ToJSONVersion. When empty, the document is
+	// written as is.
+	Version string
+
+	// Canonical writes the statements in canonical order, see
+	// SortCanonical, so documents with the same statements serialize the
+	// same way. The document itself is not reordered.
+	Canonical bool
+}
+
+// Write serializes the VEX document to JSON and writes it to w.
+func (vexDoc *VEX) Write(w io.Writer) error {
+	return vexDoc.Encode(w, &EncodeOptions{})
+}
+
+// Encode serializes the VEX document to JSON as specified in the options and
+// writes it to w. The document is streamed to the writer.
+func (vexDoc *VEX) Encode(w io.Writer, opts *EncodeOptions) error {
+	if opts.Canonical {
+		vexDoc = vexDoc.Clone()
+		vexDoc.SortCanonical()
+	}
+
+	var out any = vexDoc
+	if opts.Version != "" {
+		var err error
+		if out, err = vexDoc.inVersion(opts.Version); err != nil {
+			return err
+		}
+	}
+
 	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
+	if !opts.Compact {
+		enc.SetIndent("", "  ")
+	}
 	enc.SetEscapeHTML(false)
 
-	if err := enc.Encode(vexDoc); err != nil {
+	if err := enc.Encode(out); err != nil {
 		return fmt.Errorf("encoding vex document: %w", err)
 	}
 	return nil
 }
 
+// AddStatement validates a statement and appends it to the document. Unlike
+// appending to the Statements slice directly, it enforces the requirements of
+// the spec, such as not_affected statements having a justification.
+func (vexDoc *VEX) AddStatement(stmt Statement) error { //nolint:gocritic // statements are copied into the document
+	path := fmt.Sprintf("/statements/%d", len(vexDoc.Statements))
+	if err := stmt.ValidateJustification(); err != nil {
+		return withPathPrefix(path, err)
+	}
+	if err := stmt.Validate(); err != nil {
+		return fmt.Errorf("invalid statement: %w", withPathPrefix(path, err))
+	}
+	vexDoc.Statements = append(vexDoc.Statements, stmt)
+	vexDoc.RecordChange(ChangeAddStatement, "Added statement about "+statementSummary(&stmt))
+	return nil
+}
+
 // EffectiveStatement returns the latest VEX statement for a given product and
 // vulnerability, that is the statement that contains the latest data about
 // impact to a given product.
@@ -192,6 +297,49 @@ func (vexDoc *VEX) Matches(vulnID, product string, subcomponents []string) []Sta
 // statements are not modified. Changes in extra information and metadata
 // will not alter the hash.
 func (vexDoc *VEX) CanonicalHash() (string, error) {
+	return vexDoc.CanonicalHashWith(crypto.SHA256)
+}
+
+// canonicalHashAlgorithms maps the hash functions supported by
+// CanonicalHashWith to their names.
+var canonicalHashAlgorithms = map[crypto.Hash]Algorithm{
+	crypto.SHA256:   SHA256,
+	crypto.SHA384:   SHA384,
+	crypto.SHA512:   SHA512,
+	crypto.SHA3_224: SHA3224,
+	crypto.SHA3_256: SHA3256,
+	crypto.SHA3_384: SHA3384,
+	crypto.SHA3_512: SHA3512,
+}
+
+// CanonicalHashWith returns the canonical hash of the document (see
+// CanonicalHash) computed with the hash function h. The SHA-2 and SHA-3
+// families are supported.
+func (vexDoc *VEX) CanonicalHashWith(h crypto.Hash) (string, error) {
+	if _, ok := canonicalHashAlgorithms[h]; !ok || !h.Available() {
+		return "", fmt.Errorf("unsupported hash function %s", h)
+	}
+
+	cString, err := vexDoc.canonicalString()
+	if err != nil {
+		return "", err
+	}
+
+	// Hash the canonicalization string and return
+	hasher := h.New()
+	if _, err := hasher.Write([]byte(cString)); err != nil {
+		return "", fmt.Errorf("hashing canonicalization string: %w", err)
+	}
+	return fmt.Sprintf("%x", hasher.Sum(nil)), nil
+}
+
+// canonicalString returns the canonicalization string of the document which
+// is hashed by CanonicalHash.
+func (vexDoc *VEX) canonicalString() (string, error) {
+	if vexDoc.Timestamp == nil {
+		return "", errors.New("document has no timestamp")
+	}
+
 	// Here's the algo:
 
 	// 1. Start with the document date. In unixtime to avoid format variance.
@@ -203,8 +351,8 @@ func (vexDoc *VEX) CanonicalHash() (string, error) {
 	// 3. Author identity
 	cString += fmt.Sprintf(":%s", vexDoc.Author)
 
-	// 4. Sort the statements
-	stmts := vexDoc.Statements
+	// 4. Sort a copy of the statements
+	stmts := slices.Clone(vexDoc.Statements)
 	SortStatements(stmts, *vexDoc.Timestamp)
 
 	// 5. Now add the data from each statement
@@ -235,12 +383,12 @@ func (vexDoc *VEX) CanonicalHash() (string, error) {
 		cString += strings.Join(prods, ":")
 	}
 
-	// 6. Hash the string in sha256 and return
-	h := sha256.New()
-	if _, err := h.Write([]byte(cString)); err != nil {
-		return "", fmt.Errorf("hashing canonicalization string: %w", err)
+	// 6. The link to the previous revision, only when there is one so the
+	// hashes of documents without it don't change
+	if vexDoc.Previous != "" {
+		cString += fmt.Sprintf(":previous:%s", vexDoc.Previous)
 	}
-	return fmt.Sprintf("%x", h.Sum(nil)), nil
+	return cString, nil
 }
 
 // cstringFromComponent returns a string concatenating the data of a component
@@ -249,12 +397,23 @@ func (vexDoc *VEX) CanonicalHash() (string, error) {
 func cstringFromComponent(c Component) string {
 	s := fmt.Sprintf(":%s", c.ID)
 
-	for algo, val := range c.Hashes {
-		s += fmt.Sprintf(":%s@%s", algo, val)
+	// Maps are iterated in sorted order to keep the string stable
+	algos := make([]string, 0, len(c.Hashes))
+	for algo := range c.Hashes {
+		algos = append(algos, string(algo))
+	}
+	sort.Strings(algos)
+	for _, algo := range algos {
+		s += fmt.Sprintf(":%s@%s", algo, c.Hashes[Algorithm(algo)])
 	}
 
-	for t, id := range c.Identifiers {
-		s += fmt.Sprintf(":%s@%s", t, id)
+	types := make([]string, 0, len(c.Identifiers))
+	for t := range c.Identifiers {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		s += fmt.Sprintf(":%s@%s", t, c.Identifiers[IdentifierType(t)])
 	}
 
 	return s
@@ -280,13 +439,24 @@ func cstringFromVulnerability(v Vulnerability) string {
 // Trying to generate the id of a doc with an existing ID will
 // not do anything.
 func (vexDoc *VEX) GenerateCanonicalID() (string, error) {
+	return vexDoc.GenerateCanonicalIDWith(crypto.SHA256)
+}
+
+// GenerateCanonicalIDWith generates an ID for the document based on the
+// canonical hash computed with h. IDs generated with any hash function other
+// than SHA-256 record the algorithm name before the hash, for example
+// vex-sha-512-<hash>. SHA-256 IDs keep the vex-<hash> form.
+func (vexDoc *VEX) GenerateCanonicalIDWith(h crypto.Hash) (string, error) {
 	if vexDoc.ID != "" {
 		return vexDoc.ID, nil
 	}
-	cHash, err := vexDoc.CanonicalHash()
+	cHash, err := vexDoc.CanonicalHashWith(h)
 	if err != nil {
 		return "", fmt.Errorf("getting canonical hash: %w", err)
 	}
+	if h != crypto.SHA256 {
+		cHash = fmt.Sprintf("%s-%s", canonicalHashAlgorithms[h], cHash)
+	}
 
 	// For common namespaced documents we namespace them into /public
 	vexDoc.ID = fmt.Sprintf("%s/public/vex-%s", DefaultNamespace, cHash)
@@ -331,10 +501,16 @@ func ContextLocator() string {
 //   - Inversely, purl2 can have any number of qualifiers not found on purl1 and
 //     still match.
 //   - If any of the purls is invalid, the function returns false.
+//   - Purls with more than MaxPurlQualifiers qualifiers are considered invalid.
 //
 // Purl version ranges are not supported yet but they will be in a future version
 // of this matching function.
 func PurlMatches(purl1, purl2 string) bool {
+	if purlQualifierCount(purl1) > MaxPurlQualifiers ||
+		purlQualifierCount(purl2) > MaxPurlQualifiers {
+		return false
+	}
+
 	p1, err := packageurl.FromString(purl1)
 	if err != nil {
 		return false
@@ -364,18 +540,45 @@ func PurlMatches(purl1, purl2 string) bool {
 		return false
 	}
 
-	p1q := p1.Qualifiers.Map()
-	p2q := p2.Qualifiers.Map()
-
 	// All qualifiers in p1 must be in p2 to match
-	for k, v1 := range p1q {
-		if v2, ok := p2q[k]; !ok || v1 != v2 {
+	return qualifiersMatch(p1.Qualifiers, p2.Qualifiers)
+}
+
+// qualifiersMatch returns true if all qualifiers in q1 are found with the
+// same value in q2. The packageurl library returns qualifiers sorted by key
+// and without duplicates so we can walk both lists once without allocating.
+func qualifiersMatch(q1, q2 packageurl.Qualifiers) bool {
+	if len(q1) > len(q2) {
+		return false
+	}
+	j := 0
+	for i := range q1 {
+		for j < len(q2) && q2[j].Key < q1[i].Key {
+			j++
+		}
+		if j == len(q2) || q2[j].Key != q1[i].Key || q2[j].Value != q1[i].Value {
 			return false
 		}
+		j++
 	}
 	return true
 }
 
+// purlQualifierCount returns an upper bound of the number of qualifiers in a
+// purl string without parsing it. It is used to reject oversized purls before
+// handing them to the parser.
+func purlQualifierCount(purl string) int {
+	_, query, ok := strings.Cut(purl, "?")
+	if !ok {
+		return 0
+	}
+	query, _, _ = strings.Cut(query, "#")
+	if query == "" {
+		return 0
+	}
+	return strings.Count(query, "&") + 1
+}
+
 // StatementsByVulnerability returns a list of statements that apply to a
 // vulnerability ID. These are guaranteed to be ordered according to the VEX
 // history.
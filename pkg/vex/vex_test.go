@@ -6,7 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 package vex
 
 import (
+	"bytes"
+	"crypto"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -165,6 +168,35 @@ func genTestDoc(t *testing.T) VEX {
 	}
 }
 
+func TestAddStatement(t *testing.T) {
+	doc := New()
+	product := []Product{{Component: Component{ID: "pkg:apk/wolfi/bash@1.0.0"}}}
+
+	err := doc.AddStatement(Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+		Products:      product,
+		Status:        StatusNotAffected,
+	})
+	require.ErrorIs(t, err, ErrMissingJustification)
+
+	err = doc.AddStatement(Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+		Products:      product,
+		Status:        StatusNotAffected,
+		Justification: "it's fine",
+	})
+	require.Error(t, err)
+
+	err = doc.AddStatement(Statement{
+		Vulnerability: Vulnerability{Name: "CVE-2023-1234"},
+		Products:      product,
+		Status:        StatusNotAffected,
+		Justification: ComponentNotPresent,
+	})
+	require.NoError(t, err)
+	require.Len(t, doc.Statements, 1)
+}
+
 func TestCanonicalHash(t *testing.T) {
 	//nolint:gosec // Not a credential
 	goldenHash := `8ed99017785c3b43219018c7c50353c031cdaaf1c7efc146c683b0ce57123cf6`
@@ -252,6 +284,29 @@ func TestCanonicalHash(t *testing.T) {
 	}
 }
 
+func TestCstringFromComponent(t *testing.T) {
+	c := Component{
+		ID: "pkg:oci/app",
+		Hashes: map[Algorithm]Hash{
+			SHA512: "def",
+			SHA1:   "abc",
+			SHA256: "123",
+		},
+		Identifiers: map[IdentifierType]string{
+			PURL:  "pkg:oci/app",
+			CPE23: "cpe:2.3:a:example:app:*:*:*:*:*:*:*:*",
+			CPE22: "cpe:/a:example:app",
+		},
+	}
+	expected := ":pkg:oci/app:sha-256@123:sha-512@def:sha1@abc" +
+		":cpe22@cpe:/a:example:app:cpe23@cpe:2.3:a:example:app:*:*:*:*:*:*:*:*:purl@pkg:oci/app"
+
+	// Maps are iterated in random order, the string must not change
+	for i := 0; i < 20; i++ {
+		require.Equal(t, expected, cstringFromComponent(c))
+	}
+}
+
 func TestGenerateCanonicalID(t *testing.T) {
 	for _, tc := range []struct {
 		prepare    func(*VEX)
@@ -276,6 +331,33 @@ func TestGenerateCanonicalID(t *testing.T) {
 	}
 }
 
+func TestCanonicalHashWith(t *testing.T) {
+	doc := genTestDoc(t)
+	sha256Hash, err := doc.CanonicalHashWith(crypto.SHA256)
+	require.NoError(t, err)
+	require.Equal(t, "8ed99017785c3b43219018c7c50353c031cdaaf1c7efc146c683b0ce57123cf6", sha256Hash)
+
+	for h, size := range map[crypto.Hash]int{
+		crypto.SHA384:   48,
+		crypto.SHA512:   64,
+		crypto.SHA3_256: 32,
+		crypto.SHA3_512: 64,
+	} {
+		hash, err := doc.CanonicalHashWith(h)
+		require.NoError(t, err, h.String())
+		require.Len(t, hash, size*2, h.String())
+	}
+
+	_, err = doc.CanonicalHashWith(crypto.MD5)
+	require.Error(t, err)
+
+	id, err := doc.GenerateCanonicalIDWith(crypto.SHA512)
+	require.NoError(t, err)
+	sha512Hash, err := doc.CanonicalHashWith(crypto.SHA512)
+	require.NoError(t, err)
+	require.Equal(t, "https://openvex.dev/docs/public/vex-sha-512-"+sha512Hash, id)
+}
+
 func TestPurlMatches(t *testing.T) {
 	for caseName, tc := range map[string]struct {
 		p1        string
@@ -304,11 +386,66 @@ func TestPurlMatches(t *testing.T) {
 			"pkg:apk/wolfi/curl@8.1.2-r0?arch=x86_64&os=linux",
 			true,
 		},
+		"unsorted qualifiers": {
+			"pkg:apk/wolfi/curl@8.1.2-r0?os=linux&arch=x86_64",
+			"pkg:apk/wolfi/curl@8.1.2-r0?distro=wolfi&arch=x86_64&os=linux",
+			true,
+		},
+		"p1 qualifier missing in p2": {
+			"pkg:apk/wolfi/curl@8.1.2-r0?arch=x86_64&distro=wolfi",
+			"pkg:apk/wolfi/curl@8.1.2-r0?arch=x86_64&os=linux",
+			false,
+		},
+		"too many qualifiers": {
+			"pkg:apk/wolfi/curl@8.1.2-r0",
+			"pkg:apk/wolfi/curl@8.1.2-r0?" + manyQualifiers(MaxPurlQualifiers+1),
+			false,
+		},
+		"qualifiers at limit": {
+			"pkg:apk/wolfi/curl@8.1.2-r0",
+			"pkg:apk/wolfi/curl@8.1.2-r0?" + manyQualifiers(MaxPurlQualifiers),
+			true,
+		},
 	} {
 		require.Equal(t, tc.mustMatch, PurlMatches(tc.p1, tc.p2), fmt.Sprintf("failed testcase: %s", caseName))
 	}
 }
 
+func manyQualifiers(n int) string {
+	q := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		q = append(q, fmt.Sprintf("q%d=v%d", i, i))
+	}
+	return strings.Join(q, "&")
+}
+
+func BenchmarkPurlMatches(b *testing.B) {
+	for name, tc := range map[string]struct {
+		p1 string
+		p2 string
+	}{
+		"no qualifiers": {"pkg:apk/wolfi/curl", "pkg:apk/wolfi/curl@8.1.2-r0"},
+		"few qualifiers": {
+			"pkg:apk/wolfi/curl@8.1.2-r0?arch=x86_64",
+			"pkg:apk/wolfi/curl@8.1.2-r0?arch=x86_64&distro=wolfi&os=linux",
+		},
+		"qualifiers at limit": {
+			"pkg:apk/wolfi/curl@8.1.2-r0?" + manyQualifiers(MaxPurlQualifiers),
+			"pkg:apk/wolfi/curl@8.1.2-r0?" + manyQualifiers(MaxPurlQualifiers),
+		},
+		"adversarial": {
+			"pkg:apk/wolfi/curl@8.1.2-r0?" + manyQualifiers(10000),
+			"pkg:apk/wolfi/curl@8.1.2-r0?" + manyQualifiers(10000),
+		},
+	} {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				PurlMatches(tc.p1, tc.p2)
+			}
+		})
+	}
+}
+
 func TestDocumentMatches(t *testing.T) {
 	now := time.Now()
 	for testCase, tc := range map[string]struct {
@@ -371,3 +508,27 @@ func TestParseContext(t *testing.T) {
 		require.Equal(t, res, tc.expected, tCase)
 	}
 }
+
+func TestEncode(t *testing.T) {
+	doc, err := Load("testdata/v0.2.0.json")
+	require.NoError(t, err)
+
+	var expected, written, compact, versioned bytes.Buffer
+	require.NoError(t, doc.ToJSON(&expected))
+	require.NoError(t, doc.Write(&written))
+	require.Equal(t, expected.String(), written.String())
+
+	require.NoError(t, doc.Encode(&compact, &EncodeOptions{Compact: true}))
+	require.Less(t, compact.Len(), expected.Len())
+	require.NotContains(t, strings.TrimSpace(compact.String()), "\n")
+	parsed, err := Parse(compact.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, doc, parsed)
+
+	require.NoError(t, doc.Encode(&versioned, &EncodeOptions{Version: "0.0.1"}))
+	version, err := DetectSpecVersion(versioned.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "0.0.1", version)
+
+	require.Error(t, doc.Encode(&versioned, &EncodeOptions{Version: "9.9.9"}))
+}
@@ -5,6 +5,8 @@ SPDX-License-Identifier: Apache-2.0
 
 package vex
 
+import "strings"
+
 // Vulnerability is a struct that captures the vulnerability identifier and
 // its aliases. When defined, the ID field should be an IRI.
 type Vulnerability struct {
@@ -20,6 +22,21 @@ type Vulnerability struct {
 	// Aliases is a list of other vulnerability identifier strings that
 	// locate the vulnerability in other tracking systems.
 	Aliases []VulnerabilityID `json:"aliases,omitempty"`
+
+	// References links to more information about the vulnerability, such as
+	// advisories.
+	References []Reference `json:"references,omitempty"`
+
+	// EPSS is the exploit prediction score of the vulnerability, see
+	// EPSSScore.
+	EPSS *EPSSScore `json:"epss,omitempty"`
+
+	// CVSS lists the CVSS assessments of the vulnerability, see NewCVSS.
+	CVSS []CVSS `json:"cvss,omitempty"`
+
+	// CWEs lists the weaknesses behind the vulnerability as CWE IDs, such
+	// as CWE-502.
+	CWEs []string `json:"cwes,omitempty"`
 }
 
 // VulnerabilityID is a string that captures a vulnerability identifier. It is
@@ -45,3 +62,36 @@ func (v *Vulnerability) Matches(identifier string) bool {
 	}
 	return false
 }
+
+// CVE returns the CVE identifier of the vulnerability in upper case, taken
+// from its name or its aliases, or an empty string if it has none.
+func (v *Vulnerability) CVE() string {
+	for _, id := range vulnerabilityIDs(v) {
+		if id = strings.ToUpper(id); strings.HasPrefix(id, "CVE-") {
+			return id
+		}
+	}
+	return ""
+}
+
+// Clone returns a deep copy of the vulnerability.
+func (v *Vulnerability) Clone() Vulnerability {
+	ret := *v
+	if v.Aliases != nil {
+		ret.Aliases = append([]VulnerabilityID{}, v.Aliases...)
+	}
+	if v.References != nil {
+		ret.References = append([]Reference{}, v.References...)
+	}
+	if v.CWEs != nil {
+		ret.CWEs = append([]string{}, v.CWEs...)
+	}
+	if v.CVSS != nil {
+		ret.CVSS = append([]CVSS{}, v.CVSS...)
+	}
+	if v.EPSS != nil {
+		epss := *v.EPSS
+		ret.EPSS = &epss
+	}
+	return ret
+}
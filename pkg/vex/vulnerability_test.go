@@ -0,0 +1,27 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVulnerabilityCVE(t *testing.T) {
+	for _, tc := range []struct {
+		vuln     Vulnerability
+		expected string
+	}{
+		{Vulnerability{Name: "CVE-2023-12345"}, "CVE-2023-12345"},
+		{Vulnerability{Name: "cve-2023-12345"}, "CVE-2023-12345"},
+		{Vulnerability{Name: "GHSA-1234", Aliases: []VulnerabilityID{"cve-2023-12345"}}, "CVE-2023-12345"},
+		{Vulnerability{Name: "GHSA-1234"}, ""},
+		{Vulnerability{}, ""},
+	} {
+		require.Equal(t, tc.expected, tc.vuln.CVE())
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Warning is an issue found when parsing a document which doesn't prevent
+// reading it, such as the use of a deprecated spec version or field.
+type Warning struct {
+	// Path is a JSON pointer to the data the warning is about. It is empty
+	// for warnings about the whole document.
+	Path string
+
+	// Message describes the issue and how to fix it.
+	Message string
+}
+
+// String returns the warning message prefixed by its path.
+func (w Warning) String() string {
+	if w.Path == "" {
+		return w.Message
+	}
+	return w.Path + ": " + w.Message
+}
+
+// warn sends a warning to the OnWarning callback of the options.
+func (opts *ParseOptions) warn(path, format string, args ...any) {
+	if opts.OnWarning == nil {
+		return
+	}
+	opts.OnWarning(Warning{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// contextWarnings reports contexts of older spec versions and locators
+// which are not written in their canonical form.
+func (opts *ParseOptions) contextWarnings(locator string) {
+	version, err := ContextVersion(locator)
+	if err != nil {
+		return
+	}
+	if version != SpecVersion {
+		opts.warn("/@context", "OpenVEX %s is deprecated, upgrade the document to %s", version, SpecVersion)
+	}
+	if canonical, err := ContextLocatorFor(version); err == nil && locator != canonical {
+		opts.warn("/@context", "use the canonical context locator %q instead of %q", canonical, locator)
+	}
+}
+
+// documentWarnings reports the fields of a document which are not defined
+// in the spec nor are extension properties. They are kept but the document
+// won't validate against the schema.
+func (opts *ParseOptions) documentWarnings(vexDoc *VEX) {
+	if opts.OnWarning == nil {
+		return
+	}
+	opts.unknownFieldWarnings("", vexDoc.RawExtensions, true)
+	for i := range vexDoc.Statements {
+		stmt := &vexDoc.Statements[i]
+		path := fmt.Sprintf("/statements/%d", i)
+		opts.unknownFieldWarnings(path, stmt.RawExtensions, true)
+		for j := range stmt.Products {
+			productPath := fmt.Sprintf("%s/products/%d", path, j)
+			opts.unknownFieldWarnings(productPath, stmt.Products[j].RawExtensions, false)
+			for k := range stmt.Products[j].Subcomponents {
+				opts.unknownFieldWarnings(
+					fmt.Sprintf("%s/subcomponents/%d", productPath, k),
+					stmt.Products[j].Subcomponents[k].RawExtensions, false,
+				)
+			}
+		}
+	}
+}
+
+// unknownFieldWarnings reports the unknown fields of an object. Extension
+// properties are only reported if the object does not support them.
+func (opts *ParseOptions) unknownFieldWarnings(path string, fields map[string]json.RawMessage, extensible bool) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if extensible && strings.HasPrefix(name, ExtensionPrefix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opts.warn(path+"/"+name, "%q is not defined in the spec", name)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vex
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWarnings(t *testing.T) {
+	for m, tc := range map[string]struct {
+		data     string
+		path     string
+		expected []string
+	}{
+		"legacy fields": {
+			path: "testdata/v0.0.1-legacy-fields.json",
+			expected: []string{
+				`/@context: OpenVEX 0.0.1 is deprecated, upgrade the document to 0.2.0`,
+				`/statements/0/impact: impact is deprecated, use impact_statement`,
+				`/statements/1/justification: "We checked and the code is never reached" is not a valid justification, it was moved to the impact statement`,
+			},
+		},
+		"string version": {
+			path: "testdata/v0.0.1.json",
+			expected: []string{
+				`/@context: OpenVEX 0.0.1 is deprecated, upgrade the document to 0.2.0`,
+				`/version: the document version is a string, it must be an integer`,
+			},
+		},
+		"current": {
+			path:     "testdata/v0.2.0.json",
+			expected: []string{},
+		},
+		"non canonical context": {
+			data: `{"@context": "http://openvex.dev/ns/v0.2.0/", "statements": []}`,
+			expected: []string{
+				`/@context: use the canonical context locator "https://openvex.dev/ns/v0.2.0" instead of "http://openvex.dev/ns/v0.2.0/"`,
+			},
+		},
+		"unknown fields": {
+			data: `{"@context": "https://openvex.dev/ns/v0.2.0", "x-ticket": "SEC-1", "statements": [
+				{"vulnerability": {"name": "CVE-2023-1234"}, "notes": "", "x-approved": true,
+				 "products": [{"@id": "pkg:oci/app", "x-build": 1, "subcomponents": [{"@id": "pkg:golang/a", "scope": ""}]}]}
+			]}`,
+			expected: []string{
+				`/statements/0/notes: "notes" is not defined in the spec`,
+				`/statements/0/products/0/x-build: "x-build" is not defined in the spec`,
+				`/statements/0/products/0/subcomponents/0/scope: "scope" is not defined in the spec`,
+			},
+		},
+	} {
+		data := []byte(tc.data)
+		if tc.path != "" {
+			var err error
+			data, err = os.ReadFile(tc.path)
+			require.NoError(t, err, m)
+		}
+
+		warnings := []string{}
+		opts := &ParseOptions{OnWarning: func(w Warning) {
+			warnings = append(warnings, w.String())
+		}}
+		_, err := ParseCompatWithOptions(opts, data)
+		require.NoError(t, err, m)
+		require.Equal(t, tc.expected, warnings, m)
+	}
+}
+
+func TestParseCompatWithOptions(t *testing.T) {
+	data, err := os.ReadFile("testdata/v0.0.1.json")
+	require.NoError(t, err)
+
+	// The context of legacy documents is checked as found in the data
+	_, err = ParseCompatWithOptions(&ParseOptions{AllowedContexts: []string{ContextLocator()}}, data)
+	require.Error(t, err)
+	doc, err := ParseCompatWithOptions(&ParseOptions{AllowedContexts: []string{"https://openvex.dev/ns/v0.0.1"}}, data)
+	require.NoError(t, err)
+	require.Equal(t, ContextLocator(), doc.Context)
+
+	// Checks run on the upconverted document
+	data, err = os.ReadFile("testdata/v0.0.1-legacy-fields.json")
+	require.NoError(t, err)
+	_, err = ParseCompatWithOptions(&ParseOptions{EnforceJustification: true}, data)
+	require.NoError(t, err)
+}
+
+func TestWarningString(t *testing.T) {
+	require.Equal(t, "message", Warning{Message: "message"}.String())
+	require.Equal(t, "/version: message", Warning{Path: "/version", Message: "message"}.String())
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"fmt"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// Finding is a vulnerability reported by a scanner.
+type Finding struct {
+	// Vulnerability is the identifier of the vulnerability as reported by
+	// the scanner.
+	Vulnerability string
+
+	// Aliases are other identifiers of the vulnerability, such as the CVE
+	// of a GHSA advisory.
+	Aliases []string
+
+	// Packages identify the packages the vulnerability was found in,
+	// usually by their purl. Findings about several packages are only
+	// matched when the effective statements of all of them agree on the
+	// status.
+	Packages []string
+
+	// Products identify the artifacts the finding was reported in, in
+	// addition to the products of the result set.
+	Products []string
+}
+
+// ResultSet is a scanner report adapted to have VEX documents applied to
+// it. Adding support for a scanner format only requires implementing it.
+type ResultSet interface {
+	// Products returns the identifiers of the scanned artifact.
+	Products() []string
+
+	// Findings returns the findings in the report.
+	Findings() []Finding
+
+	// Suppress suppresses a finding, identified by its index in the
+	// findings, by removing it from the report or marking it.
+	Suppress(finding int, s *Suppression) error
+
+	// Report returns the report with the suppressions applied.
+	Report() ([]byte, error)
+}
+
+// Updater is implemented by result sets that record the effective statement
+// of all findings and not just the suppressed ones, such as the analysis of
+// CycloneDX vulnerabilities.
+type Updater interface {
+	// Update records the effective statement of a finding which is not
+	// suppressed by it.
+	Update(finding int, stmt *vex.Statement, doc *vex.VEX) error
+}
+
+// Annotator is implemented by result sets that can keep suppressed findings
+// in the report, tagged with the VEX data that suppressed them.
+type Annotator interface {
+	// Annotate tags a finding, identified by its index in the findings,
+	// with the data of the statement suppressing it.
+	Annotate(finding int, s *Suppression) error
+}
+
+// ApplyOptions control how VEX documents are applied to result sets.
+type ApplyOptions struct {
+	// Annotate keeps the suppressed findings in the report, tagged with the
+	// statement ID, status, justification and source document, instead of
+	// suppressing them. The result set must implement Annotator.
+	Annotate bool
+
+	// DryRun leaves the report unmodified. The result still lists the
+	// findings that would be suppressed.
+	DryRun bool
+
+	// Explain records in the result a trace of the statements considered
+	// for each finding and why they matched it or not.
+	Explain bool
+
+	// Policy, when set, sets the conditions statements must meet to be
+	// applied. Rejected statements are listed in the result.
+	Policy *vex.Policy
+
+	// Signatures holds the detached signatures of the documents, checked
+	// when the policy has a Verifier.
+	Signatures map[*vex.VEX]*vex.Signatures
+
+	// Decisions, when set, records a decision for every finding suppressed
+	// or annotated as suppressed. Nothing is recorded in dry-run mode.
+	Decisions *vex.DecisionWriter
+}
+
+// Rejection is a statement rejected by the policy.
+type Rejection struct {
+	Statement *vex.Statement
+	Document  *vex.VEX
+
+	// Err explains why the statement was rejected.
+	Err error
+}
+
+// Annotation is the VEX data a suppressed finding is tagged with in annotate
+// mode.
+type Annotation struct {
+	// Statement is the @id of the statement, if it has one.
+	Statement string `json:"statement,omitempty"`
+
+	// Status is the status of the vulnerability in the statement.
+	Status vex.Status `json:"status"`
+
+	// Justification and ImpactStatement explain not_affected statuses.
+	Justification   vex.Justification `json:"justification,omitempty"`
+	ImpactStatement string            `json:"impact_statement,omitempty"`
+
+	// Document is the @id of the VEX document the statement comes from.
+	Document string `json:"document,omitempty"`
+}
+
+// Annotation returns the VEX data a finding is tagged with in annotate
+// mode.
+func (s *Suppression) Annotation() *Annotation {
+	a := &Annotation{
+		Statement:       s.Statement.ID,
+		Status:          s.Statement.Status,
+		Justification:   s.Statement.Justification,
+		ImpactStatement: s.Statement.ImpactStatement,
+	}
+	if s.Document != nil {
+		a.Document = s.Document.ID
+	}
+	return a
+}
+
+// Apply applies VEX documents to a result set. See ApplyWithOptions.
+func Apply(docs []*vex.VEX, results ResultSet) (*Result, error) {
+	return ApplyWithOptions(&ApplyOptions{}, docs, results)
+}
+
+// ApplyWithOptions applies VEX documents to a result set. The findings with
+// a not_affected or fixed effective statement are suppressed, or annotated
+// when the options say so, the statements of the rest are passed to result
+// sets implementing Updater. In dry-run mode the result set is not modified.
+func ApplyWithOptions(opts *ApplyOptions, docs []*vex.VEX, results ResultSet) (*Result, error) {
+	suppress := results.Suppress
+	if opts.Annotate {
+		a, ok := results.(Annotator)
+		if !ok {
+			return nil, fmt.Errorf("result set does not support annotations")
+		}
+		suppress = a.Annotate
+	}
+	updater, _ := results.(Updater)
+	if opts.DryRun {
+		suppress = func(int, *Suppression) error { return nil }
+		updater = nil
+	}
+
+	products := results.Products()
+	findings := results.Findings()
+	ret := &Result{Findings: len(findings), Suppressed: []Suppression{}}
+
+	rejected := map[*vex.Statement]error{}
+	if opts.Policy != nil {
+		ret.Rejected = []Rejection{}
+		for _, doc := range docs {
+			for _, v := range opts.Policy.CheckDocument(doc, opts.Signatures[doc]) {
+				rejected[v.Statement] = v.Err
+				ret.Rejected = append(ret.Rejected, Rejection{Statement: v.Statement, Document: doc, Err: v.Err})
+			}
+		}
+	}
+
+	m := newMatcher(docs, rejected)
+	used := map[*vex.Statement]struct{}{}
+	for i := range findings {
+		for _, match := range m.candidates(products, &findings[i]) {
+			used[match.Statement] = struct{}{}
+		}
+
+		stmt, doc, pkg := m.resolve(products, &findings[i])
+		if opts.Explain {
+			ret.Traces = append(ret.Traces, explain(docs, rejected, products, &findings[i], stmt, doc))
+		}
+		if stmt == nil {
+			continue
+		}
+		if !suppresses(stmt) {
+			if updater != nil {
+				if err := updater.Update(i, stmt, doc); err != nil {
+					return nil, fmt.Errorf("updating finding #%d: %w", i, err)
+				}
+			}
+			continue
+		}
+		s := Suppression{
+			Vulnerability: findings[i].Vulnerability,
+			Package:       pkg,
+			Statement:     stmt,
+			Document:      doc,
+		}
+		if err := suppress(i, &s); err != nil {
+			return nil, fmt.Errorf("suppressing finding #%d: %w", i, err)
+		}
+		if opts.Decisions != nil && !opts.DryRun {
+			if err := opts.Decisions.Record(doc, stmt, decisionFinding(products, &findings[i], pkg)); err != nil {
+				return nil, fmt.Errorf("recording decision on finding #%d: %w", i, err)
+			}
+		}
+		ret.Suppressed = append(ret.Suppressed, s)
+	}
+
+	ret.Unused = unusedStatements(docs, used, rejected)
+
+	report, err := results.Report()
+	if err != nil {
+		return nil, err
+	}
+	ret.Report = report
+	return ret, nil
+}
+
+// matcher looks up the effective statements about scanner findings.
+type matcher struct {
+	idx *vex.Index
+}
+
+// newMatcher returns a matcher of the statements in the documents except
+// the rejected ones.
+func newMatcher(docs []*vex.VEX, rejected map[*vex.Statement]error) *matcher {
+	accept := func(_ *vex.VEX, stmt *vex.Statement) bool {
+		_, ok := rejected[stmt]
+		return !ok
+	}
+	return &matcher{idx: vex.NewIndexFunc(accept, docs...)}
+}
+
+// resolve returns the effective statement about a finding, the document it
+// comes from and the package it was matched to.
+func (m *matcher) resolve(products []string, f *Finding) (*vex.Statement, *vex.VEX, string) {
+	products = append(products[:len(products):len(products)], f.Products...)
+	vulnIDs := append([]string{f.Vulnerability}, f.Aliases...)
+	if len(f.Packages) == 0 {
+		stmt, doc := m.effective(products, "", vulnIDs)
+		return stmt, doc, ""
+	}
+
+	var stmt *vex.Statement
+	var doc *vex.VEX
+	var pkg string
+	for _, p := range f.Packages {
+		s, d := m.effective(products, p, vulnIDs)
+		if s == nil || (stmt != nil && s.Status != stmt.Status) {
+			return nil, nil, ""
+		}
+		if stmt == nil || stmt.EffectiveTime(doc).Before(s.EffectiveTime(d)) {
+			stmt, doc, pkg = s, d, p
+		}
+	}
+	return stmt, doc, pkg
+}
+
+// candidates returns all the statements matching a finding, effective or
+// not, in any of its packages.
+func (m *matcher) candidates(products []string, f *Finding) []vex.IndexMatch {
+	products = append(products[:len(products):len(products)], f.Products...)
+	vulnIDs := append([]string{f.Vulnerability}, f.Aliases...)
+	if len(f.Packages) == 0 {
+		return m.idx.MatchAll(vulnIDs, products, nil)
+	}
+	ret := []vex.IndexMatch{}
+	for _, p := range f.Packages {
+		ret = append(ret, m.idx.MatchAll(vulnIDs, append(products[:len(products):len(products)], p), []string{p})...)
+	}
+	return ret
+}
+
+// effective returns the effective statement about a vulnerability, known by
+// any of vulnIDs, in a package found in the scanned products. The package is
+// matched both as a product and as a subcomponent of the products.
+func (m *matcher) effective(products []string, pkg string, vulnIDs []string) (*vex.Statement, *vex.VEX) {
+	var subcomponents []string
+	if pkg != "" {
+		products = append(products[:len(products):len(products)], pkg)
+		subcomponents = []string{pkg}
+	}
+	return m.idx.ResolveAny(vulnIDs, products, subcomponents)
+}
+
+// decisionFinding returns the finding recorded in decision records. The
+// product is the first product known of the finding, the package it was
+// matched to is recorded as a subcomponent.
+func decisionFinding(products []string, f *Finding, pkg string) vex.Finding {
+	ret := vex.Finding{Vulnerability: f.Vulnerability}
+	switch {
+	case len(f.Products) > 0:
+		ret.Product = f.Products[0]
+	case len(products) > 0:
+		ret.Product = products[0]
+	}
+	if pkg != "" {
+		if ret.Product == "" {
+			ret.Product = pkg
+		} else {
+			ret.Subcomponents = []string{pkg}
+		}
+	}
+	return ret
+}
+
+// suppresses returns true if the statement removes findings from reports.
+func suppresses(stmt *vex.Statement) bool {
+	return stmt != nil && (stmt.Status == vex.StatusNotAffected || stmt.Status == vex.StatusFixed)
+}
+
+// unusedStatements returns the statements in the documents which are not in
+// the used set, in document order. Rejected statements are not listed.
+func unusedStatements(docs []*vex.VEX, used map[*vex.Statement]struct{}, rejected map[*vex.Statement]error) []UnusedStatement {
+	ret := []UnusedStatement{}
+	for _, doc := range docs {
+		for i := range doc.Statements {
+			if _, ok := rejected[&doc.Statements[i]]; ok {
+				continue
+			}
+			if _, ok := used[&doc.Statements[i]]; !ok {
+				ret = append(ret, UnusedStatement{Statement: &doc.Statements[i], Document: doc})
+			}
+		}
+	}
+	return ret
+}
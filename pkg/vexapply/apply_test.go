@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// testResults is a result set recording the calls of the engine.
+type testResults struct {
+	findings   []Finding
+	suppressed map[int]*Suppression
+	updated    map[int]*vex.Statement
+}
+
+func (rs *testResults) Products() []string { return []string{"pkg:oci/app"} }
+
+func (rs *testResults) Findings() []Finding { return rs.findings }
+
+func (rs *testResults) Suppress(finding int, s *Suppression) error {
+	rs.suppressed[finding] = s
+	return nil
+}
+
+func (rs *testResults) Report() ([]byte, error) { return []byte("report"), nil }
+
+// testUpdater is a result set that also records the non suppressing
+// statements.
+type testUpdater struct {
+	testResults
+}
+
+func (rs *testUpdater) Update(finding int, stmt *vex.Statement, _ *vex.VEX) error {
+	rs.updated[finding] = stmt
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	doc := openDocument(t)
+	later := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	doc.Statements = append(doc.Statements, vex.Statement{
+		Vulnerability: vex.Vulnerability{Name: "CVE-2023-39325"},
+		Products:      []vex.Product{{Component: vex.Component{ID: "pkg:golang/golang.org/x/net"}}},
+		Status:        vex.StatusNotAffected,
+		Justification: vex.InlineMitigationsAlreadyExist,
+		Timestamp:     &later,
+	})
+
+	findings := []Finding{
+		// Matched through the alias, the latest statement wins
+		{Vulnerability: "GO-2023-2102", Aliases: []string{"CVE-2023-39325"}, Packages: []string{"pkg:golang/golang.org/x/net@v0.7.0"}},
+		// Affected
+		{Vulnerability: "CVE-2023-44487", Packages: []string{"pkg:golang/golang.org/x/net@v0.7.0"}},
+		// Not all the packages have a statement
+		{Vulnerability: "CVE-2023-39325", Packages: []string{"pkg:golang/golang.org/x/net@v0.7.0", "pkg:golang/example.com/other@v1.0.0"}},
+		// No statement
+		{Vulnerability: "CVE-2023-0001"},
+	}
+
+	rs := &testResults{findings: findings, suppressed: map[int]*Suppression{}}
+	res, err := Apply([]*vex.VEX{doc}, rs)
+	require.NoError(t, err)
+	require.Equal(t, []byte("report"), res.Report)
+	require.Equal(t, 4, res.Findings)
+	require.Len(t, res.Suppressed, 1)
+	require.Len(t, rs.suppressed, 1)
+	require.Equal(t, vex.InlineMitigationsAlreadyExist, rs.suppressed[0].Statement.Justification)
+	require.Equal(t, "GO-2023-2102", rs.suppressed[0].Vulnerability)
+	require.Equal(t, "pkg:golang/golang.org/x/net@v0.7.0", rs.suppressed[0].Package)
+
+	// The openssl statement matched no finding. The one superseded by the
+	// later statement is still used.
+	require.Len(t, res.Unused, 1)
+	require.Equal(t, &doc.Statements[0], res.Unused[0].Statement)
+	require.Equal(t, doc, res.Unused[0].Document)
+	require.Equal(t, 1, res.Stats().Unused)
+
+	updater := &testUpdater{testResults{findings: findings, suppressed: map[int]*Suppression{}, updated: map[int]*vex.Statement{}}}
+	_, err = Apply([]*vex.VEX{doc}, updater)
+	require.NoError(t, err)
+	require.Len(t, updater.suppressed, 1)
+	require.Len(t, updater.updated, 1)
+	require.Equal(t, vex.StatusAffected, updater.updated[1].Status)
+}
+
+func TestApplyAnnotate(t *testing.T) {
+	findings := []Finding{{Vulnerability: "CVE-2023-5678", Packages: []string{"pkg:apk/alpine/openssl@3.1.0-r4"}}}
+	rs := &testResults{findings: findings, suppressed: map[int]*Suppression{}}
+	_, err := ApplyWithOptions(&ApplyOptions{Annotate: true}, []*vex.VEX{openDocument(t)}, rs)
+	require.Error(t, err)
+
+	doc := openDocument(t)
+	doc.Statements[0].ID = "https://example.com/vex-1#openssl"
+	s := Suppression{Statement: &doc.Statements[0], Document: doc}
+	require.Equal(t, &Annotation{
+		Statement:     "https://example.com/vex-1#openssl",
+		Status:        vex.StatusNotAffected,
+		Justification: vex.VulnerableCodeNotInExecutePath,
+		Document:      "https://example.com/vex-1",
+	}, s.Annotation())
+}
+
+func TestApplyDecisions(t *testing.T) {
+	findings := []Finding{
+		{Vulnerability: "CVE-2023-5678", Packages: []string{"pkg:apk/alpine/openssl@3.1.0-r4"}},
+		{Vulnerability: "CVE-2023-44487", Packages: []string{"pkg:golang/golang.org/x/net@v0.7.0"}},
+	}
+
+	var buf bytes.Buffer
+	opts := &ApplyOptions{Decisions: vex.NewDecisionWriter(&buf)}
+	rs := &testResults{findings: findings, suppressed: map[int]*Suppression{}}
+	_, err := ApplyWithOptions(opts, []*vex.VEX{openDocument(t)}, rs)
+	require.NoError(t, err)
+
+	// Only the suppressed finding is recorded
+	var rec vex.DecisionRecord
+	dec := json.NewDecoder(&buf)
+	require.NoError(t, dec.Decode(&rec))
+	require.False(t, dec.More())
+	require.Equal(t, vex.Finding{
+		Vulnerability: "CVE-2023-5678",
+		Product:       "pkg:oci/app",
+		Subcomponents: []string{"pkg:apk/alpine/openssl@3.1.0-r4"},
+	}, rec.Finding)
+	require.Equal(t, vex.StatusNotAffected, rec.Status)
+	require.Equal(t, "https://example.com/vex-1", rec.DocumentID)
+
+	// Dry runs record nothing
+	opts.DryRun = true
+	rs = &testResults{findings: findings, suppressed: map[int]*Suppression{}}
+	_, err = ApplyWithOptions(opts, []*vex.VEX{openDocument(t)}, rs)
+	require.NoError(t, err)
+	require.Zero(t, buf.Len())
+}
+
+func TestApplyPolicy(t *testing.T) {
+	data, err := os.ReadFile("testdata/trivy.json")
+	require.NoError(t, err)
+
+	doc := openDocument(t)
+	rs, err := NewTrivyResults(data)
+	require.NoError(t, err)
+	opts := &ApplyOptions{
+		Explain: true,
+		Policy:  &vex.Policy{AllowedJustifications: []vex.Justification{vex.ComponentNotPresent}},
+	}
+	res, err := ApplyWithOptions(opts, []*vex.VEX{doc}, rs)
+	require.NoError(t, err)
+	require.Empty(t, res.Suppressed)
+	require.Empty(t, res.Unused)
+	require.Len(t, res.Rejected, 2)
+	require.Equal(t, &doc.Statements[0], res.Rejected[0].Statement)
+	require.Equal(t, doc, res.Rejected[0].Document)
+	require.ErrorIs(t, res.Rejected[0].Err, vex.ErrPolicyViolation)
+
+	require.Len(t, res.Traces, 3)
+	require.Len(t, res.Traces[0].Candidates, 1)
+	require.Equal(t, OutcomeRejected, res.Traces[0].Candidates[0].Outcome)
+	require.Nil(t, res.Traces[0].Statement)
+}
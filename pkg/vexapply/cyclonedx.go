@@ -0,0 +1,248 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/cyclonedx"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// cycloneDXStates maps the OpenVEX statuses to CycloneDX analysis states.
+var cycloneDXStates = map[vex.Status]string{
+	vex.StatusNotAffected:        "not_affected",
+	vex.StatusAffected:           "exploitable",
+	vex.StatusFixed:              "resolved",
+	vex.StatusUnderInvestigation: "in_triage",
+}
+
+// cycloneDXJustifications maps the OpenVEX justifications to CycloneDX
+// analysis justifications.
+var cycloneDXJustifications = map[vex.Justification]string{
+	vex.ComponentNotPresent:                         "requires_dependency",
+	vex.VulnerableCodeNotPresent:                    "code_not_present",
+	vex.VulnerableCodeNotInExecutePath:              "code_not_reachable",
+	vex.VulnerableCodeCannotBeControlledByAdversary: "requires_environment",
+	vex.InlineMitigationsAlreadyExist:               "protected_by_mitigating_control",
+}
+
+// cycloneDXResults adapts the vulnerabilities of a CycloneDX BOM to a result
+// set.
+type cycloneDXResults struct {
+	raw      map[string]json.RawMessage
+	vulns    []map[string]json.RawMessage
+	products []string
+	findings []Finding
+}
+
+// CycloneDX applies VEX documents to the vulnerabilities embedded in a
+// CycloneDX BOM, such as a vulnerability disclosure report (VDR). The
+// components affected by each vulnerability are resolved through their
+// bom-ref and matched by their purl, or their bom-ref if they have none, as
+// products or as subcomponents of the BOM metadata component. Vulnerabilities
+// are looked up by their ID and the IDs of their references, the ones without
+// affected components are matched to the metadata component itself.
+//
+// The analysis of a vulnerability is replaced with the data of the effective
+// statements when all of its affected components have one and they agree on
+// the status, using the latest of them. No vulnerabilities are removed from
+// the BOM, the suppressed findings are the ones marked as not_affected or
+// resolved.
+func CycloneDX(bom []byte, docs ...*vex.VEX) (*Result, error) {
+	rs, err := NewCycloneDXResults(bom)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(docs, rs)
+}
+
+// NewCycloneDXResults returns the result set of the vulnerabilities in a
+// CycloneDX BOM. Annotated vulnerabilities record the statement and document
+// IDs as openvex properties besides their analysis.
+func NewCycloneDXResults(bom []byte) (ResultSet, error) {
+	cdx, err := cyclonedx.Decode(bytes.NewReader(bom))
+	if err != nil {
+		return nil, err
+	}
+	rs := &cycloneDXResults{raw: map[string]json.RawMessage{}, products: []string{}}
+	if err := json.Unmarshal(bom, &rs.raw); err != nil {
+		return nil, fmt.Errorf("decoding cyclonedx document: %w", err)
+	}
+	if data, ok := rs.raw["vulnerabilities"]; ok {
+		if err := json.Unmarshal(data, &rs.vulns); err != nil {
+			return nil, fmt.Errorf("decoding cyclonedx vulnerabilities: %w", err)
+		}
+	}
+	if c := cdx.Metadata.Component; c != nil {
+		rs.products = append(rs.products, cycloneDXComponentIDs(c)...)
+	}
+
+	for i := range cdx.Vulnerabilities {
+		v := &cdx.Vulnerabilities[i]
+		finding := Finding{Vulnerability: v.ID}
+		for _, ref := range v.References {
+			if ref.ID != "" {
+				finding.Aliases = append(finding.Aliases, ref.ID)
+			}
+		}
+		for _, a := range v.Affects {
+			ref := a.Ref
+			if c := cdx.ComponentByRef(a.Ref); c != nil {
+				ref = cycloneDXComponentIDs(c)[0]
+			}
+			if ref != "" {
+				finding.Packages = append(finding.Packages, ref)
+			}
+		}
+		rs.findings = append(rs.findings, finding)
+	}
+	return rs, nil
+}
+
+func (rs *cycloneDXResults) Products() []string { return rs.products }
+
+func (rs *cycloneDXResults) Findings() []Finding { return rs.findings }
+
+// Suppress records the suppressing statement in the analysis of a
+// vulnerability.
+func (rs *cycloneDXResults) Suppress(finding int, s *Suppression) error {
+	return setCycloneDXAnalysis(rs.vulns[finding], s.Statement, s.Document)
+}
+
+// Update records the effective statement in the analysis of a vulnerability.
+func (rs *cycloneDXResults) Update(finding int, stmt *vex.Statement, doc *vex.VEX) error {
+	return setCycloneDXAnalysis(rs.vulns[finding], stmt, doc)
+}
+
+// Annotate records the suppressing statement in the analysis of a
+// vulnerability and its IDs in the vulnerability properties.
+func (rs *cycloneDXResults) Annotate(finding int, s *Suppression) error {
+	if err := setCycloneDXAnalysis(rs.vulns[finding], s.Statement, s.Document); err != nil {
+		return err
+	}
+	return setCycloneDXProperties(rs.vulns[finding], s.Annotation())
+}
+
+func (rs *cycloneDXResults) Report() ([]byte, error) {
+	if _, ok := rs.raw["vulnerabilities"]; ok {
+		data, err := encodeJSON(rs.vulns, false)
+		if err != nil {
+			return nil, err
+		}
+		rs.raw["vulnerabilities"] = data
+	}
+	return encodeJSON(rs.raw, true)
+}
+
+// cycloneDXComponentIDs returns the identifiers a component is matched by,
+// its purl first if it has one.
+func cycloneDXComponentIDs(c *cyclonedx.Component) []string {
+	ids := []string{}
+	if c.PURL != "" {
+		ids = append(ids, c.PURL)
+	}
+	if c.BOMRef != "" {
+		ids = append(ids, c.BOMRef)
+	}
+	if len(ids) == 0 {
+		ids = append(ids, c.Name)
+	}
+	return ids
+}
+
+// setCycloneDXAnalysis replaces the analysis of a vulnerability with the
+// data of a statement. The first issued time and the responses of the
+// analysis are kept.
+func setCycloneDXAnalysis(v map[string]json.RawMessage, stmt *vex.Statement, doc *vex.VEX) error {
+	analysis := map[string]json.RawMessage{}
+	if data, ok := v["analysis"]; ok {
+		if err := json.Unmarshal(data, &analysis); err != nil {
+			return fmt.Errorf("decoding analysis: %w", err)
+		}
+	}
+	fields := map[string]any{
+		"state":         cycloneDXStates[stmt.Status],
+		"justification": "",
+		"detail":        stmt.StatusNotes,
+		"lastUpdated":   nil,
+	}
+	if stmt.Status == vex.StatusNotAffected {
+		fields["justification"] = cycloneDXJustifications[stmt.Justification]
+		fields["detail"] = stmt.ImpactStatement
+	}
+	if ts := stmt.EffectiveTime(doc); !ts.IsZero() {
+		fields["lastUpdated"] = ts.UTC()
+	}
+	if stmt.Status == vex.StatusAffected && stmt.ActionStatement != "" {
+		data, err := encodeJSON(stmt.ActionStatement, false)
+		if err != nil {
+			return err
+		}
+		v["recommendation"] = data
+	}
+
+	for key, value := range fields {
+		if value == nil || value == "" {
+			delete(analysis, key)
+			continue
+		}
+		data, err := encodeJSON(value, false)
+		if err != nil {
+			return err
+		}
+		analysis[key] = data
+	}
+	data, err := encodeJSON(analysis, false)
+	if err != nil {
+		return err
+	}
+	v["analysis"] = data
+	return nil
+}
+
+// cycloneDXProperty is a name-value property of a CycloneDX object.
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// setCycloneDXProperties records the IDs of the statement and document of
+// an annotation in the properties of a vulnerability, replacing those of
+// previous annotations.
+func setCycloneDXProperties(v map[string]json.RawMessage, a *Annotation) error {
+	props := []cycloneDXProperty{}
+	if data, ok := v["properties"]; ok {
+		if err := json.Unmarshal(data, &props); err != nil {
+			return fmt.Errorf("decoding properties: %w", err)
+		}
+	}
+	kept := []cycloneDXProperty{}
+	for _, p := range props {
+		if !strings.HasPrefix(p.Name, "openvex:") {
+			kept = append(kept, p)
+		}
+	}
+	if a.Statement != "" {
+		kept = append(kept, cycloneDXProperty{Name: "openvex:statement", Value: a.Statement})
+	}
+	if a.Document != "" {
+		kept = append(kept, cycloneDXProperty{Name: "openvex:document", Value: a.Document})
+	}
+	if len(kept) == 0 {
+		delete(v, "properties")
+		return nil
+	}
+	data, err := encodeJSON(kept, false)
+	if err != nil {
+		return err
+	}
+	v["properties"] = data
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/cyclonedx"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestCycloneDX(t *testing.T) {
+	data, err := os.ReadFile("../vex/testdata/vex.cdx.json")
+	require.NoError(t, err)
+
+	ts := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	doc := vex.New(vex.WithID("https://example.com/vex-1"))
+	doc.Timestamp = &ts
+	doc.Statements = []vex.Statement{
+		{
+			// Matched through the GHSA reference
+			Vulnerability: vex.Vulnerability{Name: "GHSA-x4qr-2fvf-3mr5"},
+			Products:      []vex.Product{{Component: vex.Component{ID: "pkg:oci/example-image"}}},
+			Status:        vex.StatusFixed,
+		},
+		{
+			Vulnerability:   vex.Vulnerability{Name: "CVE-2023-0465"},
+			Products:        []vex.Product{{Component: vex.Component{ID: "pkg:apk/wolfi/openssl"}}},
+			Status:          vex.StatusNotAffected,
+			Justification:   vex.InlineMitigationsAlreadyExist,
+			ImpactStatement: "Policy checks are disabled",
+		},
+	}
+
+	res, err := CycloneDX(data, &doc)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Findings)
+	require.Len(t, res.Suppressed, 2)
+	require.Equal(t, "CVE-2023-0286", res.Suppressed[0].Vulnerability)
+	require.Equal(t, "CVE-2023-0465", res.Suppressed[1].Vulnerability)
+	require.Equal(t, "pkg:apk/wolfi/openssl@3.0.8", res.Suppressed[1].Package)
+
+	bom, err := cyclonedx.Decode(bytes.NewReader(res.Report))
+	require.NoError(t, err)
+	require.Len(t, bom.Vulnerabilities, 3)
+
+	// The fixed statement drops the not_affected justification and detail
+	a := bom.Vulnerabilities[0].Analysis
+	require.Equal(t, "resolved", a.State)
+	require.Empty(t, a.Justification)
+	require.Empty(t, a.Detail)
+	require.Equal(t, ts, *a.LastUpdated)
+
+	// The BOM-Link reference does not match any statement
+	a = bom.Vulnerabilities[1].Analysis
+	require.Equal(t, "exploitable", a.State)
+	require.Equal(t, []string{"update"}, a.Response)
+
+	a = bom.Vulnerabilities[2].Analysis
+	require.NotNil(t, a)
+	require.Equal(t, "not_affected", a.State)
+	require.Equal(t, "protected_by_mitigating_control", a.Justification)
+	require.Equal(t, "Policy checks are disabled", a.Detail)
+
+	// Other data is kept
+	require.Equal(t, "Upgrade to openssl 3.0.9", bom.Vulnerabilities[1].Recommendation)
+	require.Equal(t, []int{843}, bom.Vulnerabilities[0].CWEs)
+	require.Equal(t, "urn:uuid:0f7a7b4e-2bd2-4c47-8a53-1f3a8b0e7d20", bom.SerialNumber)
+
+	_, err = CycloneDX([]byte(`{"bomFormat": "SPDX"}`), &doc)
+	require.Error(t, err)
+}
+
+func TestCycloneDXAnnotate(t *testing.T) {
+	data, err := os.ReadFile("../vex/testdata/vex.cdx.json")
+	require.NoError(t, err)
+
+	doc := vex.New(vex.WithID("https://example.com/vex-1"))
+	doc.Statements = []vex.Statement{{
+		ID:            "https://example.com/vex-1#1",
+		Vulnerability: vex.Vulnerability{Name: "CVE-2023-0465"},
+		Products:      []vex.Product{{Component: vex.Component{ID: "pkg:apk/wolfi/openssl"}}},
+		Status:        vex.StatusFixed,
+	}}
+
+	rs, err := NewCycloneDXResults(data)
+	require.NoError(t, err)
+	res, err := ApplyWithOptions(&ApplyOptions{Annotate: true}, []*vex.VEX{&doc}, rs)
+	require.NoError(t, err)
+	require.Len(t, res.Suppressed, 1)
+
+	bom := struct {
+		Vulnerabilities []struct {
+			Analysis   *cyclonedx.Analysis `json:"analysis"`
+			Properties []cycloneDXProperty `json:"properties"`
+		} `json:"vulnerabilities"`
+	}{}
+	require.NoError(t, json.Unmarshal(res.Report, &bom))
+	require.Equal(t, "resolved", bom.Vulnerabilities[2].Analysis.State)
+	require.Equal(t, []cycloneDXProperty{
+		{Name: "openvex:statement", Value: "https://example.com/vex-1#1"},
+		{Name: "openvex:document", Value: "https://example.com/vex-1"},
+	}, bom.Vulnerabilities[2].Properties)
+	require.Empty(t, bom.Vulnerabilities[0].Properties)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// Outcome is the result of matching a candidate statement to a finding.
+type Outcome string
+
+const (
+	// OutcomeEffective is the statement that applies to the finding.
+	OutcomeEffective Outcome = "effective"
+
+	// OutcomeSuperseded is a matching statement older than the effective
+	// one.
+	OutcomeSuperseded Outcome = "superseded"
+
+	// OutcomeConflict is a matching statement that was not applied because
+	// the packages of the finding don't all have statements with the same
+	// status.
+	OutcomeConflict Outcome = "conflict"
+
+	// OutcomeVulnerabilityMismatch is a statement about the package of the
+	// finding and another vulnerability, such as one known by an alias the
+	// finding does not list.
+	OutcomeVulnerabilityMismatch Outcome = "vulnerability_mismatch"
+
+	// OutcomeProductMismatch is a statement about the vulnerability whose
+	// products don't match the package or the scanned artifact.
+	OutcomeProductMismatch Outcome = "product_mismatch"
+
+	// OutcomeSubcomponentMismatch is a statement about the vulnerability in
+	// the scanned artifact whose subcomponents don't include the package.
+	OutcomeSubcomponentMismatch Outcome = "subcomponent_mismatch"
+
+	// OutcomeRejected is a statement rejected by the policy.
+	OutcomeRejected Outcome = "rejected"
+)
+
+// Trace records the statements considered for a finding.
+type Trace struct {
+	// Finding is the finding the statements were matched to.
+	Finding Finding
+
+	// Candidates are the statements about the vulnerability or the package
+	// of the finding, in document order.
+	Candidates []Candidate
+
+	// Statement is the effective statement of the finding, if any.
+	Statement *vex.Statement
+
+	// Suppressed is true if the effective statement suppresses the finding.
+	Suppressed bool
+}
+
+// Candidate is a statement considered for a finding.
+type Candidate struct {
+	Statement *vex.Statement
+	Document  *vex.VEX
+
+	// Outcome is the result of matching the statement.
+	Outcome Outcome
+
+	// Reason explains the outcome.
+	Reason string
+}
+
+// explain returns the trace of a finding. effective is the statement the
+// finding was resolved to.
+func explain(docs []*vex.VEX, rejected map[*vex.Statement]error, products []string, f *Finding, effective *vex.Statement, effectiveDoc *vex.VEX) Trace {
+	trace := Trace{
+		Finding:    *f,
+		Candidates: []Candidate{},
+		Statement:  effective,
+		Suppressed: suppresses(effective),
+	}
+	vulnIDs := append([]string{f.Vulnerability}, f.Aliases...)
+	targets := append(append(slices.Clone(products), f.Products...), f.Packages...)
+
+	for _, doc := range docs {
+		for i := range doc.Statements {
+			stmt := &doc.Statements[i]
+			c := Candidate{Statement: stmt, Document: doc}
+			vulnMatch := matchesVulnerability(stmt, vulnIDs)
+			if !vulnMatch && !mentionsPackage(stmt, f.Packages) {
+				continue
+			}
+
+			switch err, isRejected := rejected[stmt]; {
+			case isRejected:
+				c.Outcome = OutcomeRejected
+				c.Reason = err.Error()
+			case !vulnMatch:
+				c.Outcome = OutcomeVulnerabilityMismatch
+				c.Reason = fmt.Sprintf(
+					"the statement is about %s, which is not any of %s",
+					stmt.Vulnerability.Name, strings.Join(vulnIDs, ", "),
+				)
+			case matchesFinding(stmt, vulnIDs, products, f):
+				switch {
+				case effective == nil:
+					c.Outcome = OutcomeConflict
+					c.Reason = "not all the packages of the finding have a statement with the same status"
+				case stmt == effective:
+					c.Outcome = OutcomeEffective
+					c.Reason = "latest matching statement"
+				default:
+					c.Outcome = OutcomeSuperseded
+					c.Reason = fmt.Sprintf(
+						"superseded by the statement from %s",
+						effective.EffectiveTime(effectiveDoc).Format(time.RFC3339),
+					)
+				}
+			case slices.ContainsFunc(targets, func(t string) bool { return matchesComponent(stmt, t) }):
+				c.Outcome = OutcomeSubcomponentMismatch
+				c.Reason = fmt.Sprintf(
+					"the statement subcomponents don't include %s", strings.Join(f.Packages, ", "),
+				)
+			default:
+				c.Outcome = OutcomeProductMismatch
+				c.Reason = fmt.Sprintf(
+					"none of the statement products match %s", strings.Join(targets, ", "),
+				)
+			}
+			trace.Candidates = append(trace.Candidates, c)
+		}
+	}
+	return trace
+}
+
+// matchesVulnerability returns true if the statement is about any of the
+// vulnerability IDs.
+func matchesVulnerability(stmt *vex.Statement, vulnIDs []string) bool {
+	return slices.ContainsFunc(vulnIDs, stmt.Vulnerability.Matches)
+}
+
+// matchesFinding returns true if the statement matches the finding in any of
+// its packages, as matcher.candidates does.
+func matchesFinding(stmt *vex.Statement, vulnIDs, products []string, f *Finding) bool {
+	products = append(products[:len(products):len(products)], f.Products...)
+	if len(f.Packages) == 0 {
+		return matchesAny(stmt, vulnIDs, products, nil)
+	}
+	for _, p := range f.Packages {
+		if matchesAny(stmt, vulnIDs, append(products[:len(products):len(products)], p), []string{p}) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(stmt *vex.Statement, vulnIDs, products, subcomponents []string) bool {
+	for _, id := range vulnIDs {
+		for _, product := range products {
+			if stmt.Matches(id, product, subcomponents) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesComponent returns true if any of the statement products is the
+// identified component.
+func matchesComponent(stmt *vex.Statement, id string) bool {
+	for i := range stmt.Products {
+		if stmt.Products[i].Component.Matches(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsPackage returns true if any of the packages is a product or a
+// subcomponent in the statement.
+func mentionsPackage(stmt *vex.Statement, packages []string) bool {
+	for _, pkg := range packages {
+		if matchesComponent(stmt, pkg) {
+			return true
+		}
+		for i := range stmt.Products {
+			for j := range stmt.Products[i].Subcomponents {
+				if stmt.Products[i].Subcomponents[j].Component.Matches(pkg) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
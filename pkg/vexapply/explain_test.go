@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestExplain(t *testing.T) {
+	doc := openDocument(t)
+	later := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	doc.Statements = append(doc.Statements,
+		vex.Statement{
+			Vulnerability: vex.Vulnerability{Name: "CVE-2023-39325"},
+			Products:      []vex.Product{{Component: vex.Component{ID: "pkg:golang/golang.org/x/net"}}},
+			Status:        vex.StatusAffected,
+			Timestamp:     &later,
+		},
+		vex.Statement{
+			Vulnerability: vex.Vulnerability{Name: "CVE-2023-39325"},
+			Products:      []vex.Product{{Component: vex.Component{ID: "pkg:oci/other"}}},
+			Status:        vex.StatusNotAffected,
+			Justification: vex.ComponentNotPresent,
+		},
+		vex.Statement{
+			Vulnerability: vex.Vulnerability{Name: "CVE-2023-39325"},
+			Products: []vex.Product{{
+				Component:     vex.Component{ID: "pkg:oci/app"},
+				Subcomponents: []vex.Subcomponent{{Component: vex.Component{ID: "pkg:golang/example.com/other"}}},
+			}},
+			Status:        vex.StatusNotAffected,
+			Justification: vex.ComponentNotPresent,
+		},
+	)
+
+	rs := &testResults{
+		findings: []Finding{
+			{Vulnerability: "GO-2023-2102", Aliases: []string{"CVE-2023-39325"}, Packages: []string{"pkg:golang/golang.org/x/net@v0.7.0"}},
+		},
+		suppressed: map[int]*Suppression{},
+	}
+	res, err := ApplyWithOptions(&ApplyOptions{Explain: true}, []*vex.VEX{doc}, rs)
+	require.NoError(t, err)
+	require.Empty(t, res.Suppressed)
+	require.Len(t, res.Traces, 1)
+
+	trace := res.Traces[0]
+	require.Equal(t, &doc.Statements[3], trace.Statement)
+	require.False(t, trace.Suppressed)
+
+	outcomes := map[*vex.Statement]Outcome{}
+	for _, c := range trace.Candidates {
+		require.NotEmpty(t, c.Reason)
+		require.Equal(t, doc, c.Document)
+		outcomes[c.Statement] = c.Outcome
+	}
+	require.Equal(t, map[*vex.Statement]Outcome{
+		&doc.Statements[1]: OutcomeSuperseded,
+		&doc.Statements[2]: OutcomeVulnerabilityMismatch,
+		&doc.Statements[3]: OutcomeEffective,
+		&doc.Statements[4]: OutcomeProductMismatch,
+		&doc.Statements[5]: OutcomeSubcomponentMismatch,
+	}, outcomes)
+	require.Equal(t, "superseded by the statement from 2023-11-01T00:00:00Z", trace.Candidates[0].Reason)
+
+	// Traces are only recorded in explain mode
+	res, err = Apply([]*vex.VEX{doc}, rs)
+	require.NoError(t, err)
+	require.Empty(t, res.Traces)
+}
+
+func TestDryRun(t *testing.T) {
+	data, err := os.ReadFile("testdata/trivy.json")
+	require.NoError(t, err)
+
+	rs, err := NewTrivyResults(data)
+	require.NoError(t, err)
+	res, err := ApplyWithOptions(&ApplyOptions{DryRun: true}, []*vex.VEX{openDocument(t)}, rs)
+	require.NoError(t, err)
+	require.Len(t, res.Suppressed, 2)
+	require.JSONEq(t, string(data), string(res.Report))
+}
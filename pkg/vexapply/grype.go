@@ -0,0 +1,249 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// GrypeOptions control how VEX documents are applied to Grype reports.
+type GrypeOptions struct {
+	// MarkIgnored moves the suppressed matches to the ignoredMatches list
+	// of the report, recording the statement status and justification in
+	// their applied ignore rules the way Grype does, instead of dropping
+	// them.
+	MarkIgnored bool
+}
+
+// grypeReport captures the fields of a Grype JSON report used to identify
+// the scanned artifact.
+type grypeReport struct {
+	Source struct {
+		Type   string
+		Target json.RawMessage
+	}
+}
+
+// grypeImage is the target of Grype reports about container images.
+type grypeImage struct {
+	UserInput   string   `json:"userInput"`
+	ImageID     string   `json:"imageID"`
+	RepoDigests []string `json:"repoDigests"`
+}
+
+// grypeMatch captures the fields of a Grype match used to match it against
+// VEX statements.
+type grypeMatch struct {
+	Vulnerability struct {
+		ID string `json:"id"`
+	} `json:"vulnerability"`
+	RelatedVulnerabilities []struct {
+		ID string `json:"id"`
+	} `json:"relatedVulnerabilities"`
+	Artifact struct {
+		Name string `json:"name"`
+		PURL string `json:"purl"`
+	} `json:"artifact"`
+}
+
+// grypeIgnoreRule is the rule recorded in the matches marked as ignored.
+type grypeIgnoreRule struct {
+	Vulnerability    string `json:"vulnerability"`
+	VexStatus        string `json:"vex-status"`
+	VexJustification string `json:"vex-justification,omitempty"`
+}
+
+// grypeResults adapts a Grype JSON report to a result set.
+type grypeResults struct {
+	opts     *GrypeOptions
+	raw      map[string]json.RawMessage
+	matches  []json.RawMessage
+	ignored  []json.RawMessage
+	products []string
+	findings []Finding
+	dropped  map[int]bool
+}
+
+// Grype applies VEX documents to a Grype JSON report, dropping the
+// suppressed matches. See GrypeWithOptions.
+func Grype(report []byte, docs ...*vex.VEX) (*Result, error) {
+	return GrypeWithOptions(&GrypeOptions{}, report, docs...)
+}
+
+// GrypeWithOptions applies VEX documents to a Grype JSON report. Matches are
+// looked up by their vulnerability ID and the IDs of the related
+// vulnerabilities, such as the CVE of a GHSA advisory, and by the artifact
+// purl. Statements may be about the package itself or list it as a
+// subcomponent of the scanned source, identified by the user input and,
+// for container images, by the oci purls of its repository digests. Matches
+// with a not_affected or fixed effective statement are dropped or, when the
+// options say so, marked as ignored.
+func GrypeWithOptions(opts *GrypeOptions, report []byte, docs ...*vex.VEX) (*Result, error) {
+	rs, err := NewGrypeResults(opts, report)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(docs, rs)
+}
+
+// NewGrypeResults returns the result set of a Grype JSON report. Annotated
+// matches are tagged under the vex key.
+func NewGrypeResults(opts *GrypeOptions, report []byte) (ResultSet, error) {
+	rs := &grypeResults{
+		opts:    opts,
+		raw:     map[string]json.RawMessage{},
+		ignored: []json.RawMessage{},
+		dropped: map[int]bool{},
+	}
+	if err := json.Unmarshal(report, &rs.raw); err != nil {
+		return nil, fmt.Errorf("decoding grype report: %w", err)
+	}
+	meta := grypeReport{}
+	if err := json.Unmarshal(report, &meta); err != nil {
+		return nil, fmt.Errorf("decoding grype report: %w", err)
+	}
+	rs.products = grypeProducts(&meta)
+
+	if data, ok := rs.raw["matches"]; ok {
+		if err := json.Unmarshal(data, &rs.matches); err != nil {
+			return nil, fmt.Errorf("decoding grype matches: %w", err)
+		}
+	}
+	if data, ok := rs.raw["ignoredMatches"]; ok && opts.MarkIgnored {
+		if err := json.Unmarshal(data, &rs.ignored); err != nil {
+			return nil, fmt.Errorf("decoding grype ignored matches: %w", err)
+		}
+	}
+
+	for i, data := range rs.matches {
+		match := grypeMatch{}
+		if err := json.Unmarshal(data, &match); err != nil {
+			return nil, fmt.Errorf("decoding match #%d: %w", i, err)
+		}
+		finding := Finding{Vulnerability: match.Vulnerability.ID}
+		for _, related := range match.RelatedVulnerabilities {
+			finding.Aliases = append(finding.Aliases, related.ID)
+		}
+		pkg := match.Artifact.PURL
+		if pkg == "" {
+			pkg = match.Artifact.Name
+		}
+		finding.Packages = nonEmpty(pkg)
+		rs.findings = append(rs.findings, finding)
+	}
+	return rs, nil
+}
+
+func (rs *grypeResults) Products() []string { return rs.products }
+
+func (rs *grypeResults) Findings() []Finding { return rs.findings }
+
+// Suppress drops a match or moves it to the ignored matches.
+func (rs *grypeResults) Suppress(finding int, s *Suppression) error {
+	rs.dropped[finding] = true
+	if !rs.opts.MarkIgnored {
+		return nil
+	}
+	marked, err := grypeIgnoredMatch(rs.matches[finding], s.Vulnerability, s.Statement)
+	if err != nil {
+		return err
+	}
+	rs.ignored = append(rs.ignored, marked)
+	return nil
+}
+
+// Annotate tags a match with the statement suppressing it.
+func (rs *grypeResults) Annotate(finding int, s *Suppression) error {
+	data, err := annotateJSON(rs.matches[finding], "vex", s.Annotation())
+	if err != nil {
+		return err
+	}
+	rs.matches[finding] = data
+	return nil
+}
+
+func (rs *grypeResults) Report() ([]byte, error) {
+	kept := []json.RawMessage{}
+	for i, data := range rs.matches {
+		if !rs.dropped[i] {
+			kept = append(kept, data)
+		}
+	}
+	data, err := encodeJSON(kept, false)
+	if err != nil {
+		return nil, err
+	}
+	rs.raw["matches"] = data
+	if rs.opts.MarkIgnored && len(rs.ignored) > 0 {
+		data, err := encodeJSON(rs.ignored, false)
+		if err != nil {
+			return nil, err
+		}
+		rs.raw["ignoredMatches"] = data
+	}
+	return encodeJSON(rs.raw, true)
+}
+
+// grypeIgnoredMatch adds the ignore rule of a VEX statement to a match.
+func grypeIgnoredMatch(data json.RawMessage, vulnID string, stmt *vex.Statement) (json.RawMessage, error) {
+	match := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &match); err != nil {
+		return nil, err
+	}
+	rules := []json.RawMessage{}
+	if existing, ok := match["appliedIgnoreRules"]; ok {
+		if err := json.Unmarshal(existing, &rules); err != nil {
+			return nil, err
+		}
+	}
+	rule, err := encodeJSON(grypeIgnoreRule{
+		Vulnerability:    vulnID,
+		VexStatus:        string(stmt.Status),
+		VexJustification: string(stmt.Justification),
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	rules = append(rules, rule)
+	if match["appliedIgnoreRules"], err = encodeJSON(rules, false); err != nil {
+		return nil, err
+	}
+	return encodeJSON(match, false)
+}
+
+// grypeProducts returns the identifiers of the source scanned in a report.
+func grypeProducts(report *grypeReport) []string {
+	products := []string{}
+	if len(report.Source.Target) == 0 {
+		return products
+	}
+
+	// Directory and file sources are a plain string
+	var target string
+	if err := json.Unmarshal(report.Source.Target, &target); err == nil {
+		if target != "" {
+			products = append(products, target)
+		}
+		return products
+	}
+
+	image := grypeImage{}
+	if err := json.Unmarshal(report.Source.Target, &image); err != nil {
+		return products
+	}
+	if image.UserInput != "" {
+		products = append(products, image.UserInput)
+	}
+	for _, digest := range image.RepoDigests {
+		if p := ociPurl(digest); p != "" {
+			products = append(products, p)
+		}
+	}
+	return products
+}
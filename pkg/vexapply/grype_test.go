@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestGrype(t *testing.T) {
+	data, err := os.ReadFile("testdata/grype.json")
+	require.NoError(t, err)
+
+	doc := openDocument(t)
+	res, err := Grype(data, doc)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Findings)
+	require.Len(t, res.Suppressed, 2)
+
+	// Matched through the related CVE of the GHSA advisory
+	require.Equal(t, "GHSA-4374-p667-p6c8", res.Suppressed[0].Vulnerability)
+	require.Equal(t, &doc.Statements[1], res.Suppressed[0].Statement)
+	require.Equal(t, "CVE-2023-5678", res.Suppressed[1].Vulnerability)
+
+	report := struct {
+		Matches        []grypeMatch      `json:"matches"`
+		IgnoredMatches []json.RawMessage `json:"ignoredMatches"`
+		Descriptor     map[string]string `json:"descriptor"`
+	}{}
+	require.NoError(t, json.Unmarshal(res.Report, &report))
+	require.Len(t, report.Matches, 1)
+	require.Equal(t, "CVE-2023-44487", report.Matches[0].Vulnerability.ID)
+	require.Empty(t, report.IgnoredMatches)
+	require.Equal(t, "grype", report.Descriptor["name"])
+
+	stats := res.Stats()
+	require.Equal(t, 3, stats.Findings)
+	require.Equal(t, 2, stats.Suppressed)
+	require.Equal(t, 0, stats.Unused)
+	require.Equal(t, 2, stats.ByStatus[vex.StatusNotAffected])
+	require.Equal(t, 2, stats.ByJustification[vex.VulnerableCodeNotInExecutePath])
+	require.Equal(t, 2, stats.ByDocument["https://example.com/vex-1"])
+}
+
+func TestGrypeMarkIgnored(t *testing.T) {
+	data, err := os.ReadFile("testdata/grype.json")
+	require.NoError(t, err)
+
+	res, err := GrypeWithOptions(&GrypeOptions{MarkIgnored: true}, data, openDocument(t))
+	require.NoError(t, err)
+	require.Len(t, res.Suppressed, 2)
+
+	report := struct {
+		Matches        []grypeMatch `json:"matches"`
+		IgnoredMatches []struct {
+			grypeMatch
+			AppliedIgnoreRules []grypeIgnoreRule `json:"appliedIgnoreRules"`
+		} `json:"ignoredMatches"`
+	}{}
+	require.NoError(t, json.Unmarshal(res.Report, &report))
+	require.Len(t, report.Matches, 1)
+	require.Len(t, report.IgnoredMatches, 2)
+	require.Equal(t, "GHSA-4374-p667-p6c8", report.IgnoredMatches[0].Vulnerability.ID)
+	require.Equal(t, []grypeIgnoreRule{{
+		Vulnerability:    "GHSA-4374-p667-p6c8",
+		VexStatus:        "not_affected",
+		VexJustification: "vulnerable_code_not_in_execute_path",
+	}}, report.IgnoredMatches[0].AppliedIgnoreRules)
+}
+
+func TestGrypeProducts(t *testing.T) {
+	report := grypeReport{}
+	require.NoError(t, json.Unmarshal([]byte(`{"source": {"type": "directory", "target": "/src/app"}}`), &report))
+	require.Equal(t, []string{"/src/app"}, grypeProducts(&report))
+
+	report = grypeReport{}
+	require.Empty(t, grypeProducts(&report))
+}
+
+func TestGrypeAnnotate(t *testing.T) {
+	data, err := os.ReadFile("testdata/grype.json")
+	require.NoError(t, err)
+
+	rs, err := NewGrypeResults(&GrypeOptions{}, data)
+	require.NoError(t, err)
+	res, err := ApplyWithOptions(&ApplyOptions{Annotate: true}, []*vex.VEX{openDocument(t)}, rs)
+	require.NoError(t, err)
+	require.Len(t, res.Suppressed, 2)
+
+	report := struct {
+		Matches []struct {
+			grypeMatch
+			VEX *Annotation `json:"vex"`
+		} `json:"matches"`
+	}{}
+	require.NoError(t, json.Unmarshal(res.Report, &report))
+	require.Len(t, report.Matches, 3)
+	require.Equal(t, vex.StatusNotAffected, report.Matches[0].VEX.Status)
+	require.Equal(t, "https://example.com/vex-1", report.Matches[1].VEX.Document)
+	require.Nil(t, report.Matches[2].VEX)
+}
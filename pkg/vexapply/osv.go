@@ -0,0 +1,312 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// osvEcosystemTypes maps the OSV ecosystems to purl types and namespaces
+var osvEcosystemTypes = map[string]string{
+	"ALPINE":    "apk/alpine",
+	"CRATES.IO": "cargo",
+	"DEBIAN":    "deb/debian",
+	"GO":        "golang",
+	"HEX":       "hex",
+	"MAVEN":     "maven",
+	"NPM":       "npm",
+	"NUGET":     "nuget",
+	"PACKAGIST": "composer",
+	"PUB":       "pub",
+	"PYPI":      "pypi",
+	"RUBYGEMS":  "gem",
+	"UBUNTU":    "deb/ubuntu",
+}
+
+// OSVScannerOptions control how VEX documents are applied to osv-scanner
+// reports.
+type OSVScannerOptions struct {
+	// Products are the identifiers of the scanned artifact. The paths of
+	// the scanned sources, such as lockfiles, are always matched as well.
+	Products []string
+}
+
+// osvSource is the source of the packages in an osv-scanner result.
+type osvSource struct {
+	Path string `json:"path"`
+}
+
+// osvPackage identifies a package in an osv-scanner report.
+type osvPackage struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+// osvVulnerability captures the fields of an OSV entry used to match it
+// against VEX statements.
+type osvVulnerability struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+}
+
+// purl returns the purl of the package, or an empty string if the
+// ecosystem has no purl type.
+func (p *osvPackage) purl() string {
+	ecosystem, _, _ := strings.Cut(p.Ecosystem, ":")
+	t, ok := osvEcosystemTypes[strings.ToUpper(ecosystem)]
+	if !ok || p.Name == "" {
+		return ""
+	}
+	name := p.Name
+	if t == "maven" {
+		name = strings.Replace(name, ":", "/", 1)
+	}
+	purl := "pkg:" + t + "/" + name
+	if p.Version != "" {
+		purl += "@" + p.Version
+	}
+	return purl
+}
+
+// osvResults adapts an osv-scanner JSON report to a result set.
+type osvResults struct {
+	opts     *OSVScannerOptions
+	raw      map[string]json.RawMessage
+	results  []map[string]json.RawMessage
+	packages [][]map[string]json.RawMessage
+	vulns    [][][]json.RawMessage
+	findings []Finding
+
+	// locations holds the result, package and vulnerability index of each
+	// finding
+	locations [][3]int
+	dropped   map[[3]int]bool
+
+	// suppressedIDs holds the IDs and aliases of the vulnerabilities
+	// dropped from each package
+	suppressedIDs map[[2]int]map[string]bool
+}
+
+// OSVScanner applies VEX documents to an osv-scanner JSON report. See
+// OSVScannerWithOptions.
+func OSVScanner(report []byte, docs ...*vex.VEX) (*Result, error) {
+	return OSVScannerWithOptions(&OSVScannerOptions{}, report, docs...)
+}
+
+// OSVScannerWithOptions applies VEX documents to an osv-scanner JSON report.
+// Vulnerabilities are matched by their OSV ID and aliases and the package
+// by the purl built from its ecosystem coordinates. Vulnerabilities with a
+// not_affected or fixed effective statement are removed from the report,
+// together with the groups, packages and results left without any.
+func OSVScannerWithOptions(opts *OSVScannerOptions, report []byte, docs ...*vex.VEX) (*Result, error) {
+	rs, err := NewOSVScannerResults(opts, report)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(docs, rs)
+}
+
+// NewOSVScannerResults returns the result set of an osv-scanner JSON report.
+// Annotated vulnerabilities are tagged under the vex key.
+func NewOSVScannerResults(opts *OSVScannerOptions, report []byte) (ResultSet, error) {
+	rs := &osvResults{
+		opts:          opts,
+		raw:           map[string]json.RawMessage{},
+		dropped:       map[[3]int]bool{},
+		suppressedIDs: map[[2]int]map[string]bool{},
+	}
+	if err := json.Unmarshal(report, &rs.raw); err != nil {
+		return nil, fmt.Errorf("decoding osv-scanner report: %w", err)
+	}
+	if data, ok := rs.raw["results"]; ok {
+		if err := json.Unmarshal(data, &rs.results); err != nil {
+			return nil, fmt.Errorf("decoding osv-scanner results: %w", err)
+		}
+	}
+
+	rs.packages = make([][]map[string]json.RawMessage, len(rs.results))
+	rs.vulns = make([][][]json.RawMessage, len(rs.results))
+	for i, result := range rs.results {
+		source := osvSource{}
+		if data, ok := result["source"]; ok {
+			if err := json.Unmarshal(data, &source); err != nil {
+				return nil, fmt.Errorf("decoding source of result #%d: %w", i, err)
+			}
+		}
+		if data, ok := result["packages"]; ok {
+			if err := json.Unmarshal(data, &rs.packages[i]); err != nil {
+				return nil, fmt.Errorf("decoding packages of result #%d: %w", i, err)
+			}
+		}
+		rs.vulns[i] = make([][]json.RawMessage, len(rs.packages[i]))
+		for j, pkg := range rs.packages[i] {
+			if err := rs.addPackage(i, j, pkg, source.Path); err != nil {
+				return nil, fmt.Errorf("decoding package #%d of result #%d: %w", j, i, err)
+			}
+		}
+	}
+	return rs, nil
+}
+
+// addPackage adds the vulnerabilities of a package to the findings.
+func (rs *osvResults) addPackage(result, index int, pkg map[string]json.RawMessage, source string) error {
+	info := osvPackage{}
+	if data, ok := pkg["package"]; ok {
+		if err := json.Unmarshal(data, &info); err != nil {
+			return fmt.Errorf("decoding package: %w", err)
+		}
+	}
+	if data, ok := pkg["vulnerabilities"]; ok {
+		if err := json.Unmarshal(data, &rs.vulns[result][index]); err != nil {
+			return fmt.Errorf("decoding vulnerabilities: %w", err)
+		}
+	}
+
+	pkgID := info.purl()
+	if pkgID == "" {
+		pkgID = info.Name
+	}
+	for i, data := range rs.vulns[result][index] {
+		v := osvVulnerability{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("decoding vulnerability #%d: %w", i, err)
+		}
+		rs.findings = append(rs.findings, Finding{
+			Vulnerability: v.ID,
+			Aliases:       v.Aliases,
+			Packages:      nonEmpty(pkgID),
+			Products:      nonEmpty(source),
+		})
+		rs.locations = append(rs.locations, [3]int{result, index, i})
+	}
+	return nil
+}
+
+func (rs *osvResults) Products() []string { return rs.opts.Products }
+
+func (rs *osvResults) Findings() []Finding { return rs.findings }
+
+// Suppress removes a vulnerability from its package.
+func (rs *osvResults) Suppress(finding int, _ *Suppression) error {
+	l := rs.locations[finding]
+	rs.dropped[l] = true
+	pkg := [2]int{l[0], l[1]}
+	if rs.suppressedIDs[pkg] == nil {
+		rs.suppressedIDs[pkg] = map[string]bool{}
+	}
+	f := &rs.findings[finding]
+	rs.suppressedIDs[pkg][f.Vulnerability] = true
+	for _, id := range f.Aliases {
+		rs.suppressedIDs[pkg][id] = true
+	}
+	return nil
+}
+
+// Annotate tags a vulnerability with the statement suppressing it.
+func (rs *osvResults) Annotate(finding int, s *Suppression) error {
+	l := rs.locations[finding]
+	data, err := annotateJSON(rs.vulns[l[0]][l[1]][l[2]], "vex", s.Annotation())
+	if err != nil {
+		return err
+	}
+	rs.vulns[l[0]][l[1]][l[2]] = data
+	return nil
+}
+
+func (rs *osvResults) Report() ([]byte, error) {
+	keptResults := []map[string]json.RawMessage{}
+	for i, result := range rs.results {
+		keptPackages := []map[string]json.RawMessage{}
+		for j, pkg := range rs.packages[i] {
+			keep, err := rs.reducePackage(i, j, pkg)
+			if err != nil {
+				return nil, fmt.Errorf("encoding package #%d of result #%d: %w", j, i, err)
+			}
+			if keep {
+				keptPackages = append(keptPackages, pkg)
+			}
+		}
+		if len(rs.packages[i]) > 0 && len(keptPackages) == 0 {
+			continue
+		}
+
+		if _, ok := result["packages"]; ok {
+			data, err := encodeJSON(keptPackages, false)
+			if err != nil {
+				return nil, err
+			}
+			result["packages"] = data
+		}
+		keptResults = append(keptResults, result)
+	}
+
+	if _, ok := rs.raw["results"]; ok {
+		data, err := encodeJSON(keptResults, false)
+		if err != nil {
+			return nil, err
+		}
+		rs.raw["results"] = data
+	}
+	return encodeJSON(rs.raw, true)
+}
+
+// reducePackage removes the suppressed vulnerabilities, and their groups,
+// from a package. It returns false if the package is left without
+// vulnerabilities.
+func (rs *osvResults) reducePackage(result, index int, pkg map[string]json.RawMessage) (bool, error) {
+	vulns := rs.vulns[result][index]
+	if len(vulns) == 0 {
+		return true, nil
+	}
+	kept := []json.RawMessage{}
+	for i, data := range vulns {
+		if !rs.dropped[[3]int{result, index, i}] {
+			kept = append(kept, data)
+		}
+	}
+	if len(kept) == 0 {
+		return false, nil
+	}
+
+	data, err := encodeJSON(kept, false)
+	if err != nil {
+		return false, err
+	}
+	pkg["vulnerabilities"] = data
+
+	// Drop the groups of the suppressed vulnerabilities
+	suppressed := rs.suppressedIDs[[2]int{result, index}]
+	if data, ok := pkg["groups"]; ok && len(suppressed) > 0 {
+		groups := []json.RawMessage{}
+		if err := json.Unmarshal(data, &groups); err != nil {
+			return false, fmt.Errorf("decoding groups: %w", err)
+		}
+		keptGroups := []json.RawMessage{}
+		for _, g := range groups {
+			group := struct {
+				IDs []string `json:"ids"`
+			}{}
+			if err := json.Unmarshal(g, &group); err != nil {
+				return false, fmt.Errorf("decoding group: %w", err)
+			}
+			for _, id := range group.IDs {
+				if !suppressed[id] {
+					keptGroups = append(keptGroups, g)
+					break
+				}
+			}
+		}
+		if pkg["groups"], err = encodeJSON(keptGroups, false); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
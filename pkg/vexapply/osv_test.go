@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestOSVScanner(t *testing.T) {
+	data, err := os.ReadFile("testdata/osv-scanner.json")
+	require.NoError(t, err)
+
+	doc := openDocument(t)
+	doc.Statements = append(doc.Statements, vex.Statement{
+		Vulnerability: vex.Vulnerability{Name: "CVE-2022-25883"},
+		Products: []vex.Product{{
+			Component:     vex.Component{ID: "/src/app/package-lock.json"},
+			Subcomponents: []vex.Subcomponent{{Component: vex.Component{ID: "pkg:npm/semver"}}},
+		}},
+		Status: vex.StatusFixed,
+	})
+
+	// The image statement only matches when the image is a product
+	res, err := OSVScanner(data, doc)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Findings)
+	require.Len(t, res.Suppressed, 1)
+	require.Equal(t, "GHSA-c2qf-rxjj-qqgw", res.Suppressed[0].Vulnerability)
+	require.Equal(t, "pkg:npm/semver@7.5.1", res.Suppressed[0].Package)
+
+	res, err = OSVScannerWithOptions(&OSVScannerOptions{Products: []string{"pkg:oci/app"}}, data, doc)
+	require.NoError(t, err)
+	require.Len(t, res.Suppressed, 2)
+	require.Equal(t, "GO-2023-2102", res.Suppressed[0].Vulnerability)
+	require.Equal(t, "pkg:golang/golang.org/x/net@0.7.0", res.Suppressed[0].Package)
+
+	report := struct {
+		Results []struct {
+			Source   osvSource `json:"source"`
+			Packages []struct {
+				Vulnerabilities []osvVulnerability `json:"vulnerabilities"`
+				Groups          []struct {
+					IDs []string `json:"ids"`
+				} `json:"groups"`
+			} `json:"packages"`
+		} `json:"results"`
+	}{}
+	require.NoError(t, json.Unmarshal(res.Report, &report))
+
+	// The npm result is left without vulnerabilities
+	require.Len(t, report.Results, 1)
+	require.Equal(t, "/src/app/go.mod", report.Results[0].Source.Path)
+	require.Len(t, report.Results[0].Packages[0].Vulnerabilities, 1)
+	require.Equal(t, "GHSA-qppj-fm5r-hxr3", report.Results[0].Packages[0].Vulnerabilities[0].ID)
+	require.Len(t, report.Results[0].Packages[0].Groups, 1)
+	require.Equal(t, []string{"GHSA-qppj-fm5r-hxr3"}, report.Results[0].Packages[0].Groups[0].IDs)
+}
+
+func TestOSVPackagePurl(t *testing.T) {
+	for _, tc := range []struct {
+		pkg      osvPackage
+		expected string
+	}{
+		{osvPackage{Name: "golang.org/x/net", Version: "0.7.0", Ecosystem: "Go"}, "pkg:golang/golang.org/x/net@0.7.0"},
+		{osvPackage{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven"}, "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+		{osvPackage{Name: "openssl", Version: "3.0.11-1", Ecosystem: "Debian:12"}, "pkg:deb/debian/openssl@3.0.11-1"},
+		{osvPackage{Name: "serde", Ecosystem: "crates.io"}, "pkg:cargo/serde"},
+		{osvPackage{Name: "linux", Ecosystem: "Linux"}, ""},
+	} {
+		require.Equal(t, tc.expected, tc.pkg.purl())
+	}
+}
+
+func TestOSVScannerAnnotate(t *testing.T) {
+	data, err := os.ReadFile("testdata/osv-scanner.json")
+	require.NoError(t, err)
+
+	rs, err := NewOSVScannerResults(&OSVScannerOptions{Products: []string{"pkg:oci/app"}}, data)
+	require.NoError(t, err)
+	res, err := ApplyWithOptions(&ApplyOptions{Annotate: true}, []*vex.VEX{openDocument(t)}, rs)
+	require.NoError(t, err)
+	require.Len(t, res.Suppressed, 1)
+
+	report := struct {
+		Results []struct {
+			Packages []struct {
+				Vulnerabilities []struct {
+					ID  string      `json:"id"`
+					VEX *Annotation `json:"vex"`
+				} `json:"vulnerabilities"`
+				Groups []json.RawMessage `json:"groups"`
+			} `json:"packages"`
+		} `json:"results"`
+	}{}
+	require.NoError(t, json.Unmarshal(res.Report, &report))
+	require.Len(t, report.Results, 2)
+	pkg := report.Results[0].Packages[0]
+	require.Len(t, pkg.Vulnerabilities, 2)
+	require.Len(t, pkg.Groups, 2)
+	require.Equal(t, "GO-2023-2102", pkg.Vulnerabilities[0].ID)
+	require.Equal(t, vex.VulnerableCodeNotInExecutePath, pkg.Vulnerabilities[0].VEX.Justification)
+	require.Nil(t, pkg.Vulnerabilities[1].VEX)
+}
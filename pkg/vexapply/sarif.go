@@ -0,0 +1,225 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// SARIFOptions control how VEX documents are applied to SARIF reports.
+type SARIFOptions struct {
+	// Products are the identifiers of the scanned artifact. SARIF reports
+	// don't record what was scanned so, without them, only statements about
+	// the packages themselves match the results.
+	Products []string
+}
+
+// sarifResult captures the fields of a SARIF result used to match it against
+// VEX statements.
+type sarifResult struct {
+	RuleID     string         `json:"ruleId"`
+	Properties map[string]any `json:"properties"`
+	Locations  []struct {
+		LogicalLocations []struct {
+			FullyQualifiedName string `json:"fullyQualifiedName"`
+		} `json:"logicalLocations"`
+	} `json:"locations"`
+}
+
+// sarifSuppression is the suppression added to the results covered by a
+// statement.
+type sarifSuppression struct {
+	Kind          string         `json:"kind"`
+	Status        string         `json:"status"`
+	Justification string         `json:"justification,omitempty"`
+	Properties    map[string]any `json:"properties,omitempty"`
+}
+
+// sarifResults adapts a SARIF report to a result set.
+type sarifResults struct {
+	opts     *SARIFOptions
+	raw      map[string]json.RawMessage
+	runs     []map[string]json.RawMessage
+	results  [][]json.RawMessage
+	findings []Finding
+
+	// locations holds the run and result index of each finding
+	locations [][2]int
+}
+
+// SARIF applies VEX documents to a SARIF report. See SARIFWithOptions.
+func SARIF(report []byte, docs ...*vex.VEX) (*Result, error) {
+	return SARIFWithOptions(&SARIFOptions{}, report, docs...)
+}
+
+// SARIFWithOptions applies VEX documents to a SARIF report. The rule ID of
+// each result is taken as the vulnerability ID and the package is read from
+// the purl in the result properties or, failing that, from a logical
+// location named with a purl. Results with a not_affected or fixed effective
+// statement are kept in the report but marked with an accepted external
+// suppression whose justification explains the VEX statement, which SARIF
+// consumers such as GitHub code scanning show as dismissed.
+func SARIFWithOptions(opts *SARIFOptions, report []byte, docs ...*vex.VEX) (*Result, error) {
+	rs, err := NewSARIFResults(opts, report)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(docs, rs)
+}
+
+// NewSARIFResults returns the result set of a SARIF report. Suppressed
+// results are always kept in the report, so annotating them is the same as
+// suppressing them.
+func NewSARIFResults(opts *SARIFOptions, report []byte) (ResultSet, error) {
+	rs := &sarifResults{opts: opts, raw: map[string]json.RawMessage{}}
+	if err := json.Unmarshal(report, &rs.raw); err != nil {
+		return nil, fmt.Errorf("decoding sarif report: %w", err)
+	}
+	if data, ok := rs.raw["runs"]; ok {
+		if err := json.Unmarshal(data, &rs.runs); err != nil {
+			return nil, fmt.Errorf("decoding sarif runs: %w", err)
+		}
+	}
+
+	rs.results = make([][]json.RawMessage, len(rs.runs))
+	for i := range rs.runs {
+		data, ok := rs.runs[i]["results"]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(data, &rs.results[i]); err != nil {
+			return nil, fmt.Errorf("decoding results of run #%d: %w", i, err)
+		}
+		for j := range rs.results[i] {
+			finding := sarifResult{}
+			if err := json.Unmarshal(rs.results[i][j], &finding); err != nil {
+				return nil, fmt.Errorf("decoding result #%d of run #%d: %w", j, i, err)
+			}
+			if finding.RuleID == "" {
+				continue
+			}
+			rs.findings = append(rs.findings, Finding{
+				Vulnerability: finding.RuleID,
+				Packages:      nonEmpty(finding.purl()),
+			})
+			rs.locations = append(rs.locations, [2]int{i, j})
+		}
+	}
+	return rs, nil
+}
+
+func (rs *sarifResults) Products() []string { return rs.opts.Products }
+
+func (rs *sarifResults) Findings() []Finding { return rs.findings }
+
+// Suppress adds a suppression to the result of a finding.
+func (rs *sarifResults) Suppress(finding int, s *Suppression) error {
+	l := rs.locations[finding]
+	marked, err := sarifSuppressedResult(rs.results[l[0]][l[1]], newSARIFSuppression(s.Statement, s.Document))
+	if err != nil {
+		return err
+	}
+	rs.results[l[0]][l[1]] = marked
+	return nil
+}
+
+// Annotate adds a suppression to the result of a finding.
+func (rs *sarifResults) Annotate(finding int, s *Suppression) error {
+	return rs.Suppress(finding, s)
+}
+
+func (rs *sarifResults) Report() ([]byte, error) {
+	for i := range rs.runs {
+		if _, ok := rs.runs[i]["results"]; !ok {
+			continue
+		}
+		data, err := encodeJSON(rs.results[i], false)
+		if err != nil {
+			return nil, err
+		}
+		rs.runs[i]["results"] = data
+	}
+	if _, ok := rs.raw["runs"]; ok {
+		data, err := encodeJSON(rs.runs, false)
+		if err != nil {
+			return nil, err
+		}
+		rs.raw["runs"] = data
+	}
+	return encodeJSON(rs.raw, true)
+}
+
+// sarifSuppressedResult adds a suppression to a result.
+func sarifSuppressedResult(data json.RawMessage, suppression *sarifSuppression) (json.RawMessage, error) {
+	result := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	suppressions := []json.RawMessage{}
+	if existing, ok := result["suppressions"]; ok {
+		if err := json.Unmarshal(existing, &suppressions); err != nil {
+			return nil, err
+		}
+	}
+	s, err := encodeJSON(suppression, false)
+	if err != nil {
+		return nil, err
+	}
+	if result["suppressions"], err = encodeJSON(append(suppressions, s), false); err != nil {
+		return nil, err
+	}
+	return encodeJSON(result, false)
+}
+
+// purl returns the purl of the package a result was found in.
+func (res *sarifResult) purl() string {
+	for _, key := range []string{"purl", "packageUrl"} {
+		if p, ok := res.Properties[key].(string); ok && p != "" {
+			return p
+		}
+	}
+	for _, l := range res.Locations {
+		for _, ll := range l.LogicalLocations {
+			if strings.HasPrefix(ll.FullyQualifiedName, "pkg:") {
+				return ll.FullyQualifiedName
+			}
+		}
+	}
+	return ""
+}
+
+// newSARIFSuppression returns the suppression of a result covered by a
+// statement.
+func newSARIFSuppression(stmt *vex.Statement, doc *vex.VEX) *sarifSuppression {
+	parts := []string{string(stmt.Status)}
+	if stmt.Justification != "" {
+		parts = append(parts, string(stmt.Justification))
+	}
+	if stmt.ImpactStatement != "" {
+		parts = append(parts, stmt.ImpactStatement)
+	}
+
+	props := map[string]any{"vexStatus": string(stmt.Status)}
+	if stmt.Justification != "" {
+		props["vexJustification"] = string(stmt.Justification)
+	}
+	if stmt.ID != "" {
+		props["vexStatement"] = stmt.ID
+	}
+	if doc != nil && doc.ID != "" {
+		props["vexDocument"] = doc.ID
+	}
+	return &sarifSuppression{
+		Kind:          "external",
+		Status:        "accepted",
+		Justification: strings.Join(parts, ": "),
+		Properties:    props,
+	}
+}
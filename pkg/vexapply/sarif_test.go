@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func TestSARIF(t *testing.T) {
+	data, err := os.ReadFile("testdata/results.sarif")
+	require.NoError(t, err)
+
+	doc := openDocument(t)
+	doc.Statements[1].ImpactStatement = "The HTTP/2 server is not used"
+
+	// Without the image the subcomponent statement does not match
+	res, err := SARIF(data, doc)
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Findings)
+	require.Empty(t, res.Suppressed)
+
+	res, err = SARIFWithOptions(&SARIFOptions{Products: []string{"pkg:oci/app"}}, data, doc)
+	require.NoError(t, err)
+	require.Len(t, res.Suppressed, 1)
+	require.Equal(t, "CVE-2023-39325", res.Suppressed[0].Vulnerability)
+	require.Equal(t, "pkg:golang/golang.org/x/net@v0.7.0", res.Suppressed[0].Package)
+
+	report := struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID       string             `json:"ruleId"`
+				Suppressions []sarifSuppression `json:"suppressions"`
+			} `json:"results"`
+		} `json:"runs"`
+	}{}
+	require.NoError(t, json.Unmarshal(res.Report, &report))
+	require.Equal(t, "2.1.0", report.Version)
+	require.Len(t, report.Runs[0].Results, 2)
+	require.Equal(t, []sarifSuppression{{
+		Kind:          "external",
+		Status:        "accepted",
+		Justification: "not_affected: vulnerable_code_not_in_execute_path: The HTTP/2 server is not used",
+		Properties: map[string]any{
+			"vexStatus":        "not_affected",
+			"vexJustification": "vulnerable_code_not_in_execute_path",
+			"vexDocument":      "https://example.com/vex-1",
+		},
+	}}, report.Runs[0].Results[0].Suppressions)
+	require.Empty(t, report.Runs[0].Results[1].Suppressions)
+
+	// The package is read from the result properties
+	doc.Statements[2].Status = vex.StatusFixed
+	res, err = SARIF(data, doc)
+	require.NoError(t, err)
+	require.Len(t, res.Suppressed, 1)
+	require.Equal(t, "CVE-2023-44487", res.Suppressed[0].Vulnerability)
+	require.Equal(t, 1, res.Stats().ByStatus[vex.StatusFixed])
+}
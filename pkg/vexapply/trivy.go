@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// trivyReport captures the fields of a Trivy JSON report used to identify
+// the scanned artifact.
+type trivyReport struct {
+	ArtifactName string
+	Metadata     struct {
+		RepoDigests []string
+	}
+}
+
+// trivyVulnerability captures the fields of a Trivy finding used to match it
+// against VEX statements.
+type trivyVulnerability struct {
+	VulnerabilityID string
+	PkgName         string
+	PkgIdentifier   struct {
+		PURL string
+	}
+}
+
+// trivyResults adapts a Trivy JSON report to a result set.
+type trivyResults struct {
+	raw      map[string]json.RawMessage
+	results  []map[string]json.RawMessage
+	vulns    [][]json.RawMessage
+	products []string
+	findings []Finding
+
+	// locations holds the result and vulnerability index of each finding
+	locations [][2]int
+	dropped   map[[2]int]bool
+}
+
+// Trivy applies VEX documents to a Trivy JSON report. Findings are matched
+// by their vulnerability ID and package purl to the effective statements
+// about the scanned artifact or the package itself. The artifact is
+// identified by its name and, for container images, by the oci purls of its
+// repository digests. Findings with a not_affected or fixed effective
+// statement are removed from the report, the rest of the report is kept as
+// is.
+func Trivy(report []byte, docs ...*vex.VEX) (*Result, error) {
+	rs, err := NewTrivyResults(report)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(docs, rs)
+}
+
+// NewTrivyResults returns the result set of a Trivy JSON report. Annotated
+// findings are tagged under the VEX key.
+func NewTrivyResults(report []byte) (ResultSet, error) {
+	rs := &trivyResults{raw: map[string]json.RawMessage{}, dropped: map[[2]int]bool{}}
+	if err := json.Unmarshal(report, &rs.raw); err != nil {
+		return nil, fmt.Errorf("decoding trivy report: %w", err)
+	}
+	meta := trivyReport{}
+	if err := json.Unmarshal(report, &meta); err != nil {
+		return nil, fmt.Errorf("decoding trivy report: %w", err)
+	}
+	rs.products = trivyProducts(&meta)
+
+	if data, ok := rs.raw["Results"]; ok {
+		if err := json.Unmarshal(data, &rs.results); err != nil {
+			return nil, fmt.Errorf("decoding trivy results: %w", err)
+		}
+	}
+	rs.vulns = make([][]json.RawMessage, len(rs.results))
+	for i := range rs.results {
+		data, ok := rs.results[i]["Vulnerabilities"]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(data, &rs.vulns[i]); err != nil {
+			return nil, fmt.Errorf("decoding vulnerabilities of result #%d: %w", i, err)
+		}
+		for j, v := range rs.vulns[i] {
+			finding := trivyVulnerability{}
+			if err := json.Unmarshal(v, &finding); err != nil {
+				return nil, fmt.Errorf("decoding vulnerability #%d of result #%d: %w", j, i, err)
+			}
+			pkg := finding.PkgIdentifier.PURL
+			if pkg == "" {
+				pkg = finding.PkgName
+			}
+			rs.findings = append(rs.findings, Finding{
+				Vulnerability: finding.VulnerabilityID,
+				Packages:      nonEmpty(pkg),
+			})
+			rs.locations = append(rs.locations, [2]int{i, j})
+		}
+	}
+	return rs, nil
+}
+
+func (rs *trivyResults) Products() []string { return rs.products }
+
+func (rs *trivyResults) Findings() []Finding { return rs.findings }
+
+// Suppress removes a finding from the report.
+func (rs *trivyResults) Suppress(finding int, _ *Suppression) error {
+	rs.dropped[rs.locations[finding]] = true
+	return nil
+}
+
+// Annotate tags a finding with the statement suppressing it.
+func (rs *trivyResults) Annotate(finding int, s *Suppression) error {
+	l := rs.locations[finding]
+	data, err := annotateJSON(rs.vulns[l[0]][l[1]], "VEX", s.Annotation())
+	if err != nil {
+		return err
+	}
+	rs.vulns[l[0]][l[1]] = data
+	return nil
+}
+
+func (rs *trivyResults) Report() ([]byte, error) {
+	for i := range rs.results {
+		if len(rs.vulns[i]) == 0 {
+			continue
+		}
+		kept := []json.RawMessage{}
+		for j, v := range rs.vulns[i] {
+			if !rs.dropped[[2]int{i, j}] {
+				kept = append(kept, v)
+			}
+		}
+
+		// Trivy omits the vulnerabilities of results without findings
+		if len(kept) == 0 {
+			delete(rs.results[i], "Vulnerabilities")
+			continue
+		}
+		data, err := encodeJSON(kept, false)
+		if err != nil {
+			return nil, err
+		}
+		rs.results[i]["Vulnerabilities"] = data
+	}
+
+	if _, ok := rs.raw["Results"]; ok {
+		data, err := encodeJSON(rs.results, false)
+		if err != nil {
+			return nil, err
+		}
+		rs.raw["Results"] = data
+	}
+	return encodeJSON(rs.raw, true)
+}
+
+// trivyProducts returns the identifiers of the artifact scanned in a report.
+func trivyProducts(report *trivyReport) []string {
+	products := []string{}
+	if report.ArtifactName != "" {
+		products = append(products, report.ArtifactName)
+	}
+	for _, digest := range report.Metadata.RepoDigests {
+		if p := ociPurl(digest); p != "" {
+			products = append(products, p)
+		}
+	}
+	return products
+}
+
+// ociPurl returns the oci purl of an image repository digest such as
+// ghcr.io/example/app@sha256:abc.
+func ociPurl(repoDigest string) string {
+	repo, digest, ok := strings.Cut(repoDigest, "@")
+	if !ok || repo == "" || digest == "" {
+		return ""
+	}
+	return packageurl.NewPackageURL(
+		packageurl.TypeOCI, "", path.Base(repo), digest,
+		packageurl.Qualifiers{{Key: "repository_url", Value: repo}}, "",
+	).ToString()
+}
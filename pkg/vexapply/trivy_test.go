@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexapply
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// openDocument reads the VEX document with statements about the findings
+// in the test reports.
+func openDocument(t *testing.T) *vex.VEX {
+	t.Helper()
+	doc, err := vex.Open("testdata/vex.json")
+	require.NoError(t, err)
+	return doc
+}
+
+func TestTrivy(t *testing.T) {
+	data, err := os.ReadFile("testdata/trivy.json")
+	require.NoError(t, err)
+
+	doc := openDocument(t)
+	res, err := Trivy(data, doc)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Findings)
+	require.Len(t, res.Suppressed, 2)
+	require.Equal(t, "CVE-2023-5678", res.Suppressed[0].Vulnerability)
+	require.Equal(t, "pkg:apk/alpine/openssl@3.1.0-r4?arch=x86_64&distro=3.18.0", res.Suppressed[0].Package)
+	require.Equal(t, &doc.Statements[0], res.Suppressed[0].Statement)
+	require.Equal(t, doc, res.Suppressed[0].Document)
+
+	// Matched as a subcomponent of the image
+	require.Equal(t, "CVE-2023-39325", res.Suppressed[1].Vulnerability)
+	require.Equal(t, &doc.Statements[1], res.Suppressed[1].Statement)
+
+	report := map[string]any{}
+	require.NoError(t, json.Unmarshal(res.Report, &report))
+	require.Equal(t, float64(2), report["SchemaVersion"])
+	results, ok := report["Results"].([]any)
+	require.True(t, ok)
+	require.Len(t, results, 2)
+	require.NotContains(t, results[0], "Vulnerabilities")
+	require.Equal(t, "alpine", results[0].(map[string]any)["Type"])
+	vulns, ok := results[1].(map[string]any)["Vulnerabilities"].([]any)
+	require.True(t, ok)
+	require.Len(t, vulns, 1)
+	require.Equal(t, "CVE-2023-44487", vulns[0].(map[string]any)["VulnerabilityID"])
+
+	// Without documents the findings are kept
+	res, err = Trivy(data)
+	require.NoError(t, err)
+	require.Empty(t, res.Suppressed)
+	require.JSONEq(t, string(data), string(res.Report))
+	require.Contains(t, string(res.Report), "arch=x86_64&distro=3.18.0")
+
+	_, err = Trivy([]byte("not json"), doc)
+	require.Error(t, err)
+}
+
+func TestOCIPurl(t *testing.T) {
+	require.Equal(
+		t, "pkg:oci/app@sha256%3Aabc?repository_url=ghcr.io%2Fexample%2Fapp",
+		ociPurl("ghcr.io/example/app@sha256:abc"),
+	)
+	require.Empty(t, ociPurl("ghcr.io/example/app"))
+}
+
+func TestTrivyAnnotate(t *testing.T) {
+	data, err := os.ReadFile("testdata/trivy.json")
+	require.NoError(t, err)
+
+	rs, err := NewTrivyResults(data)
+	require.NoError(t, err)
+	res, err := ApplyWithOptions(&ApplyOptions{Annotate: true}, []*vex.VEX{openDocument(t)}, rs)
+	require.NoError(t, err)
+	require.Len(t, res.Suppressed, 2)
+
+	report := struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID string
+				VEX             *Annotation
+			}
+		}
+	}{}
+	require.NoError(t, json.Unmarshal(res.Report, &report))
+	require.Len(t, report.Results[0].Vulnerabilities, 1)
+	require.Equal(t, &Annotation{
+		Status:        vex.StatusNotAffected,
+		Justification: vex.VulnerableCodeNotInExecutePath,
+		Document:      "https://example.com/vex-1",
+	}, report.Results[0].Vulnerabilities[0].VEX)
+	require.Len(t, report.Results[1].Vulnerabilities, 2)
+	require.NotNil(t, report.Results[1].Vulnerabilities[0].VEX)
+	require.Nil(t, report.Results[1].Vulnerabilities[1].VEX)
+}
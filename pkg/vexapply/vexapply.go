@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package vexapply applies VEX documents to the reports of vulnerability
+// scanners, removing or marking as suppressed the findings that the
+// effective VEX statements mark as not_affected or fixed and recording which
+// statement suppressed each one.
+//
+// Each supported format has a function applying the documents directly and
+// a ResultSet constructor. Result sets can be passed to ApplyWithOptions to
+// keep the suppressed findings in the report, annotated with the VEX data
+// that suppressed them, for auditing.
+package vexapply
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// Suppression records a finding suppressed in a report and the statement
+// that suppressed it.
+type Suppression struct {
+	// Vulnerability is the identifier of the vulnerability as reported by
+	// the scanner.
+	Vulnerability string
+
+	// Package is the identifier, usually a purl, of the package the
+	// vulnerability was found in.
+	Package string
+
+	// Statement is the effective statement that suppressed the finding.
+	Statement *vex.Statement
+
+	// Document is the VEX document the statement comes from.
+	Document *vex.VEX
+}
+
+// Result is the outcome of applying VEX documents to a scanner report.
+type Result struct {
+	// Report is the report with the VEX documents applied.
+	Report []byte
+
+	// Findings is the number of findings in the original report.
+	Findings int
+
+	// Suppressed lists the findings suppressed by the VEX documents.
+	Suppressed []Suppression
+
+	// Unused lists the statements that did not match any finding. These
+	// are often stale statements about products or vulnerabilities no
+	// longer found in the scanned artifacts.
+	Unused []UnusedStatement
+
+	// Rejected lists the statements rejected by the policy, if any.
+	Rejected []Rejection
+
+	// Traces explain how each finding was matched. They are only recorded
+	// in explain mode.
+	Traces []Trace
+}
+
+// UnusedStatement is a statement that did not match any finding of a report.
+type UnusedStatement struct {
+	Statement *vex.Statement
+	Document  *vex.VEX
+}
+
+// Stats summarizes the findings suppressed when applying VEX documents.
+type Stats struct {
+	// Findings is the number of findings in the original report.
+	Findings int
+
+	// Suppressed is the number of findings suppressed.
+	Suppressed int
+
+	// Unused is the number of statements that matched no finding.
+	Unused int
+
+	// ByStatus counts the suppressed findings by statement status.
+	ByStatus map[vex.Status]int
+
+	// ByJustification counts the findings suppressed by not_affected
+	// statements by their justification. Statements without a
+	// justification are counted under the empty string.
+	ByJustification map[vex.Justification]int
+
+	// ByDocument counts the suppressed findings by the ID of the document
+	// the statement comes from.
+	ByDocument map[string]int
+}
+
+// Stats returns the suppression statistics of the result.
+func (res *Result) Stats() Stats {
+	stats := Stats{
+		Findings:        res.Findings,
+		Suppressed:      len(res.Suppressed),
+		Unused:          len(res.Unused),
+		ByStatus:        map[vex.Status]int{},
+		ByJustification: map[vex.Justification]int{},
+		ByDocument:      map[string]int{},
+	}
+	for _, s := range res.Suppressed {
+		stats.ByStatus[s.Statement.Status]++
+		if s.Statement.Status == vex.StatusNotAffected {
+			stats.ByJustification[s.Statement.Justification]++
+		}
+		if s.Document != nil {
+			stats.ByDocument[s.Document.ID]++
+		}
+	}
+	return stats
+}
+
+// encodeJSON serializes a report without escaping HTML characters so the
+// data not touched by VEX is written back as it was read.
+func encodeJSON(v any, indent bool) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if indent {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("encoding report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// nonEmpty returns a list with the identifier, or an empty list if it is
+// empty.
+func nonEmpty(id string) []string {
+	if id == "" {
+		return nil
+	}
+	return []string{id}
+}
+
+// annotateJSON adds an annotation under the key of a JSON object.
+func annotateJSON(data json.RawMessage, key string, a *Annotation) (json.RawMessage, error) {
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("decoding finding: %w", err)
+	}
+	annotation, err := encodeJSON(a, false)
+	if err != nil {
+		return nil, err
+	}
+	object[key] = annotation
+	return encodeJSON(object, false)
+}
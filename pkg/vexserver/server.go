@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package vexserver serves VEX documents over HTTP from a Store.
+//
+// The Handler exposes two endpoints:
+//
+//	GET /v1/document?id=<@id>
+//	GET /v1/documents?purl=<purl>&vulnerability=<id>
+//
+// The first returns a single document. The second returns the documents
+// with statements about a product, a vulnerability or both as
+//
+//	{"documents": [...]}
+//
+// which is the format read by the hub package client. Responses carry an
+// ETag and honor If-None-Match. The ETag is the hash of the bytes served
+// rather than the CanonicalHash of the documents: the canonical hash leaves
+// out fields such as impact and action statements, so edits to them would
+// keep the ETag and clients would go on using a stale copy.
+//
+// When the handler has a signer, single documents are sent with a JWS of the
+// document in the X-OpenVEX-Signature header, which clients check with
+// VEX.VerifyJWS.
+package vexserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// MediaType is the content type of single documents
+	MediaType = "application/openvex+json"
+
+	// SignatureHeader carries the JWS of a document
+	SignatureHeader = "X-OpenVEX-Signature"
+)
+
+// Handler is an http.Handler serving documents from a store.
+type Handler struct {
+	// Store holds the documents served
+	Store Store
+
+	// Signer signs the single documents served. It is optional.
+	Signer vex.JWSSigner
+}
+
+// NewHandler returns a handler serving the documents in store.
+func NewHandler(store Store) *Handler {
+	return &Handler{Store: store}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch strings.TrimSuffix(req.URL.Path, "/") {
+	case "/v1/document":
+		h.serveDocument(w, req)
+	case "/v1/documents":
+		h.serveDocuments(w, req)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (h *Handler) serveDocument(w http.ResponseWriter, req *http.Request) {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "the id parameter is required", http.StatusBadRequest)
+		return
+	}
+	doc, err := h.Store.Document(req.Context(), id)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "document not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "reading document: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if h.Signer != nil {
+		jws, err := doc.ToJWS(h.Signer)
+		if err != nil {
+			http.Error(w, "signing document: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(SignatureHeader, jws)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.ToJSON(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	write(w, req, MediaType, buf.Bytes())
+}
+
+func (h *Handler) serveDocuments(w http.ResponseWriter, req *http.Request) {
+	purl := req.URL.Query().Get("purl")
+	vuln := req.URL.Query().Get("vulnerability")
+
+	var docs []*vex.VEX
+	var err error
+	switch {
+	case purl != "":
+		docs, err = h.Store.DocumentsByProduct(req.Context(), purl)
+		if vuln != "" {
+			docs = slices.DeleteFunc(docs, func(doc *vex.VEX) bool {
+				return doc.Query().Product(purl).Vulnerability(vuln).Count() == 0
+			})
+		}
+	case vuln != "":
+		docs, err = h.Store.DocumentsByVulnerability(req.Context(), vuln)
+	default:
+		http.Error(w, "the purl or vulnerability parameters are required", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "reading documents: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(struct {
+		Documents []*vex.VEX `json:"documents"`
+	}{docs})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	write(w, req, "application/json", data)
+}
+
+// write sends data unless the client already has it. The ETag is the hash
+// of data so any change in the response, including fields left out of the
+// canonical hash of the documents, gets a new one.
+func write(w http.ResponseWriter, req *http.Request, contentType string, data []byte) {
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	w.Header().Set("ETag", etag)
+	for _, tag := range strings.Split(req.Header.Get("If-None-Match"), ",") {
+		if tag = strings.TrimSpace(tag); tag == etag || tag == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	if req.Method == http.MethodHead {
+		return
+	}
+	w.Write(data) //nolint:errcheck
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexserver
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/hub"
+	"github.com/openvex/go-vex/pkg/store"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+func testStore(t *testing.T) *store.MemoryStore {
+	s := store.NewMemoryStore()
+	for _, id := range []string{"1", "2", "3"} {
+		doc, err := vex.Open("testdata/" + id + ".vex.json")
+		require.NoError(t, err)
+		require.NoError(t, s.Put(context.Background(), doc))
+	}
+	return s
+}
+
+func getDocuments(t *testing.T, h http.Handler, query string) []string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/documents?"+query, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	res := struct {
+		Documents []*vex.VEX `json:"documents"`
+	}{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	ids := []string{}
+	for _, doc := range res.Documents {
+		ids = append(ids, doc.ID)
+	}
+	return ids
+}
+
+func TestDocuments(t *testing.T) {
+	h := NewHandler(testStore(t))
+	for _, tc := range []struct {
+		name  string
+		query string
+		ids   []string
+	}{
+		{"product", "purl=pkg:npm/example@1.0.0", []string{"1", "2"}},
+		{"generic purl", "purl=pkg:npm/example", []string{}},
+		{"vulnerability", "vulnerability=CVE-2023-1111", []string{"1", "3"}},
+		{"both", "purl=pkg:npm/example@1.0.0&vulnerability=CVE-2023-2222", []string{"2"}},
+		{"none", "purl=pkg:npm/missing@1.0.0", []string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := []string{}
+			for _, id := range tc.ids {
+				expected = append(expected, "https://example.com/vex/"+id)
+			}
+			require.Equal(t, expected, getDocuments(t, h, tc.query))
+		})
+	}
+}
+
+func TestDocument(t *testing.T) {
+	store := testStore(t)
+	h := NewHandler(store)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/document?id=https://example.com/vex/1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, MediaType, rec.Header().Get("Content-Type"))
+	require.Empty(t, rec.Header().Get(SignatureHeader))
+
+	sum := sha256.Sum256(rec.Body.Bytes())
+	etag := rec.Header().Get("ETag")
+	require.Equal(t, `"`+hex.EncodeToString(sum[:])+`"`, etag)
+
+	// The stored document is not reordered when served
+	stored, err := store.Document(context.Background(), "https://example.com/vex/1")
+	require.NoError(t, err)
+	require.Equal(t, vex.VulnerabilityID("CVE-2023-9999"), stored.Statements[0].Vulnerability.Name)
+
+	// Conditional requests
+	req := httptest.NewRequest(http.MethodGet, "/v1/document?id=https://example.com/vex/1", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotModified, rec.Code)
+	require.Empty(t, rec.Body.Bytes())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/v1/document?id=https://example.com/vex/1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, etag, rec.Header().Get("ETag"))
+	require.Empty(t, rec.Body.Bytes())
+
+	// Edits left out of the canonical hash change the ETag
+	edited := stored.Clone()
+	edited.Statements[0].StatusNotes = "Looking into it"
+	require.NoError(t, store.Put(context.Background(), edited))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/document?id=https://example.com/vex/1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	for _, tc := range []struct {
+		method string
+		target string
+		code   int
+	}{
+		{http.MethodGet, "/v1/document?id=https://example.com/vex/9", http.StatusNotFound},
+		{http.MethodGet, "/v1/document", http.StatusBadRequest},
+		{http.MethodGet, "/v1/documents", http.StatusBadRequest},
+		{http.MethodGet, "/v2/documents", http.StatusNotFound},
+		{http.MethodPost, "/v1/documents", http.StatusMethodNotAllowed},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, nil))
+		require.Equal(t, tc.code, rec.Code, tc.target)
+	}
+}
+
+func TestDocumentSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	h := NewHandler(testStore(t))
+	h.Signer = vex.NewED25519Signer("key", priv)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/document?id=https://example.com/vex/2", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	doc, err := vex.Parse(rec.Body.Bytes())
+	require.NoError(t, err)
+	jws := rec.Header().Get(SignatureHeader)
+	require.NotEmpty(t, jws)
+	require.NoError(t, doc.VerifyJWS(jws, vex.NewED25519Verifier("key", pub)))
+}
+
+func TestHubClient(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(testStore(t)))
+	defer srv.Close()
+
+	docs, err := hub.NewClient(srv.URL).Documents(context.Background(), "pkg:npm/other@2.0.0")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "https://example.com/vex/3", docs[0].ID)
+}
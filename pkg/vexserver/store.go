@@ -0,0 +1,27 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vexserver
+
+import (
+	"context"
+
+	"github.com/openvex/go-vex/pkg/store"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// ErrNotFound is returned by stores when a document does not exist
+var ErrNotFound = store.ErrNotFound
+
+// Store is the source of the documents served by the Handler. It is the
+// read side of store.Store so any of the stores in that package can be
+// served.
+type Store interface {
+	Document(ctx context.Context, id string) (*vex.VEX, error)
+	DocumentsByProduct(ctx context.Context, product string) ([]*vex.VEX, error)
+	DocumentsByVulnerability(ctx context.Context, id string) ([]*vex.VEX, error)
+}
+
+var _ Store = store.Store(nil)
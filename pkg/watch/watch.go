@@ -0,0 +1,291 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package watch polls remote VEX sources and reports the documents that
+// appear, change or go away, so downstream systems can react when a vendor
+// updates its assessment of a vulnerability.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/discovery"
+	"github.com/openvex/go-vex/pkg/hub"
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// DefaultInterval is the time between polls when the Poller has none set
+const DefaultInterval = 15 * time.Minute
+
+// Source returns the documents currently published by a remote source.
+type Source func(ctx context.Context) ([]*vex.VEX, error)
+
+// HubSource returns a source listing the documents a VEX repository has
+// about purl.
+func HubSource(c *hub.Client, purl string) Source {
+	return func(ctx context.Context) ([]*vex.VEX, error) {
+		return c.Documents(ctx, purl)
+	}
+}
+
+// DiscoverySource returns a source listing the documents discovered for a
+// purl or repository URL. Finding no documents is not an error.
+func DiscoverySource(d *discovery.Discoverer, locator string) Source {
+	return func(ctx context.Context) ([]*vex.VEX, error) {
+		docs, err := d.Discover(ctx, locator)
+		if errors.Is(err, discovery.ErrNotFound) {
+			return []*vex.VEX{}, nil
+		}
+		return docs, err
+	}
+}
+
+// URLSource returns a source fetching a single document from a URL.
+func URLSource(client *http.Client, u string) Source {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) ([]*vex.VEX, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", u, err)
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s: server returned %s", u, res.Status)
+		}
+		data, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", u, err)
+		}
+		doc, err := vex.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*vex.VEX{doc}, nil
+	}
+}
+
+// ChangeType is the kind of change reported for a document.
+type ChangeType string
+
+const (
+	// Added is reported the first time a document is seen
+	Added ChangeType = "added"
+
+	// Updated is reported when the version or the statements of a document
+	// change
+	Updated ChangeType = "updated"
+
+	// Removed is reported when a source stops publishing a document
+	Removed ChangeType = "removed"
+)
+
+// Change is a change in the documents published by a source.
+type Change struct {
+	// Source is the name of the source
+	Source string
+
+	Type ChangeType
+
+	// Document is the new document. It is nil for removed documents.
+	Document *vex.VEX
+
+	// Previous is the document seen before. It is nil for added documents.
+	Previous *vex.VEX
+}
+
+// SourceError is the error of a source in a poll.
+type SourceError struct {
+	Source string
+	Err    error
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("polling %s: %v", e.Source, e.Err)
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// Poller polls sources and reports the changes in their documents.
+// Documents are told apart by their @id and considered updated when any of
+// their content changes, as detected by vex.VEX.ContentHash.
+type Poller struct {
+	// Sources are the sources to poll by name
+	Sources map[string]Source
+
+	// Interval is the time between polls. It defaults to DefaultInterval.
+	Interval time.Duration
+
+	// OnError is called with the errors of the sources in Run. A failing
+	// source is retried in the next poll and does not report its
+	// documents as removed.
+	OnError func(source string, err error)
+
+	mu   sync.Mutex
+	seen map[string]map[string]*seenDocument
+}
+
+type seenDocument struct {
+	doc  *vex.VEX
+	hash string
+}
+
+// NewPoller returns a poller of the sources.
+func NewPoller(sources map[string]Source) *Poller {
+	return &Poller{Sources: sources}
+}
+
+// Poll fetches all the sources once and returns the changes since the last
+// poll. The first poll reports all documents as added. The SourceErrors of
+// the sources that failed are joined in the returned error, the changes of
+// the sources that succeeded are returned anyway.
+func (p *Poller) Poll(ctx context.Context) ([]Change, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.seen == nil {
+		p.seen = map[string]map[string]*seenDocument{}
+	}
+
+	names := make([]string, 0, len(p.Sources))
+	for name := range p.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changes := []Change{}
+	var errs []error
+	for _, name := range names {
+		docs, err := p.Sources[name](ctx)
+		if err != nil {
+			errs = append(errs, &SourceError{Source: name, Err: err})
+			continue
+		}
+		c, err := p.update(name, docs)
+		if err != nil {
+			errs = append(errs, &SourceError{Source: name, Err: err})
+			continue
+		}
+		changes = append(changes, c...)
+	}
+	return changes, errors.Join(errs...)
+}
+
+// update records the documents of a source and returns the changes.
+func (p *Poller) update(source string, docs []*vex.VEX) ([]Change, error) {
+	previous := p.seen[source]
+	current := map[string]*seenDocument{}
+	changes := []Change{}
+	for _, doc := range docs {
+		hash, err := doc.ContentHash()
+		if err != nil {
+			return nil, fmt.Errorf("hashing document: %w", err)
+		}
+		key := doc.ID
+		if key == "" {
+			key = hash
+		}
+		current[key] = &seenDocument{doc: doc, hash: hash}
+
+		prev, ok := previous[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Source: source, Type: Added, Document: doc})
+		case prev.hash != hash:
+			changes = append(changes, Change{Source: source, Type: Updated, Document: doc, Previous: prev.doc})
+		}
+	}
+
+	removed := []string{}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		changes = append(changes, Change{Source: source, Type: Removed, Previous: previous[key].doc})
+	}
+
+	p.seen[source] = current
+	return changes, nil
+}
+
+// Run polls the sources every interval and calls fn with each change until
+// the context is done or fn returns an error, which is returned. The first
+// poll happens right away.
+func (p *Poller) Run(ctx context.Context, fn func(Change) error) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		changes, err := p.Poll(ctx)
+		if err != nil && p.OnError != nil && ctx.Err() == nil {
+			p.reportErrors(err)
+		}
+		for _, c := range changes {
+			if err := fn(c); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Changes runs the poller in a goroutine and returns a channel delivering
+// the changes. The channel is closed when the context is done.
+func (p *Poller) Changes(ctx context.Context) <-chan Change {
+	ch := make(chan Change)
+	go func() {
+		defer close(ch)
+		p.Run(ctx, func(c Change) error { //nolint:errcheck // only returns the context error
+			select {
+			case ch <- c:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return ch
+}
+
+// reportErrors calls OnError with each of the source errors joined by Poll.
+func (p *Poller) reportErrors(err error) {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		p.OnError("", err)
+		return
+	}
+	for _, e := range joined.Unwrap() {
+		source := ""
+		var serr *SourceError
+		if errors.As(e, &serr) {
+			source = serr.Source
+		}
+		p.OnError(source, e)
+	}
+}
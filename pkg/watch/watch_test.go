@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The OpenVEX Authors
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+// openDocuments reads documents from the testdata directory
+func openDocuments(t *testing.T, names ...string) []*vex.VEX {
+	t.Helper()
+	docs := []*vex.VEX{}
+	for _, name := range names {
+		doc, err := vex.Open(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// fakeSource serves the documents it is set to
+type fakeSource struct {
+	mu   sync.Mutex
+	docs []*vex.VEX
+	err  error
+}
+
+func (f *fakeSource) set(err error, docs ...*vex.VEX) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.docs, f.err = docs, err
+}
+
+func (f *fakeSource) source(context.Context) ([]*vex.VEX, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.docs, f.err
+}
+
+func changeTypes(changes []Change) []string {
+	ret := []string{}
+	for _, c := range changes {
+		id := ""
+		if c.Document != nil {
+			id = c.Document.ID
+		} else {
+			id = c.Previous.ID
+		}
+		ret = append(ret, c.Source+" "+string(c.Type)+" "+id[len("https://example.com/vex/"):])
+	}
+	return ret
+}
+
+func TestPoll(t *testing.T) {
+	a, b := &fakeSource{}, &fakeSource{}
+	p := NewPoller(map[string]Source{"a": a.source, "b": b.source})
+	ctx := context.Background()
+
+	a.set(nil, openDocuments(t, "1.vex.json", "2.vex.json")...)
+	b.set(nil, openDocuments(t, "3.vex.json")...)
+	changes, err := p.Poll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a added 1", "a added 2", "b added 3"}, changeTypes(changes))
+
+	// Nothing changed
+	changes, err = p.Poll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// The vendor flips a status and drops a document, the other source fails
+	a.set(nil, openDocuments(t, "1-v2.vex.json")...)
+	b.set(errors.New("unavailable"))
+	changes, err = p.Poll(ctx)
+	var serr *SourceError
+	require.ErrorAs(t, err, &serr)
+	require.Equal(t, "b", serr.Source)
+	require.Equal(t, []string{"a updated 1", "a removed 2"}, changeTypes(changes))
+	require.Equal(t, vex.StatusAffected, changes[0].Document.Statements[0].Status)
+	require.Equal(t, vex.StatusUnderInvestigation, changes[0].Previous.Statements[0].Status)
+
+	// Failing sources don't lose their documents
+	b.set(nil, openDocuments(t, "3.vex.json")...)
+	changes, err = p.Poll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// Statement changes are detected even if the version is not bumped
+	b.set(nil, openDocuments(t, "3-affected.vex.json")...)
+	changes, err = p.Poll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b updated 3"}, changeTypes(changes))
+
+	// So are edits to fields outside the canonical hash
+	b.set(nil, openDocuments(t, "3-action.vex.json")...)
+	changes, err = p.Poll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b updated 3"}, changeTypes(changes))
+	require.Equal(t, "Upgrade to 1.2", changes[0].Document.Statements[0].ActionStatement)
+}
+
+func TestChanges(t *testing.T) {
+	var mu sync.Mutex
+	name := "1.vex.json"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(data) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := NewPoller(map[string]Source{"vendor": URLSource(nil, srv.URL)})
+	p.Interval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := p.Changes(ctx)
+	c := <-ch
+	require.Equal(t, Added, c.Type)
+
+	mu.Lock()
+	name = "1-v2.vex.json"
+	mu.Unlock()
+	c = <-ch
+	require.Equal(t, Updated, c.Type)
+	require.Equal(t, vex.StatusAffected, c.Document.Statements[0].Status)
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	src := &fakeSource{}
+	src.set(errors.New("unavailable"))
+	p := NewPoller(map[string]Source{"vendor": src.source})
+	p.Interval = time.Millisecond
+
+	// The source recovers after the first error is reported
+	var reported []string
+	p.OnError = func(source string, _ error) {
+		reported = append(reported, source)
+		src.set(nil, openDocuments(t, "2.vex.json")...)
+	}
+	stop := errors.New("stop")
+	err := p.Run(context.Background(), func(c Change) error {
+		require.Equal(t, Added, c.Type)
+		return stop
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, []string{"vendor"}, reported)
+}